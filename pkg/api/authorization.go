@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/user"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// fieldRoles is the role required to resolve root fields, keyed by
+// "<operation type>.<field name>". Fields that are not listed require the
+// default role of their operation type.
+var fieldRoles = map[string]models.UserRole{
+	// own account
+	"Mutation.userChangePassword": models.UserRoleViewer,
+	"Mutation.generateAPIKey":     models.UserRoleViewer,
+
+	// own ratings, o-counters and playback activity
+	"Mutation.sceneIncrementO":     models.UserRoleViewer,
+	"Mutation.sceneDecrementO":     models.UserRoleViewer,
+	"Mutation.sceneResetO":         models.UserRoleViewer,
+	"Mutation.sceneUpdateUserData": models.UserRoleViewer,
+	"Mutation.sceneAddPlay":        models.UserRoleViewer,
+	"Mutation.sceneSaveActivity":   models.UserRoleViewer,
+	"Mutation.imageIncrementO":     models.UserRoleViewer,
+	"Mutation.imageDecrementO":     models.UserRoleViewer,
+	"Mutation.imageResetO":         models.UserRoleViewer,
+	"Mutation.imageUpdateUserData": models.UserRoleViewer,
+
+	// configuration
+	"Query.directory":               models.UserRoleAdmin,
+	"Query.logs":                    models.UserRoleAdmin,
+	"Query.jobHistory":              models.UserRoleAdmin,
+	"Subscription.loggingSubscribe": models.UserRoleAdmin,
+	"Mutation.setup":                models.UserRoleAdmin,
+	"Mutation.migrate":              models.UserRoleAdmin,
+	"Mutation.configureGeneral":     models.UserRoleAdmin,
+	"Mutation.configureInterface":   models.UserRoleAdmin,
+	"Mutation.configureDLNA":        models.UserRoleAdmin,
+	"Mutation.configureScraping":    models.UserRoleAdmin,
+	"Mutation.enableDLNA":           models.UserRoleAdmin,
+	"Mutation.disableDLNA":          models.UserRoleAdmin,
+	"Mutation.addTempDLNAIP":        models.UserRoleAdmin,
+	"Mutation.removeTempDLNAIP":     models.UserRoleAdmin,
+	"Mutation.reloadScrapers":       models.UserRoleAdmin,
+	"Mutation.reloadPlugins":        models.UserRoleAdmin,
+	"Mutation.runPluginTask":        models.UserRoleAdmin,
+
+	// users
+	"Query.users":          models.UserRoleAdmin,
+	"Mutation.userCreate":  models.UserRoleAdmin,
+	"Mutation.userUpdate":  models.UserRoleAdmin,
+	"Mutation.userDestroy": models.UserRoleAdmin,
+
+	// destructive tasks
+	"Mutation.importObjects":     models.UserRoleAdmin,
+	"Mutation.metadataImport":    models.UserRoleAdmin,
+	"Mutation.metadataClean":     models.UserRoleAdmin,
+	"Mutation.migrateHashNaming": models.UserRoleAdmin,
+	"Mutation.backupDatabase":    models.UserRoleAdmin,
+
+	// scheduled tasks
+	"Mutation.scheduleCreate":  models.UserRoleAdmin,
+	"Mutation.schedulePause":   models.UserRoleAdmin,
+	"Mutation.scheduleDestroy": models.UserRoleAdmin,
+	"Mutation.scheduleRun":     models.UserRoleAdmin,
+}
+
+// defaultFieldRoles is the role required to resolve root fields that are
+// not listed in fieldRoles.
+var defaultFieldRoles = map[string]models.UserRole{
+	"Query":        models.UserRoleViewer,
+	"Mutation":     models.UserRoleEditor,
+	"Subscription": models.UserRoleViewer,
+}
+
+// authorizeFieldMiddleware returns an error if the current user does not
+// have the role required to resolve a root field.
+func authorizeFieldMiddleware(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+
+	defaultRole, isRoot := defaultFieldRoles[fc.Object]
+	if !isRoot {
+		return next(ctx)
+	}
+
+	role, found := fieldRoles[fc.Object+"."+fc.Field.Name]
+	if !found {
+		role = defaultRole
+	}
+
+	if err := requireRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	return next(ctx)
+}
+
+// requireRole returns an error if the current user does not have the
+// provided role.
+func requireRole(ctx context.Context, role models.UserRole) error {
+	if !user.HasRole(session.GetCurrentUser(ctx), role) {
+		return fmt.Errorf("%w: %s role required", ErrForbidden, role)
+	}
+
+	return nil
+}
+
+// requireRoleHandler returns a middleware that responds with a forbidden
+// status if the current user does not have the provided role.
+func requireRoleHandler(role models.UserRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := requireRole(r.Context(), role); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requireRoleToDeleteFiles returns an error if files are to be deleted and
+// the current user is not an admin.
+func requireRoleToDeleteFiles(ctx context.Context, deleteFile *bool, deleteGenerated *bool) error {
+	if (deleteFile != nil && *deleteFile) || (deleteGenerated != nil && *deleteGenerated) {
+		return requireRole(ctx, models.UserRoleAdmin)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestAuthorizeFieldMiddleware(t *testing.T) {
+	tests := []struct {
+		role      models.UserRole
+		object    string
+		field     string
+		forbidden bool
+	}{
+		{models.UserRoleViewer, "Query", "findScenes", false},
+		{models.UserRoleViewer, "Query", "users", true},
+		{models.UserRoleViewer, "Mutation", "sceneUpdate", true},
+		{models.UserRoleViewer, "Mutation", "userChangePassword", false},
+		{models.UserRoleViewer, "Mutation", "sceneIncrementO", false},
+		{models.UserRoleViewer, "Mutation", "sceneUpdateUserData", false},
+		{models.UserRoleViewer, "Mutation", "imageUpdateUserData", false},
+		{models.UserRoleViewer, "Scene", "title", false},
+		{models.UserRoleEditor, "Mutation", "sceneUpdate", false},
+		{models.UserRoleEditor, "Mutation", "configureGeneral", true},
+		{models.UserRoleEditor, "Mutation", "metadataClean", true},
+		{models.UserRoleEditor, "Subscription", "loggingSubscribe", true},
+		{models.UserRoleViewer, "Query", "jobHistory", true},
+		{models.UserRoleEditor, "Query", "jobHistory", true},
+		{models.UserRoleAdmin, "Query", "jobHistory", false},
+		{models.UserRoleAdmin, "Mutation", "configureGeneral", false},
+		{models.UserRoleAdmin, "Query", "users", false},
+	}
+
+	next := func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	}
+
+	for _, tt := range tests {
+		ctx := session.SetCurrentUser(context.Background(), &models.User{ID: 1, Role: tt.role})
+		ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+			Object: tt.object,
+			Field: graphql.CollectedField{
+				Field: &ast.Field{Name: tt.field},
+			},
+		})
+
+		_, err := authorizeFieldMiddleware(ctx, next)
+		assert.Equal(t, tt.forbidden, errors.Is(err, ErrForbidden), "%s %s.%s", tt.role, tt.object, tt.field)
+	}
+}
+
+func TestRequireRoleToDeleteFiles(t *testing.T) {
+	yes := true
+	no := false
+
+	editor := session.SetCurrentUser(context.Background(), &models.User{ID: 1, Role: models.UserRoleEditor})
+	admin := session.SetCurrentUser(context.Background(), &models.User{ID: 2, Role: models.UserRoleAdmin})
+
+	assert.Nil(t, requireRoleToDeleteFiles(editor, nil, nil))
+	assert.Nil(t, requireRoleToDeleteFiles(editor, &no, &no))
+	assert.True(t, errors.Is(requireRoleToDeleteFiles(editor, &yes, nil), ErrForbidden))
+	assert.True(t, errors.Is(requireRoleToDeleteFiles(editor, nil, &yes), ErrForbidden))
+	assert.Nil(t, requireRoleToDeleteFiles(admin, &yes, &yes))
+}
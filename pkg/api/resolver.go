@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"sort"
 	"strconv"
 
@@ -11,6 +13,7 @@ import (
 )
 
 type hookExecutor interface {
+	ExecutePreHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, inputFields []string) (map[string]interface{}, error)
 	ExecutePostHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, inputFields []string)
 }
 
@@ -34,12 +37,21 @@ func (r *Resolver) Query() models.QueryResolver {
 func (r *Resolver) Scene() models.SceneResolver {
 	return &sceneResolver{r}
 }
+func (r *Resolver) SceneFile() models.SceneFileResolver {
+	return &sceneFileResolver{r}
+}
+func (r *Resolver) JobHistory() models.JobHistoryResolver {
+	return &jobHistoryResolver{r}
+}
 func (r *Resolver) Image() models.ImageResolver {
 	return &imageResolver{r}
 }
 func (r *Resolver) SceneMarker() models.SceneMarkerResolver {
 	return &sceneMarkerResolver{r}
 }
+func (r *Resolver) GalleryChapter() models.GalleryChapterResolver {
+	return &galleryChapterResolver{r}
+}
 func (r *Resolver) Studio() models.StudioResolver {
 	return &studioResolver{r}
 }
@@ -52,6 +64,12 @@ func (r *Resolver) Subscription() models.SubscriptionResolver {
 func (r *Resolver) Tag() models.TagResolver {
 	return &tagResolver{r}
 }
+func (r *Resolver) User() models.UserResolver {
+	return &userResolver{r}
+}
+func (r *Resolver) SearchResult() models.SearchResultResolver {
+	return &searchResultResolver{r}
+}
 
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -60,11 +78,16 @@ type subscriptionResolver struct{ *Resolver }
 type galleryResolver struct{ *Resolver }
 type performerResolver struct{ *Resolver }
 type sceneResolver struct{ *Resolver }
+type sceneFileResolver struct{ *Resolver }
 type sceneMarkerResolver struct{ *Resolver }
+type galleryChapterResolver struct{ *Resolver }
 type imageResolver struct{ *Resolver }
 type studioResolver struct{ *Resolver }
 type movieResolver struct{ *Resolver }
 type tagResolver struct{ *Resolver }
+type jobHistoryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
+type searchResultResolver struct{ *Resolver }
 
 func (r *Resolver) withTxn(ctx context.Context, fn func(r models.Repository) error) error {
 	return r.txnManager.WithTxn(ctx, fn)
@@ -74,6 +97,45 @@ func (r *Resolver) withReadTxn(ctx context.Context, fn func(r models.ReaderRepos
 	return r.txnManager.WithReadTxn(ctx, fn)
 }
 
+// executePreHooks executes the pre hooks of hookType against the mutation
+// input, which must be a pointer to the input object. Input fields changed by
+// the hooks are applied to input and added to inputMap, if not nil. Returns
+// an error if a hook vetoed the mutation.
+func (r *Resolver) executePreHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, inputMap map[string]interface{}) error {
+	translator := changesetTranslator{
+		inputMap: inputMap,
+	}
+
+	changes, err := r.hookExecutor.ExecutePreHooks(ctx, id, hookType, input, translator.getFields())
+	if err != nil {
+		return err
+	}
+
+	// hooks may not redirect the mutation to a different object
+	delete(changes, "id")
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("error applying %s hook changes: %w", hookType.String(), err)
+	}
+
+	if err := json.Unmarshal(data, input); err != nil {
+		return fmt.Errorf("error applying %s hook changes: %w", hookType.String(), err)
+	}
+
+	if inputMap != nil {
+		for k, v := range changes {
+			inputMap[k] = v
+		}
+	}
+
+	return nil
+}
+
 func (r *queryResolver) MarkerWall(ctx context.Context, q *string) (ret []*models.SceneMarker, err error) {
 	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
 		ret, err = repo.SceneMarker().Wall(q)
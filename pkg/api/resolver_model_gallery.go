@@ -46,6 +46,17 @@ func (r *galleryResolver) Cover(ctx context.Context, obj *models.Gallery) (ret *
 			ret = imgs[0]
 		}
 
+		// the chosen cover is ignored if the image was removed from the
+		// gallery
+		if obj.CoverImageID.Valid {
+			for _, img := range imgs {
+				if img.ID == int(obj.CoverImageID.Int64) {
+					ret = img
+					return nil
+				}
+			}
+		}
+
 		for _, img := range imgs {
 			if image.IsCover(img) {
 				ret = img
@@ -61,6 +72,17 @@ func (r *galleryResolver) Cover(ctx context.Context, obj *models.Gallery) (ret *
 	return ret, nil
 }
 
+func (r *galleryResolver) Chapters(ctx context.Context, obj *models.Gallery) (ret []*models.GalleryChapter, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.GalleryChapter().FindByGalleryID(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *galleryResolver) Date(ctx context.Context, obj *models.Gallery) (*string, error) {
 	if obj.Date.Valid {
 		result := utils.GetYMDFromDatabaseDate(obj.Date.String)
@@ -7,6 +7,8 @@ import (
 	"github.com/stashapp/stash/pkg/api/urlbuilders"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 func (r *imageResolver) Title(ctx context.Context, obj *models.Image) (*string, error) {
@@ -14,14 +16,56 @@ func (r *imageResolver) Title(ctx context.Context, obj *models.Image) (*string,
 	return &ret, nil
 }
 
+func (r *imageResolver) Date(ctx context.Context, obj *models.Image) (*string, error) {
+	if obj.Date.Valid {
+		result := utils.GetYMDFromDatabaseDate(obj.Date.String)
+		return &result, nil
+	}
+	return nil, nil
+}
+
+func (r *imageResolver) getUserData(ctx context.Context, obj *models.Image) (ret *models.UserData, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Image().GetUserData(obj.ID, session.GetUserDataID(ctx))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *imageResolver) Rating(ctx context.Context, obj *models.Image) (*int, error) {
-	if obj.Rating.Valid {
-		rating := int(obj.Rating.Int64)
+	userData, err := r.getUserData(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if userData.Rating.Valid {
+		rating := int(userData.Rating.Int64)
 		return &rating, nil
 	}
 	return nil, nil
 }
 
+func (r *imageResolver) OCounter(ctx context.Context, obj *models.Image) (*int, error) {
+	userData, err := r.getUserData(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userData.OCounter, nil
+}
+
+func (r *imageResolver) Watched(ctx context.Context, obj *models.Image) (bool, error) {
+	userData, err := r.getUserData(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+
+	return userData.Watched, nil
+}
+
 func (r *imageResolver) File(ctx context.Context, obj *models.Image) (*models.ImageFileType, error) {
 	width := int(obj.Width.Int64)
 	height := int(obj.Height.Int64)
@@ -33,6 +77,23 @@ func (r *imageResolver) File(ctx context.Context, obj *models.Image) (*models.Im
 	}, nil
 }
 
+func (r *imageResolver) VisualFile(ctx context.Context, obj *models.Image) (*models.ImageVisualFile, error) {
+	ret := &models.ImageVisualFile{
+		Type:     models.ImageVisualFileTypeImage,
+		MimeType: image.GetMimeType(obj),
+	}
+
+	if obj.IsVideo {
+		ret.Type = models.ImageVisualFileTypeVideo
+	}
+
+	if obj.IsAnimated {
+		ret.Duration = &obj.Duration.Float64
+	}
+
+	return ret, nil
+}
+
 func (r *imageResolver) Paths(ctx context.Context, obj *models.Image) (*models.ImagePathsType, error) {
 	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
 	builder := urlbuilders.NewImageURLBuilder(baseURL, obj)
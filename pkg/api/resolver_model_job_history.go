@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *jobHistoryResolver) Status(ctx context.Context, obj *models.JobHistory) (models.JobStatus, error) {
+	return models.JobStatus(obj.Status), nil
+}
+
+func (r *jobHistoryResolver) Error(ctx context.Context, obj *models.JobHistory) (*string, error) {
+	if obj.Error.Valid {
+		return &obj.Error.String, nil
+	}
+	return nil, nil
+}
+
+func (r *jobHistoryResolver) AddTime(ctx context.Context, obj *models.JobHistory) (*time.Time, error) {
+	return &obj.AddTime.Timestamp, nil
+}
+
+func (r *jobHistoryResolver) StartTime(ctx context.Context, obj *models.JobHistory) (*time.Time, error) {
+	if obj.StartTime.Valid {
+		return &obj.StartTime.Timestamp, nil
+	}
+	return nil, nil
+}
+
+func (r *jobHistoryResolver) EndTime(ctx context.Context, obj *models.JobHistory) (*time.Time, error) {
+	return &obj.EndTime.Timestamp, nil
+}
+
+func (r *jobHistoryResolver) Logs(ctx context.Context, obj *models.JobHistory) (ret []*models.LogEntry, err error) {
+	var logs []*models.JobHistoryLog
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		logs, err = repo.JobHistory().GetLogs(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	ret = make([]*models.LogEntry, len(logs))
+	for i, l := range logs {
+		ret[i] = &models.LogEntry{
+			Time:    l.Time.Timestamp,
+			Level:   getLogLevel(l.Level),
+			Message: l.Message,
+		}
+	}
+
+	return ret, nil
+}
@@ -5,8 +5,8 @@ import (
 	"time"
 
 	"github.com/stashapp/stash/pkg/api/urlbuilders"
-	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -53,34 +53,144 @@ func (r *sceneResolver) Date(ctx context.Context, obj *models.Scene) (*string, e
 	return nil, nil
 }
 
+func (r *sceneResolver) getUserData(ctx context.Context, obj *models.Scene) (ret *models.UserData, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Scene().GetUserData(obj.ID, session.GetUserDataID(ctx))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *sceneResolver) Rating(ctx context.Context, obj *models.Scene) (*int, error) {
-	if obj.Rating.Valid {
-		rating := int(obj.Rating.Int64)
+	userData, err := r.getUserData(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if userData.Rating.Valid {
+		rating := int(userData.Rating.Int64)
 		return &rating, nil
 	}
 	return nil, nil
 }
 
+func (r *sceneResolver) OCounter(ctx context.Context, obj *models.Scene) (*int, error) {
+	userData, err := r.getUserData(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userData.OCounter, nil
+}
+
+func (r *sceneResolver) Watched(ctx context.Context, obj *models.Scene) (bool, error) {
+	userData, err := r.getUserData(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+
+	return userData.Watched, nil
+}
+
+func (r *sceneResolver) getPlayHistory(ctx context.Context, obj *models.Scene) (ret *models.ScenePlayHistory, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Scene().GetPlayHistory(obj.ID, session.GetUserDataID(ctx))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) PlayCount(ctx context.Context, obj *models.Scene) (int, error) {
+	history, err := r.getPlayHistory(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return history.PlayCount, nil
+}
+
+func (r *sceneResolver) PlayDuration(ctx context.Context, obj *models.Scene) (float64, error) {
+	history, err := r.getPlayHistory(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return history.PlayDuration, nil
+}
+
+func (r *sceneResolver) LastPlayedAt(ctx context.Context, obj *models.Scene) (*time.Time, error) {
+	history, err := r.getPlayHistory(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if !history.LastPlayedAt.Valid {
+		return nil, nil
+	}
+
+	return &history.LastPlayedAt.Timestamp, nil
+}
+
+func (r *sceneResolver) ResumeTime(ctx context.Context, obj *models.Scene) (float64, error) {
+	history, err := r.getPlayHistory(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return history.ResumeTime, nil
+}
+
 func (r *sceneResolver) File(ctx context.Context, obj *models.Scene) (*models.SceneFileType, error) {
 	width := int(obj.Width.Int64)
 	height := int(obj.Height.Int64)
 	bitrate := int(obj.Bitrate.Int64)
+
+	var tracks []*models.SceneTrack
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		var err error
+		tracks, err = repo.Scene().GetTracks(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	videoTracks := []*models.SceneTrack{}
+	audioTracks := []*models.SceneTrack{}
+	for _, t := range tracks {
+		switch t.TrackType {
+		case models.TrackTypeVideo:
+			videoTracks = append(videoTracks, t)
+		case models.TrackTypeAudio:
+			audioTracks = append(audioTracks, t)
+		}
+	}
+
 	return &models.SceneFileType{
-		Size:       &obj.Size.String,
-		Duration:   handleFloat64(obj.Duration.Float64),
-		VideoCodec: &obj.VideoCodec.String,
-		AudioCodec: &obj.AudioCodec.String,
-		Width:      &width,
-		Height:     &height,
-		Framerate:  handleFloat64(obj.Framerate.Float64),
-		Bitrate:    &bitrate,
+		Size:        &obj.Size.String,
+		Duration:    handleFloat64(obj.Duration.Float64),
+		VideoCodec:  &obj.VideoCodec.String,
+		AudioCodec:  &obj.AudioCodec.String,
+		Width:       &width,
+		Height:      &height,
+		Framerate:   handleFloat64(obj.Framerate.Float64),
+		Bitrate:     &bitrate,
+		VideoTracks: videoTracks,
+		AudioTracks: audioTracks,
 	}, nil
 }
 
 func (r *sceneResolver) Paths(ctx context.Context, obj *models.Scene) (*models.ScenePathsType, error) {
 	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
 	builder := urlbuilders.NewSceneURLBuilder(baseURL, obj.ID)
-	builder.APIKey = config.GetInstance().GetAPIKey()
+	if u := session.GetCurrentUser(ctx); u != nil {
+		builder.APIKey = u.APIKey.String
+	}
 	screenshotPath := builder.GetScreenshotURL(obj.UpdatedAt.Timestamp)
 	previewPath := builder.GetStreamPreviewURL()
 	streamPath := builder.GetStreamURL()
@@ -89,6 +199,7 @@ func (r *sceneResolver) Paths(ctx context.Context, obj *models.Scene) (*models.S
 	spritePath := builder.GetSpriteURL()
 	chaptersVttPath := builder.GetChaptersVTTURL()
 	funscriptPath := builder.GetFunscriptURL()
+	captionPath := builder.GetCaptionURL()
 
 	return &models.ScenePathsType{
 		Screenshot:  &screenshotPath,
@@ -99,6 +210,7 @@ func (r *sceneResolver) Paths(ctx context.Context, obj *models.Scene) (*models.S
 		ChaptersVtt: &chaptersVttPath,
 		Sprite:      &spritePath,
 		Funscript:   &funscriptPath,
+		Caption:     &captionPath,
 	}, nil
 }
 
@@ -208,6 +320,17 @@ func (r *sceneResolver) StashIds(ctx context.Context, obj *models.Scene) (ret []
 	return ret, nil
 }
 
+func (r *sceneResolver) Captions(ctx context.Context, obj *models.Scene) (ret []*models.SceneCaption, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Scene().GetCaptions(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *sceneResolver) Phash(ctx context.Context, obj *models.Scene) (*string, error) {
 	if obj.Phash.Valid {
 		hexval := utils.PhashToString(obj.Phash.Int64)
@@ -227,3 +350,14 @@ func (r *sceneResolver) UpdatedAt(ctx context.Context, obj *models.Scene) (*time
 func (r *sceneResolver) FileModTime(ctx context.Context, obj *models.Scene) (*time.Time, error) {
 	return &obj.FileModTime.Timestamp, nil
 }
+
+func (r *sceneResolver) Files(ctx context.Context, obj *models.Scene) (ret []*models.SceneFile, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Scene().GetFiles(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
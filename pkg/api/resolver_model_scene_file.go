@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+func (r *sceneFileResolver) Checksum(ctx context.Context, obj *models.SceneFile) (*string, error) {
+	if obj.Checksum.Valid {
+		return &obj.Checksum.String, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) Oshash(ctx context.Context, obj *models.SceneFile) (*string, error) {
+	if obj.OSHash.Valid {
+		return &obj.OSHash.String, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) Phash(ctx context.Context, obj *models.SceneFile) (*string, error) {
+	if obj.Phash.Valid {
+		hexval := utils.PhashToString(obj.Phash.Int64)
+		return &hexval, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) Size(ctx context.Context, obj *models.SceneFile) (*string, error) {
+	if obj.Size.Valid {
+		return &obj.Size.String, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) Duration(ctx context.Context, obj *models.SceneFile) (*float64, error) {
+	if obj.Duration.Valid {
+		return &obj.Duration.Float64, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) VideoCodec(ctx context.Context, obj *models.SceneFile) (*string, error) {
+	if obj.VideoCodec.Valid {
+		return &obj.VideoCodec.String, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) AudioCodec(ctx context.Context, obj *models.SceneFile) (*string, error) {
+	if obj.AudioCodec.Valid {
+		return &obj.AudioCodec.String, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) Format(ctx context.Context, obj *models.SceneFile) (*string, error) {
+	if obj.Format.Valid {
+		return &obj.Format.String, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) Width(ctx context.Context, obj *models.SceneFile) (*int, error) {
+	if obj.Width.Valid {
+		width := int(obj.Width.Int64)
+		return &width, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) Height(ctx context.Context, obj *models.SceneFile) (*int, error) {
+	if obj.Height.Valid {
+		height := int(obj.Height.Int64)
+		return &height, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) Framerate(ctx context.Context, obj *models.SceneFile) (*float64, error) {
+	if obj.Framerate.Valid {
+		return &obj.Framerate.Float64, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) Bitrate(ctx context.Context, obj *models.SceneFile) (*int, error) {
+	if obj.Bitrate.Valid {
+		bitrate := int(obj.Bitrate.Int64)
+		return &bitrate, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneFileResolver) FileModTime(ctx context.Context, obj *models.SceneFile) (*time.Time, error) {
+	if obj.FileModTime.Valid {
+		return &obj.FileModTime.Timestamp, nil
+	}
+	return nil, nil
+}
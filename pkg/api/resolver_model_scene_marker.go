@@ -46,6 +46,13 @@ func (r *sceneMarkerResolver) Tags(ctx context.Context, obj *models.SceneMarker)
 	return ret, err
 }
 
+func (r *sceneMarkerResolver) EndSeconds(ctx context.Context, obj *models.SceneMarker) (*float64, error) {
+	if obj.EndSeconds.Valid {
+		return &obj.EndSeconds.Float64, nil
+	}
+	return nil, nil
+}
+
 func (r *sceneMarkerResolver) Stream(ctx context.Context, obj *models.SceneMarker) (string, error) {
 	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
 	sceneID := int(obj.SceneID.Int64)
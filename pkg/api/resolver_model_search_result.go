@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *searchResultResolver) Scene(ctx context.Context, obj *models.SearchResult) (ret *models.Scene, err error) {
+	if obj.Type != models.SearchResultTypeScene {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Scene().Find(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *searchResultResolver) Performer(ctx context.Context, obj *models.SearchResult) (ret *models.Performer, err error) {
+	if obj.Type != models.SearchResultTypePerformer {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Performer().Find(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *searchResultResolver) Studio(ctx context.Context, obj *models.SearchResult) (ret *models.Studio, err error) {
+	if obj.Type != models.SearchResultTypeStudio {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Studio().Find(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *searchResultResolver) Tag(ctx context.Context, obj *models.SearchResult) (ret *models.Tag, err error) {
+	if obj.Type != models.SearchResultTypeTag {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Tag().Find(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
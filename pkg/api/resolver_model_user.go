@@ -0,0 +1,20 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *userResolver) HasAPIKey(ctx context.Context, obj *models.User) (bool, error) {
+	return obj.APIKey.Valid && obj.APIKey.String != "", nil
+}
+
+func (r *userResolver) CreatedAt(ctx context.Context, obj *models.User) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *userResolver) UpdatedAt(ctx context.Context, obj *models.User) (*time.Time, error) {
+	return &obj.UpdatedAt.Timestamp, nil
+}
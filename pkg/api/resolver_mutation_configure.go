@@ -2,14 +2,19 @@ package api
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/user"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -115,17 +120,31 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 		c.Set(config.MaxStreamingTranscodeSize, input.MaxStreamingTranscodeSize.String())
 	}
 
-	if input.Username != nil {
-		c.Set(config.Username, input.Username)
+	refreshTranscodeProfile := false
+	if input.TranscodeHardwareAcceleration != nil {
+		c.Set(config.TranscodeHardwareAcceleration, input.TranscodeHardwareAcceleration.String())
+		refreshTranscodeProfile = true
+	}
+	if input.TranscodeHardwareDevice != nil {
+		c.Set(config.TranscodeHardwareDevice, *input.TranscodeHardwareDevice)
+		refreshTranscodeProfile = true
+	}
+	if input.TranscodeCustomInputArgs != nil {
+		c.Set(config.TranscodeCustomInputArgs, input.TranscodeCustomInputArgs)
+		refreshTranscodeProfile = true
+	}
+	if input.TranscodeCustomVideoArgs != nil {
+		c.Set(config.TranscodeCustomVideoArgs, input.TranscodeCustomVideoArgs)
+		refreshTranscodeProfile = true
 	}
 
-	if input.Password != nil {
-		// bit of a hack - check if the passed in password is the same as the stored hash
-		// and only set if they are different
-		currentPWHash := c.GetPasswordHash()
+	if input.PreferredAudioLanguages != nil {
+		c.Set(config.PreferredAudioLanguages, input.PreferredAudioLanguages)
+	}
 
-		if *input.Password != currentPWHash {
-			c.SetPassword(*input.Password)
+	if input.Username != nil || input.Password != nil {
+		if err := r.configureCredentials(ctx, input.Username, input.Password); err != nil {
+			return makeConfigGeneralResult(), err
 		}
 	}
 
@@ -167,6 +186,65 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 
 	c.Set(config.CreateGalleriesFromFolders, input.CreateGalleriesFromFolders)
 
+	if input.CreateImageClipsFromVideos != nil {
+		c.Set(config.CreateImageClipsFromVideos, *input.CreateImageClipsFromVideos)
+	}
+
+	if input.WatchEnabled != nil {
+		c.Set(config.WatchEnabled, *input.WatchEnabled)
+	}
+
+	if input.WatchPollInterval != nil {
+		if *input.WatchPollInterval <= 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("watch poll interval must be greater than zero")
+		}
+		c.Set(config.WatchPollInterval, *input.WatchPollInterval)
+	}
+
+	if input.WatchGeneratePreviews != nil {
+		c.Set(config.WatchGeneratePreviews, *input.WatchGeneratePreviews)
+	}
+
+	if input.WatchGenerateSprites != nil {
+		c.Set(config.WatchGenerateSprites, *input.WatchGenerateSprites)
+	}
+
+	if input.WatchGeneratePhashes != nil {
+		c.Set(config.WatchGeneratePhashes, *input.WatchGeneratePhashes)
+	}
+
+	if input.JobHistoryRetentionDays != nil {
+		if *input.JobHistoryRetentionDays < 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("job history retention days must not be negative")
+		}
+		c.Set(config.JobHistoryRetentionDays, *input.JobHistoryRetentionDays)
+	}
+
+	if input.JobHistoryMaxEntries != nil {
+		if *input.JobHistoryMaxEntries < 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("job history max entries must not be negative")
+		}
+		c.Set(config.JobHistoryMaxEntries, *input.JobHistoryMaxEntries)
+	}
+
+	jobLimits := []struct {
+		key   string
+		value *int
+	}{
+		{config.ScanJobLimit, input.ScanJobLimit},
+		{config.GenerateJobLimit, input.GenerateJobLimit},
+		{config.MetadataJobLimit, input.MetadataJobLimit},
+		{config.ScrapingJobLimit, input.ScrapingJobLimit},
+	}
+	for _, l := range jobLimits {
+		if l.value != nil {
+			if *l.value < 1 {
+				return makeConfigGeneralResult(), fmt.Errorf("%s must be at least 1", l.key)
+			}
+			c.Set(l.key, *l.value)
+		}
+	}
+
 	if input.CustomPerformerImageLocation != nil {
 		c.Set(config.CustomPerformerImageLocation, *input.CustomPerformerImageLocation)
 		initialiseCustomImages()
@@ -199,11 +277,22 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 	}
 
 	manager.GetInstance().RefreshConfig()
+	manager.GetInstance().RefreshWatcher()
 	if refreshScraperCache {
 		manager.GetInstance().RefreshScraperCache()
 	}
+	if refreshTranscodeProfile {
+		manager.GetInstance().RefreshTranscodeProfile()
+	}
+
+	ret := makeConfigGeneralResult()
+	u, err := r.getCurrentUser(ctx)
+	if err != nil {
+		return ret, err
+	}
+	setConfigGeneralUserResult(ctx, ret, u)
 
-	return makeConfigGeneralResult(), nil
+	return ret, nil
 }
 
 func (r *mutationResolver) ConfigureInterface(ctx context.Context, input models.ConfigInterfaceInput) (*models.ConfigInterfaceResult, error) {
@@ -332,24 +421,149 @@ func (r *mutationResolver) ConfigureScraping(ctx context.Context, input models.C
 }
 
 func (r *mutationResolver) GenerateAPIKey(ctx context.Context, input models.GenerateAPIKeyInput) (string, error) {
-	c := config.GetInstance()
+	currentUserID := session.GetCurrentUserID(ctx)
+
+	var userID int
+	if input.UserID != nil {
+		var err error
+		userID, err = strconv.Atoi(*input.UserID)
+		if err != nil {
+			return "", err
+		}
+	} else if currentUserID != nil {
+		userID = *currentUserID
+	} else {
+		// no user to generate the key for
+		return "", nil
+	}
+
+	// only admins may generate keys for other users
+	if currentUserID == nil || userID != *currentUserID {
+		if err := requireRole(ctx, models.UserRoleAdmin); err != nil {
+			return "", err
+		}
+	}
 
 	var newAPIKey string
-	if input.Clear == nil || !*input.Clear {
-		username := c.GetUsername()
-		if username != "" {
-			var err error
-			newAPIKey, err = manager.GenerateAPIKey(username)
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.User()
+
+		u, err := qb.Find(userID)
+		if err != nil {
+			return err
+		}
+
+		if u == nil {
+			return fmt.Errorf("user with id %d not found", userID)
+		}
+
+		if input.Clear == nil || !*input.Clear {
+			newAPIKey, err = manager.GenerateAPIKey(u.Username)
 			if err != nil {
-				return "", err
+				return err
 			}
 		}
-	}
 
-	c.Set(config.ApiKey, newAPIKey)
-	if err := c.Write(); err != nil {
-		return newAPIKey, err
+		u.APIKey = sql.NullString{String: newAPIKey, Valid: newAPIKey != ""}
+		u.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		_, err = qb.Update(*u)
+		return err
+	}); err != nil {
+		return "", err
 	}
 
 	return newAPIKey, nil
 }
+
+// configureCredentials sets the username and password of the current user.
+// Empty and unchanged values are ignored. If authentication is not enabled,
+// then an admin user is created if both the username and password are set.
+func (r *mutationResolver) configureCredentials(ctx context.Context, username *string, password *string) error {
+	if username != nil && *username == "" {
+		username = nil
+	}
+	if password != nil && *password == "" {
+		password = nil
+	}
+
+	currentUserID := session.GetCurrentUserID(ctx)
+
+	return r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.User()
+
+		if currentUserID == nil {
+			if username == nil || password == nil {
+				return nil
+			}
+
+			count, err := qb.Count()
+			if err != nil {
+				return err
+			}
+
+			if count > 0 {
+				return errors.New("credentials can only be set when logged in as a user")
+			}
+
+			newUsername, err := user.ValidateUsername(*username)
+			if err != nil {
+				return err
+			}
+
+			passwordHash, err := user.HashPassword(*password)
+			if err != nil {
+				return err
+			}
+
+			currentTime := time.Now()
+			u, err := qb.Create(models.User{
+				Username:  newUsername,
+				Password:  passwordHash,
+				Role:      models.UserRoleAdmin,
+				CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+				UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+			})
+			if err != nil {
+				return err
+			}
+
+			// the existing ratings and o-counters belong to the new admin
+			return qb.TransferUserData(0, u.ID)
+		}
+
+		u, err := qb.Find(*currentUserID)
+		if err != nil {
+			return err
+		}
+
+		if u == nil {
+			return fmt.Errorf("user with id %d not found", *currentUserID)
+		}
+
+		if username != nil && *username != u.Username {
+			u.Username, err = user.ValidateUsername(*username)
+			if err != nil {
+				return err
+			}
+
+			if err := ensureUsernameUnique(u.ID, u.Username, qb); err != nil {
+				return err
+			}
+		}
+
+		// bit of a hack - check if the passed in password is the same as the stored hash
+		// and only set if they are different
+		if password != nil && *password != u.Password {
+			u.Password, err = user.HashPassword(*password)
+			if err != nil {
+				return err
+			}
+		}
+
+		u.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		_, err = qb.Update(*u)
+		return err
+	})
+}
@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
+	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
@@ -26,6 +28,10 @@ func (r *mutationResolver) getGallery(ctx context.Context, id int) (ret *models.
 }
 
 func (r *mutationResolver) GalleryCreate(ctx context.Context, input models.GalleryCreateInput) (*models.Gallery, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.GalleryCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
 	// name must be provided
 	if input.Title == "" {
 		return nil, errors.New("title must not be empty")
@@ -135,6 +141,15 @@ func (r *mutationResolver) GalleryUpdate(ctx context.Context, input models.Galle
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	galleryID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.executePreHooks(ctx, galleryID, plugin.GalleryUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	// Start the transaction and save the gallery
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		ret, err = r.galleryUpdate(input, translator, repo)
@@ -151,6 +166,17 @@ func (r *mutationResolver) GalleryUpdate(ctx context.Context, input models.Galle
 func (r *mutationResolver) GalleriesUpdate(ctx context.Context, input []*models.GalleryUpdateInput) (ret []*models.Gallery, err error) {
 	inputMaps := getUpdateInputMaps(ctx)
 
+	for i, gallery := range input {
+		galleryID, err := strconv.Atoi(gallery.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.executePreHooks(ctx, galleryID, plugin.GalleryUpdatePre, gallery, inputMaps[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	// Start the transaction and save the gallery
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		for i, gallery := range input {
@@ -268,13 +294,18 @@ func (r *mutationResolver) galleryUpdate(input models.GalleryUpdateInput, transl
 }
 
 func (r *mutationResolver) BulkGalleryUpdate(ctx context.Context, input models.BulkGalleryUpdateInput) ([]*models.Gallery, error) {
-	// Populate gallery from the input
-	updatedTime := time.Now()
-
 	translator := changesetTranslator{
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	// bulk updates are not specific to a single gallery
+	if err := r.executePreHooks(ctx, 0, plugin.GalleryUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
+	// Populate gallery from the input
+	updatedTime := time.Now()
+
 	updatedGallery := models.GalleryPartial{
 		UpdatedAt: &models.SQLiteTimestamp{Timestamp: updatedTime},
 	}
@@ -389,6 +420,10 @@ func adjustGallerySceneIDs(qb models.GalleryReader, galleryID int, ids models.Bu
 }
 
 func (r *mutationResolver) GalleryDestroy(ctx context.Context, input models.GalleryDestroyInput) (bool, error) {
+	if err := requireRoleToDeleteFiles(ctx, input.DeleteFile, input.DeleteGenerated); err != nil {
+		return false, err
+	}
+
 	galleryIDs, err := utils.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return false, err
@@ -574,3 +609,287 @@ func (r *mutationResolver) RemoveGalleryImages(ctx context.Context, input models
 
 	return true, nil
 }
+
+func (r *mutationResolver) InsertGalleryImages(ctx context.Context, input models.GalleryInsertInput) (bool, error) {
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	galleryID, err := strconv.Atoi(input.GalleryID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.executePreHooks(ctx, galleryID, plugin.GalleryUpdatePre, &input, translator.inputMap); err != nil {
+		return false, err
+	}
+
+	imageIDs, err := utils.StringSliceToIntSlice(input.ImageIds)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Gallery()
+		g, err := qb.Find(galleryID)
+		if err != nil {
+			return err
+		}
+
+		if g == nil {
+			return errors.New("gallery not found")
+		}
+
+		// images in zip galleries can be moved, but not added
+		if g.Zip {
+			existing, err := qb.GetImageIDs(galleryID)
+			if err != nil {
+				return err
+			}
+
+			if len(utils.IntExclude(imageIDs, existing)) > 0 {
+				return errors.New("cannot modify zip gallery images")
+			}
+		}
+
+		position := math.MaxInt32
+		if input.Position != nil {
+			position = *input.Position
+		}
+
+		return gallery.InsertImages(qb, repo.Image(), galleryID, imageIDs, position)
+	}); err != nil {
+		return false, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, galleryID, plugin.GalleryUpdatePost, input, translator.getFields())
+	return true, nil
+}
+
+func (r *mutationResolver) ReorderGalleryImages(ctx context.Context, input models.GalleryReorderInput) (bool, error) {
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	galleryID, err := strconv.Atoi(input.GalleryID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.executePreHooks(ctx, galleryID, plugin.GalleryUpdatePre, &input, translator.inputMap); err != nil {
+		return false, err
+	}
+
+	imageIDs, err := utils.StringSliceToIntSlice(input.ImageIds)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Gallery()
+		g, err := qb.Find(galleryID)
+		if err != nil {
+			return err
+		}
+
+		if g == nil {
+			return errors.New("gallery not found")
+		}
+
+		return gallery.SetImageOrder(qb, repo.Image(), galleryID, imageIDs)
+	}); err != nil {
+		return false, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, galleryID, plugin.GalleryUpdatePost, input, translator.getFields())
+	return true, nil
+}
+
+func (r *mutationResolver) SetGalleryCover(ctx context.Context, input models.GallerySetCoverInput) (*models.Gallery, error) {
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	galleryID, err := strconv.Atoi(input.GalleryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.executePreHooks(ctx, galleryID, plugin.GalleryUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
+	var imageID *int
+	if input.CoverImageID != nil {
+		id, err := strconv.Atoi(*input.CoverImageID)
+		if err != nil {
+			return nil, err
+		}
+		imageID = &id
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Gallery()
+		g, err := qb.Find(galleryID)
+		if err != nil {
+			return err
+		}
+
+		if g == nil {
+			return errors.New("gallery not found")
+		}
+
+		_, err = gallery.SetCover(qb, galleryID, imageID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, galleryID, plugin.GalleryUpdatePost, input, translator.getFields())
+	return r.getGallery(ctx, galleryID)
+}
+
+func (r *mutationResolver) getGalleryChapter(ctx context.Context, id int) (ret *models.GalleryChapter, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.GalleryChapter().Find(id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// validateGalleryChapter checks that the gallery of the chapter exists and
+// that the image index of the chapter is valid.
+func validateGalleryChapter(repo models.Repository, chapter models.GalleryChapter) error {
+	if chapter.ImageIndex < 1 {
+		return fmt.Errorf("image index (%d) must be greater than zero", chapter.ImageIndex)
+	}
+
+	g, err := repo.Gallery().Find(int(chapter.GalleryID.Int64))
+	if err != nil {
+		return err
+	}
+
+	if g == nil {
+		return errors.New("gallery not found")
+	}
+
+	return nil
+}
+
+func (r *mutationResolver) GalleryChapterCreate(ctx context.Context, input models.GalleryChapterCreateInput) (*models.GalleryChapter, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.GalleryChapterCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
+	galleryID, err := strconv.Atoi(input.GalleryID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentTime := time.Now()
+	newChapter := models.GalleryChapter{
+		Title:      input.Title,
+		ImageIndex: input.ImageIndex,
+		GalleryID:  sql.NullInt64{Int64: int64(galleryID), Valid: true},
+		CreatedAt:  models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt:  models.SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	var ret *models.GalleryChapter
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		if err := validateGalleryChapter(repo, newChapter); err != nil {
+			return err
+		}
+
+		ret, err = repo.GalleryChapter().Create(newChapter)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, ret.ID, plugin.GalleryChapterCreatePost, input, nil)
+	return r.getGalleryChapter(ctx, ret.ID)
+}
+
+func (r *mutationResolver) GalleryChapterUpdate(ctx context.Context, input models.GalleryChapterUpdateInput) (*models.GalleryChapter, error) {
+	chapterID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	if err := r.executePreHooks(ctx, chapterID, plugin.GalleryChapterUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
+	galleryID, err := strconv.Atoi(input.GalleryID)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedChapter := models.GalleryChapter{
+		ID:         chapterID,
+		Title:      input.Title,
+		ImageIndex: input.ImageIndex,
+		GalleryID:  sql.NullInt64{Int64: int64(galleryID), Valid: true},
+		UpdatedAt:  models.SQLiteTimestamp{Timestamp: time.Now()},
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.GalleryChapter()
+		existing, err := qb.Find(chapterID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return fmt.Errorf("gallery chapter with id %d not found", chapterID)
+		}
+
+		if err := validateGalleryChapter(repo, updatedChapter); err != nil {
+			return err
+		}
+
+		updatedChapter.CreatedAt = existing.CreatedAt
+		_, err = qb.Update(updatedChapter)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, chapterID, plugin.GalleryChapterUpdatePost, input, translator.getFields())
+	return r.getGalleryChapter(ctx, chapterID)
+}
+
+func (r *mutationResolver) GalleryChapterDestroy(ctx context.Context, id string) (bool, error) {
+	chapterID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.GalleryChapter()
+		chapter, err := qb.Find(chapterID)
+		if err != nil {
+			return err
+		}
+
+		if chapter == nil {
+			return fmt.Errorf("gallery chapter with id %d not found", chapterID)
+		}
+
+		return qb.Destroy(chapterID)
+	}); err != nil {
+		return false, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, chapterID, plugin.GalleryChapterDestroyPost, id, nil)
+
+	return true, nil
+}
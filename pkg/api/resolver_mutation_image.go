@@ -9,6 +9,7 @@ import (
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/session"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -28,9 +29,18 @@ func (r *mutationResolver) ImageUpdate(ctx context.Context, input models.ImageUp
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	imageID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.executePreHooks(ctx, imageID, plugin.ImageUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	// Start the transaction and save the image
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
-		ret, err = r.imageUpdate(input, translator, repo)
+		ret, err = r.imageUpdate(ctx, input, translator, repo)
 		return err
 	}); err != nil {
 		return nil, err
@@ -44,6 +54,17 @@ func (r *mutationResolver) ImageUpdate(ctx context.Context, input models.ImageUp
 func (r *mutationResolver) ImagesUpdate(ctx context.Context, input []*models.ImageUpdateInput) (ret []*models.Image, err error) {
 	inputMaps := getUpdateInputMaps(ctx)
 
+	for i, image := range input {
+		imageID, err := strconv.Atoi(image.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.executePreHooks(ctx, imageID, plugin.ImageUpdatePre, image, inputMaps[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	// Start the transaction and save the image
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		for i, image := range input {
@@ -51,7 +72,7 @@ func (r *mutationResolver) ImagesUpdate(ctx context.Context, input []*models.Ima
 				inputMap: inputMaps[i],
 			}
 
-			thisImage, err := r.imageUpdate(*image, translator, repo)
+			thisImage, err := r.imageUpdate(ctx, *image, translator, repo)
 			if err != nil {
 				return err
 			}
@@ -83,7 +104,7 @@ func (r *mutationResolver) ImagesUpdate(ctx context.Context, input []*models.Ima
 	return newRet, nil
 }
 
-func (r *mutationResolver) imageUpdate(input models.ImageUpdateInput, translator changesetTranslator, repo models.Repository) (*models.Image, error) {
+func (r *mutationResolver) imageUpdate(ctx context.Context, input models.ImageUpdateInput, translator changesetTranslator, repo models.Repository) (*models.Image, error) {
 	// Populate image from the input
 	imageID, err := strconv.Atoi(input.ID)
 	if err != nil {
@@ -97,16 +118,25 @@ func (r *mutationResolver) imageUpdate(input models.ImageUpdateInput, translator
 	}
 
 	updatedImage.Title = translator.nullString(input.Title, "title")
-	updatedImage.Rating = translator.nullInt64(input.Rating, "rating")
+	updatedImage.Date = translator.sqliteDate(input.Date, "date")
 	updatedImage.StudioID = translator.nullInt64FromString(input.StudioID, "studio_id")
 	updatedImage.Organized = input.Organized
 
+	updatedUserData := models.UserDataPartial{
+		Rating:  translator.nullInt64(input.Rating, "rating"),
+		Watched: input.Watched,
+	}
+
 	qb := repo.Image()
 	image, err := qb.Update(updatedImage)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := qb.UpdateUserData(imageID, session.GetUserDataID(ctx), updatedUserData); err != nil {
+		return nil, err
+	}
+
 	if translator.hasField("gallery_ids") {
 		if err := r.updateImageGalleries(qb, imageID, input.GalleryIds); err != nil {
 			return nil, err
@@ -155,6 +185,15 @@ func (r *mutationResolver) updateImageTags(qb models.ImageReaderWriter, imageID
 }
 
 func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input models.BulkImageUpdateInput) (ret []*models.Image, err error) {
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	// bulk updates are not specific to a single image
+	if err := r.executePreHooks(ctx, 0, plugin.ImageUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	imageIDs, err := utils.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return nil, err
@@ -167,15 +206,17 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input models.Bul
 		UpdatedAt: &models.SQLiteTimestamp{Timestamp: updatedTime},
 	}
 
-	translator := changesetTranslator{
-		inputMap: getUpdateInputMap(ctx),
-	}
-
 	updatedImage.Title = translator.nullString(input.Title, "title")
-	updatedImage.Rating = translator.nullInt64(input.Rating, "rating")
+	updatedImage.Date = translator.sqliteDate(input.Date, "date")
 	updatedImage.StudioID = translator.nullInt64FromString(input.StudioID, "studio_id")
 	updatedImage.Organized = input.Organized
 
+	updatedUserData := models.UserDataPartial{
+		Rating:  translator.nullInt64(input.Rating, "rating"),
+		Watched: input.Watched,
+	}
+	userID := session.GetUserDataID(ctx)
+
 	// Start the transaction and save the image marker
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Image()
@@ -188,6 +229,10 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input models.Bul
 				return err
 			}
 
+			if err := qb.UpdateUserData(imageID, userID, updatedUserData); err != nil {
+				return err
+			}
+
 			ret = append(ret, image)
 
 			// Save the galleries
@@ -276,6 +321,10 @@ func adjustImageTagIDs(qb models.ImageReader, imageID int, ids models.BulkUpdate
 }
 
 func (r *mutationResolver) ImageDestroy(ctx context.Context, input models.ImageDestroyInput) (ret bool, err error) {
+	if err := requireRoleToDeleteFiles(ctx, input.DeleteFile, input.DeleteGenerated); err != nil {
+		return false, err
+	}
+
 	imageID, err := strconv.Atoi(input.ID)
 	if err != nil {
 		return false, err
@@ -318,6 +367,10 @@ func (r *mutationResolver) ImageDestroy(ctx context.Context, input models.ImageD
 }
 
 func (r *mutationResolver) ImagesDestroy(ctx context.Context, input models.ImagesDestroyInput) (ret bool, err error) {
+	if err := requireRoleToDeleteFiles(ctx, input.DeleteFile, input.DeleteGenerated); err != nil {
+		return false, err
+	}
+
 	imageIDs, err := utils.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return false, err
@@ -369,6 +422,30 @@ func (r *mutationResolver) ImagesDestroy(ctx context.Context, input models.Image
 	return true, nil
 }
 
+func (r *mutationResolver) ImageUpdateUserData(ctx context.Context, input models.ImageUserDataUpdateInput) (*models.Image, error) {
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	imageID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedUserData := models.UserDataPartial{
+		Rating:  translator.nullInt64(input.Rating, "rating"),
+		Watched: input.Watched,
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		return repo.Image().UpdateUserData(imageID, session.GetUserDataID(ctx), updatedUserData)
+	}); err != nil {
+		return nil, err
+	}
+
+	return r.getImage(ctx, imageID)
+}
+
 func (r *mutationResolver) ImageIncrementO(ctx context.Context, id string) (ret int, err error) {
 	imageID, err := strconv.Atoi(id)
 	if err != nil {
@@ -378,7 +455,7 @@ func (r *mutationResolver) ImageIncrementO(ctx context.Context, id string) (ret
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Image()
 
-		ret, err = qb.IncrementOCounter(imageID)
+		ret, err = qb.IncrementOCounter(imageID, session.GetUserDataID(ctx))
 		return err
 	}); err != nil {
 		return 0, err
@@ -396,7 +473,7 @@ func (r *mutationResolver) ImageDecrementO(ctx context.Context, id string) (ret
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Image()
 
-		ret, err = qb.DecrementOCounter(imageID)
+		ret, err = qb.DecrementOCounter(imageID, session.GetUserDataID(ctx))
 		return err
 	}); err != nil {
 		return 0, err
@@ -414,7 +491,7 @@ func (r *mutationResolver) ImageResetO(ctx context.Context, id string) (ret int,
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Image()
 
-		ret, err = qb.ResetOCounter(imageID)
+		ret, err = qb.ResetOCounter(imageID, session.GetUserDataID(ctx))
 		return err
 	}); err != nil {
 		return 0, err
@@ -21,3 +21,29 @@ func (r *mutationResolver) StopAllJobs(ctx context.Context) (bool, error) {
 	manager.GetInstance().JobManager.CancelAll()
 	return true, nil
 }
+
+func (r *mutationResolver) PauseJob(ctx context.Context, jobID string) (bool, error) {
+	idInt, err := strconv.Atoi(jobID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := manager.GetInstance().JobManager.PauseJob(idInt); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) ResumeJob(ctx context.Context, jobID string) (bool, error) {
+	idInt, err := strconv.Atoi(jobID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := manager.GetInstance().JobManager.ResumeJob(idInt); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
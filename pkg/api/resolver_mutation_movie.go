@@ -23,6 +23,10 @@ func (r *mutationResolver) getMovie(ctx context.Context, id int) (ret *models.Mo
 }
 
 func (r *mutationResolver) MovieCreate(ctx context.Context, input models.MovieCreateInput) (*models.Movie, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.MovieCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
 	// generate checksum from movie name rather than image
 	checksum := utils.MD5FromString(input.Name)
 
@@ -136,6 +140,10 @@ func (r *mutationResolver) MovieUpdate(ctx context.Context, input models.MovieUp
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	if err := r.executePreHooks(ctx, movieID, plugin.MovieUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	var frontimageData []byte
 	frontImageIncluded := translator.hasField("front_image")
 	if input.FrontImage != nil {
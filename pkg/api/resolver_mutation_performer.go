@@ -25,6 +25,10 @@ func (r *mutationResolver) getPerformer(ctx context.Context, id int) (ret *model
 }
 
 func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.PerformerCreateInput) (*models.Performer, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.PerformerCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
 	// generate checksum from performer name rather than image
 	checksum := utils.MD5FromString(input.Name)
 
@@ -174,6 +178,10 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	if err := r.executePreHooks(ctx, performerID, plugin.PerformerUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	var imageData []byte
 	var err error
 	imageIncluded := translator.hasField("image")
@@ -293,6 +301,15 @@ func (r *mutationResolver) updatePerformerTags(qb models.PerformerReaderWriter,
 }
 
 func (r *mutationResolver) BulkPerformerUpdate(ctx context.Context, input models.BulkPerformerUpdateInput) ([]*models.Performer, error) {
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	// bulk updates are not specific to a single performer
+	if err := r.executePreHooks(ctx, 0, plugin.PerformerUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	performerIDs, err := utils.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return nil, err
@@ -301,10 +318,6 @@ func (r *mutationResolver) BulkPerformerUpdate(ctx context.Context, input models
 	// Populate performer from the input
 	updatedTime := time.Now()
 
-	translator := changesetTranslator{
-		inputMap: getUpdateInputMap(ctx),
-	}
-
 	updatedPerformer := models.PerformerPartial{
 		UpdatedAt: &models.SQLiteTimestamp{Timestamp: updatedTime},
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/session"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -30,9 +31,18 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	sceneID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.executePreHooks(ctx, sceneID, plugin.SceneUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	// Start the transaction and save the scene
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
-		ret, err = r.sceneUpdate(input, translator, repo)
+		ret, err = r.sceneUpdate(ctx, input, translator, repo)
 		return err
 	}); err != nil {
 		return nil, err
@@ -42,9 +52,73 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 	return r.getScene(ctx, ret.ID)
 }
 
+func (r *mutationResolver) SceneSetPrimaryFile(ctx context.Context, input models.SceneSetPrimaryFileInput) (*models.Scene, error) {
+	sceneID, err := strconv.Atoi(input.SceneID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileID, err := strconv.Atoi(input.FileID)
+	if err != nil {
+		return nil, err
+	}
+
+	var original, updated *models.Scene
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Scene()
+
+		original, err = qb.Find(sceneID)
+		if err != nil {
+			return err
+		}
+		if original == nil {
+			return fmt.Errorf("scene with id %d not found", sceneID)
+		}
+
+		file, err := qb.FindFile(fileID)
+		if err != nil {
+			return err
+		}
+		if file == nil || file.SceneID != sceneID {
+			return fmt.Errorf("file with id %d does not belong to scene %d", fileID, sceneID)
+		}
+
+		if err := qb.SetPrimaryFile(sceneID, fileID); err != nil {
+			return err
+		}
+
+		updated, err = qb.Find(sceneID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	// generated files are named after the primary file's hash
+	fileNamingAlgo := config.GetInstance().GetVideoFileNamingAlgorithm()
+	oldHash := original.GetHash(fileNamingAlgo)
+	newHash := updated.GetHash(fileNamingAlgo)
+	if oldHash != "" && newHash != "" && oldHash != newHash {
+		manager.MigrateHash(oldHash, newHash)
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, sceneID, plugin.SceneUpdatePost, input, nil)
+	return r.getScene(ctx, sceneID)
+}
+
 func (r *mutationResolver) ScenesUpdate(ctx context.Context, input []*models.SceneUpdateInput) (ret []*models.Scene, err error) {
 	inputMaps := getUpdateInputMaps(ctx)
 
+	for i, scene := range input {
+		sceneID, err := strconv.Atoi(scene.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.executePreHooks(ctx, sceneID, plugin.SceneUpdatePre, scene, inputMaps[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	// Start the transaction and save the scene
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		for i, scene := range input {
@@ -52,7 +126,7 @@ func (r *mutationResolver) ScenesUpdate(ctx context.Context, input []*models.Sce
 				inputMap: inputMaps[i],
 			}
 
-			thisScene, err := r.sceneUpdate(*scene, translator, repo)
+			thisScene, err := r.sceneUpdate(ctx, *scene, translator, repo)
 			ret = append(ret, thisScene)
 
 			if err != nil {
@@ -85,7 +159,7 @@ func (r *mutationResolver) ScenesUpdate(ctx context.Context, input []*models.Sce
 	return newRet, nil
 }
 
-func (r *mutationResolver) sceneUpdate(input models.SceneUpdateInput, translator changesetTranslator, repo models.Repository) (*models.Scene, error) {
+func (r *mutationResolver) sceneUpdate(ctx context.Context, input models.SceneUpdateInput, translator changesetTranslator, repo models.Repository) (*models.Scene, error) {
 	// Populate scene from the input
 	sceneID, err := strconv.Atoi(input.ID)
 	if err != nil {
@@ -104,10 +178,14 @@ func (r *mutationResolver) sceneUpdate(input models.SceneUpdateInput, translator
 	updatedScene.Details = translator.nullString(input.Details, "details")
 	updatedScene.URL = translator.nullString(input.URL, "url")
 	updatedScene.Date = translator.sqliteDate(input.Date, "date")
-	updatedScene.Rating = translator.nullInt64(input.Rating, "rating")
 	updatedScene.StudioID = translator.nullInt64FromString(input.StudioID, "studio_id")
 	updatedScene.Organized = input.Organized
 
+	updatedUserData := models.UserDataPartial{
+		Rating:  translator.nullInt64(input.Rating, "rating"),
+		Watched: input.Watched,
+	}
+
 	if input.CoverImage != nil && *input.CoverImage != "" {
 		var err error
 		coverImageData, err = utils.ProcessImageInput(*input.CoverImage)
@@ -124,6 +202,10 @@ func (r *mutationResolver) sceneUpdate(input models.SceneUpdateInput, translator
 		return nil, err
 	}
 
+	if err := qb.UpdateUserData(sceneID, session.GetUserDataID(ctx), updatedUserData); err != nil {
+		return nil, err
+	}
+
 	// update cover table
 	if len(coverImageData) > 0 {
 		if err := qb.UpdateCover(sceneID, coverImageData); err != nil {
@@ -229,6 +311,15 @@ func (r *mutationResolver) updateSceneGalleries(qb models.SceneReaderWriter, sce
 }
 
 func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input models.BulkSceneUpdateInput) ([]*models.Scene, error) {
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	// bulk updates are not specific to a single scene
+	if err := r.executePreHooks(ctx, 0, plugin.SceneUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	sceneIDs, err := utils.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return nil, err
@@ -237,10 +328,6 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input models.Bul
 	// Populate scene from the input
 	updatedTime := time.Now()
 
-	translator := changesetTranslator{
-		inputMap: getUpdateInputMap(ctx),
-	}
-
 	updatedScene := models.ScenePartial{
 		UpdatedAt: &models.SQLiteTimestamp{Timestamp: updatedTime},
 	}
@@ -249,10 +336,15 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input models.Bul
 	updatedScene.Details = translator.nullString(input.Details, "details")
 	updatedScene.URL = translator.nullString(input.URL, "url")
 	updatedScene.Date = translator.sqliteDate(input.Date, "date")
-	updatedScene.Rating = translator.nullInt64(input.Rating, "rating")
 	updatedScene.StudioID = translator.nullInt64FromString(input.StudioID, "studio_id")
 	updatedScene.Organized = input.Organized
 
+	updatedUserData := models.UserDataPartial{
+		Rating:  translator.nullInt64(input.Rating, "rating"),
+		Watched: input.Watched,
+	}
+	userID := session.GetUserDataID(ctx)
+
 	ret := []*models.Scene{}
 
 	// Start the transaction and save the scene marker
@@ -267,6 +359,10 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input models.Bul
 				return err
 			}
 
+			if err := qb.UpdateUserData(sceneID, userID, updatedUserData); err != nil {
+				return err
+			}
+
 			ret = append(ret, scene)
 
 			// Save the performers
@@ -450,12 +546,17 @@ func adjustSceneMovieIDs(qb models.SceneReader, sceneID int, updateIDs models.Bu
 }
 
 func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneDestroyInput) (bool, error) {
+	if err := requireRoleToDeleteFiles(ctx, input.DeleteFile, input.DeleteGenerated); err != nil {
+		return false, err
+	}
+
 	sceneID, err := strconv.Atoi(input.ID)
 	if err != nil {
 		return false, err
 	}
 
 	var scene *models.Scene
+	var files []*models.SceneFile
 	var postCommitFunc func()
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Scene()
@@ -469,6 +570,11 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 			return fmt.Errorf("scene with id %d not found", sceneID)
 		}
 
+		files, err = qb.GetFiles(sceneID)
+		if err != nil {
+			return err
+		}
+
 		postCommitFunc, err = manager.DestroyScene(scene, repo)
 		return err
 	}); err != nil {
@@ -484,10 +590,10 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 		manager.DeleteGeneratedSceneFiles(scene, config.GetInstance().GetVideoFileNamingAlgorithm())
 	}
 
-	// if delete file is true, then delete the file as well
+	// if delete file is true, then delete the files as well
 	// if it fails, just log a message
 	if input.DeleteFile != nil && *input.DeleteFile {
-		manager.DeleteSceneFile(scene)
+		manager.DeleteSceneFiles(files)
 	}
 
 	// call post hook after performing the other actions
@@ -497,7 +603,12 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 }
 
 func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.ScenesDestroyInput) (bool, error) {
+	if err := requireRoleToDeleteFiles(ctx, input.DeleteFile, input.DeleteGenerated); err != nil {
+		return false, err
+	}
+
 	var scenes []*models.Scene
+	sceneFiles := make(map[int][]*models.SceneFile)
 	var postCommitFuncs []func()
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Scene()
@@ -512,6 +623,12 @@ func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.Scene
 			if scene != nil {
 				scenes = append(scenes, scene)
 			}
+
+			sceneFiles[sceneID], err = qb.GetFiles(sceneID)
+			if err != nil {
+				return err
+			}
+
 			f, err := manager.DestroyScene(scene, repo)
 			if err != nil {
 				return err
@@ -537,10 +654,10 @@ func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.Scene
 			manager.DeleteGeneratedSceneFiles(scene, fileNamingAlgo)
 		}
 
-		// if delete file is true, then delete the file as well
+		// if delete file is true, then delete the files as well
 		// if it fails, just log a message
 		if input.DeleteFile != nil && *input.DeleteFile {
-			manager.DeleteSceneFile(scene)
+			manager.DeleteSceneFiles(sceneFiles[scene.ID])
 		}
 
 		// call post hook after performing the other actions
@@ -562,6 +679,10 @@ func (r *mutationResolver) getSceneMarker(ctx context.Context, id int) (ret *mod
 }
 
 func (r *mutationResolver) SceneMarkerCreate(ctx context.Context, input models.SceneMarkerCreateInput) (*models.SceneMarker, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.SceneMarkerCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
 	primaryTagID, err := strconv.Atoi(input.PrimaryTagID)
 	if err != nil {
 		return nil, err
@@ -572,10 +693,16 @@ func (r *mutationResolver) SceneMarkerCreate(ctx context.Context, input models.S
 		return nil, err
 	}
 
+	endSeconds, err := getMarkerEndSeconds(input.Seconds, input.EndSeconds)
+	if err != nil {
+		return nil, err
+	}
+
 	currentTime := time.Now()
 	newSceneMarker := models.SceneMarker{
 		Title:        input.Title,
 		Seconds:      input.Seconds,
+		EndSeconds:   endSeconds,
 		PrimaryTagID: primaryTagID,
 		SceneID:      sql.NullInt64{Int64: int64(sceneID), Valid: sceneID != 0},
 		CreatedAt:    models.SQLiteTimestamp{Timestamp: currentTime},
@@ -603,6 +730,14 @@ func (r *mutationResolver) SceneMarkerUpdate(ctx context.Context, input models.S
 		return nil, err
 	}
 
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	if err := r.executePreHooks(ctx, sceneMarkerID, plugin.SceneMarkerUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	primaryTagID, err := strconv.Atoi(input.PrimaryTagID)
 	if err != nil {
 		return nil, err
@@ -613,10 +748,16 @@ func (r *mutationResolver) SceneMarkerUpdate(ctx context.Context, input models.S
 		return nil, err
 	}
 
+	endSeconds, err := getMarkerEndSeconds(input.Seconds, input.EndSeconds)
+	if err != nil {
+		return nil, err
+	}
+
 	updatedSceneMarker := models.SceneMarker{
 		ID:           sceneMarkerID,
 		Title:        input.Title,
 		Seconds:      input.Seconds,
+		EndSeconds:   endSeconds,
 		SceneID:      sql.NullInt64{Int64: int64(sceneID), Valid: sceneID != 0},
 		PrimaryTagID: primaryTagID,
 		UpdatedAt:    models.SQLiteTimestamp{Timestamp: time.Now()},
@@ -632,13 +773,24 @@ func (r *mutationResolver) SceneMarkerUpdate(ctx context.Context, input models.S
 		return nil, err
 	}
 
-	translator := changesetTranslator{
-		inputMap: getUpdateInputMap(ctx),
-	}
 	r.hookExecutor.ExecutePostHooks(ctx, ret.ID, plugin.SceneMarkerUpdatePost, input, translator.getFields())
 	return r.getSceneMarker(ctx, ret.ID)
 }
 
+// getMarkerEndSeconds returns the end time of a marker, which must be after
+// its start time.
+func getMarkerEndSeconds(seconds float64, endSeconds *float64) (sql.NullFloat64, error) {
+	if endSeconds == nil {
+		return sql.NullFloat64{}, nil
+	}
+
+	if *endSeconds <= seconds {
+		return sql.NullFloat64{}, fmt.Errorf("end seconds (%v) must be greater than seconds (%v)", *endSeconds, seconds)
+	}
+
+	return sql.NullFloat64{Float64: *endSeconds, Valid: true}, nil
+}
+
 func (r *mutationResolver) SceneMarkerDestroy(ctx context.Context, id string) (bool, error) {
 	markerID, err := strconv.Atoi(id)
 	if err != nil {
@@ -717,8 +869,8 @@ func (r *mutationResolver) changeMarker(ctx context.Context, changeType int, cha
 		return nil, err
 	}
 
-	// remove the marker preview if the timestamp was changed
-	if scene != nil && existingMarker != nil && existingMarker.Seconds != changedMarker.Seconds {
+	// remove the marker preview if the timestamp or range was changed
+	if scene != nil && existingMarker != nil && (existingMarker.Seconds != changedMarker.Seconds || existingMarker.EndSeconds != changedMarker.EndSeconds) {
 		seconds := int(existingMarker.Seconds)
 		manager.DeleteSceneMarkerFiles(scene, seconds, config.GetInstance().GetVideoFileNamingAlgorithm())
 	}
@@ -735,7 +887,7 @@ func (r *mutationResolver) SceneIncrementO(ctx context.Context, id string) (ret
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Scene()
 
-		ret, err = qb.IncrementOCounter(sceneID)
+		ret, err = qb.IncrementOCounter(sceneID, session.GetUserDataID(ctx))
 		return err
 	}); err != nil {
 		return 0, err
@@ -753,7 +905,7 @@ func (r *mutationResolver) SceneDecrementO(ctx context.Context, id string) (ret
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Scene()
 
-		ret, err = qb.DecrementOCounter(sceneID)
+		ret, err = qb.DecrementOCounter(sceneID, session.GetUserDataID(ctx))
 		return err
 	}); err != nil {
 		return 0, err
@@ -771,7 +923,49 @@ func (r *mutationResolver) SceneResetO(ctx context.Context, id string) (ret int,
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Scene()
 
-		ret, err = qb.ResetOCounter(sceneID)
+		ret, err = qb.ResetOCounter(sceneID, session.GetUserDataID(ctx))
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) SceneUpdateUserData(ctx context.Context, input models.SceneUserDataUpdateInput) (*models.Scene, error) {
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	sceneID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedUserData := models.UserDataPartial{
+		Rating:  translator.nullInt64(input.Rating, "rating"),
+		Watched: input.Watched,
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		return repo.Scene().UpdateUserData(sceneID, session.GetUserDataID(ctx), updatedUserData)
+	}); err != nil {
+		return nil, err
+	}
+
+	return r.getScene(ctx, sceneID)
+}
+
+func (r *mutationResolver) SceneAddPlay(ctx context.Context, id string) (ret int, err error) {
+	sceneID, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Scene()
+
+		ret, err = qb.AddPlay(sceneID, session.GetUserDataID(ctx), time.Now())
 		return err
 	}); err != nil {
 		return 0, err
@@ -780,6 +974,23 @@ func (r *mutationResolver) SceneResetO(ctx context.Context, id string) (ret int,
 	return ret, nil
 }
 
+func (r *mutationResolver) SceneSaveActivity(ctx context.Context, id string, resumeTime *float64, playDuration *float64) (bool, error) {
+	sceneID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Scene()
+
+		return qb.SaveActivity(sceneID, session.GetUserDataID(ctx), resumeTime, playDuration)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (r *mutationResolver) SceneGenerateScreenshot(ctx context.Context, id string, at *float64) (string, error) {
 	if at != nil {
 		manager.GetInstance().GenerateScreenshot(ctx, id, *at)
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *mutationResolver) ScheduleCreate(ctx context.Context, input models.ScheduleCreateInput) (*models.Schedule, error) {
+	return manager.GetInstance().Scheduler.Create(input)
+}
+
+func (r *mutationResolver) SchedulePause(ctx context.Context, name string, paused bool) (*models.Schedule, error) {
+	return manager.GetInstance().Scheduler.SetPaused(name, paused)
+}
+
+func (r *mutationResolver) ScheduleDestroy(ctx context.Context, name string) (bool, error) {
+	if err := manager.GetInstance().Scheduler.Destroy(name); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) ScheduleRun(ctx context.Context, name string) (string, error) {
+	jobID, err := manager.GetInstance().Scheduler.Run(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
@@ -25,6 +25,10 @@ func (r *mutationResolver) getStudio(ctx context.Context, id int) (ret *models.S
 }
 
 func (r *mutationResolver) StudioCreate(ctx context.Context, input models.StudioCreateInput) (*models.Studio, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.StudioCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
 	// generate checksum from studio name rather than image
 	checksum := utils.MD5FromString(input.Name)
 
@@ -120,6 +124,10 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	if err := r.executePreHooks(ctx, studioID, plugin.StudioUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	updatedStudio := models.StudioPartial{
 		ID:        studioID,
 		UpdatedAt: &models.SQLiteTimestamp{Timestamp: time.Now()},
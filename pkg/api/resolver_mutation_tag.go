@@ -24,6 +24,10 @@ func (r *mutationResolver) getTag(ctx context.Context, id int) (ret *models.Tag,
 }
 
 func (r *mutationResolver) TagCreate(ctx context.Context, input models.TagCreateInput) (*models.Tag, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.TagCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
 	// Populate a new tag from the input
 	currentTime := time.Now()
 	newTag := models.Tag{
@@ -119,6 +123,10 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input models.TagUpdate
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	if err := r.executePreHooks(ctx, tagID, plugin.TagUpdatePre, &input, translator.inputMap); err != nil {
+		return nil, err
+	}
+
 	imageIncluded := translator.hasField("image")
 	if input.Image != nil {
 		imageData, err = utils.ProcessImageInput(*input.Image)
@@ -28,7 +28,14 @@ const existingTagID = 1
 const existingTagName = "existingTagName"
 const newTagID = 2
 
-type mockHookExecutor struct{}
+type mockHookExecutor struct {
+	preHookChanges map[string]interface{}
+	preHookErr     error
+}
+
+func (e *mockHookExecutor) ExecutePreHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, inputFields []string) (map[string]interface{}, error) {
+	return e.preHookChanges, e.preHookErr
+}
 
 func (*mockHookExecutor) ExecutePostHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, inputFields []string) {
 }
@@ -105,3 +112,50 @@ func TestTagCreate(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, tag)
 }
+
+func TestTagCreatePreHook(t *testing.T) {
+	r := newResolver()
+	hookExecutor := r.hookExecutor.(*mockHookExecutor)
+
+	tagRW := r.txnManager.(*mocks.TransactionManager).Tag().(*mocks.TagReaderWriter)
+
+	// vetoed by pre hook
+	expectedErr := errors.New("pre hook error")
+	hookExecutor.preHookErr = expectedErr
+
+	_, err := r.Mutation().TagCreate(context.TODO(), models.TagCreateInput{
+		Name: errTagName,
+	})
+
+	assert.Equal(t, expectedErr, err)
+	tagRW.AssertNotCalled(t, "Create", mock.Anything)
+
+	// input rewritten by pre hook
+	hookExecutor.preHookErr = nil
+	hookExecutor.preHookChanges = map[string]interface{}{
+		"name": tagName,
+	}
+
+	pp := 1
+	findFilter := &models.FindFilterType{
+		PerPage: &pp,
+	}
+
+	tagRW.On("Query", mock.AnythingOfType("*models.TagFilterType"), findFilter).Return(nil, 0, nil).Twice()
+	newTag := &models.Tag{
+		ID:   newTagID,
+		Name: tagName,
+	}
+	tagRW.On("Create", mock.MatchedBy(func(tag models.Tag) bool {
+		return tag.Name == tagName
+	})).Return(newTag, nil).Once()
+	tagRW.On("Find", newTagID).Return(newTag, nil)
+
+	tag, err := r.Mutation().TagCreate(context.TODO(), models.TagCreateInput{
+		Name: errTagName,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, tagName, tag.Name)
+	tagRW.AssertExpectations(t)
+}
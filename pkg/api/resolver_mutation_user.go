@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/user"
+)
+
+var ErrLastAdmin = errors.New("at least one admin user is required")
+
+// ErrFirstUserNotAdmin is returned when the first user does not have the
+// admin role. Only admin users can manage users and the configuration, so
+// creating any other user first would lock these functions.
+var ErrFirstUserNotAdmin = errors.New("the first user must be an admin")
+
+func (r *mutationResolver) UserCreate(ctx context.Context, input models.UserCreateInput) (ret *models.User, err error) {
+	username, err := user.ValidateUsername(input.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := user.HashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	currentTime := time.Now()
+	newUser := models.User{
+		Username:  username,
+		Password:  password,
+		Role:      input.Role,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.User()
+
+		if err := ensureUsernameUnique(0, username, qb); err != nil {
+			return err
+		}
+
+		count, err := qb.Count()
+		if err != nil {
+			return err
+		}
+
+		if count == 0 && newUser.Role != models.UserRoleAdmin {
+			return ErrFirstUserNotAdmin
+		}
+
+		ret, err = qb.Create(newUser)
+		if err != nil {
+			return err
+		}
+
+		// the existing ratings and o-counters belong to the first admin
+		if count == 0 {
+			return qb.TransferUserData(0, ret.ID)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) UserUpdate(ctx context.Context, input models.UserUpdateInput) (ret *models.User, err error) {
+	userID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.User()
+
+		u, err := qb.Find(userID)
+		if err != nil {
+			return err
+		}
+
+		if u == nil {
+			return fmt.Errorf("user with id %d not found", userID)
+		}
+
+		if input.Username != nil {
+			username, err := user.ValidateUsername(*input.Username)
+			if err != nil {
+				return err
+			}
+
+			if err := ensureUsernameUnique(u.ID, username, qb); err != nil {
+				return err
+			}
+
+			u.Username = username
+		}
+
+		if input.Password != nil {
+			u.Password, err = user.HashPassword(*input.Password)
+			if err != nil {
+				return err
+			}
+		}
+
+		if input.Role != nil && *input.Role != u.Role {
+			if u.Role == models.UserRoleAdmin {
+				if err := ensureNotLastAdmin(qb); err != nil {
+					return err
+				}
+			}
+
+			u.Role = *input.Role
+		}
+
+		u.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		ret, err = qb.Update(*u)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) UserDestroy(ctx context.Context, input models.UserDestroyInput) (bool, error) {
+	userID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.User()
+
+		u, err := qb.Find(userID)
+		if err != nil {
+			return err
+		}
+
+		if u == nil {
+			return fmt.Errorf("user with id %d not found", userID)
+		}
+
+		if u.Role == models.UserRoleAdmin {
+			if err := ensureNotLastAdmin(qb); err != nil {
+				return err
+			}
+		}
+
+		return qb.Destroy(userID)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) UserChangePassword(ctx context.Context, input models.UserChangePasswordInput) (bool, error) {
+	userID := session.GetCurrentUserID(ctx)
+	if userID == nil {
+		return false, errors.New("not logged in as a user")
+	}
+
+	password, err := user.HashPassword(input.NewPassword)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.User()
+
+		u, err := qb.Find(*userID)
+		if err != nil {
+			return err
+		}
+
+		if u == nil || !user.CheckPassword(u, input.CurrentPassword) {
+			return errors.New("current password is incorrect")
+		}
+
+		u.Password = password
+		u.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		_, err = qb.Update(*u)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ensureUsernameUnique returns an error if a user other than the user with
+// the provided id has the provided username.
+func ensureUsernameUnique(id int, username string, qb models.UserReader) error {
+	existing, err := qb.FindByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != id {
+		return fmt.Errorf("user with username '%s' already exists", username)
+	}
+
+	return nil
+}
+
+// ensureNotLastAdmin returns ErrLastAdmin if there is only one admin user.
+// Should be called before removing the admin role from a user.
+func ensureNotLastAdmin(qb models.UserReader) error {
+	count, err := qb.CountByRole(models.UserRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	if count <= 1 {
+		return ErrLastAdmin
+	}
+
+	return nil
+}
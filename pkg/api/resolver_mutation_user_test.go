@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const firstUserID = 1
+
+func TestUserCreateFirstUser(t *testing.T) {
+	r := newResolver()
+
+	userRW := r.txnManager.(*mocks.TransactionManager).User().(*mocks.UserReaderWriter)
+
+	userRW.On("FindByUsername", mock.Anything).Return(nil, nil)
+	userRW.On("Count").Return(0, nil)
+	userRW.On("Create", mock.MatchedBy(func(u models.User) bool {
+		return u.Role == models.UserRoleAdmin
+	})).Return(&models.User{
+		ID:   firstUserID,
+		Role: models.UserRoleAdmin,
+	}, nil).Once()
+	userRW.On("TransferUserData", 0, firstUserID).Return(nil).Once()
+
+	for _, role := range []models.UserRole{models.UserRoleEditor, models.UserRoleViewer} {
+		_, err := r.Mutation().UserCreate(context.TODO(), models.UserCreateInput{
+			Username: "user",
+			Password: "password",
+			Role:     role,
+		})
+
+		assert.Equal(t, ErrFirstUserNotAdmin, err)
+	}
+
+	u, err := r.Mutation().UserCreate(context.TODO(), models.UserCreateInput{
+		Username: "admin",
+		Password: "password",
+		Role:     models.UserRoleAdmin,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, firstUserID, u.ID)
+
+	userRW.AssertExpectations(t)
+}
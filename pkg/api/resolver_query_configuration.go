@@ -3,13 +3,25 @@ package api
 import (
 	"context"
 
+	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/user"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
 func (r *queryResolver) Configuration(ctx context.Context) (*models.ConfigResult, error) {
-	return makeConfigResult(), nil
+	ret := makeConfigResult()
+
+	u, err := r.getCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	setConfigGeneralUserResult(ctx, ret.General, u)
+
+	return ret, nil
 }
 
 func (r *queryResolver) Directory(ctx context.Context, path *string) (*models.Directory, error) {
@@ -56,42 +68,79 @@ func makeConfigGeneralResult() *models.ConfigGeneralResult {
 	scraperCDPPath := config.GetScraperCDPPath()
 
 	return &models.ConfigGeneralResult{
-		Stashes:                      config.GetStashPaths(),
-		DatabasePath:                 config.GetDatabasePath(),
-		GeneratedPath:                config.GetGeneratedPath(),
-		ConfigFilePath:               config.GetConfigFilePath(),
-		ScrapersPath:                 config.GetScrapersPath(),
-		CachePath:                    config.GetCachePath(),
-		CalculateMd5:                 config.IsCalculateMD5(),
-		VideoFileNamingAlgorithm:     config.GetVideoFileNamingAlgorithm(),
-		ParallelTasks:                config.GetParallelTasks(),
-		PreviewAudio:                 config.GetPreviewAudio(),
-		PreviewSegments:              config.GetPreviewSegments(),
-		PreviewSegmentDuration:       config.GetPreviewSegmentDuration(),
-		PreviewExcludeStart:          config.GetPreviewExcludeStart(),
-		PreviewExcludeEnd:            config.GetPreviewExcludeEnd(),
-		PreviewPreset:                config.GetPreviewPreset(),
-		MaxTranscodeSize:             &maxTranscodeSize,
-		MaxStreamingTranscodeSize:    &maxStreamingTranscodeSize,
-		APIKey:                       config.GetAPIKey(),
-		Username:                     config.GetUsername(),
-		Password:                     config.GetPasswordHash(),
-		MaxSessionAge:                config.GetMaxSessionAge(),
-		LogFile:                      &logFile,
-		LogOut:                       config.GetLogOut(),
-		LogLevel:                     config.GetLogLevel(),
-		LogAccess:                    config.GetLogAccess(),
-		VideoExtensions:              config.GetVideoExtensions(),
-		ImageExtensions:              config.GetImageExtensions(),
-		GalleryExtensions:            config.GetGalleryExtensions(),
-		CreateGalleriesFromFolders:   config.GetCreateGalleriesFromFolders(),
-		Excludes:                     config.GetExcludes(),
-		ImageExcludes:                config.GetImageExcludes(),
-		CustomPerformerImageLocation: &customPerformerImageLocation,
-		ScraperUserAgent:             &scraperUserAgent,
-		ScraperCertCheck:             config.GetScraperCertCheck(),
-		ScraperCDPPath:               &scraperCDPPath,
-		StashBoxes:                   config.GetStashBoxes(),
+		Stashes:                             config.GetStashPaths(),
+		DatabasePath:                        config.GetDatabasePath(),
+		GeneratedPath:                       config.GetGeneratedPath(),
+		ConfigFilePath:                      config.GetConfigFilePath(),
+		ScrapersPath:                        config.GetScrapersPath(),
+		CachePath:                           config.GetCachePath(),
+		CalculateMd5:                        config.IsCalculateMD5(),
+		VideoFileNamingAlgorithm:            config.GetVideoFileNamingAlgorithm(),
+		ParallelTasks:                       config.GetParallelTasks(),
+		PreviewAudio:                        config.GetPreviewAudio(),
+		PreviewSegments:                     config.GetPreviewSegments(),
+		PreviewSegmentDuration:              config.GetPreviewSegmentDuration(),
+		PreviewExcludeStart:                 config.GetPreviewExcludeStart(),
+		PreviewExcludeEnd:                   config.GetPreviewExcludeEnd(),
+		PreviewPreset:                       config.GetPreviewPreset(),
+		MaxTranscodeSize:                    &maxTranscodeSize,
+		MaxStreamingTranscodeSize:           &maxStreamingTranscodeSize,
+		TranscodeHardwareAcceleration:       config.GetTranscodeHardwareAcceleration(),
+		ActiveTranscodeHardwareAcceleration: manager.GetInstance().GetTranscodeProfile().HWAccel,
+		TranscodeHardwareDevice:             config.GetTranscodeHardwareDevice(),
+		TranscodeCustomInputArgs:            config.GetTranscodeCustomInputArgs(),
+		TranscodeCustomVideoArgs:            config.GetTranscodeCustomVideoArgs(),
+		PreferredAudioLanguages:             config.GetPreferredAudioLanguages(),
+		MaxSessionAge:                       config.GetMaxSessionAge(),
+		LogFile:                             &logFile,
+		LogOut:                              config.GetLogOut(),
+		LogLevel:                            config.GetLogLevel(),
+		LogAccess:                           config.GetLogAccess(),
+		VideoExtensions:                     config.GetVideoExtensions(),
+		ImageExtensions:                     config.GetImageExtensions(),
+		GalleryExtensions:                   config.GetGalleryExtensions(),
+		CreateGalleriesFromFolders:          config.GetCreateGalleriesFromFolders(),
+		CreateImageClipsFromVideos:          config.GetCreateImageClipsFromVideos(),
+		WatchEnabled:                        config.GetWatchEnabled(),
+		WatchPollInterval:                   config.GetWatchPollInterval(),
+		WatchGeneratePreviews:               config.GetWatchGeneratePreviews(),
+		WatchGenerateSprites:                config.GetWatchGenerateSprites(),
+		WatchGeneratePhashes:                config.GetWatchGeneratePhashes(),
+		JobHistoryRetentionDays:             config.GetJobHistoryRetentionDays(),
+		JobHistoryMaxEntries:                config.GetJobHistoryMaxEntries(),
+		ScanJobLimit:                        config.GetScanJobLimit(),
+		GenerateJobLimit:                    config.GetGenerateJobLimit(),
+		MetadataJobLimit:                    config.GetMetadataJobLimit(),
+		ScrapingJobLimit:                    config.GetScrapingJobLimit(),
+		Excludes:                            config.GetExcludes(),
+		ImageExcludes:                       config.GetImageExcludes(),
+		CustomPerformerImageLocation:        &customPerformerImageLocation,
+		ScraperUserAgent:                    &scraperUserAgent,
+		ScraperCertCheck:                    config.GetScraperCertCheck(),
+		ScraperCDPPath:                      &scraperCDPPath,
+		StashBoxes:                          config.GetStashBoxes(),
+	}
+}
+
+// setConfigGeneralUserResult sets the fields of the general configuration
+// result that belong to the user u. Stash-box API keys are hidden from users
+// that are not admins.
+func setConfigGeneralUserResult(ctx context.Context, ret *models.ConfigGeneralResult, u *models.User) {
+	if u != nil {
+		ret.APIKey = u.APIKey.String
+		ret.Username = u.Username
+		ret.Password = u.Password
+	}
+
+	if !user.HasRole(session.GetCurrentUser(ctx), models.UserRoleAdmin) {
+		var stashBoxes []*models.StashBox
+		for _, box := range ret.StashBoxes {
+			stashBoxes = append(stashBoxes, &models.StashBox{
+				Endpoint: box.Endpoint,
+				Name:     box.Name,
+			})
+		}
+		ret.StashBoxes = stashBoxes
 	}
 }
 
@@ -33,6 +33,26 @@ func (r *queryResolver) FindJob(ctx context.Context, input models.FindJobInput)
 	return jobToJobModel(*j), nil
 }
 
+func (r *queryResolver) JobHistory(ctx context.Context, filter *models.FindFilterType, jobFilter *models.JobHistoryFilterType) (ret *models.FindJobHistoryResultType, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		jobs, total, err := repo.JobHistory().Query(jobFilter, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &models.FindJobHistoryResultType{
+			Count: total,
+			Jobs:  jobs,
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func jobToJobModel(j job.Job) *models.Job {
 	ret := &models.Job{
 		ID:          strconv.Itoa(j.ID),
@@ -42,6 +62,8 @@ func jobToJobModel(j job.Job) *models.Job {
 		StartTime:   j.StartTime,
 		EndTime:     j.EndTime,
 		AddTime:     j.AddTime,
+		Pausable:    j.Pausable,
+		Group:       models.JobGroup(j.Group),
 	}
 
 	if j.Progress != -1 {
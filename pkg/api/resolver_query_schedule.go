@@ -0,0 +1,12 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) Schedules(ctx context.Context) ([]*models.Schedule, error) {
+	return manager.GetInstance().Scheduler.List(), nil
+}
@@ -51,6 +51,10 @@ func (r *queryResolver) ListGalleryScrapers(ctx context.Context) ([]*models.Scra
 	return manager.GetInstance().ScraperCache.ListGalleryScrapers(), nil
 }
 
+func (r *queryResolver) ListImageScrapers(ctx context.Context) ([]*models.Scraper, error) {
+	return manager.GetInstance().ScraperCache.ListImageScrapers(), nil
+}
+
 func (r *queryResolver) ListMovieScrapers(ctx context.Context) ([]*models.Scraper, error) {
 	return manager.GetInstance().ScraperCache.ListMovieScrapers(), nil
 }
@@ -105,6 +109,10 @@ func (r *queryResolver) ScrapeGalleryURL(ctx context.Context, url string) (*mode
 	return manager.GetInstance().ScraperCache.ScrapeGalleryURL(url)
 }
 
+func (r *queryResolver) ScrapeImageURL(ctx context.Context, url string) (*models.ScrapedImage, error) {
+	return manager.GetInstance().ScraperCache.ScrapeImageURL(url)
+}
+
 func (r *queryResolver) ScrapeMovieURL(ctx context.Context, url string) (*models.ScrapedMovie, error) {
 	return manager.GetInstance().ScraperCache.ScrapeMovieURL(url)
 }
@@ -319,6 +327,76 @@ func (r *queryResolver) ScrapeSingleGallery(ctx context.Context, source models.S
 	return nil, errors.New("scraper_id must be set")
 }
 
+func (r *queryResolver) ScrapeSingleImage(ctx context.Context, source models.ScraperSourceInput, input models.ScrapeSingleImageInput) ([]*models.ScrapedImage, error) {
+	if source.ScraperID != nil {
+		var singleImage *models.ScrapedImage
+		var err error
+
+		if input.ImageID != nil {
+			var imageID int
+			imageID, err = strconv.Atoi(*input.ImageID)
+			if err != nil {
+				return nil, err
+			}
+			singleImage, err = manager.GetInstance().ScraperCache.ScrapeImage(*source.ScraperID, imageID)
+		} else if input.ImageInput != nil {
+			singleImage, err = manager.GetInstance().ScraperCache.ScrapeImageFragment(*source.ScraperID, *input.ImageInput)
+		} else {
+			return nil, errors.New("not implemented")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if singleImage != nil {
+			return []*models.ScrapedImage{singleImage}, nil
+		}
+
+		return nil, nil
+	} else if source.StashBoxIndex != nil {
+		return nil, errors.New("not supported")
+	}
+
+	return nil, errors.New("scraper_id must be set")
+}
+
 func (r *queryResolver) ScrapeSingleMovie(ctx context.Context, source models.ScraperSourceInput, input models.ScrapeSingleMovieInput) ([]*models.ScrapedMovie, error) {
-	return nil, errors.New("not supported")
+	if source.ScraperID != nil {
+		if input.MovieID != nil || input.MovieInput != nil {
+			var singleMovie *models.ScrapedMovie
+			var err error
+
+			if input.MovieID != nil {
+				var movieID int
+				movieID, err = strconv.Atoi(*input.MovieID)
+				if err != nil {
+					return nil, err
+				}
+				singleMovie, err = manager.GetInstance().ScraperCache.ScrapeMovieByID(*source.ScraperID, movieID)
+			} else {
+				singleMovie, err = manager.GetInstance().ScraperCache.ScrapeMovie(*source.ScraperID, *input.MovieInput)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			if singleMovie != nil {
+				return []*models.ScrapedMovie{singleMovie}, nil
+			}
+
+			return nil, nil
+		}
+
+		if input.Query != nil {
+			return manager.GetInstance().ScraperCache.ScrapeMovieList(*source.ScraperID, *input.Query)
+		}
+
+		return nil, errors.New("not implemented")
+	} else if source.StashBoxIndex != nil {
+		return nil, errors.New("not supported")
+	}
+
+	return nil, errors.New("scraper_id must be set")
 }
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const defaultSearchLimit = 25
+
+func (r *queryResolver) Search(ctx context.Context, query string, types []models.SearchResultType, limit *int) (ret *models.SearchResultsType, err error) {
+	l := defaultSearchLimit
+	if limit != nil && *limit > 0 {
+		l = *limit
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		results, count, err := repo.Search().Search(query, types, l)
+		if err != nil {
+			return err
+		}
+
+		ret = &models.SearchResultsType{
+			Count:   count,
+			Results: results,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if ret.Results == nil {
+		ret.Results = []*models.SearchResult{}
+	}
+
+	return ret, nil
+}
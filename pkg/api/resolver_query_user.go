@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+func (r *queryResolver) Users(ctx context.Context) (ret []*models.User, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.User().All()
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) Me(ctx context.Context) (*models.User, error) {
+	return r.getCurrentUser(ctx)
+}
+
+// getCurrentUser returns the latest state of the current user. Returns nil
+// if the current user is not stored in the database.
+func (r *Resolver) getCurrentUser(ctx context.Context) (ret *models.User, err error) {
+	userID := session.GetCurrentUserID(ctx)
+	if userID == nil {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.User().Find(*userID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
@@ -32,16 +33,45 @@ func (rs imageRoutes) Routes() chi.Router {
 // region Handlers
 
 func (rs imageRoutes) Thumbnail(w http.ResponseWriter, r *http.Request) {
-	image := r.Context().Value(imageKey).(*models.Image)
-	filepath := manager.GetInstance().Paths.Generated.GetThumbnailPath(image.Checksum, models.DefaultGthumbWidth)
-
-	// if the thumbnail doesn't exist, fall back to the original file
-	exists, _ := utils.FileExists(filepath)
-	if exists {
-		http.ServeFile(w, r, filepath)
-	} else {
+	i := r.Context().Value(imageKey).(*models.Image)
+
+	if i.IsAnimated {
+		animatedPath := manager.GetInstance().Paths.Generated.GetAnimatedThumbnailPath(i.Checksum, models.DefaultGthumbWidth)
+		if exists, _ := utils.FileExists(animatedPath); exists {
+			w.Header().Set("Content-Type", "image/webp")
+			http.ServeFile(w, r, animatedPath)
+			return
+		}
+	}
+
+	// the size is rounded up to the nearest thumbnail size, so that only a
+	// few sizes of each image are cached
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+	size = image.GetThumbnailSize(size)
+	formats := image.GetThumbnailFormats(r.Header.Get("Accept"))
+
+	// the format depends on the Accept header
+	w.Header().Add("Vary", "Accept")
+
+	filepath, format, err := manager.GetInstance().ImageThumbnails.Get(r.Context(), i, size, formats)
+	if err != nil {
+		if r.Context().Err() != nil {
+			return
+		}
+
+		logger.Errorf("error generating thumbnail for image %s: %s", i.Path, err.Error())
+	}
+
+	// if the thumbnail can't be generated or isn't needed, fall back to the
+	// original file
+	if filepath == "" {
 		rs.Image(w, r)
+		return
 	}
+
+	w.Header().Set("Content-Type", format.MimeType())
+	w.Header().Set("Cache-Control", "max-age=604800000")
+	http.ServeFile(w, r, filepath)
 }
 
 func (rs imageRoutes) Image(w http.ResponseWriter, r *http.Request) {
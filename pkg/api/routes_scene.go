@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -15,6 +17,8 @@ import (
 	"github.com/stashapp/stash/pkg/utils"
 )
 
+var hlsSessionIDRE = regexp.MustCompile(`^[0-9a-f]+$`)
+
 type sceneRoutes struct {
 	txnManager models.TransactionManager
 }
@@ -30,7 +34,9 @@ func (rs sceneRoutes) Routes() chi.Router {
 		r.Get("/stream.mkv", rs.StreamMKV)
 		r.Get("/stream.webm", rs.StreamWebM)
 		r.Get("/stream.m3u8", rs.StreamHLS)
-		r.Get("/stream.ts", rs.StreamTS)
+		r.Get("/stream/master.m3u8", rs.StreamHLSMaster)
+		r.Get("/stream/caption.m3u8", rs.StreamHLSCaption)
+		r.Get("/stream/{hlsSession:[0-9a-f]+}/{segment:[0-9]+}.ts", rs.StreamHLSSegment)
 		r.Get("/stream.mp4", rs.StreamMp4)
 
 		r.Get("/screenshot", rs.Screenshot)
@@ -38,6 +44,7 @@ func (rs sceneRoutes) Routes() chi.Router {
 		r.Get("/webp", rs.Webp)
 		r.Get("/vtt/chapter", rs.ChapterVtt)
 		r.Get("/funscript", rs.Funscript)
+		r.Get("/caption", rs.Caption)
 
 		r.Get("/scene_marker/{sceneMarkerId}/stream", rs.SceneMarkerStream)
 		r.Get("/scene_marker/{sceneMarkerId}/preview", rs.SceneMarkerPreview)
@@ -100,6 +107,87 @@ func (rs sceneRoutes) StreamMp4(w http.ResponseWriter, r *http.Request) {
 	rs.streamTranscode(w, r, ffmpeg.CodecH264)
 }
 
+func (rs sceneRoutes) getCaptions(ctx context.Context, scene *models.Scene) (ret []*models.SceneCaption, err error) {
+	err = rs.txnManager.WithReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Scene().GetCaptions(scene.ID)
+		return err
+	})
+
+	return ret, err
+}
+
+// getStreamTracks returns the indexes of the video and audio streams of the
+// video and audio query parameters. If no audio stream is requested, the
+// audio stream in the preferred audio language is used, if any. Nil indexes
+// select the first streams.
+func getStreamTracks(r *http.Request, videoFile *ffmpeg.VideoFile) (videoTrack *int, audioTrack *int, err error) {
+	query := r.URL.Query()
+
+	if v := query.Get("video"); v != "" {
+		index, err := strconv.Atoi(v)
+		if err != nil || !videoFile.HasVideoStream(index) {
+			return nil, nil, fmt.Errorf("invalid video track: %s", v)
+		}
+		videoTrack = &index
+	}
+
+	if v := query.Get("audio"); v != "" {
+		index, err := strconv.Atoi(v)
+		if err != nil || !videoFile.HasAudioStream(index) {
+			return nil, nil, fmt.Errorf("invalid audio track: %s", v)
+		}
+		audioTrack = &index
+	} else if stream := videoFile.SelectAudioStream(config.GetInstance().GetPreferredAudioLanguages()); stream != nil {
+		audioTrack = &stream.Index
+	}
+
+	return videoTrack, audioTrack, nil
+}
+
+func (rs sceneRoutes) StreamHLSMaster(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	videoFile, err := ffmpeg.NewVideoFile(manager.GetInstance().FFProbePath, scene.Path, false)
+	if err != nil {
+		logger.Errorf("[stream] error reading video file: %s", err.Error())
+		return
+	}
+
+	sceneCaptions, err := rs.getCaptions(r.Context(), scene)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	logger.Debug("Returning HLS master playlist")
+
+	// the renditions share a session, so that only the rendition that the
+	// viewer is watching is transcoded
+	sessionID := utils.GenerateRandomKey(16)
+	playlistURL := strings.TrimSuffix(r.URL.Path, "/master.m3u8") + ".m3u8"
+	renditions := ffmpeg.GetHLSRenditions(*videoFile, config.GetInstance().GetMaxStreamingTranscodeSize())
+
+	var captions []ffmpeg.HLSCaption
+	for _, c := range sceneCaptions {
+		query := r.URL.Query()
+		query.Set("lang", c.LanguageCode)
+		query.Set("type", c.CaptionType)
+		captions = append(captions, ffmpeg.HLSCaption{
+			Name:         c.LanguageCode + " (" + c.CaptionType + ")",
+			LanguageCode: c.LanguageCode,
+			PlaylistURL:  "caption.m3u8?" + query.Encode(),
+		})
+	}
+
+	w.Header().Set("Content-Type", ffmpeg.MimeHLS)
+	ffmpeg.WriteHLSMasterPlaylist(renditions, captions, func(rendition ffmpeg.HLSRendition) string {
+		query := r.URL.Query()
+		query.Set("resolution", rendition.Resolution.String())
+		query.Set("session", sessionID)
+		return playlistURL + "?" + query.Encode()
+	}, w)
+}
+
 func (rs sceneRoutes) StreamHLS(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 
@@ -115,7 +203,15 @@ func (rs sceneRoutes) StreamHLS(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", ffmpeg.MimeHLS)
 	var str strings.Builder
 
-	ffmpeg.WriteHLSPlaylist(*videoFile, r.URL.String(), &str)
+	// playlists that are not part of a master playlist start a new session,
+	// so that every viewer has their own transcode
+	sessionID := r.URL.Query().Get("session")
+	if !hlsSessionIDRE.MatchString(sessionID) {
+		sessionID = utils.GenerateRandomKey(16)
+	}
+
+	segmentURL := strings.TrimSuffix(r.URL.Path, ".m3u8") + "/" + sessionID + "/"
+	ffmpeg.WriteHLSPlaylist(*videoFile, segmentURL, r.URL.RawQuery, &str)
 
 	requestByteRange := utils.CreateByteRange(r.Header.Get("Range"))
 	if requestByteRange.RawString != "" {
@@ -129,8 +225,94 @@ func (rs sceneRoutes) StreamHLS(w http.ResponseWriter, r *http.Request) {
 	w.Write(ret)
 }
 
-func (rs sceneRoutes) StreamTS(w http.ResponseWriter, r *http.Request) {
-	rs.streamTranscode(w, r, ffmpeg.CodecHLS)
+// StreamHLSCaption returns a playlist of the caption of the scene with the
+// language and type of the lang and type query parameters.
+func (rs sceneRoutes) StreamHLSCaption(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	videoFile, err := ffmpeg.NewVideoFile(manager.GetInstance().FFProbePath, scene.Path, false)
+	if err != nil {
+		logger.Errorf("[stream] error reading video file: %s", err.Error())
+		return
+	}
+
+	captionURL := strings.TrimSuffix(r.URL.Path, "/stream/caption.m3u8") + "/caption?" + r.URL.RawQuery
+
+	w.Header().Set("Content-Type", ffmpeg.MimeHLS)
+	ffmpeg.WriteHLSCaptionPlaylist(*videoFile, captionURL, w)
+}
+
+func (rs sceneRoutes) StreamHLSSegment(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	sessionID := chi.URLParam(r, "hlsSession")
+	segment, _ := strconv.Atoi(chi.URLParam(r, "segment"))
+
+	resolution := config.GetInstance().GetMaxStreamingTranscodeSize()
+	if requestedSize := r.URL.Query().Get("resolution"); requestedSize != "" {
+		resolution = models.StreamingResolutionEnum(requestedSize)
+	}
+
+	if !resolution.IsValid() {
+		http.Error(w, "invalid resolution", http.StatusBadRequest)
+		return
+	}
+
+	// the tracks are part of the rendition, so that changing the track
+	// restarts the transcode
+	rendition := resolution.String()
+	for _, param := range []string{"video", "audio"} {
+		if v := r.URL.Query().Get(param); v != "" {
+			index, err := strconv.Atoi(v)
+			if err != nil || index < 0 {
+				http.Error(w, "invalid "+param+" track", http.StatusBadRequest)
+				return
+			}
+			rendition += "_" + param + strconv.Itoa(index)
+		}
+	}
+
+	getOptions := func() (*ffmpeg.HLSTranscodeOptions, error) {
+		videoFile, err := ffmpeg.NewVideoFile(manager.GetInstance().FFProbePath, scene.Path, false)
+		if err != nil {
+			return nil, fmt.Errorf("error reading video file: %s", err.Error())
+		}
+
+		videoTrack, audioTrack, err := getStreamTracks(r, videoFile)
+		if err != nil {
+			return nil, err
+		}
+
+		audioCodec := ffmpeg.MissingUnsupported
+		if scene.AudioCodec.Valid {
+			audioCodec = ffmpeg.AudioCodec(scene.AudioCodec.String)
+		}
+
+		return &ffmpeg.HLSTranscodeOptions{
+			ProbeResult:      *videoFile,
+			MaxTranscodeSize: resolution,
+			Profile:          manager.GetInstance().GetTranscodeProfile(),
+			// ffmpeg fails if it trys to transcode a non supported audio codec
+			VideoOnly:  audioCodec == ffmpeg.MissingUnsupported,
+			VideoTrack: videoTrack,
+			AudioTrack: audioTrack,
+		}, nil
+	}
+
+	segmentPath, err := manager.GetInstance().HLSSessions.Segment(r.Context(), sessionID, rendition, scene.Path, segment, getOptions)
+	if err != nil {
+		// the viewer closed the connection
+		if r.Context().Err() != nil {
+			return
+		}
+
+		logger.Errorf("[stream] error serving HLS segment: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ffmpeg.MimeMpegts)
+	http.ServeFile(w, r, segmentPath)
 }
 
 func (rs sceneRoutes) streamTranscode(w http.ResponseWriter, r *http.Request, videoCodec ffmpeg.Codec) {
@@ -160,6 +342,20 @@ func (rs sceneRoutes) streamTranscode(w http.ResponseWriter, r *http.Request, vi
 	options := ffmpeg.GetTranscodeStreamOptions(*videoFile, videoCodec, audioCodec)
 	options.StartTime = startTime
 	options.MaxTranscodeSize = config.GetInstance().GetMaxStreamingTranscodeSize()
+	options.Profile = manager.GetInstance().GetTranscodeProfile()
+
+	captions, err := rs.getCaptions(r.Context(), scene)
+	if err != nil {
+		logger.Errorf("[stream] error getting captions: %s", err.Error())
+	}
+	options.Captions = manager.GetTranscodeCaptions(captions)
+
+	options.VideoTrack, options.AudioTrack, err = getStreamTracks(r, videoFile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	if requestedSize != "" {
 		options.MaxTranscodeSize = models.StreamingResolutionEnum(requestedSize)
 	}
@@ -245,8 +441,7 @@ func (rs sceneRoutes) ChapterVtt(w http.ResponseWriter, r *http.Request) {
 	vttLines := []string{"WEBVTT", ""}
 	for i, marker := range sceneMarkers {
 		vttLines = append(vttLines, strconv.Itoa(i+1))
-		time := utils.GetVTTTime(marker.Seconds)
-		vttLines = append(vttLines, time+" --> "+time)
+		vttLines = append(vttLines, getChapterVttTiming(marker))
 		vttLines = append(vttLines, rs.getChapterVttTitle(r.Context(), marker))
 		vttLines = append(vttLines, "")
 	}
@@ -256,12 +451,33 @@ func (rs sceneRoutes) ChapterVtt(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(vtt))
 }
 
+// getChapterVttTiming returns the cue timing of a marker. Markers without an
+// end time are a single point in time, so their cue ends where it starts.
+func getChapterVttTiming(marker *models.SceneMarker) string {
+	start := utils.GetVTTTime(marker.Seconds)
+	end := start
+	if marker.EndSeconds.Valid {
+		end = utils.GetVTTTime(marker.EndSeconds.Float64)
+	}
+
+	return start + " --> " + end
+}
+
 func (rs sceneRoutes) Funscript(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	funscript := utils.GetFunscriptPath(scene.Path)
 	utils.ServeFileNoCache(w, r, funscript)
 }
 
+func (rs sceneRoutes) Caption(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	ss := manager.SceneServer{
+		TXNManager: rs.txnManager,
+	}
+	ss.ServeCaption(scene, w, r)
+}
+
 func (rs sceneRoutes) VttThumbs(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	w.Header().Set("Content-Type", "text/vtt")
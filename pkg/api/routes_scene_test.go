@@ -0,0 +1,37 @@
+package api
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChapterVttTiming(t *testing.T) {
+	tests := []struct {
+		name   string
+		marker models.SceneMarker
+		want   string
+	}{
+		{
+			"point",
+			models.SceneMarker{Seconds: 61.5},
+			"00:01:01.500 --> 00:01:01.500",
+		},
+		{
+			"range",
+			models.SceneMarker{
+				Seconds:    61.5,
+				EndSeconds: sql.NullFloat64{Float64: 3723, Valid: true},
+			},
+			"00:01:01.500 --> 01:02:03.000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getChapterVttTiming(&tt.marker))
+		})
+	}
+}
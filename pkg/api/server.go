@@ -48,7 +48,8 @@ func allowUnauthenticated(r *http.Request) bool {
 func authenticateHandler() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			userID, err := manager.GetInstance().SessionStore.Authenticate(w, r)
+			store := manager.GetInstance().SessionStore
+			u, err := store.Authenticate(w, r)
 			if err != nil {
 				if err != session.ErrUnauthorized {
 					w.WriteHeader(http.StatusInternalServerError)
@@ -65,11 +66,21 @@ func authenticateHandler() func(http.Handler) http.Handler {
 				return
 			}
 
-			c := config.GetInstance()
 			ctx := r.Context()
 
+			authRequired, err := store.AuthenticationRequired(ctx)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			if !authRequired {
+				// everyone has full access if there are no users
+				u = anonymousUser
+			}
+
 			// handle redirect if no user and user is required
-			if userID == "" && c.HasCredentials() && !allowUnauthenticated(r) {
+			if u == nil && !allowUnauthenticated(r) {
 				// if we don't have a userID, then redirect
 				// if graphql was requested, we just return a forbidden error
 				if r.URL.Path == "/graphql" {
@@ -89,7 +100,7 @@ func authenticateHandler() func(http.Handler) http.Handler {
 				return
 			}
 
-			ctx = session.SetCurrentUserID(ctx, userID)
+			ctx = session.SetCurrentUser(ctx, u)
 
 			r = r.WithContext(ctx)
 
@@ -98,6 +109,30 @@ func authenticateHandler() func(http.Handler) http.Handler {
 	}
 }
 
+// anonymousUser is the current user when authentication is not required.
+var anonymousUser = &models.User{Role: models.UserRoleAdmin}
+
+// pluginAuthenticateHandler authenticates GraphQL requests made by plugins
+// through the in-process handler. Requests are made as the user that
+// triggered the plugin, or with full access if the plugin was not triggered
+// by a user, such as for scheduled tasks.
+func pluginAuthenticateHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, err := manager.GetInstance().SessionStore.Authenticate(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if u == nil {
+			u = anonymousUser
+		}
+
+		r = r.WithContext(session.SetCurrentUser(r.Context(), u))
+		next.ServeHTTP(w, r)
+	})
+}
+
 const loginEndPoint = "/login"
 
 func Start() {
@@ -142,6 +177,7 @@ func Start() {
 
 	gqlSrv := gqlHandler.New(models.NewExecutableSchema(models.Config{Resolvers: resolver}))
 	gqlSrv.SetRecoverFunc(recoverFunc)
+	gqlSrv.AroundFields(authorizeFieldMiddleware)
 	gqlSrv.AddTransport(gqlTransport.Websocket{
 		Upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
@@ -166,7 +202,7 @@ func Start() {
 
 	// register GQL handler with plugin cache
 	// chain the visited plugin handler
-	manager.GetInstance().PluginCache.RegisterGQLHandler(visitedPluginHandler(http.HandlerFunc(gqlHandlerFunc)))
+	manager.GetInstance().PluginCache.RegisterGQLHandler(pluginAuthenticateHandler(visitedPluginHandler(http.HandlerFunc(gqlHandlerFunc))))
 
 	r.HandleFunc("/graphql", gqlHandlerFunc)
 	r.HandleFunc("/playground", gqlPlayground.Handler("GraphQL playground", "/graphql"))
@@ -195,7 +231,8 @@ func Start() {
 	r.Mount("/tag", tagRoutes{
 		txnManager: txnManager,
 	}.Routes())
-	r.Mount("/downloads", downloadsRoutes{}.Routes())
+	// downloads are created by exportObjects, which requires the editor role
+	r.With(requireRoleHandler(models.UserRoleEditor)).Mount("/downloads", downloadsRoutes{}.Routes())
 
 	r.HandleFunc("/css", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/css")
@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	"github.com/stashapp/stash/pkg/manager"
-	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/session"
 )
 
@@ -32,7 +31,13 @@ func redirectToLogin(w http.ResponseWriter, returnURL string, loginError string)
 }
 
 func getLoginHandler(w http.ResponseWriter, r *http.Request) {
-	if !config.GetInstance().HasCredentials() {
+	authRequired, err := manager.GetInstance().SessionStore.AuthenticationRequired(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if !authRequired {
 		http.Redirect(w, r, "/", http.StatusFound)
 		return
 	}
@@ -0,0 +1,163 @@
+// Package archive reads the files of archive files, such as the zip, rar, 7z
+// and tar archives of galleries.
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is returned when opening a file that is not of a supported
+// archive format.
+var ErrUnsupported = errors.New("unsupported archive format")
+
+// File is a file in an archive.
+type File struct {
+	// Name is the path of the file in the archive, using '/' as the
+	// directory separator.
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// FileInfo returns the os.FileInfo of the file.
+func (f File) FileInfo() os.FileInfo {
+	return fileInfo{f}
+}
+
+type fileInfo struct {
+	f File
+}
+
+func (fi fileInfo) Name() string       { return filepath.Base(fi.f.Name) }
+func (fi fileInfo) Size() int64        { return fi.f.Size }
+func (fi fileInfo) Mode() os.FileMode  { return 0444 }
+func (fi fileInfo) ModTime() time.Time { return fi.f.ModTime }
+func (fi fileInfo) IsDir() bool        { return false }
+func (fi fileInfo) Sys() interface{}   { return nil }
+
+// Reader reads the files of an archive.
+type Reader interface {
+	// Files returns the files of the archive in archive order. Directories
+	// are not included.
+	Files() []File
+	// Open opens the file with the name.
+	Open(name string) (io.ReadCloser, error)
+	Close() error
+}
+
+// Format is an archive format.
+type Format struct {
+	Name string
+	// Extensions are the file extensions of the format, without the leading
+	// dot.
+	Extensions []string
+	// Match returns true if the start of a file is the signature of the
+	// format.
+	Match func(header []byte) bool
+	Open  func(path string) (Reader, error)
+}
+
+// headerSize is the number of bytes read to match archive signatures.
+const headerSize = 512
+
+var formats = []Format{
+	zipFormat,
+	rarFormat,
+	sevenZipFormat,
+	tarFormat,
+}
+
+// Register adds an archive format. Signatures are matched in the order that
+// the formats are registered.
+func Register(f Format) {
+	formats = append(formats, f)
+}
+
+// Extensions returns the file extensions of the archive formats.
+func Extensions() []string {
+	var ret []string
+	for _, f := range formats {
+		ret = append(ret, f.Extensions...)
+	}
+
+	return ret
+}
+
+func matchSignature(path string) (*Format, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := make([]byte, headerSize)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	header = header[:n]
+
+	for i := range formats {
+		if formats[i].Match(header) {
+			return &formats[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func matchExtension(path string) *Format {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	for i := range formats {
+		for _, e := range formats[i].Extensions {
+			if e == ext {
+				return &formats[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// Open opens the archive file at path. The format is detected from the
+// signature of the file, since archives often have the extension of another
+// format, such as cbr files that are zip files. The file extension is used
+// for formats without a signature.
+func Open(path string) (Reader, error) {
+	f, err := matchSignature(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if f == nil {
+		f = matchExtension(path)
+	}
+
+	if f == nil {
+		return nil, fmt.Errorf("%s: %w", path, ErrUnsupported)
+	}
+
+	return f.Open(path)
+}
+
+// errFileNotFound returns the error of a file that is not in the archive.
+func errFileNotFound(name string, path string) error {
+	return fmt.Errorf("file with name '%s' not found in archive file '%s': %w", name, path, os.ErrNotExist)
+}
+
+// readCloser closes the archive file that a file is read from when the file
+// is closed.
+type readCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (r *readCloser) Close() error {
+	return r.closer.Close()
+}
@@ -0,0 +1,366 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testArchiveFile struct {
+	name  string
+	data  string
+	isDir bool
+}
+
+var testArchiveFiles = []testArchiveFile{
+	{name: "sub/", isDir: true},
+	{name: "a.jpg", data: "first image"},
+	{name: "sub/b.png", data: "second image in a directory"},
+	{name: "empty.jpg"},
+}
+
+var testModTime = time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func writeTestZip(t *testing.T, path string) {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for _, f := range testArchiveFiles {
+		fw, err := w.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Modified: testModTime,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(f.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTestTar(t *testing.T, path string) {
+	buf := new(bytes.Buffer)
+	w := tar.NewWriter(buf)
+	for _, f := range testArchiveFiles {
+		h := &tar.Header{
+			Name:     f.name,
+			Size:     int64(len(f.data)),
+			Mode:     0644,
+			ModTime:  testModTime,
+			Typeflag: tar.TypeReg,
+		}
+		if f.isDir {
+			h.Typeflag = tar.TypeDir
+		}
+
+		if err := w.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(f.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// links are not files
+	if err := w.WriteHeader(&tar.Header{
+		Name:     "link.jpg",
+		Linkname: "a.jpg",
+		Typeflag: tar.TypeSymlink,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// rar5Vint returns the RAR 5 encoding of the number, which is the same as
+// the encoding of unsigned varints.
+func rar5Vint(n int) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return buf[:binary.PutUvarint(buf, uint64(n))]
+}
+
+// writeRAR5Block appends a RAR 5 block with the header fields, preceded by
+// the header CRC and size.
+func writeRAR5Block(buf *bytes.Buffer, fields []byte, data []byte) {
+	header := append(rar5Vint(len(fields)), fields...)
+	crc := make([]byte, 4)
+	binary.LittleEndian.PutUint32(crc, crc32.ChecksumIEEE(header))
+
+	buf.Write(crc)
+	buf.Write(header)
+	buf.Write(data)
+}
+
+// writeTestRAR writes a RAR 5 archive with stored files.
+func writeTestRAR(t *testing.T, path string) {
+	buf := new(bytes.Buffer)
+	buf.WriteString("Rar!\x1a\x07\x01\x00")
+
+	// main archive header
+	writeRAR5Block(buf, []byte{1, 0, 0}, nil)
+
+	for _, f := range testArchiveFiles {
+		name := f.name
+		// file flags: unix mtime, and directory
+		fileFlags := 0x02
+		if f.isDir {
+			fileFlags |= 0x01
+			name = name[:len(name)-1]
+		}
+
+		var fields []byte
+		// file header with data
+		fields = append(fields, 2, 0x02)
+		fields = append(fields, rar5Vint(len(f.data))...)
+		fields = append(fields, rar5Vint(fileFlags)...)
+		fields = append(fields, rar5Vint(len(f.data))...)
+		// attributes
+		fields = append(fields, 0)
+		mtime := make([]byte, 4)
+		binary.LittleEndian.PutUint32(mtime, uint32(testModTime.Unix()))
+		fields = append(fields, mtime...)
+		// stored, unix host
+		fields = append(fields, 0, 1)
+		fields = append(fields, rar5Vint(len(name))...)
+		fields = append(fields, name...)
+
+		writeRAR5Block(buf, fields, []byte(f.data))
+	}
+
+	// end of archive
+	writeRAR5Block(buf, []byte{5, 0, 0}, nil)
+
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// the test files written by bsdtar, with the copy, lzma and lzma2 methods
+var test7zArchives = map[string]string{
+	"copy": "377abcaf271c00034781a0f32600000000000000ed000000000000000e4ed90d" +
+		"666972737420696d6167657365636f6e6420696d61676520696e206120646972" +
+		"6563746f72790104060002090b1b00070b02000101000101000c0b1b00080a01" +
+		"41bd0a1e2bcab6a2000005040e01300f0180113d0061002e006a007000670000" +
+		"007300750062002f0062002e0070006e006700000065006d007000740079002e" +
+		"006a007000670000007300750062000000142201008080f4edb3e0d6018080f4" +
+		"edb3e0d6018080f4edb3e0d601180e3268015fdd0112220100180e3268015fdd" +
+		"01180e3268015fdd01180e3268015fdd01180e3268015fdd01132201008080f4" +
+		"edb3e0d6018080f4edb3e0d6018080f4edb3e0d6013a5f3168015fdd01151201" +
+		"002080a4812080a4812080a4811080ed410000",
+	"lzma": "377abcaf271c000377c55b51c90000000000000022000000000000003247d399" +
+		"00331a4aac0c72bf81b3185c5acd65daa18f650187377ea56439b78674272fcd" +
+		"c8ad4a92f94affffbfc880000000813307ae0fd0c44b3c9f3f474158d6fe026a" +
+		"25a8e880857c3e037b4e93ad3cab7e0c9a08fbc9b87e61b32d6432126f56d497" +
+		"925a10b7b12544f2ec5001664868a565478b3a18c26f3f02920843fd08397628" +
+		"9ae23e867a53dc13bc6f3fc581d93d5eec523c66d620fb57310e399c992df601" +
+		"8983613f9431210dbe4d6d55f72e38056cbd2bab96bf34d204aa9f257e131635" +
+		"280901ffffbc47000017062c0109809d00070b01000123030101055d00008000" +
+		"0c80f40a013ab5f8e80000",
+	"lzma2": "377abcaf271c00034334d722c3000000000000001c000000000000005cc83f8d" +
+		"010025666972737420696d6167657365636f6e6420696d61676520696e206120" +
+		"6469726563746f727900e000ed00915d0000813307ae0fd0a606bc9f3f474104" +
+		"04327aff94364819f9d924b7d06d7341814d9df04e4621d6c76b5c08d453c0d7" +
+		"e4aa16ca96b0474dad1b772f69c665ad32b5b0febd21ba5b5648ece40ddefdae" +
+		"a4953f95890ea7cea131b32116531993fcc6e3b23c9433411479bddf49c35664" +
+		"fddf02541cad1936c0f465c17d0d5e82c50312771d4a7b1fb7bf02552c6fc000" +
+		"00000017062a0109809900070b010001212101160c80ee0a017f95054b0000",
+}
+
+func assertArchiveFiles(t *testing.T, path string) {
+	r, err := Open(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer r.Close()
+
+	var names []string
+	for _, f := range r.Files() {
+		names = append(names, f.Name)
+		assert.True(t, f.ModTime.Equal(testModTime), "%s mod time %s", f.Name, f.ModTime)
+	}
+	assert.ElementsMatch(t, []string{"a.jpg", "sub/b.png", "empty.jpg"}, names)
+
+	for _, f := range testArchiveFiles {
+		if f.isDir {
+			continue
+		}
+
+		rc, err := r.Open(f.name)
+		if !assert.Nil(t, err, f.name) {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		assert.Nil(t, err, f.name)
+		assert.Equal(t, f.data, string(data), f.name)
+	}
+
+	_, err = r.Open("missing.jpg")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writers := map[string]func(*testing.T, string){
+		"test.zip": writeTestZip,
+		"test.tar": writeTestTar,
+		"test.rar": writeTestRAR,
+		// the format is detected from the signature
+		"zip.cbr": writeTestZip,
+		"rar.cbz": writeTestRAR,
+	}
+
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			write(t, path)
+			assertArchiveFiles(t, path)
+		})
+	}
+
+	for name, data := range test7zArchives {
+		t.Run(name+".7z", func(t *testing.T) {
+			b, err := hex.DecodeString(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join(dir, name+".7z")
+			if err := ioutil.WriteFile(path, b, 0644); err != nil {
+				t.Fatal(err)
+			}
+			assertArchiveFiles(t, path)
+		})
+	}
+}
+
+func readArchiveFile(t *testing.T, r Reader, name string) string {
+	rc, err := r.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestOpenStream(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.tar")
+	writeTestTar(t, path)
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the reader of the archive is reused
+	r2, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Same(t, r, r2)
+
+	// files are read in any order, and more than once
+	assert.Equal(t, "second image in a directory", readArchiveFile(t, r, "sub/b.png"))
+	assert.Equal(t, "second image in a directory", readArchiveFile(t, r, "sub/b.png"))
+	assert.Equal(t, "first image", readArchiveFile(t, r, "a.jpg"))
+	assert.Equal(t, "", readArchiveFile(t, r, "empty.jpg"))
+	assert.Equal(t, "first image", readArchiveFile(t, r, "a.jpg"))
+
+	// the archive is listed again when it changes
+	testArchiveFiles[1].data = "changed image"
+	defer func() {
+		testArchiveFiles[1].data = "first image"
+	}()
+	writeTestTar(t, path)
+	if err := os.Chtimes(path, testModTime, testModTime); err != nil {
+		t.Fatal(err)
+	}
+
+	r2, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotSame(t, r, r2)
+	assert.Equal(t, "changed image", readArchiveFile(t, r2, "a.jpg"))
+}
+
+func TestOpenUnsupported(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.txt")
+	if err := ioutil.WriteFile(path, []byte("not an archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Open(path)
+	assert.True(t, errors.Is(err, ErrUnsupported))
+
+	// invalid archives of a supported format
+	path = filepath.Join(dir, "test.rar")
+	if err := ioutil.WriteFile(path, []byte("Rar!\x1a\x07\x01\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Open(path)
+	assert.NotNil(t, err)
+
+	path = filepath.Join(dir, "test.7z")
+	if err := ioutil.WriteFile(path, append([]byte{}, sevenZipSignature...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Open(path)
+	assert.NotNil(t, err)
+}
+
+func TestExtensions(t *testing.T) {
+	assert.Equal(t, []string{"zip", "cbz", "rar", "cbr", "7z", "cb7", "tar", "cbt"}, Extensions())
+}
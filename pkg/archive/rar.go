@@ -0,0 +1,46 @@
+package archive
+
+import (
+	"bytes"
+
+	"github.com/nwaples/rardecode"
+)
+
+var rarFormat = Format{
+	Name:       "rar",
+	Extensions: []string{"rar", "cbr"},
+	Match: func(header []byte) bool {
+		// the signature of RAR 1.5 to 4 archives is followed by 0, and of
+		// RAR 5 archives by 1 0
+		return bytes.HasPrefix(header, []byte("Rar!\x1a\x07"))
+	},
+	Open: func(path string) (Reader, error) {
+		return openStreamReader(path, openRarStream)
+	},
+}
+
+type rarStream struct {
+	*rardecode.ReadCloser
+}
+
+func openRarStream(path string) (stream, error) {
+	r, err := rardecode.OpenReader(path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return rarStream{r}, nil
+}
+
+func (s rarStream) Next() (File, bool, error) {
+	h, err := s.ReadCloser.Next()
+	if err != nil {
+		return File{}, false, err
+	}
+
+	return File{
+		Name:    h.Name,
+		Size:    h.UnPackedSize,
+		ModTime: h.ModificationTime,
+	}, h.IsDir, nil
+}
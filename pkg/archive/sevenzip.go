@@ -0,0 +1,1115 @@
+package archive
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/ulikunitz/xz/lzma"
+)
+
+var sevenZipSignature = []byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}
+
+var sevenZipFormat = Format{
+	Name:       "7z",
+	Extensions: []string{"7z", "cb7"},
+	Match: func(header []byte) bool {
+		return bytes.HasPrefix(header, sevenZipSignature)
+	},
+	Open: open7z,
+}
+
+// 7z property ids
+const (
+	id7zEnd                   = 0x00
+	id7zHeader                = 0x01
+	id7zArchiveProperties     = 0x02
+	id7zAdditionalStreamsInfo = 0x03
+	id7zMainStreamsInfo       = 0x04
+	id7zFilesInfo             = 0x05
+	id7zPackInfo              = 0x06
+	id7zUnpackInfo            = 0x07
+	id7zSubStreamsInfo        = 0x08
+	id7zSize                  = 0x09
+	id7zCRC                   = 0x0a
+	id7zFolder                = 0x0b
+	id7zCodersUnpackSize      = 0x0c
+	id7zNumUnpackStream       = 0x0d
+	id7zEmptyStream           = 0x0e
+	id7zEmptyFile             = 0x0f
+	id7zName                  = 0x11
+	id7zMTime                 = 0x14
+	id7zEncodedHeader         = 0x17
+)
+
+// 7z coder ids
+const (
+	coder7zCopy    = "\x00"
+	coder7zLZMA    = "\x03\x01\x01"
+	coder7zLZMA2   = "\x21"
+	coder7zDeflate = "\x04\x01\x08"
+	coder7zBZip2   = "\x04\x02\x02"
+	coder7zAES     = "\x06\xf1\x07\x01"
+)
+
+// signatureHeaderSize is the size of the signature header at the start of
+// 7z archives. Offsets in the archive are from the end of this header.
+const signatureHeaderSize = 32
+
+// maxHeaderSize limits the memory used to read the headers of invalid
+// archives.
+const maxHeaderSize = 64 << 20
+
+var errInvalid7z = errors.New("invalid 7z archive")
+
+type sevenZipCoder struct {
+	id         string
+	properties []byte
+}
+
+type sevenZipBindPair struct {
+	in  int
+	out int
+}
+
+// sevenZipFolder is a block of coders that decode one or more packed streams
+// into the data of one or more files. Only coders with a single input and
+// output are supported.
+type sevenZipFolder struct {
+	coders        []sevenZipCoder
+	bindPairs     []sevenZipBindPair
+	packedStreams []int
+	unpackSizes   []int64
+
+	// packOffset is the offset of the first packed stream of the folder
+	packOffset int64
+	packSizes  []int64
+	// streamSizes are the sizes of the files in the folder
+	streamSizes []int64
+	// hasDigest is true if the unpacked data of the folder has a CRC
+	hasDigest bool
+}
+
+type sevenZipStreams struct {
+	packPos   int64
+	packSizes []int64
+	folders   []*sevenZipFolder
+}
+
+type sevenZipFile struct {
+	File
+	folder int
+	// offset is the offset of the file in the unpacked data of the folder
+	offset int64
+}
+
+// sevenZipReader reads 7z archives. Files in solid archives share a folder,
+// which is decoded from its start each time that a file is opened.
+type sevenZipReader struct {
+	path    string
+	f       *os.File
+	streams *sevenZipStreams
+	files   []sevenZipFile
+}
+
+func open7z(path string) (Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &sevenZipReader{
+		path: path,
+		f:    f,
+	}
+
+	if err := r.readHeaders(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error reading 7z archive %s: %w", path, err)
+	}
+
+	return r, nil
+}
+
+func (r *sevenZipReader) Files() []File {
+	var ret []File
+	for _, f := range r.files {
+		ret = append(ret, f.File)
+	}
+
+	return ret
+}
+
+func (r *sevenZipReader) Open(name string) (io.ReadCloser, error) {
+	for _, f := range r.files {
+		if f.Name != name {
+			continue
+		}
+
+		if f.folder == -1 {
+			return ioutil.NopCloser(bytes.NewReader(nil)), nil
+		}
+
+		folder, err := r.streams.decode(r.f, f.folder)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := io.CopyN(ioutil.Discard, folder, f.offset); err != nil {
+			return nil, err
+		}
+
+		return ioutil.NopCloser(io.LimitReader(folder, f.Size)), nil
+	}
+
+	return nil, errFileNotFound(name, r.path)
+}
+
+func (r *sevenZipReader) Close() error {
+	return r.f.Close()
+}
+
+func (r *sevenZipReader) readHeaders() error {
+	var sh [signatureHeaderSize]byte
+	if _, err := io.ReadFull(r.f, sh[:]); err != nil {
+		return errInvalid7z
+	}
+
+	if !bytes.HasPrefix(sh[:], sevenZipSignature) {
+		return errInvalid7z
+	}
+
+	offset := int64(binary.LittleEndian.Uint64(sh[12:20]))
+	size := int64(binary.LittleEndian.Uint64(sh[20:28]))
+	if offset < 0 || size <= 0 || size > maxHeaderSize {
+		return errInvalid7z
+	}
+
+	header := make([]byte, size)
+	if _, err := r.f.ReadAt(header, signatureHeaderSize+offset); err != nil {
+		return errInvalid7z
+	}
+
+	b := &sevenZipBuffer{data: header}
+	id, err := b.readByte()
+	if err != nil {
+		return err
+	}
+
+	// the header is usually compressed, and described by streams info
+	for id == id7zEncodedHeader {
+		streams, err := b.readStreamsInfo()
+		if err != nil {
+			return err
+		}
+
+		if len(streams.folders) == 0 {
+			return errInvalid7z
+		}
+
+		folder, err := streams.decode(r.f, 0)
+		if err != nil {
+			return err
+		}
+
+		unpackSize := streams.folders[0].unpackSize()
+		if unpackSize > maxHeaderSize {
+			return errInvalid7z
+		}
+
+		header = make([]byte, unpackSize)
+		if _, err := io.ReadFull(folder, header); err != nil {
+			return err
+		}
+
+		b = &sevenZipBuffer{data: header}
+		if id, err = b.readByte(); err != nil {
+			return err
+		}
+	}
+
+	if id != id7zHeader {
+		return errInvalid7z
+	}
+
+	return r.readHeader(b)
+}
+
+func (r *sevenZipReader) readHeader(b *sevenZipBuffer) error {
+	id, err := b.readByte()
+	if err != nil {
+		return err
+	}
+
+	if id == id7zArchiveProperties {
+		if err := b.skipProperties(); err != nil {
+			return err
+		}
+		if id, err = b.readByte(); err != nil {
+			return err
+		}
+	}
+
+	if id == id7zAdditionalStreamsInfo {
+		if _, err := b.readStreamsInfo(); err != nil {
+			return err
+		}
+		if id, err = b.readByte(); err != nil {
+			return err
+		}
+	}
+
+	r.streams = &sevenZipStreams{}
+	if id == id7zMainStreamsInfo {
+		if r.streams, err = b.readStreamsInfo(); err != nil {
+			return err
+		}
+		if id, err = b.readByte(); err != nil {
+			return err
+		}
+	}
+
+	if id == id7zFilesInfo {
+		if err := r.readFilesInfo(b); err != nil {
+			return err
+		}
+		if id, err = b.readByte(); err != nil {
+			return err
+		}
+	}
+
+	if id != id7zEnd {
+		return errInvalid7z
+	}
+
+	return nil
+}
+
+func (r *sevenZipReader) readFilesInfo(b *sevenZipBuffer) error {
+	numFiles, err := b.readCount()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, numFiles)
+	modTimes := make([]time.Time, numFiles)
+	emptyStream := make([]bool, numFiles)
+	var emptyFile []bool
+
+	for {
+		id, err := b.readByte()
+		if err != nil {
+			return err
+		}
+
+		if id == id7zEnd {
+			break
+		}
+
+		size, err := b.readCount()
+		if err != nil {
+			return err
+		}
+
+		data, err := b.read(size)
+		if err != nil {
+			return err
+		}
+		pb := &sevenZipBuffer{data: data}
+
+		switch id {
+		case id7zEmptyStream:
+			if emptyStream, err = pb.readBits(numFiles); err != nil {
+				return err
+			}
+		case id7zEmptyFile:
+			numEmpty := 0
+			for _, e := range emptyStream {
+				if e {
+					numEmpty++
+				}
+			}
+			if emptyFile, err = pb.readBits(numEmpty); err != nil {
+				return err
+			}
+		case id7zName:
+			if names, err = pb.readNames(numFiles); err != nil {
+				return err
+			}
+		case id7zMTime:
+			if modTimes, err = pb.readTimes(numFiles); err != nil {
+				return err
+			}
+		}
+	}
+
+	// files with data are the streams of the folders in order
+	folder := 0
+	stream := 0
+	var offset int64
+	empty := 0
+	for i := 0; i < numFiles; i++ {
+		f := sevenZipFile{
+			File: File{
+				// directory separators are '\' in archives created on
+				// Windows
+				Name:    strings.ReplaceAll(names[i], "\\", "/"),
+				ModTime: modTimes[i],
+			},
+			folder: -1,
+		}
+
+		if emptyStream[i] {
+			isFile := empty < len(emptyFile) && emptyFile[empty]
+			empty++
+
+			// empty streams that are not empty files are directories
+			if isFile {
+				r.files = append(r.files, f)
+			}
+			continue
+		}
+
+		for folder < len(r.streams.folders) && stream >= len(r.streams.folders[folder].streamSizes) {
+			folder++
+			stream = 0
+			offset = 0
+		}
+
+		if folder >= len(r.streams.folders) {
+			return errInvalid7z
+		}
+
+		f.folder = folder
+		f.offset = offset
+		f.Size = r.streams.folders[folder].streamSizes[stream]
+		offset += f.Size
+		stream++
+
+		r.files = append(r.files, f)
+	}
+
+	return nil
+}
+
+// unpackSize returns the size of the output of the folder, which is the
+// output of the coder that is not bound to the input of another coder.
+func (f *sevenZipFolder) unpackSize() int64 {
+	out := f.mainOutput()
+	if out < 0 || out >= len(f.unpackSizes) {
+		return 0
+	}
+
+	return f.unpackSizes[out]
+}
+
+func (f *sevenZipFolder) mainOutput() int {
+	for out := range f.coders {
+		bound := false
+		for _, bp := range f.bindPairs {
+			if bp.out == out {
+				bound = true
+				break
+			}
+		}
+
+		if !bound {
+			return out
+		}
+	}
+
+	return -1
+}
+
+// decode returns the output of the folder, reading the packed streams from
+// the archive file.
+func (s *sevenZipStreams) decode(ra io.ReaderAt, index int) (io.Reader, error) {
+	f := s.folders[index]
+	out := f.mainOutput()
+	if out < 0 {
+		return nil, errInvalid7z
+	}
+
+	return f.decodeOutput(ra, s.packPos, out)
+}
+
+// decodeOutput returns the output of the coder. Since each coder has one
+// input and output, the index of the coder is the index of its input and
+// output stream.
+func (f *sevenZipFolder) decodeOutput(ra io.ReaderAt, packPos int64, coder int) (io.Reader, error) {
+	if coder >= len(f.coders) || coder >= len(f.unpackSizes) {
+		return nil, errInvalid7z
+	}
+
+	var input io.Reader
+	for _, bp := range f.bindPairs {
+		if bp.in == coder {
+			var err error
+			if input, err = f.decodeOutput(ra, packPos, bp.out); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	if input == nil {
+		offset := signatureHeaderSize + packPos + f.packOffset
+		found := false
+		for i, in := range f.packedStreams {
+			if in == coder {
+				input = io.NewSectionReader(ra, offset, f.packSizes[i])
+				found = true
+				break
+			}
+			offset += f.packSizes[i]
+		}
+
+		if !found {
+			return nil, errInvalid7z
+		}
+	}
+
+	return newSevenZipDecoder(f.coders[coder], input, f.unpackSizes[coder])
+}
+
+func newSevenZipDecoder(c sevenZipCoder, r io.Reader, size int64) (io.Reader, error) {
+	switch c.id {
+	case coder7zCopy:
+		return r, nil
+	case coder7zLZMA:
+		if len(c.properties) != 5 {
+			return nil, errInvalid7z
+		}
+
+		// the lzma reader expects the header of .lzma files, which is the
+		// properties followed by the unpacked size
+		header := make([]byte, 13)
+		copy(header, c.properties)
+		binary.LittleEndian.PutUint64(header[5:], uint64(size))
+		return lzma.NewReader(io.MultiReader(bytes.NewReader(header), r))
+	case coder7zLZMA2:
+		if len(c.properties) != 1 || c.properties[0] > 40 {
+			return nil, errInvalid7z
+		}
+
+		p := uint(c.properties[0])
+		dictCap := (2 | p&1) << (p/2 + 11)
+		if p == 40 {
+			dictCap = 0xffffffff
+		}
+
+		config := lzma.Reader2Config{DictCap: int(dictCap)}
+		return config.NewReader2(r)
+	case coder7zDeflate:
+		return flate.NewReader(r), nil
+	case coder7zBZip2:
+		return bzip2.NewReader(r), nil
+	case coder7zAES:
+		return nil, errors.New("encrypted 7z archives are not supported")
+	}
+
+	return nil, fmt.Errorf("unsupported 7z coder %x", c.id)
+}
+
+// sevenZipBuffer reads the values of 7z headers.
+type sevenZipBuffer struct {
+	data []byte
+	pos  int
+}
+
+func (b *sevenZipBuffer) readByte() (byte, error) {
+	if b.pos >= len(b.data) {
+		return 0, errInvalid7z
+	}
+
+	ret := b.data[b.pos]
+	b.pos++
+	return ret, nil
+}
+
+func (b *sevenZipBuffer) read(n int) ([]byte, error) {
+	if n < 0 || n > len(b.data)-b.pos {
+		return nil, errInvalid7z
+	}
+
+	ret := b.data[b.pos : b.pos+n]
+	b.pos += n
+	return ret, nil
+}
+
+// readNumber reads a variable length number. The number of leading one bits
+// of the first byte is the number of bytes that follow it.
+func (b *sevenZipBuffer) readNumber() (uint64, error) {
+	first, err := b.readByte()
+	if err != nil {
+		return 0, err
+	}
+
+	var ret uint64
+	mask := byte(0x80)
+	for i := 0; i < 8; i++ {
+		if first&mask == 0 {
+			high := uint64(first & (mask - 1))
+			return ret | high<<(8*i), nil
+		}
+
+		next, err := b.readByte()
+		if err != nil {
+			return 0, err
+		}
+		ret |= uint64(next) << (8 * i)
+		mask >>= 1
+	}
+
+	return ret, nil
+}
+
+// readCount reads a number that is used as a count or size, which cannot be
+// larger than the header.
+func (b *sevenZipBuffer) readCount() (int, error) {
+	n, err := b.readNumber()
+	if err != nil {
+		return 0, err
+	}
+
+	if n > uint64(len(b.data)) {
+		return 0, errInvalid7z
+	}
+
+	return int(n), nil
+}
+
+func (b *sevenZipBuffer) readSize() (int64, error) {
+	n, err := b.readNumber()
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 1<<62 {
+		return 0, errInvalid7z
+	}
+
+	return int64(n), nil
+}
+
+func (b *sevenZipBuffer) readUint32() (uint32, error) {
+	data, err := b.read(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(data), nil
+}
+
+func (b *sevenZipBuffer) readUint64() (uint64, error) {
+	data, err := b.read(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(data), nil
+}
+
+// readBits reads a bit vector, with the most significant bit first.
+func (b *sevenZipBuffer) readBits(n int) ([]bool, error) {
+	data, err := b.read((n + 7) / 8)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]bool, n)
+	for i := range ret {
+		ret[i] = data[i/8]&(0x80>>(i%8)) != 0
+	}
+
+	return ret, nil
+}
+
+// readDefined reads the vector of the values that are defined, which starts
+// with a byte that is set if all values are defined.
+func (b *sevenZipBuffer) readDefined(n int) ([]bool, error) {
+	all, err := b.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if all == 0 {
+		return b.readBits(n)
+	}
+
+	ret := make([]bool, n)
+	for i := range ret {
+		ret[i] = true
+	}
+
+	return ret, nil
+}
+
+// skipDigests skips the CRCs of n values, returning the values that have a
+// CRC.
+func (b *sevenZipBuffer) skipDigests(n int) ([]bool, error) {
+	defined, err := b.readDefined(n)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range defined {
+		if d {
+			if _, err := b.readUint32(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return defined, nil
+}
+
+func (b *sevenZipBuffer) skipProperties() error {
+	for {
+		id, err := b.readByte()
+		if err != nil {
+			return err
+		}
+
+		if id == id7zEnd {
+			return nil
+		}
+
+		size, err := b.readCount()
+		if err != nil {
+			return err
+		}
+
+		if _, err := b.read(size); err != nil {
+			return err
+		}
+	}
+}
+
+// readExternal reads the byte that is set if the values are in another
+// stream, which is not supported.
+func (b *sevenZipBuffer) readExternal() error {
+	external, err := b.readByte()
+	if err != nil {
+		return err
+	}
+
+	if external != 0 {
+		return errors.New("external 7z headers are not supported")
+	}
+
+	return nil
+}
+
+// readNames reads the null-terminated UTF-16 names of the files.
+func (b *sevenZipBuffer) readNames(n int) ([]string, error) {
+	if err := b.readExternal(); err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, n)
+	for i := range ret {
+		var name []uint16
+		for {
+			data, err := b.read(2)
+			if err != nil {
+				return nil, err
+			}
+
+			c := binary.LittleEndian.Uint16(data)
+			if c == 0 {
+				break
+			}
+			name = append(name, c)
+		}
+
+		ret[i] = string(utf16.Decode(name))
+	}
+
+	return ret, nil
+}
+
+// readTimes reads Windows file times, which are 100 nanosecond intervals
+// since 1601.
+func (b *sevenZipBuffer) readTimes(n int) ([]time.Time, error) {
+	defined, err := b.readDefined(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.readExternal(); err != nil {
+		return nil, err
+	}
+
+	const epochDiff = 116444736000000000
+	ret := make([]time.Time, n)
+	for i, d := range defined {
+		if !d {
+			continue
+		}
+
+		t, err := b.readUint64()
+		if err != nil {
+			return nil, err
+		}
+
+		ret[i] = time.Unix(0, (int64(t)-epochDiff)*100)
+	}
+
+	return ret, nil
+}
+
+func (b *sevenZipBuffer) readStreamsInfo() (*sevenZipStreams, error) {
+	s := &sevenZipStreams{}
+
+	for {
+		id, err := b.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch id {
+		case id7zEnd:
+			return s, s.assignPackedStreams()
+		case id7zPackInfo:
+			err = b.readPackInfo(s)
+		case id7zUnpackInfo:
+			err = b.readUnpackInfo(s)
+		case id7zSubStreamsInfo:
+			err = b.readSubStreamsInfo(s)
+		default:
+			err = errInvalid7z
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (b *sevenZipBuffer) readPackInfo(s *sevenZipStreams) error {
+	var err error
+	if s.packPos, err = b.readSize(); err != nil {
+		return err
+	}
+
+	numPackStreams, err := b.readCount()
+	if err != nil {
+		return err
+	}
+
+	for {
+		id, err := b.readByte()
+		if err != nil {
+			return err
+		}
+
+		switch id {
+		case id7zEnd:
+			if len(s.packSizes) != numPackStreams {
+				return errInvalid7z
+			}
+			return nil
+		case id7zSize:
+			s.packSizes = make([]int64, numPackStreams)
+			for i := range s.packSizes {
+				if s.packSizes[i], err = b.readSize(); err != nil {
+					return err
+				}
+			}
+		case id7zCRC:
+			if _, err := b.skipDigests(numPackStreams); err != nil {
+				return err
+			}
+		default:
+			return errInvalid7z
+		}
+	}
+}
+
+func (b *sevenZipBuffer) readFolder() (*sevenZipFolder, error) {
+	numCoders, err := b.readCount()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &sevenZipFolder{}
+	for i := 0; i < numCoders; i++ {
+		flags, err := b.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := b.read(int(flags & 0x0f))
+		if err != nil {
+			return nil, err
+		}
+
+		// coders with more than one input or output, such as BCJ2
+		if flags&0x10 != 0 {
+			numIn, err := b.readNumber()
+			if err != nil {
+				return nil, err
+			}
+			numOut, err := b.readNumber()
+			if err != nil {
+				return nil, err
+			}
+
+			if numIn != 1 || numOut != 1 {
+				return nil, fmt.Errorf("unsupported 7z coder %x", id)
+			}
+		}
+
+		c := sevenZipCoder{id: string(id)}
+		if flags&0x20 != 0 {
+			size, err := b.readCount()
+			if err != nil {
+				return nil, err
+			}
+			if c.properties, err = b.read(size); err != nil {
+				return nil, err
+			}
+		}
+
+		if flags&0x80 != 0 {
+			return nil, errors.New("alternative 7z coders are not supported")
+		}
+
+		f.coders = append(f.coders, c)
+	}
+
+	for i := 0; i < numCoders-1; i++ {
+		in, err := b.readCount()
+		if err != nil {
+			return nil, err
+		}
+		out, err := b.readCount()
+		if err != nil {
+			return nil, err
+		}
+
+		f.bindPairs = append(f.bindPairs, sevenZipBindPair{in: in, out: out})
+	}
+
+	numPacked := numCoders - len(f.bindPairs)
+	if numPacked == 1 {
+		// the packed stream is the input that is not bound
+		for in := 0; in < numCoders; in++ {
+			bound := false
+			for _, bp := range f.bindPairs {
+				if bp.in == in {
+					bound = true
+					break
+				}
+			}
+
+			if !bound {
+				f.packedStreams = append(f.packedStreams, in)
+				break
+			}
+		}
+	} else {
+		for i := 0; i < numPacked; i++ {
+			in, err := b.readCount()
+			if err != nil {
+				return nil, err
+			}
+			f.packedStreams = append(f.packedStreams, in)
+		}
+	}
+
+	if len(f.packedStreams) != numPacked {
+		return nil, errInvalid7z
+	}
+
+	return f, nil
+}
+
+func (b *sevenZipBuffer) readUnpackInfo(s *sevenZipStreams) error {
+	id, err := b.readByte()
+	if err != nil {
+		return err
+	}
+	if id != id7zFolder {
+		return errInvalid7z
+	}
+
+	numFolders, err := b.readCount()
+	if err != nil {
+		return err
+	}
+
+	if err := b.readExternal(); err != nil {
+		return err
+	}
+
+	s.folders = make([]*sevenZipFolder, numFolders)
+	for i := range s.folders {
+		if s.folders[i], err = b.readFolder(); err != nil {
+			return err
+		}
+	}
+
+	if id, err = b.readByte(); err != nil {
+		return err
+	}
+	if id != id7zCodersUnpackSize {
+		return errInvalid7z
+	}
+
+	for _, f := range s.folders {
+		f.unpackSizes = make([]int64, len(f.coders))
+		for i := range f.unpackSizes {
+			if f.unpackSizes[i], err = b.readSize(); err != nil {
+				return err
+			}
+		}
+
+		// each folder has one file unless there is substreams info
+		f.streamSizes = []int64{f.unpackSize()}
+	}
+
+	for {
+		id, err := b.readByte()
+		if err != nil {
+			return err
+		}
+
+		switch id {
+		case id7zEnd:
+			return nil
+		case id7zCRC:
+			defined, err := b.skipDigests(numFolders)
+			if err != nil {
+				return err
+			}
+
+			for i, f := range s.folders {
+				f.hasDigest = defined[i]
+			}
+		default:
+			return errInvalid7z
+		}
+	}
+}
+
+func (b *sevenZipBuffer) readSubStreamsInfo(s *sevenZipStreams) error {
+	numStreams := make([]int, len(s.folders))
+	for i := range numStreams {
+		numStreams[i] = 1
+	}
+
+	id, err := b.readByte()
+	if err != nil {
+		return err
+	}
+
+	if id == id7zNumUnpackStream {
+		for i := range numStreams {
+			if numStreams[i], err = b.readCount(); err != nil {
+				return err
+			}
+		}
+		if id, err = b.readByte(); err != nil {
+			return err
+		}
+	}
+
+	// the size of the last stream of each folder is the rest of the folder
+	hasSizes := id == id7zSize
+	for i, f := range s.folders {
+		if numStreams[i] == 0 {
+			f.streamSizes = nil
+			continue
+		}
+
+		f.streamSizes = make([]int64, numStreams[i])
+		remaining := f.unpackSize()
+		for j := 0; j < numStreams[i]-1; j++ {
+			if !hasSizes {
+				return errInvalid7z
+			}
+
+			size, err := b.readSize()
+			if err != nil {
+				return err
+			}
+			if size > remaining {
+				return errInvalid7z
+			}
+
+			f.streamSizes[j] = size
+			remaining -= size
+		}
+		f.streamSizes[numStreams[i]-1] = remaining
+	}
+
+	if hasSizes {
+		if id, err = b.readByte(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		switch id {
+		case id7zEnd:
+			return nil
+		case id7zCRC:
+			if err := b.skipSubStreamDigests(s, numStreams); err != nil {
+				return err
+			}
+		default:
+			return errInvalid7z
+		}
+
+		if id, err = b.readByte(); err != nil {
+			return err
+		}
+	}
+}
+
+// skipSubStreamDigests skips the digests of the streams. The digest of the
+// single stream of a folder is the digest of the folder, if it has one.
+func (b *sevenZipBuffer) skipSubStreamDigests(s *sevenZipStreams, numStreams []int) error {
+	n := 0
+	for i, f := range s.folders {
+		if numStreams[i] != 1 || !f.hasDigest {
+			n += numStreams[i]
+		}
+	}
+
+	_, err := b.skipDigests(n)
+	return err
+}
+
+// assignPackedStreams sets the packed streams of each folder, which follow
+// the packed streams of the previous folders.
+func (s *sevenZipStreams) assignPackedStreams() error {
+	var offset int64
+	index := 0
+	for _, f := range s.folders {
+		n := len(f.packedStreams)
+		if index+n > len(s.packSizes) {
+			return errInvalid7z
+		}
+
+		f.packOffset = offset
+		f.packSizes = s.packSizes[index : index+n]
+		for _, size := range f.packSizes {
+			offset += size
+		}
+		index += n
+	}
+
+	return nil
+}
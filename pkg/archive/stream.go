@@ -0,0 +1,262 @@
+package archive
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// stream reads the files of an archive that can only be read in order.
+type stream interface {
+	// Next advances to the next file or directory, returning io.EOF at the
+	// end of the archive.
+	Next() (f File, isDir bool, err error)
+	// Read reads the current file.
+	Read(p []byte) (int, error)
+	Close() error
+}
+
+type openStreamFunc func(path string) (stream, error)
+
+// maxStreamReaders is the number of stream readers that are kept open.
+const maxStreamReaders = 8
+
+// maxBufferedFileSize is the size of the largest file that is kept in
+// memory after it is read. Larger files are read from a separate stream.
+const maxBufferedFileSize = 64 << 20
+
+// streamReaders are the readers of the most recently opened stream
+// archives, most recent first. Opening the same archive again returns the
+// same reader, so that the archive is only listed once, and the files of
+// the archive can be read in order without reading the archive from the
+// start for each file.
+var streamReaders struct {
+	sync.Mutex
+	readers []*streamReader
+}
+
+// streamReader reads archives that can only be read in order. The files are
+// listed when the archive is opened. The archive is kept open at the last
+// file that was read, so that reading the files in archive order, as the
+// scan does, decompresses the archive only once. The last file is kept in
+// memory, since it is usually read more than once.
+type streamReader struct {
+	path    string
+	modTime time.Time
+	size    int64
+	files   []File
+	open    openStreamFunc
+
+	mutex sync.Mutex
+	// s is positioned at the file with the index pos in files
+	s        stream
+	pos      int
+	last     int
+	lastData []byte
+}
+
+func openStreamReader(path string, open openStreamFunc) (Reader, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if r := getStreamReader(path, info); r != nil {
+		return r, nil
+	}
+
+	r := &streamReader{
+		path:    path,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		open:    open,
+		pos:     -1,
+		last:    -1,
+	}
+
+	if err := r.list(); err != nil {
+		return nil, err
+	}
+
+	addStreamReader(r)
+	return r, nil
+}
+
+// getStreamReader returns the open reader of the archive at path, if the
+// archive has not changed since it was opened.
+func getStreamReader(path string, info os.FileInfo) *streamReader {
+	streamReaders.Lock()
+	defer streamReaders.Unlock()
+
+	for i, r := range streamReaders.readers {
+		if r.path != path {
+			continue
+		}
+
+		if !r.modTime.Equal(info.ModTime()) || r.size != info.Size() {
+			removeStreamReader(i)
+			return nil
+		}
+
+		// move to the front
+		copy(streamReaders.readers[1:i+1], streamReaders.readers[:i])
+		streamReaders.readers[0] = r
+		return r
+	}
+
+	return nil
+}
+
+func addStreamReader(r *streamReader) {
+	streamReaders.Lock()
+	defer streamReaders.Unlock()
+
+	// another reader of the archive may have been opened at the same time
+	for i, existing := range streamReaders.readers {
+		if existing.path == r.path {
+			removeStreamReader(i)
+			break
+		}
+	}
+
+	streamReaders.readers = append([]*streamReader{r}, streamReaders.readers...)
+	if len(streamReaders.readers) > maxStreamReaders {
+		removeStreamReader(maxStreamReaders)
+	}
+}
+
+// removeStreamReader closes and removes the reader with the index i. The
+// lock must be held.
+func removeStreamReader(i int) {
+	r := streamReaders.readers[i]
+	streamReaders.readers = append(streamReaders.readers[:i], streamReaders.readers[i+1:]...)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.reset()
+}
+
+func (r *streamReader) list() error {
+	s, err := r.open(r.path)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	for {
+		f, isDir, err := s.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !isDir {
+			r.files = append(r.files, f)
+		}
+	}
+}
+
+func (r *streamReader) Files() []File {
+	return r.files
+}
+
+func (r *streamReader) Open(name string) (io.ReadCloser, error) {
+	index := -1
+	for i, f := range r.files {
+		if f.Name == name {
+			index = i
+			break
+		}
+	}
+
+	if index == -1 {
+		return nil, errFileNotFound(name, r.path)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if index == r.last {
+		return ioutil.NopCloser(bytes.NewReader(r.lastData)), nil
+	}
+
+	if r.s == nil || index <= r.pos {
+		r.reset()
+
+		s, err := r.open(r.path)
+		if err != nil {
+			return nil, err
+		}
+		r.s = s
+	}
+
+	if err := r.seek(index); err != nil {
+		r.reset()
+		return nil, err
+	}
+
+	if r.files[index].Size > maxBufferedFileSize {
+		// the caller reads the file from the stream, so the next file is
+		// read from a new stream
+		s := r.s
+		r.s = nil
+		r.pos = -1
+		return &readCloser{
+			Reader: s,
+			closer: s,
+		}, nil
+	}
+
+	data, err := ioutil.ReadAll(r.s)
+	if err != nil {
+		r.reset()
+		return nil, err
+	}
+
+	r.last = index
+	r.lastData = data
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// seek advances the stream to the file with the index in files.
+func (r *streamReader) seek(index int) error {
+	for r.pos < index {
+		_, isDir, err := r.s.Next()
+		if err == io.EOF {
+			return errFileNotFound(r.files[index].Name, r.path)
+		}
+		if err != nil {
+			return err
+		}
+
+		if !isDir {
+			r.pos++
+		}
+	}
+
+	return nil
+}
+
+// reset closes the stream and discards the last file. The mutex must be
+// held.
+func (r *streamReader) reset() {
+	if r.s != nil {
+		r.s.Close()
+		r.s = nil
+	}
+	r.pos = -1
+	r.last = -1
+	r.lastData = nil
+}
+
+// Close does nothing, since the reader is shared by the callers that open
+// the archive. The archive is closed when the reader is replaced by the
+// readers of other archives.
+func (r *streamReader) Close() error {
+	return nil
+}
@@ -0,0 +1,60 @@
+package archive
+
+import (
+	"archive/tar"
+	"os"
+)
+
+var tarFormat = Format{
+	Name:       "tar",
+	Extensions: []string{"tar", "cbt"},
+	Match: func(header []byte) bool {
+		// POSIX and GNU tar archives have the magic at offset 257. Older
+		// archives are matched by extension.
+		return len(header) >= 262 && string(header[257:262]) == "ustar"
+	},
+	Open: func(path string) (Reader, error) {
+		return openStreamReader(path, openTarStream)
+	},
+}
+
+type tarStream struct {
+	f *os.File
+	r *tar.Reader
+}
+
+func openTarStream(path string) (stream, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tarStream{
+		f: f,
+		r: tar.NewReader(f),
+	}, nil
+}
+
+func (s *tarStream) Next() (File, bool, error) {
+	h, err := s.r.Next()
+	if err != nil {
+		return File{}, false, err
+	}
+
+	// links and other special files are skipped like directories
+	isFile := h.Typeflag == tar.TypeReg || h.Typeflag == tar.TypeRegA
+
+	return File{
+		Name:    h.Name,
+		Size:    h.Size,
+		ModTime: h.ModTime,
+	}, !isFile, nil
+}
+
+func (s *tarStream) Read(p []byte) (int, error) {
+	return s.r.Read(p)
+}
+
+func (s *tarStream) Close() error {
+	return s.f.Close()
+}
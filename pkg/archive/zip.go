@@ -0,0 +1,69 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+)
+
+var zipFormat = Format{
+	Name:       "zip",
+	Extensions: []string{"zip", "cbz"},
+	Match: func(header []byte) bool {
+		return bytes.HasPrefix(header, []byte("PK\x03\x04")) || bytes.HasPrefix(header, []byte("PK\x05\x06"))
+	},
+	Open: openZip,
+}
+
+// zipReader keeps the zip file open until it is closed, since the zip
+// directory has the offsets of the files.
+type zipReader struct {
+	path string
+	r    *zip.ReadCloser
+}
+
+func openZip(path string) (Reader, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zipReader{
+		path: path,
+		r:    r,
+	}, nil
+}
+
+func (z *zipReader) Files() []File {
+	var ret []File
+	for _, f := range z.r.File {
+		fi := f.FileInfo()
+		if fi.IsDir() {
+			continue
+		}
+
+		ret = append(ret, File{
+			Name:    f.Name,
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+		})
+	}
+
+	return ret
+}
+
+func (z *zipReader) Open(name string) (io.ReadCloser, error) {
+	// zip files may have more than one file with the name, so the first
+	// is used
+	for _, f := range z.r.File {
+		if f.Name == name {
+			return f.Open()
+		}
+	}
+
+	return nil, errFileNotFound(name, z.path)
+}
+
+func (z *zipReader) Close() error {
+	return z.r.Close()
+}
@@ -23,7 +23,7 @@ import (
 var DB *sqlx.DB
 var WriteMu *sync.Mutex
 var dbPath string
-var appSchemaVersion uint = 27
+var appSchemaVersion uint = 41
 var databaseSchemaVersion uint
 
 var (
@@ -34,6 +34,10 @@ var (
 	// ErrDatabaseNotInitialized indicates that the database is not
 	// initialized, usually due to an incomplete configuration.
 	ErrDatabaseNotInitialized = errors.New("database not initialized")
+
+	// ErrFullTextSearchUnavailable indicates that the SQLite library was
+	// built without the FTS5 extension, which the search index requires.
+	ErrFullTextSearchUnavailable = errors.New("SQLite full-text search (FTS5) is not available: stash must be built with the sqlite_fts5 build tag")
 )
 
 const sqlite3Driver = "sqlite3ex"
@@ -59,6 +63,10 @@ func init() {
 func Initialize(databasePath string) error {
 	dbPath = databasePath
 
+	if err := checkFullTextSearch(); err != nil {
+		return err
+	}
+
 	if err := getDatabaseSchemaVersion(); err != nil {
 		return fmt.Errorf("error getting database schema version: %s", err.Error())
 	}
@@ -89,6 +97,27 @@ func Initialize(databasePath string) error {
 	return nil
 }
 
+// checkFullTextSearch returns ErrFullTextSearchUnavailable if the FTS5
+// extension is not compiled into the SQLite library.
+func checkFullTextSearch() error {
+	conn, err := sql.Open(sqlite3Driver, ":memory:")
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var enabled bool
+	if err := conn.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled); err != nil {
+		return fmt.Errorf("error checking for SQLite full-text search: %s", err.Error())
+	}
+
+	if !enabled {
+		return ErrFullTextSearchUnavailable
+	}
+
+	return nil
+}
+
 func Close() error {
 	WriteMu.Lock()
 	defer WriteMu.Unlock()
@@ -0,0 +1,23 @@
+CREATE TABLE `job_history` (
+  `id` integer not null primary key autoincrement,
+  `description` varchar(255) not null,
+  `status` varchar(255) not null,
+  `error` text,
+  `processed` integer not null default 0,
+  `total` integer not null default 0,
+  `add_time` datetime not null,
+  `start_time` datetime,
+  `end_time` datetime not null
+);
+
+CREATE INDEX `index_job_history_on_end_time` on `job_history` (`end_time`);
+
+CREATE TABLE `job_history_logs` (
+  `job_id` integer not null,
+  `time` datetime not null,
+  `level` varchar(255) not null,
+  `message` text not null,
+  foreign key(`job_id`) references `job_history`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_job_history_logs_on_job_id` on `job_history_logs` (`job_id`);
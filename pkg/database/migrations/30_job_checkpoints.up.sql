@@ -0,0 +1,8 @@
+CREATE TABLE `job_checkpoints` (
+  `key` varchar(255) not null,
+  `item` text not null,
+  `time` datetime not null,
+  primary key (`key`, `item`)
+);
+
+CREATE INDEX `index_job_checkpoints_on_time` on `job_checkpoints` (`time`);
@@ -0,0 +1,12 @@
+CREATE TABLE `users` (
+  `id` integer not null primary key autoincrement,
+  `username` varchar(255) not null,
+  `password` varchar(255) not null,
+  `role` varchar(255) not null,
+  `api_key` text,
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
+
+CREATE UNIQUE INDEX `index_users_on_username` on `users` (`username`);
+CREATE UNIQUE INDEX `index_users_on_api_key` on `users` (`api_key`);
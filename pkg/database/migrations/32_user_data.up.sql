@@ -0,0 +1,179 @@
+-- user_id is 0 for values set while authentication is not enabled
+CREATE TABLE `scenes_users` (
+  `scene_id` integer not null,
+  `user_id` integer not null,
+  `rating` tinyint,
+  `o_counter` tinyint not null default 0,
+  `watched` boolean not null default '0',
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `user_id`)
+);
+
+CREATE INDEX `index_scenes_users_on_user_id` on `scenes_users` (`user_id`);
+
+CREATE TABLE `images_users` (
+  `image_id` integer not null,
+  `user_id` integer not null,
+  `rating` tinyint,
+  `o_counter` tinyint not null default 0,
+  `watched` boolean not null default '0',
+  foreign key(`image_id`) references `images`(`id`) on delete CASCADE,
+  PRIMARY KEY(`image_id`, `user_id`)
+);
+
+CREATE INDEX `index_images_users_on_user_id` on `images_users` (`user_id`);
+
+-- existing values are assigned to no user. They are moved to the admin user
+-- when the credentials in the configuration file are migrated.
+INSERT INTO `scenes_users` (`scene_id`, `user_id`, `rating`, `o_counter`)
+  SELECT `id`, 0, `rating`, `o_counter`
+  FROM `scenes`
+  WHERE `rating` IS NOT NULL OR `o_counter` > 0;
+
+INSERT INTO `images_users` (`image_id`, `user_id`, `rating`, `o_counter`)
+  SELECT `id`, 0, `rating`, `o_counter`
+  FROM `images`
+  WHERE `rating` IS NOT NULL OR `o_counter` > 0;
+
+-- remove the rating and o_counter columns from scenes and images
+CREATE TABLE `scenes_new` (
+  `id` integer not null primary key autoincrement,
+  `path` varchar(510) not null,
+  `checksum` varchar(255),
+  `oshash` varchar(255),
+  `title` varchar(255),
+  `details` text,
+  `url` varchar(255),
+  `date` date,
+  `size` varchar(255),
+  `duration` float,
+  `video_codec` varchar(255),
+  `audio_codec` varchar(255),
+  `width` tinyint,
+  `height` tinyint,
+  `framerate` float,
+  `bitrate` integer,
+  `studio_id` integer,
+  `format` varchar(255),
+  `created_at` datetime not null,
+  `updated_at` datetime not null,
+  `file_mod_time` datetime,
+  `organized` boolean not null default '0',
+  `phash` blob,
+  `interactive` boolean not null default '0',
+  foreign key(`studio_id`) references `studios`(`id`) on delete SET NULL,
+  CHECK (`checksum` is not null or `oshash` is not null)
+);
+
+INSERT INTO `scenes_new`
+  (
+    `id`,
+    `path`,
+    `checksum`,
+    `oshash`,
+    `title`,
+    `details`,
+    `url`,
+    `date`,
+    `size`,
+    `duration`,
+    `video_codec`,
+    `audio_codec`,
+    `width`,
+    `height`,
+    `framerate`,
+    `bitrate`,
+    `studio_id`,
+    `format`,
+    `created_at`,
+    `updated_at`,
+    `file_mod_time`,
+    `organized`,
+    `phash`,
+    `interactive`
+  )
+  SELECT
+    `id`,
+    `path`,
+    `checksum`,
+    `oshash`,
+    `title`,
+    `details`,
+    `url`,
+    `date`,
+    `size`,
+    `duration`,
+    `video_codec`,
+    `audio_codec`,
+    `width`,
+    `height`,
+    `framerate`,
+    `bitrate`,
+    `studio_id`,
+    `format`,
+    `created_at`,
+    `updated_at`,
+    `file_mod_time`,
+    `organized`,
+    `phash`,
+    `interactive`
+  FROM `scenes`;
+
+DROP TABLE `scenes`;
+ALTER TABLE `scenes_new` rename to `scenes`;
+
+CREATE UNIQUE INDEX `scenes_path_unique` on `scenes` (`path`);
+CREATE UNIQUE INDEX `scenes_checksum_unique` on `scenes` (`checksum`);
+CREATE UNIQUE INDEX `scenes_oshash_unique` on `scenes` (`oshash`);
+CREATE INDEX `index_scenes_on_studio_id` on `scenes` (`studio_id`);
+
+CREATE TABLE `images_new` (
+  `id` integer not null primary key autoincrement,
+  `path` varchar(510) not null,
+  `checksum` varchar(255) not null,
+  `title` varchar(255),
+  `size` integer,
+  `width` tinyint,
+  `height` tinyint,
+  `studio_id` integer,
+  `created_at` datetime not null,
+  `updated_at` datetime not null,
+  `file_mod_time` datetime,
+  `organized` boolean not null default '0',
+  foreign key(`studio_id`) references `studios`(`id`) on delete SET NULL
+);
+
+INSERT INTO `images_new`
+  (
+    `id`,
+    `path`,
+    `checksum`,
+    `title`,
+    `size`,
+    `width`,
+    `height`,
+    `studio_id`,
+    `created_at`,
+    `updated_at`,
+    `file_mod_time`,
+    `organized`
+  )
+  SELECT
+    `id`,
+    `path`,
+    `checksum`,
+    `title`,
+    `size`,
+    `width`,
+    `height`,
+    `studio_id`,
+    `created_at`,
+    `updated_at`,
+    `file_mod_time`,
+    `organized`
+  FROM `images`;
+
+DROP TABLE `images`;
+ALTER TABLE `images_new` rename to `images`;
+
+CREATE INDEX `index_images_on_studio_id` on `images` (`studio_id`);
@@ -0,0 +1,4 @@
+ALTER TABLE `scenes_users` ADD COLUMN `play_count` integer not null default 0;
+ALTER TABLE `scenes_users` ADD COLUMN `play_duration` float not null default 0;
+ALTER TABLE `scenes_users` ADD COLUMN `last_played_at` datetime;
+ALTER TABLE `scenes_users` ADD COLUMN `resume_time` float not null default 0;
@@ -0,0 +1,37 @@
+-- the rowid of each document is the id of the object shifted left by three
+-- bits, with the object type in the low bits:
+-- 1 = scene, 2 = performer, 3 = studio, 4 = tag
+CREATE VIRTUAL TABLE `search_index` USING fts5(
+  `name`,
+  `aliases`,
+  `details`,
+  `path`,
+  `markers`,
+  tokenize = 'porter unicode61 remove_diacritics 2'
+);
+
+INSERT INTO `search_index` (`rowid`, `name`, `details`, `path`, `markers`)
+  SELECT
+    (`id` << 3) | 1,
+    `title`,
+    `details`,
+    `path`,
+    (SELECT group_concat(`title`, ' ') FROM `scene_markers` WHERE `scene_markers`.`scene_id` = `scenes`.`id`)
+  FROM `scenes`;
+
+INSERT INTO `search_index` (`rowid`, `name`, `aliases`)
+  SELECT (`id` << 3) | 2, `name`, `aliases` FROM `performers`;
+
+INSERT INTO `search_index` (`rowid`, `name`, `aliases`)
+  SELECT
+    (`id` << 3) | 3,
+    `name`,
+    (SELECT group_concat(`alias`, ' ') FROM `studio_aliases` WHERE `studio_aliases`.`studio_id` = `studios`.`id`)
+  FROM `studios`;
+
+INSERT INTO `search_index` (`rowid`, `name`, `aliases`)
+  SELECT
+    (`id` << 3) | 4,
+    `name`,
+    (SELECT group_concat(`alias`, ' ') FROM `tag_aliases` WHERE `tag_aliases`.`tag_id` = `tags`.`id`)
+  FROM `tags`;
@@ -0,0 +1,11 @@
+CREATE TABLE `scene_captions` (
+  `file_id` integer not null,
+  `language_code` varchar(255) not null,
+  `caption_type` varchar(255) not null,
+  `path` varchar(510),
+  `stream_index` integer,
+  foreign key(`file_id`) references `scene_files`(`id`) on delete CASCADE,
+  CHECK (`path` is not null or `stream_index` is not null)
+);
+
+CREATE INDEX `index_scene_captions_on_file_id` on `scene_captions` (`file_id`);
@@ -0,0 +1,13 @@
+CREATE TABLE `scene_tracks` (
+  `file_id` integer not null,
+  `stream_index` integer not null,
+  `track_type` varchar(255) not null,
+  `codec` varchar(255) not null default '',
+  `language` varchar(255) not null default '',
+  `title` varchar(255) not null default '',
+  `channels` integer not null default 0,
+  `is_default` boolean not null default '0',
+  foreign key(`file_id`) references `scene_files`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_scene_tracks_on_file_id` on `scene_tracks` (`file_id`);
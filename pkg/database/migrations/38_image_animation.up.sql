@@ -0,0 +1,3 @@
+ALTER TABLE `images` ADD COLUMN `duration` float;
+ALTER TABLE `images` ADD COLUMN `is_animated` boolean not null default '0';
+ALTER TABLE `images` ADD COLUMN `is_video` boolean not null default '0';
@@ -0,0 +1,2 @@
+ALTER TABLE `galleries_images` ADD COLUMN `position` integer;
+ALTER TABLE `galleries` ADD COLUMN `cover_image_id` integer REFERENCES `images`(`id`) ON DELETE SET NULL;
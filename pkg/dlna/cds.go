@@ -39,6 +39,7 @@ import (
 	"github.com/anacrolix/dms/upnpav"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -76,7 +77,7 @@ func (me *contentDirectoryService) updateIDString() string {
 	return fmt.Sprintf("%d", uint32(os.Getpid()))
 }
 
-func sceneToContainer(scene *models.Scene, parent string, host string) interface{} {
+func sceneToContainer(scene *models.Scene, captions []*models.SceneCaption, parent string, host string) interface{} {
 	// make stash server URL
 	// TODO - fix this
 	iconURI := (&url.URL{
@@ -135,6 +136,22 @@ func sceneToContainer(scene *models.Scene, parent string, host string) interface
 		ProtocolInfo: "http-get:*:image/jpeg:DLNA.ORG_PN=JPEG_MED",
 	})
 
+	for _, c := range captions {
+		item.Res = append(item.Res, upnpav.Resource{
+			URL: (&url.URL{
+				Scheme: "http",
+				Host:   host,
+				Path:   captionPath,
+				RawQuery: url.Values{
+					"scene": {strconv.Itoa(scene.ID)},
+					"lang":  {c.LanguageCode},
+					"type":  {c.CaptionType},
+				}.Encode(),
+			}).String(),
+			ProtocolInfo: "http-get:*:text/vtt:*",
+		})
+	}
+
 	return item
 }
 
@@ -240,7 +257,7 @@ func (me *contentDirectoryService) handleBrowseDirectChildren(obj object, host s
 	if strings.HasPrefix(obj.Path, "all/") {
 		page := getPageFromID(paths)
 		if page != nil {
-			objs = me.getPageVideos(&models.SceneFilterType{}, "all", *page, host)
+			objs = me.getPageVideos(&models.SceneFilterType{}, titleSort, "all", *page, host)
 		}
 	}
 
@@ -327,6 +344,11 @@ func (me *contentDirectoryService) handleBrowseDirectChildren(obj object, host s
 		objs = me.getRatingScenes(childPath(paths), host)
 	}
 
+	// Recently played
+	if obj.Path == "recent" || strings.HasPrefix(obj.Path, "recent/") {
+		objs = me.getRecentlyPlayedScenes(childPath(paths), host)
+	}
+
 	return makeBrowseResult(objs, me.updateIDString())
 }
 
@@ -350,20 +372,22 @@ func (me *contentDirectoryService) handleBrowseMetadata(obj object, host string)
 		updateID = me.updateIDString()
 	} else {
 		var scene *models.Scene
+		var captions []*models.SceneCaption
 
 		if err := me.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
 			scene, err = r.Scene().Find(sceneID)
-			if err != nil {
+			if err != nil || scene == nil {
 				return err
 			}
 
-			return nil
+			captions, err = r.Scene().GetCaptions(sceneID)
+			return err
 		}); err != nil {
 			logger.Error(err.Error())
 		}
 
 		if scene != nil {
-			upnpObject := sceneToContainer(scene, "-1", host)
+			upnpObject := sceneToContainer(scene, captions, "-1", host)
 			objs = []interface{}{upnpObject}
 
 			// http://upnp.org/specs/av/UPnP-av-ContentDirectory-v1-Service.pdf
@@ -423,21 +447,17 @@ func getRootObjects() []interface{} {
 	objs = append(objs, makeStorageFolder("studios", "studios", rootID))
 	objs = append(objs, makeStorageFolder("movies", "movies", rootID))
 	objs = append(objs, makeStorageFolder("rating", "rating", rootID))
+	objs = append(objs, makeStorageFolder("recent", "recently played", rootID))
 
 	return objs
 }
 
-func (me *contentDirectoryService) getVideos(sceneFilter *models.SceneFilterType, parentID string, host string) []interface{} {
+func (me *contentDirectoryService) getVideos(sceneFilter *models.SceneFilterType, sort sceneSort, parentID string, host string) []interface{} {
 	var objs []interface{}
 
-	if err := me.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
-		sort := "title"
-		findFilter := &models.FindFilterType{
-			PerPage: &pageSize,
-			Sort:    &sort,
-		}
-
-		scenes, total, err := r.Scene().Query(sceneFilter, findFilter)
+	// scene filters such as rating and play count are per user
+	if err := me.txnManager.WithReadTxn(me.userContext(), func(r models.ReaderRepository) error {
+		scenes, total, err := r.Scene().Query(sceneFilter, sort.findFilter(1, pageSize))
 		if err != nil {
 			return err
 		}
@@ -445,6 +465,7 @@ func (me *contentDirectoryService) getVideos(sceneFilter *models.SceneFilterType
 		if total > pageSize {
 			pager := scenePager{
 				sceneFilter: sceneFilter,
+				sort:        sort,
 				parentID:    parentID,
 			}
 
@@ -454,7 +475,12 @@ func (me *contentDirectoryService) getVideos(sceneFilter *models.SceneFilterType
 			}
 		} else {
 			for _, s := range scenes {
-				objs = append(objs, sceneToContainer(s, parentID, host))
+				captions, err := r.Scene().GetCaptions(s.ID)
+				if err != nil {
+					return err
+				}
+
+				objs = append(objs, sceneToContainer(s, captions, parentID, host))
 			}
 		}
 
@@ -466,12 +492,13 @@ func (me *contentDirectoryService) getVideos(sceneFilter *models.SceneFilterType
 	return objs
 }
 
-func (me *contentDirectoryService) getPageVideos(sceneFilter *models.SceneFilterType, parentID string, page int, host string) []interface{} {
+func (me *contentDirectoryService) getPageVideos(sceneFilter *models.SceneFilterType, sort sceneSort, parentID string, page int, host string) []interface{} {
 	var objs []interface{}
 
-	if err := me.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+	if err := me.txnManager.WithReadTxn(me.userContext(), func(r models.ReaderRepository) error {
 		pager := scenePager{
 			sceneFilter: sceneFilter,
+			sort:        sort,
 			parentID:    parentID,
 		}
 
@@ -504,7 +531,7 @@ func getPageFromID(paths []string) *int {
 }
 
 func (me *contentDirectoryService) getAllScenes(host string) []interface{} {
-	return me.getVideos(&models.SceneFilterType{}, "all", host)
+	return me.getVideos(&models.SceneFilterType{}, titleSort, "all", host)
 }
 
 func (me *contentDirectoryService) getStudios() []interface{} {
@@ -541,10 +568,10 @@ func (me *contentDirectoryService) getStudioScenes(paths []string, host string)
 
 	page := getPageFromID(paths)
 	if page != nil {
-		return me.getPageVideos(sceneFilter, parentID, *page, host)
+		return me.getPageVideos(sceneFilter, titleSort, parentID, *page, host)
 	}
 
-	return me.getVideos(sceneFilter, parentID, host)
+	return me.getVideos(sceneFilter, titleSort, parentID, host)
 }
 
 func (me *contentDirectoryService) getTags() []interface{} {
@@ -581,10 +608,10 @@ func (me *contentDirectoryService) getTagScenes(paths []string, host string) []i
 
 	page := getPageFromID(paths)
 	if page != nil {
-		return me.getPageVideos(sceneFilter, parentID, *page, host)
+		return me.getPageVideos(sceneFilter, titleSort, parentID, *page, host)
 	}
 
-	return me.getVideos(sceneFilter, parentID, host)
+	return me.getVideos(sceneFilter, titleSort, parentID, host)
 }
 
 func (me *contentDirectoryService) getPerformers() []interface{} {
@@ -620,10 +647,10 @@ func (me *contentDirectoryService) getPerformerScenes(paths []string, host strin
 
 	page := getPageFromID(paths)
 	if page != nil {
-		return me.getPageVideos(sceneFilter, parentID, *page, host)
+		return me.getPageVideos(sceneFilter, titleSort, parentID, *page, host)
 	}
 
-	return me.getVideos(sceneFilter, parentID, host)
+	return me.getVideos(sceneFilter, titleSort, parentID, host)
 }
 
 func (me *contentDirectoryService) getMovies() []interface{} {
@@ -659,10 +686,10 @@ func (me *contentDirectoryService) getMovieScenes(paths []string, host string) [
 
 	page := getPageFromID(paths)
 	if page != nil {
-		return me.getPageVideos(sceneFilter, parentID, *page, host)
+		return me.getPageVideos(sceneFilter, titleSort, parentID, *page, host)
 	}
 
-	return me.getVideos(sceneFilter, parentID, host)
+	return me.getVideos(sceneFilter, titleSort, parentID, host)
 }
 
 func (me *contentDirectoryService) getRating() []interface{} {
@@ -693,10 +720,61 @@ func (me *contentDirectoryService) getRatingScenes(paths []string, host string)
 
 	page := getPageFromID(paths)
 	if page != nil {
-		return me.getPageVideos(sceneFilter, parentID, *page, host)
+		return me.getPageVideos(sceneFilter, titleSort, parentID, *page, host)
+	}
+
+	return me.getVideos(sceneFilter, titleSort, parentID, host)
+}
+
+// getRecentlyPlayedScenes returns the scenes that have been played, most
+// recently played first, using the play history of the DLNA user.
+func (me *contentDirectoryService) getRecentlyPlayedScenes(paths []string, host string) []interface{} {
+	sceneFilter := &models.SceneFilterType{
+		PlayCount: &models.IntCriterionInput{
+			Modifier: models.CriterionModifierGreaterThan,
+			Value:    0,
+		},
+	}
+
+	parentID := strings.Join(append([]string{"recent"}, paths...), "/")
+
+	page := getPageFromID(paths)
+	if page != nil {
+		return me.getPageVideos(sceneFilter, recentlyPlayedSort, parentID, *page, host)
+	}
+
+	return me.getVideos(sceneFilter, recentlyPlayedSort, parentID, host)
+}
+
+// userContext returns a context with the user whose per-user values, such
+// as ratings and play history, are shown to DLNA clients. DLNA clients do not log in, so this is the first
+// admin user, which is also the user that imported values without a user
+// are assigned to. If there are no users, the context has no user.
+func (me *contentDirectoryService) userContext() context.Context {
+	ctx := context.TODO()
+
+	var users []*models.User
+	if err := me.txnManager.WithReadTxn(ctx, func(r models.ReaderRepository) error {
+		var err error
+		users, err = r.User().All()
+		return err
+	}); err != nil {
+		logger.Errorf("error getting DLNA user: %v", err)
+		return ctx
+	}
+
+	var ret *models.User
+	for _, u := range users {
+		if u.Role == models.UserRoleAdmin && (ret == nil || u.ID < ret.ID) {
+			ret = u
+		}
+	}
+
+	if ret == nil {
+		return ctx
 	}
 
-	return me.getVideos(sceneFilter, parentID, host)
+	return session.SetCurrentUser(ctx, ret)
 }
 
 // Represents a ContentDirectory object.
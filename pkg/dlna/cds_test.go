@@ -27,12 +27,16 @@ package dlna
 // SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/session"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestEscapeObjectID(t *testing.T) {
@@ -80,3 +84,59 @@ func TestBrowseMetadataTags(t *testing.T) {
 
 	assert.Nil(t, err)
 }
+
+// userRecordingTxnManager records the user of each read transaction.
+type userRecordingTxnManager struct {
+	*mocks.TransactionManager
+	userIDs []int
+}
+
+func (m *userRecordingTxnManager) WithReadTxn(ctx context.Context, fn func(r models.ReaderRepository) error) error {
+	m.userIDs = append(m.userIDs, session.GetUserDataID(ctx))
+	return m.TransactionManager.WithReadTxn(ctx, fn)
+}
+
+func TestBrowseDirectChildrenUser(t *testing.T) {
+	const adminID = 2
+
+	tests := []struct {
+		name     string
+		objectID string
+		users    []*models.User
+		userID   int
+	}{
+		{"rating without users", "rating%2F5", nil, 0},
+		{"rating", "rating%2F5", []*models.User{
+			{ID: 1, Role: models.UserRoleViewer},
+			{ID: adminID, Role: models.UserRoleAdmin},
+			{ID: 3, Role: models.UserRoleAdmin},
+		}, adminID},
+		{"recently played", "recent", []*models.User{
+			{ID: adminID, Role: models.UserRoleAdmin},
+		}, adminID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txnManager := &userRecordingTxnManager{
+				TransactionManager: mocks.NewTransactionManager(),
+			}
+			txnManager.User().(*mocks.UserReaderWriter).On("All").Return(tt.users, nil)
+			txnManager.Scene().(*mocks.SceneReaderWriter).On("Query", mock.Anything, mock.Anything).Return(nil, 0, nil)
+
+			cds := contentDirectoryService{
+				Server:     &Server{},
+				txnManager: txnManager,
+			}
+
+			argsXML := `<u:Browse xmlns:u="urn:schemas-upnp-org:service:ContentDirectory:1"><ObjectID>` + tt.objectID + `</ObjectID><BrowseFlag>BrowseDirectChildren</BrowseFlag><Filter>*</Filter><StartingIndex>0</StartingIndex><RequestedCount>0</RequestedCount><SortCriteria></SortCriteria></u:Browse>`
+			_, err := cds.Handle("Browse", []byte(argsXML), &http.Request{})
+			assert.Nil(t, err)
+
+			// the scenes are queried in the last transaction
+			if assert.NotEmpty(t, txnManager.userIDs) {
+				assert.Equal(t, tt.userID, txnManager.userIDs[len(txnManager.userIDs)-1])
+			}
+		})
+	}
+}
@@ -57,6 +57,7 @@ const (
 	rootDeviceModelName         = "dms 1.0xb"
 	resPath                     = "/res"
 	iconPath                    = "/icon"
+	captionPath                 = "/caption"
 	rootDescPath                = "/rootDesc.xml"
 	contentDirectoryEventSubURL = "/evt/ContentDirectory"
 	serviceControlURL           = "/ctl"
@@ -432,6 +433,29 @@ func (me *Server) serveIcon(w http.ResponseWriter, r *http.Request) {
 	me.sceneServer.ServeScreenshot(scene, w, r)
 }
 
+func (me *Server) serveCaption(w http.ResponseWriter, r *http.Request) {
+	sceneId := r.URL.Query().Get("scene")
+	if sceneId == "" {
+		return
+	}
+
+	var scene *models.Scene
+	me.txnManager.WithReadTxn(context.Background(), func(r models.ReaderRepository) error {
+		idInt, err := strconv.Atoi(sceneId)
+		if err != nil {
+			return nil
+		}
+		scene, _ = r.Scene().Find(idInt)
+		return nil
+	})
+
+	if scene == nil {
+		return
+	}
+
+	me.sceneServer.ServeCaption(scene, w, r)
+}
+
 func (me *Server) contentDirectoryInitialEvent(urls []*url.URL, sid string) {
 	body := xmlMarshalOrPanic(upnp.PropertySet{
 		Properties: []upnp.Property{
@@ -549,6 +573,7 @@ func (me *Server) initMux(mux *http.ServeMux) {
 	})
 	mux.HandleFunc(contentDirectoryEventSubURL, me.contentDirectoryEventSubHandler)
 	mux.HandleFunc(iconPath, me.serveIcon)
+	mux.HandleFunc(captionPath, me.serveCaption)
 	mux.HandleFunc(resPath, func(w http.ResponseWriter, r *http.Request) {
 		sceneId := r.URL.Query().Get("scene")
 		var scene *models.Scene
@@ -8,8 +8,31 @@ import (
 	"github.com/stashapp/stash/pkg/models"
 )
 
+// sceneSort is the order in which scenes are listed in a folder.
+type sceneSort struct {
+	sort      string
+	direction models.SortDirectionEnum
+}
+
+var (
+	titleSort          = sceneSort{sort: "title", direction: models.SortDirectionEnumAsc}
+	recentlyPlayedSort = sceneSort{sort: "last_played_at", direction: models.SortDirectionEnumDesc}
+)
+
+func (s sceneSort) findFilter(page int, perPage int) *models.FindFilterType {
+	sort := s.sort
+	direction := s.direction
+	return &models.FindFilterType{
+		Page:      &page,
+		PerPage:   &perPage,
+		Sort:      &sort,
+		Direction: &direction,
+	}
+}
+
 type scenePager struct {
 	sceneFilter *models.SceneFilterType
+	sort        sceneSort
 	parentID    string
 }
 
@@ -23,19 +46,12 @@ func (p *scenePager) getPages(r models.ReaderRepository, total int) ([]interface
 	// get the first scene of each page to set an appropriate title
 	pages := int(math.Ceil(float64(total) / float64(pageSize)))
 
-	singlePageSize := 1
-	sort := "title"
-	findFilter := &models.FindFilterType{
-		PerPage: &singlePageSize,
-		Sort:    &sort,
-	}
-
 	for page := 1; page <= pages; page++ {
 		// TODO - this is really slow. Not sure if there's a better way
 		title := fmt.Sprintf("Page %d", page)
 		if pages <= 10 || (page-1)%(pages/10) == 0 {
 			thisPage := ((page - 1) * pageSize) + 1
-			findFilter.Page = &thisPage
+			findFilter := p.sort.findFilter(thisPage, 1)
 			scenes, _, err := r.Scene().Query(p.sceneFilter, findFilter)
 			if err != nil {
 				return nil, err
@@ -60,12 +76,7 @@ func (p *scenePager) getPages(r models.ReaderRepository, total int) ([]interface
 func (p *scenePager) getPageVideos(r models.ReaderRepository, page int, host string) ([]interface{}, error) {
 	var objs []interface{}
 
-	sort := "title"
-	findFilter := &models.FindFilterType{
-		PerPage: &pageSize,
-		Page:    &page,
-		Sort:    &sort,
-	}
+	findFilter := p.sort.findFilter(page, pageSize)
 
 	scenes, _, err := r.Scene().Query(p.sceneFilter, findFilter)
 	if err != nil {
@@ -73,7 +84,12 @@ func (p *scenePager) getPageVideos(r models.ReaderRepository, page int, host str
 	}
 
 	for _, s := range scenes {
-		objs = append(objs, sceneToContainer(s, p.parentID, host))
+		captions, err := r.Scene().GetCaptions(s.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		objs = append(objs, sceneToContainer(s, captions, p.parentID, host))
 	}
 
 	return objs, nil
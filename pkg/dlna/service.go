@@ -26,6 +26,7 @@ type dmsConfig struct {
 type sceneServer interface {
 	StreamSceneDirect(scene *models.Scene, w http.ResponseWriter, r *http.Request)
 	ServeScreenshot(scene *models.Scene, w http.ResponseWriter, r *http.Request)
+	ServeCaption(scene *models.Scene, w http.ResponseWriter, r *http.Request)
 }
 
 type Service struct {
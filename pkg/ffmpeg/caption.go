@@ -0,0 +1,65 @@
+package ffmpeg
+
+import (
+	"strconv"
+)
+
+// textSubtitleCodecs are the subtitle codecs that can be converted to
+// WebVTT. Image based subtitles such as PGS and DVD subtitles cannot.
+var textSubtitleCodecs = map[string]bool{
+	"subrip":   true,
+	"srt":      true,
+	"text":     true,
+	"mov_text": true,
+	"webvtt":   true,
+	"ass":      true,
+	"ssa":      true,
+}
+
+// IsTextSubtitleCodec returns true if the subtitle codec can be converted
+// to WebVTT.
+func IsTextSubtitleCodec(codec string) bool {
+	return textSubtitleCodecs[codec]
+}
+
+// GetTextSubtitleStreams returns the subtitle streams of the video file that
+// can be converted to WebVTT.
+func (v *VideoFile) GetTextSubtitleStreams() []*FFProbeStream {
+	var ret []*FFProbeStream
+	for i, stream := range v.JSON.Streams {
+		if stream.CodecType == "subtitle" && IsTextSubtitleCodec(stream.CodecName) {
+			ret = append(ret, &v.JSON.Streams[i])
+		}
+	}
+
+	return ret
+}
+
+// Caption is a caption track that is added to a transcoded stream.
+type Caption struct {
+	// Path is the caption file. The subtitle stream at StreamIndex of the
+	// video file is used if empty.
+	Path         string
+	StreamIndex  int
+	LanguageCode string
+}
+
+// extractCaptionArgs returns the arguments to convert the subtitle stream
+// at streamIndex of the video file to WebVTT on stdout.
+func extractCaptionArgs(path string, streamIndex int) []string {
+	return []string{
+		"-hide_banner",
+		"-v", "error",
+		"-i", path,
+		"-map", "0:" + strconv.Itoa(streamIndex),
+		"-c:s", "webvtt",
+		"-f", "webvtt",
+		"-",
+	}
+}
+
+// ExtractCaption returns the subtitle stream at streamIndex of the video
+// file at path converted to WebVTT.
+func (e *Encoder) ExtractCaption(path string, streamIndex int) (string, error) {
+	return e.run(VideoFile{Path: path}, extractCaptionArgs(path, streamIndex))
+}
@@ -0,0 +1,42 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStreamArgsCaptions(t *testing.T) {
+	o := TranscodeStreamOptions{
+		ProbeResult: VideoFile{
+			Path:   "in.mkv",
+			Width:  1280,
+			Height: 720,
+		},
+		Codec:     CodecH264,
+		StartTime: "30",
+		Captions: []Caption{
+			{Path: "in.en.srt", LanguageCode: "en"},
+			{StreamIndex: 3, LanguageCode: "jpn"},
+		},
+	}
+
+	want := "-hide_banner -v error -ss 30 -i in.mkv -ss 30 -i in.en.srt " +
+		"-map 0:v:0 -map 0:a:0? -map 1:s:0 -metadata:s:s:0 language=en -map 0:3 -metadata:s:s:1 language=jpn -c:s mov_text " +
+		"-c:v libx264 -vf scale=iw:-2 -pix_fmt yuv420p -preset veryfast -crf 25 -movflags frag_keyframe+empty_moov -ac 2 -f mp4 pipe:"
+	assert.Equal(t, want, strings.Join(o.getStreamArgs(), " "))
+
+	// audio is not mapped for video only streams
+	o.VideoOnly = true
+	assert.Contains(t, strings.Join(o.getStreamArgs(), " "), "-map 0:v:0 -map 1:s:0")
+
+	// captions are not added to formats without a caption codec
+	o.Codec = CodecHLS
+	assert.NotContains(t, strings.Join(o.getStreamArgs(), " "), "in.en.srt")
+}
+
+func TestExtractCaptionArgs(t *testing.T) {
+	want := "-hide_banner -v error -i in.mkv -map 0:3 -c:s webvtt -f webvtt -"
+	assert.Equal(t, want, strings.Join(extractCaptionArgs("in.mkv", 3), " "))
+}
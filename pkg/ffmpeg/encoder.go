@@ -2,6 +2,7 @@ package ffmpeg
 
 import (
 	"bytes"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -140,9 +141,15 @@ func (e *Encoder) runTranscode(probeResult VideoFile, args []string) (string, er
 }
 
 func (e *Encoder) run(probeResult VideoFile, args []string) (string, error) {
+	return e.runInput(probeResult, args, nil)
+}
+
+// runInput runs ffmpeg with stdin read from input, if it is not nil.
+func (e *Encoder) runInput(probeResult VideoFile, args []string, input io.Reader) (string, error) {
 	cmd := exec.Command(e.Path, args...)
 
 	var stdout, stderr bytes.Buffer
+	cmd.Stdin = input
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
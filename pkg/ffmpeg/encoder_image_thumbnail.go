@@ -0,0 +1,134 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+type AnimatedThumbnailOptions struct {
+	// InputPath is the animated image or video clip. Input is read instead
+	// if it is not nil.
+	InputPath string
+	Input     io.Reader
+	// OutputPath is the animated WebP thumbnail
+	OutputPath string
+	// MaxDimension is the maximum width and height of the thumbnail
+	MaxDimension int
+	// MaxDuration limits the length of the thumbnail. Zero for no limit.
+	MaxDuration float64
+}
+
+func (o AnimatedThumbnailOptions) getArgs() []string {
+	input := o.InputPath
+	if o.Input != nil {
+		input = "pipe:0"
+	}
+
+	args := []string{
+		"-hide_banner",
+		"-v", "error",
+		"-i", input,
+	}
+
+	if o.MaxDuration > 0 {
+		args = append(args, "-t", strconv.FormatFloat(o.MaxDuration, 'f', -1, 64))
+	}
+
+	return append(args,
+		"-vf", fmt.Sprintf("scale='min(%d,iw)':'min(%d,ih)':force_original_aspect_ratio=decrease", o.MaxDimension, o.MaxDimension),
+		"-an",
+		"-c:v", "libwebp",
+		"-lossless", "0",
+		"-q:v", "70",
+		"-loop", "0",
+		"-f", "webp",
+		"-y",
+		o.OutputPath,
+	)
+}
+
+// AnimatedThumbnail encodes an animated WebP thumbnail of an animated image
+// or video clip.
+func (e *Encoder) AnimatedThumbnail(options AnimatedThumbnailOptions) error {
+	_, err := e.runInput(VideoFile{Path: options.InputPath}, options.getArgs(), options.Input)
+	return err
+}
+
+// thumbnailCodecArgs are the encoder arguments of the thumbnail formats
+// that are encoded by ffmpeg.
+var thumbnailCodecArgs = map[string][]string{
+	"webp": {"-c:v", "libwebp", "-q:v", "75", "-f", "webp"},
+	"avif": {"-c:v", "libaom-av1", "-still-picture", "1", "-crf", "32", "-cpu-used", "6", "-pix_fmt", "yuv420p", "-f", "avif"},
+}
+
+type ThumbnailEncodeOptions struct {
+	// Input is the PNG thumbnail to encode
+	Input      io.Reader
+	OutputPath string
+	// Format is webp or avif
+	Format string
+}
+
+// ThumbnailFormats returns the thumbnail formats that can be encoded by
+// ffmpeg, which are the formats that ffmpeg has the encoder of.
+func (e *Encoder) ThumbnailFormats() ([]string, error) {
+	out, err := exec.Command(e.Path, "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return getThumbnailFormats(string(out)), nil
+}
+
+// getThumbnailFormats returns the thumbnail formats of the encoders in the
+// output of ffmpeg -encoders. Encoder lines start with the capabilities of
+// the encoder, followed by its name.
+func getThumbnailFormats(encodersOutput string) []string {
+	encoders := make(map[string]bool)
+	for _, line := range strings.Split(encodersOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			encoders[fields[1]] = true
+		}
+	}
+
+	var ret []string
+	for format, args := range thumbnailCodecArgs {
+		// the encoder follows -c:v
+		if encoders[args[1]] {
+			ret = append(ret, format)
+		}
+	}
+
+	return ret
+}
+
+func (o ThumbnailEncodeOptions) getArgs() ([]string, error) {
+	codecArgs, ok := thumbnailCodecArgs[o.Format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported thumbnail format: %s", o.Format)
+	}
+
+	args := []string{
+		"-hide_banner",
+		"-v", "error",
+		"-f", "png_pipe",
+		"-i", "pipe:0",
+	}
+	args = append(args, codecArgs...)
+	return append(args, "-y", o.OutputPath), nil
+}
+
+// EncodeThumbnail encodes a PNG thumbnail to WebP or AVIF.
+func (e *Encoder) EncodeThumbnail(options ThumbnailEncodeOptions) error {
+	args, err := options.getArgs()
+	if err != nil {
+		return err
+	}
+
+	_, err = e.runInput(VideoFile{Path: options.OutputPath}, args, options.Input)
+	return err
+}
@@ -0,0 +1,63 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnimatedThumbnailArgs(t *testing.T) {
+	o := AnimatedThumbnailOptions{
+		InputPath:    "clip.mp4",
+		OutputPath:   "thumb.webp",
+		MaxDimension: 640,
+		MaxDuration:  30,
+	}
+
+	want := "-hide_banner -v error -i clip.mp4 -t 30 " +
+		"-vf scale='min(640,iw)':'min(640,ih)':force_original_aspect_ratio=decrease " +
+		"-an -c:v libwebp -lossless 0 -q:v 70 -loop 0 -f webp -y thumb.webp"
+	assert.Equal(t, want, strings.Join(o.getArgs(), " "))
+
+	// files in zip files are read from stdin
+	o.Input = strings.NewReader("")
+	o.MaxDuration = 0
+	args := strings.Join(o.getArgs(), " ")
+	assert.Contains(t, args, "-i pipe:0 -vf")
+}
+
+func TestThumbnailEncodeArgs(t *testing.T) {
+	o := ThumbnailEncodeOptions{
+		OutputPath: "thumb.webp",
+		Format:     "webp",
+	}
+
+	args, err := o.getArgs()
+	assert.Nil(t, err)
+	assert.Equal(t, "-hide_banner -v error -f png_pipe -i pipe:0 -c:v libwebp -q:v 75 -f webp -y thumb.webp", strings.Join(args, " "))
+
+	o.Format = "avif"
+	o.OutputPath = "thumb.avif"
+	args, err = o.getArgs()
+	assert.Nil(t, err)
+	assert.Contains(t, strings.Join(args, " "), "-c:v libaom-av1 -still-picture 1")
+	assert.Equal(t, "thumb.avif", args[len(args)-1])
+
+	o.Format = "jpg"
+	_, err = o.getArgs()
+	assert.NotNil(t, err)
+}
+
+func TestGetThumbnailFormats(t *testing.T) {
+	const encoders = `Encoders:
+ V..... = Video
+ ------
+ V....D libwebp_anim         libwebp WebP image (codec webp)
+ V....D libwebp              libwebp WebP image (codec webp)
+ V....D mjpeg                MJPEG (Motion JPEG)
+`
+
+	assert.Equal(t, []string{"webp"}, getThumbnailFormats(encoders))
+	assert.Len(t, getThumbnailFormats(encoders+" V....D libaom-av1           libaom AV1 (codec av1)\n"), 2)
+}
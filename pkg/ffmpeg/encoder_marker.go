@@ -5,18 +5,41 @@ import (
 	"strconv"
 )
 
+const (
+	markerVideoDuration = 20
+	markerImageDuration = 5
+)
+
 type SceneMarkerOptions struct {
-	ScenePath  string
-	Seconds    int
+	ScenePath string
+	Seconds   int
+	// Duration is the length of the marker in seconds. If zero, the
+	// default preview length is used.
+	Duration   float64
 	Width      int
 	OutputPath string
 }
 
+// getDuration returns the length of the preview to generate, which is the
+// duration of the marker limited to max, or def if the marker has no
+// duration.
+func (o SceneMarkerOptions) getDuration(def float64, max float64) string {
+	ret := def
+	if o.Duration > 0 {
+		ret = o.Duration
+		if max > 0 && ret > max {
+			ret = max
+		}
+	}
+
+	return strconv.FormatFloat(ret, 'f', -1, 64)
+}
+
 func (e *Encoder) SceneMarkerVideo(probeResult VideoFile, options SceneMarkerOptions) error {
 	args := []string{
 		"-v", "error",
 		"-ss", strconv.Itoa(options.Seconds),
-		"-t", "20",
+		"-t", options.getDuration(markerVideoDuration, 0),
 		"-i", probeResult.Path,
 		"-max_muxing_queue_size", "1024", // https://trac.ffmpeg.org/ticket/6375
 		"-c:v", "libx264",
@@ -42,7 +65,7 @@ func (e *Encoder) SceneMarkerImage(probeResult VideoFile, options SceneMarkerOpt
 	args := []string{
 		"-v", "error",
 		"-ss", strconv.Itoa(options.Seconds),
-		"-t", "5",
+		"-t", options.getDuration(markerImageDuration, markerImageDuration),
 		"-i", probeResult.Path,
 		"-c:v", "libwebp",
 		"-lossless", "1",
@@ -13,6 +13,8 @@ type ScenePreviewChunkOptions struct {
 	Width      int
 	OutputPath string
 	Audio      bool
+	// Profile selects the encoder of the preview video
+	Profile TranscodeProfile
 }
 
 func (e *Encoder) ScenePreviewVideoChunk(probeResult VideoFile, options ScenePreviewChunkOptions, preset string, fallback bool) error {
@@ -57,6 +59,8 @@ func (e *Encoder) ScenePreviewVideoChunk(probeResult VideoFile, options ScenePre
 		}
 	}
 
+	args = append(args, options.Profile.inputArgs()...)
+
 	if fastSeek > 0 {
 		args = append(args, "-ss")
 		args = append(args, strconv.FormatFloat(fastSeek, 'f', 2, 64))
@@ -74,16 +78,19 @@ func (e *Encoder) ScenePreviewVideoChunk(probeResult VideoFile, options ScenePre
 		"-t", strconv.FormatFloat(options.Duration, 'f', 2, 64),
 		"-max_muxing_queue_size", "1024", // https://trac.ffmpeg.org/ticket/6375
 		"-y",
-		"-c:v", "libx264",
+	}
+
+	encoderArgs := []string{
 		"-pix_fmt", "yuv420p",
 		"-profile:v", "high",
 		"-level", "4.2",
 		"-preset", preset,
 		"-crf", "21",
 		"-threads", "4",
-		"-vf", fmt.Sprintf("scale=%v:-2", options.Width),
-		"-strict", "-2",
 	}
+	scale := fmt.Sprintf("%v:-2", options.Width)
+	args2 = append(args2, options.Profile.videoArgs("libx264", scale, encoderArgs)...)
+	args2 = append(args2, "-strict", "-2")
 
 	args3 := append(args, args2...)
 	args3 = append(args3, argsAudio...)
@@ -11,27 +11,39 @@ type TranscodeOptions struct {
 	MaxTranscodeSize models.StreamingResolutionEnum
 }
 
-func calculateTranscodeScale(probeResult VideoFile, maxTranscodeSize models.StreamingResolutionEnum) string {
-	maxSize := 0
+// getMaxTranscodeSize returns the size of the smaller dimension of videos
+// transcoded to the resolution. Returns 0 for the original resolution.
+func getMaxTranscodeSize(maxTranscodeSize models.StreamingResolutionEnum) int {
 	switch maxTranscodeSize {
 	case models.StreamingResolutionEnumLow:
-		maxSize = 240
+		return 240
 	case models.StreamingResolutionEnumStandard:
-		maxSize = 480
+		return 480
 	case models.StreamingResolutionEnumStandardHd:
-		maxSize = 720
+		return 720
 	case models.StreamingResolutionEnumFullHd:
-		maxSize = 1080
+		return 1080
 	case models.StreamingResolutionEnumFourK:
-		maxSize = 2160
+		return 2160
 	}
 
-	// get the smaller dimension of the video file
+	return 0
+}
+
+// getVideoSize returns the smaller dimension of the video file.
+func getVideoSize(probeResult VideoFile) int {
 	videoSize := probeResult.Height
 	if probeResult.Width < videoSize {
 		videoSize = probeResult.Width
 	}
 
+	return videoSize
+}
+
+func calculateTranscodeScale(probeResult VideoFile, maxTranscodeSize models.StreamingResolutionEnum) string {
+	maxSize := getMaxTranscodeSize(maxTranscodeSize)
+	videoSize := getVideoSize(probeResult)
+
 	// if our streaming resolution is larger than the video dimension
 	// or we are streaming the original resolution, then just set the
 	// input width
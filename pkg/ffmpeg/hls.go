@@ -1,14 +1,26 @@
 package ffmpeg
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 const hlsSegmentLength = 10.0
 
-func WriteHLSPlaylist(probeResult VideoFile, baseUrl string, w io.Writer) {
+// WriteHLSPlaylist writes a playlist of the HLS segments of the video file.
+// The URL of each segment is segmentURL followed by the segment filename,
+// and query if it is not empty.
+func WriteHLSPlaylist(probeResult VideoFile, segmentURL string, query string, w io.Writer) {
 	fmt.Fprint(w, "#EXTM3U\n")
 	fmt.Fprint(w, "#EXT-X-VERSION:3\n")
 	fmt.Fprint(w, "#EXT-X-MEDIA-SEQUENCE:0\n")
@@ -16,13 +28,14 @@ func WriteHLSPlaylist(probeResult VideoFile, baseUrl string, w io.Writer) {
 	fmt.Fprintf(w, "#EXT-X-TARGETDURATION:%d\n", int(hlsSegmentLength))
 	fmt.Fprint(w, "#EXT-X-PLAYLIST-TYPE:VOD\n")
 
+	if query != "" {
+		query = "?" + query
+	}
+
 	duration := probeResult.Duration
 
 	leftover := duration
-	upTo := 0.0
-
-	i := strings.LastIndex(baseUrl, ".m3u8")
-	tsURL := baseUrl[0:i] + ".ts"
+	segment := 0
 
 	for leftover > 0 {
 		thisLength := hlsSegmentLength
@@ -31,11 +44,269 @@ func WriteHLSPlaylist(probeResult VideoFile, baseUrl string, w io.Writer) {
 		}
 
 		fmt.Fprintf(w, "#EXTINF: %f,\n", thisLength)
-		fmt.Fprintf(w, "%s?start=%f\n", tsURL, upTo)
+		fmt.Fprintf(w, "%s%s%s\n", segmentURL, HLSSegmentFilename(segment), query)
 
 		leftover -= thisLength
-		upTo += thisLength
+		segment++
+	}
+
+	fmt.Fprint(w, "#EXT-X-ENDLIST\n")
+}
+
+// HLSRendition is a rendition of a video file in an HLS master playlist.
+type HLSRendition struct {
+	Resolution models.StreamingResolutionEnum
+	Width      int
+	Height     int
+	// Bandwidth is the estimated peak bitrate of the rendition in bits
+	// per second
+	Bandwidth int
+}
+
+// hlsLadder are the resolutions of the renditions below the resolution of
+// the video file.
+var hlsLadder = []models.StreamingResolutionEnum{
+	models.StreamingResolutionEnumLow,
+	models.StreamingResolutionEnumStandard,
+	models.StreamingResolutionEnumStandardHd,
+	models.StreamingResolutionEnumFullHd,
+	models.StreamingResolutionEnumFourK,
+}
+
+const hlsAudioBandwidth = 128000
+
+func newHLSRendition(probeResult VideoFile, resolution models.StreamingResolutionEnum) HLSRendition {
+	width := probeResult.Width
+	height := probeResult.Height
+
+	size := getMaxTranscodeSize(resolution)
+	if size != 0 && size < getVideoSize(probeResult) {
+		// scale the larger dimension to the nearest even size, as ffmpeg does
+		if width > height {
+			width = int(math.Round(float64(width)*float64(size)/float64(height)/2)) * 2
+			height = size
+		} else {
+			height = int(math.Round(float64(height)*float64(size)/float64(width)/2)) * 2
+			width = size
+		}
+	}
+
+	return HLSRendition{
+		Resolution: resolution,
+		Width:      width,
+		Height:     height,
+		Bandwidth:  width*height*5/2 + hlsAudioBandwidth,
+	}
+}
+
+// GetHLSRenditions returns the renditions of the video file from the lowest
+// to the highest resolution. Renditions are no larger than the video file
+// or maxSize.
+func GetHLSRenditions(probeResult VideoFile, maxSize models.StreamingResolutionEnum) []HLSRendition {
+	videoSize := getVideoSize(probeResult)
+	max := getMaxTranscodeSize(maxSize)
+
+	var ret []HLSRendition
+	for _, resolution := range hlsLadder {
+		size := getMaxTranscodeSize(resolution)
+		if size >= videoSize || (max != 0 && size > max) {
+			break
+		}
+
+		ret = append(ret, newHLSRendition(probeResult, resolution))
+	}
+
+	// add the original resolution if it is not limited by maxSize
+	if max == 0 || max >= videoSize {
+		ret = append(ret, newHLSRendition(probeResult, models.StreamingResolutionEnumOriginal))
+	}
+
+	return ret
+}
+
+// HLSCaption is a caption track of an HLS master playlist.
+type HLSCaption struct {
+	Name         string
+	LanguageCode string
+	// PlaylistURL is the URL of the caption playlist
+	PlaylistURL string
+}
+
+const hlsCaptionGroup = "subs"
+
+// WriteHLSMasterPlaylist writes a master playlist of the renditions and
+// captions. The URL of the playlist of each rendition is returned by
+// playlistURL.
+func WriteHLSMasterPlaylist(renditions []HLSRendition, captions []HLSCaption, playlistURL func(rendition HLSRendition) string, w io.Writer) {
+	fmt.Fprint(w, "#EXTM3U\n")
+	fmt.Fprint(w, "#EXT-X-VERSION:3\n")
+
+	for _, c := range captions {
+		fmt.Fprintf(w, "#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID=%q,NAME=%q,LANGUAGE=%q,AUTOSELECT=YES,URI=%q\n", hlsCaptionGroup, c.Name, c.LanguageCode, c.PlaylistURL)
 	}
 
+	subtitles := ""
+	if len(captions) > 0 {
+		subtitles = fmt.Sprintf(",SUBTITLES=%q", hlsCaptionGroup)
+	}
+
+	for _, r := range renditions {
+		fmt.Fprintf(w, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d%s\n", r.Bandwidth, r.Width, r.Height, subtitles)
+		fmt.Fprintf(w, "%s\n", playlistURL(r))
+	}
+}
+
+// WriteHLSCaptionPlaylist writes a playlist of a caption track of the video
+// file. The playlist has a single segment, which is the WebVTT caption at
+// captionURL.
+func WriteHLSCaptionPlaylist(probeResult VideoFile, captionURL string, w io.Writer) {
+	fmt.Fprint(w, "#EXTM3U\n")
+	fmt.Fprint(w, "#EXT-X-VERSION:3\n")
+	fmt.Fprint(w, "#EXT-X-MEDIA-SEQUENCE:0\n")
+	fmt.Fprintf(w, "#EXT-X-TARGETDURATION:%d\n", int(math.Ceil(probeResult.Duration)))
+	fmt.Fprint(w, "#EXT-X-PLAYLIST-TYPE:VOD\n")
+	fmt.Fprintf(w, "#EXTINF: %f,\n", probeResult.Duration)
+	fmt.Fprintf(w, "%s\n", captionURL)
 	fmt.Fprint(w, "#EXT-X-ENDLIST\n")
 }
+
+// HLSSegmentCount returns the number of HLS segments of the video file.
+func HLSSegmentCount(probeResult VideoFile) int {
+	return int(math.Ceil(probeResult.Duration / hlsSegmentLength))
+}
+
+// HLSSegmentFilename returns the filename of an HLS segment.
+func HLSSegmentFilename(segment int) string {
+	return strconv.Itoa(segment) + ".ts"
+}
+
+type HLSTranscodeOptions struct {
+	ProbeResult      VideoFile
+	MaxTranscodeSize models.StreamingResolutionEnum
+	Profile          TranscodeProfile
+	// remove the audio if its codec is not supported by ffmpeg
+	VideoOnly bool
+	// VideoTrack and AudioTrack are the indexes of the streams to transcode.
+	// The first streams are used if they are nil.
+	VideoTrack *int
+	AudioTrack *int
+
+	// StartSegment is the first segment that is transcoded
+	StartSegment int
+	// OutputDir is the directory that the segments are written to
+	OutputDir string
+}
+
+func (o HLSTranscodeOptions) getArgs() []string {
+	start := strconv.FormatFloat(float64(o.StartSegment)*hlsSegmentLength, 'f', -1, 64)
+
+	args := []string{
+		"-hide_banner",
+		"-v", "error",
+	}
+
+	args = append(args, o.Profile.inputArgs()...)
+
+	if o.StartSegment > 0 {
+		args = append(args, "-ss", start)
+	}
+
+	args = append(args, "-i", o.ProbeResult.Path)
+	args = append(args, getTrackMapArgs(o.VideoTrack, o.AudioTrack, o.VideoOnly)...)
+
+	if o.VideoOnly {
+		args = append(args, "-an")
+	}
+
+	scale := calculateTranscodeScale(o.ProbeResult, o.MaxTranscodeSize)
+	args = append(args, o.Profile.videoArgs(CodecHLS.Codec, scale, CodecHLS.encoderArgs)...)
+
+	args = append(args,
+		// start a new segment exactly every segment length, so that segments
+		// line up with the playlist when the transcode is restarted
+		"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", int(hlsSegmentLength)),
+		"-c:a", "aac",
+		// this is needed for 5-channel ac3 files
+		"-ac", "2",
+		"-sn",
+		// keep the timestamps continuous with the previous segments
+		"-output_ts_offset", start,
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(int(hlsSegmentLength)),
+		"-hls_list_size", "0",
+		"-hls_segment_type", "mpegts",
+		// segments are only renamed to their final filename when complete
+		"-hls_flags", "temp_file",
+		"-start_number", strconv.Itoa(o.StartSegment),
+		"-hls_segment_filename", filepath.Join(o.OutputDir, "%d.ts"),
+		filepath.Join(o.OutputDir, "stream.m3u8"),
+	)
+
+	return args
+}
+
+// HLSTranscode is a running transcode of a video file into HLS segments.
+type HLSTranscode struct {
+	process *os.Process
+	done    chan struct{}
+	err     error
+}
+
+// StartHLSTranscode starts transcoding the video file into HLS segments,
+// starting at the start segment of the options. Segments are written to the
+// output directory as they are completed.
+func (e *Encoder) StartHLSTranscode(options HLSTranscodeOptions) (*HLSTranscode, error) {
+	if err := os.MkdirAll(options.OutputDir, 0755); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(e.Path, options.getArgs()...)
+	logger.Debugf("[stream] transcoding HLS segments via: %s", strings.Join(cmd.Args, " "))
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	path := options.ProbeResult.Path
+	registerRunningEncoder(path, cmd.Process)
+
+	ret := &HLSTranscode{
+		process: cmd.Process,
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		if err := waitAndDeregister(path, cmd); err != nil {
+			ret.err = fmt.Errorf("%s: %s", err.Error(), strings.TrimSpace(stderr.String()))
+		}
+		close(ret.done)
+	}()
+
+	return ret, nil
+}
+
+// Done returns a channel that is closed when the transcode ends.
+func (t *HLSTranscode) Done() <-chan struct{} {
+	return t.done
+}
+
+// Err returns the error that ended the transcode. Only valid after the
+// transcode is done.
+func (t *HLSTranscode) Err() error {
+	return t.err
+}
+
+// Stop kills the transcode and waits for it to end.
+func (t *HLSTranscode) Stop() {
+	select {
+	case <-t.done:
+		return
+	default:
+	}
+
+	_ = t.process.Kill()
+	<-t.done
+}
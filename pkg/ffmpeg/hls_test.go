@@ -0,0 +1,181 @@
+package ffmpeg
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestWriteHLSPlaylist(t *testing.T) {
+	probeResult := VideoFile{
+		Duration: 25,
+	}
+
+	var b strings.Builder
+	WriteHLSPlaylist(probeResult, "/scene/1/stream/abc/", "resolution=LOW", &b)
+
+	want := `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-MEDIA-SEQUENCE:0
+#EXT-X-ALLOW-CACHE:YES
+#EXT-X-TARGETDURATION:10
+#EXT-X-PLAYLIST-TYPE:VOD
+#EXTINF: 10.000000,
+/scene/1/stream/abc/0.ts?resolution=LOW
+#EXTINF: 10.000000,
+/scene/1/stream/abc/1.ts?resolution=LOW
+#EXTINF: 5.000000,
+/scene/1/stream/abc/2.ts?resolution=LOW
+#EXT-X-ENDLIST
+`
+
+	assert.Equal(t, want, b.String())
+	assert.Equal(t, 3, HLSSegmentCount(probeResult))
+}
+
+func TestHLSTranscodeArgs(t *testing.T) {
+	o := HLSTranscodeOptions{
+		ProbeResult: VideoFile{
+			Path:   "in.mkv",
+			Width:  1920,
+			Height: 1080,
+		},
+		StartSegment: 3,
+		OutputDir:    "out",
+	}
+
+	want := []string{
+		"-hide_banner", "-v", "error",
+		"-ss", "30",
+		"-i", "in.mkv",
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-c:v", "libx264", "-vf", "scale=iw:-2", "-pix_fmt", "yuv420p", "-preset", "veryfast", "-crf", "25",
+		"-force_key_frames", "expr:gte(t,n_forced*10)",
+		"-c:a", "aac", "-ac", "2", "-sn",
+		"-output_ts_offset", "30",
+		"-f", "hls", "-hls_time", "10", "-hls_list_size", "0", "-hls_segment_type", "mpegts", "-hls_flags", "temp_file",
+		"-start_number", "3",
+		"-hls_segment_filename", filepath.Join("out", "%d.ts"),
+		filepath.Join("out", "stream.m3u8"),
+	}
+
+	assert.Equal(t, want, o.getArgs())
+
+	// the first segment is not seeked and video only streams drop the audio
+	o.StartSegment = 0
+	o.VideoOnly = true
+	args := strings.Join(o.getArgs(), " ")
+	assert.NotContains(t, args, "-ss")
+	assert.Contains(t, args, "-map 0:v:0 -an")
+}
+
+func TestGetHLSRenditions(t *testing.T) {
+	fullHD := VideoFile{
+		Width:  1920,
+		Height: 1080,
+	}
+	portrait := VideoFile{
+		Width:  720,
+		Height: 1280,
+	}
+
+	resolutions := func(renditions []HLSRendition) []models.StreamingResolutionEnum {
+		var ret []models.StreamingResolutionEnum
+		for _, r := range renditions {
+			ret = append(ret, r.Resolution)
+		}
+		return ret
+	}
+
+	// renditions are capped by the source size
+	renditions := GetHLSRenditions(fullHD, models.StreamingResolutionEnumOriginal)
+	assert.Equal(t, []models.StreamingResolutionEnum{
+		models.StreamingResolutionEnumLow,
+		models.StreamingResolutionEnumStandard,
+		models.StreamingResolutionEnumStandardHd,
+		models.StreamingResolutionEnumOriginal,
+	}, resolutions(renditions))
+	assert.Equal(t, HLSRendition{
+		Resolution: models.StreamingResolutionEnumStandard,
+		Width:      854,
+		Height:     480,
+		Bandwidth:  854*480*5/2 + hlsAudioBandwidth,
+	}, renditions[1])
+	assert.Equal(t, 1920, renditions[3].Width)
+
+	// and by the maximum streaming size
+	assert.Equal(t, []models.StreamingResolutionEnum{
+		models.StreamingResolutionEnumLow,
+		models.StreamingResolutionEnumStandard,
+	}, resolutions(GetHLSRenditions(fullHD, models.StreamingResolutionEnumStandard)))
+	assert.Equal(t, []models.StreamingResolutionEnum{
+		models.StreamingResolutionEnumLow,
+		models.StreamingResolutionEnumStandard,
+		models.StreamingResolutionEnumStandardHd,
+		models.StreamingResolutionEnumOriginal,
+	}, resolutions(GetHLSRenditions(fullHD, models.StreamingResolutionEnumFourK)))
+
+	// the smaller dimension of portrait videos is scaled
+	renditions = GetHLSRenditions(portrait, models.StreamingResolutionEnumOriginal)
+	assert.Equal(t, 480, renditions[1].Width)
+	assert.Equal(t, 854, renditions[1].Height)
+}
+
+func TestWriteHLSMasterPlaylist(t *testing.T) {
+	renditions := []HLSRendition{
+		{Resolution: models.StreamingResolutionEnumLow, Width: 426, Height: 240, Bandwidth: 383600},
+		{Resolution: models.StreamingResolutionEnumOriginal, Width: 1280, Height: 720, Bandwidth: 2432000},
+	}
+
+	var b strings.Builder
+	WriteHLSMasterPlaylist(renditions, nil, func(r HLSRendition) string {
+		return "stream.m3u8?resolution=" + r.Resolution.String()
+	}, &b)
+
+	want := `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-STREAM-INF:BANDWIDTH=383600,RESOLUTION=426x240
+stream.m3u8?resolution=LOW
+#EXT-X-STREAM-INF:BANDWIDTH=2432000,RESOLUTION=1280x720
+stream.m3u8?resolution=ORIGINAL
+`
+	assert.Equal(t, want, b.String())
+
+	// captions are a subtitle group of every rendition
+	captions := []HLSCaption{
+		{Name: "en", LanguageCode: "en", PlaylistURL: "stream/caption.m3u8?lang=en&type=srt"},
+	}
+
+	b.Reset()
+	WriteHLSMasterPlaylist(renditions[:1], captions, func(r HLSRendition) string {
+		return "stream.m3u8?resolution=" + r.Resolution.String()
+	}, &b)
+
+	want = `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="en",LANGUAGE="en",AUTOSELECT=YES,URI="stream/caption.m3u8?lang=en&type=srt"
+#EXT-X-STREAM-INF:BANDWIDTH=383600,RESOLUTION=426x240,SUBTITLES="subs"
+stream.m3u8?resolution=LOW
+`
+	assert.Equal(t, want, b.String())
+}
+
+func TestWriteHLSCaptionPlaylist(t *testing.T) {
+	var b strings.Builder
+	WriteHLSCaptionPlaylist(VideoFile{Duration: 25.5}, "/scene/1/caption?lang=en&type=srt", &b)
+
+	want := `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-MEDIA-SEQUENCE:0
+#EXT-X-TARGETDURATION:26
+#EXT-X-PLAYLIST-TYPE:VOD
+#EXTINF: 25.500000,
+/scene/1/caption?lang=en&type=srt
+#EXT-X-ENDLIST
+`
+	assert.Equal(t, want, b.String())
+}
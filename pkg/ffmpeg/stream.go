@@ -42,73 +42,87 @@ func (s *Stream) Serve(w http.ResponseWriter, r *http.Request) {
 }
 
 type Codec struct {
-	Codec     string
-	format    string
-	MimeType  string
-	extraArgs []string
-	hls       bool
+	Codec    string
+	format   string
+	MimeType string
+	// encoderArgs are the arguments of the software encoder. They are not
+	// used when the transcode profile replaces the encoder.
+	encoderArgs []string
+	extraArgs   []string
+	// captionCodec is the codec of the caption tracks. Captions are not
+	// added to formats without a caption codec.
+	captionCodec string
 }
 
 var CodecHLS = Codec{
 	Codec:    "libx264",
 	format:   "mpegts",
 	MimeType: MimeMpegts,
-	extraArgs: []string{
-		"-acodec", "aac",
+	encoderArgs: []string{
 		"-pix_fmt", "yuv420p",
 		"-preset", "veryfast",
 		"-crf", "25",
 	},
-	hls: true,
+	extraArgs: []string{
+		"-acodec", "aac",
+	},
 }
 
 var CodecH264 = Codec{
 	Codec:    "libx264",
 	format:   "mp4",
 	MimeType: MimeMp4,
-	extraArgs: []string{
-		"-movflags", "frag_keyframe+empty_moov",
+	encoderArgs: []string{
 		"-pix_fmt", "yuv420p",
 		"-preset", "veryfast",
 		"-crf", "25",
 	},
+	extraArgs: []string{
+		"-movflags", "frag_keyframe+empty_moov",
+	},
+	captionCodec: "mov_text",
 }
 
 var CodecVP9 = Codec{
 	Codec:    "libvpx-vp9",
 	format:   "webm",
 	MimeType: MimeWebm,
-	extraArgs: []string{
+	encoderArgs: []string{
 		"-deadline", "realtime",
 		"-cpu-used", "5",
 		"-row-mt", "1",
 		"-crf", "30",
 		"-b:v", "0",
 	},
+	captionCodec: "webvtt",
 }
 
 var CodecVP8 = Codec{
 	Codec:    "libvpx",
 	format:   "webm",
 	MimeType: MimeWebm,
-	extraArgs: []string{
+	encoderArgs: []string{
 		"-deadline", "realtime",
 		"-cpu-used", "5",
 		"-crf", "12",
 		"-b:v", "3M",
 		"-pix_fmt", "yuv420p",
 	},
+	captionCodec: "webvtt",
 }
 
 var CodecHEVC = Codec{
 	Codec:    "libx265",
 	format:   "mp4",
 	MimeType: MimeMp4,
-	extraArgs: []string{
-		"-movflags", "frag_keyframe",
+	encoderArgs: []string{
 		"-preset", "veryfast",
 		"-crf", "30",
 	},
+	extraArgs: []string{
+		"-movflags", "frag_keyframe",
+	},
+	captionCodec: "mov_text",
 }
 
 // it is very common in MKVs to have just the audio codec unsupported
@@ -122,6 +136,7 @@ var CodecMKVAudio = Codec{
 		"-b:a", "96k",
 		"-vbr", "on",
 	},
+	captionCodec: "webvtt",
 }
 
 type TranscodeStreamOptions struct {
@@ -129,6 +144,14 @@ type TranscodeStreamOptions struct {
 	Codec            Codec
 	StartTime        string
 	MaxTranscodeSize models.StreamingResolutionEnum
+	// Profile selects the encoder of the video stream
+	Profile TranscodeProfile
+	// Captions are added to the stream if the format supports them
+	Captions []Caption
+	// VideoTrack and AudioTrack are the indexes of the streams to transcode.
+	// ffmpeg selects the streams if they are nil.
+	VideoTrack *int
+	AudioTrack *int
 	// transcode the video, remove the audio
 	// in some videos where the audio codec is not supported by ffmpeg
 	// ffmpeg fails if you try to transcode the audio
@@ -159,29 +182,44 @@ func (o TranscodeStreamOptions) getStreamArgs() []string {
 		args = append(args, "-ss", o.StartTime)
 	}
 
-	if o.Codec.hls {
-		// we only serve a fixed segment length
-		args = append(args, "-t", strconv.Itoa(int(hlsSegmentLength)))
+	// hardware devices are initialised before the input
+	if o.Codec.Codec != CopyStreamCodec {
+		args = append(args, o.Profile.inputArgs()...)
 	}
 
 	args = append(args,
 		"-i", o.ProbeResult.Path,
 	)
 
+	captions := o.Captions
+	if o.Codec.captionCodec == "" {
+		captions = nil
+	}
+
+	// caption files are additional inputs, seeked like the video file
+	for _, c := range captions {
+		if c.Path != "" {
+			if o.StartTime != "" {
+				args = append(args, "-ss", o.StartTime)
+			}
+			args = append(args, "-i", c.Path)
+		}
+	}
+
+	if len(captions) > 0 || o.VideoTrack != nil || o.AudioTrack != nil {
+		args = append(args, o.getMapArgs(captions)...)
+	}
+
 	if o.VideoOnly {
 		args = append(args, "-an")
 	}
 
-	args = append(args,
-		"-c:v", o.Codec.Codec,
-	)
-
 	// don't set scale when copying video stream
 	if o.Codec.Codec != CopyStreamCodec {
 		scale := calculateTranscodeScale(o.ProbeResult, o.MaxTranscodeSize)
-		args = append(args,
-			"-vf", "scale="+scale,
-		)
+		args = append(args, o.Profile.videoArgs(o.Codec.Codec, scale, o.Codec.encoderArgs)...)
+	} else {
+		args = append(args, "-c:v", CopyStreamCodec)
 	}
 
 	if len(o.Codec.extraArgs) > 0 {
@@ -198,6 +236,51 @@ func (o TranscodeStreamOptions) getStreamArgs() []string {
 	return args
 }
 
+// getTrackMapArgs returns the arguments that select the video and audio
+// tracks. The first tracks are used if the tracks are nil.
+func getTrackMapArgs(videoTrack *int, audioTrack *int, videoOnly bool) []string {
+	args := []string{"-map", "0:v:0"}
+	if videoTrack != nil {
+		args[1] = "0:" + strconv.Itoa(*videoTrack)
+	}
+
+	if videoOnly {
+		return args
+	}
+
+	if audioTrack != nil {
+		return append(args, "-map", "0:"+strconv.Itoa(*audioTrack))
+	}
+
+	return append(args, "-map", "0:a:0?")
+}
+
+// getMapArgs returns the arguments that select the video, audio and caption
+// streams of the output.
+func (o TranscodeStreamOptions) getMapArgs(captions []Caption) []string {
+	args := getTrackMapArgs(o.VideoTrack, o.AudioTrack, o.VideoOnly)
+
+	input := 1
+	for i, c := range captions {
+		if c.Path != "" {
+			args = append(args, "-map", strconv.Itoa(input)+":s:0")
+			input++
+		} else {
+			args = append(args, "-map", "0:"+strconv.Itoa(c.StreamIndex))
+		}
+
+		if c.LanguageCode != "" {
+			args = append(args, "-metadata:s:s:"+strconv.Itoa(i), "language="+c.LanguageCode)
+		}
+	}
+
+	if len(captions) > 0 {
+		args = append(args, "-c:s", o.Codec.captionCodec)
+	}
+
+	return args
+}
+
 func (e *Encoder) GetTranscodeStream(options TranscodeStreamOptions) (*Stream, error) {
 	return e.stream(options.ProbeResult, options)
 }
@@ -0,0 +1,81 @@
+package ffmpeg
+
+import (
+	"strings"
+)
+
+const (
+	streamTypeVideo = "video"
+	streamTypeAudio = "audio"
+)
+
+// GetVideoStreams returns the video streams of the file. Attached pictures
+// such as cover art are not included.
+func (v *VideoFile) GetVideoStreams() []*FFProbeStream {
+	var ret []*FFProbeStream
+	for i, stream := range v.JSON.Streams {
+		if stream.CodecType == streamTypeVideo && stream.Disposition.AttachedPic == 0 {
+			ret = append(ret, &v.JSON.Streams[i])
+		}
+	}
+
+	return ret
+}
+
+// GetAudioStreams returns the audio streams of the file.
+func (v *VideoFile) GetAudioStreams() []*FFProbeStream {
+	var ret []*FFProbeStream
+	for i, stream := range v.JSON.Streams {
+		if stream.CodecType == streamTypeAudio {
+			ret = append(ret, &v.JSON.Streams[i])
+		}
+	}
+
+	return ret
+}
+
+func findStream(streams []*FFProbeStream, index int) *FFProbeStream {
+	for _, s := range streams {
+		if s.Index == index {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// HasVideoStream returns true if the stream at index is a video stream.
+func (v *VideoFile) HasVideoStream(index int) bool {
+	return findStream(v.GetVideoStreams(), index) != nil
+}
+
+// HasAudioStream returns true if the stream at index is an audio stream.
+func (v *VideoFile) HasAudioStream(index int) bool {
+	return findStream(v.GetAudioStreams(), index) != nil
+}
+
+// SelectAudioStream returns the audio stream in the first of the languages
+// that the file has an audio stream in. Returns nil if no audio stream is
+// in any of the languages.
+func (v *VideoFile) SelectAudioStream(languages []string) *FFProbeStream {
+	streams := v.GetAudioStreams()
+	for _, lang := range languages {
+		var match *FFProbeStream
+		for _, s := range streams {
+			if !strings.EqualFold(s.Tags.Language, lang) {
+				continue
+			}
+
+			// prefer the default stream of the language
+			if match == nil || (match.Disposition.Default == 0 && s.Disposition.Default != 0) {
+				match = s
+			}
+		}
+
+		if match != nil {
+			return match
+		}
+	}
+
+	return nil
+}
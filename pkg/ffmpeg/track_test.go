@@ -0,0 +1,115 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTrackTestFile() *VideoFile {
+	videoFile := &VideoFile{Path: "in.mkv", Width: 1280, Height: 720}
+	videoFile.JSON.Streams = []FFProbeStream{
+		{Index: 0, CodecType: "video", CodecName: "h264"},
+		{Index: 1, CodecType: "audio", CodecName: "aac"},
+		{Index: 2, CodecType: "audio", CodecName: "ac3"},
+		{Index: 3, CodecType: "audio", CodecName: "aac"},
+		{Index: 4, CodecType: "subtitle", CodecName: "subrip"},
+		{Index: 5, CodecType: "video", CodecName: "mjpeg"},
+	}
+	videoFile.JSON.Streams[1].Tags.Language = "eng"
+	videoFile.JSON.Streams[2].Tags.Language = "jpn"
+	videoFile.JSON.Streams[3].Tags.Language = "jpn"
+	videoFile.JSON.Streams[3].Disposition.Default = 1
+	videoFile.JSON.Streams[5].Disposition.AttachedPic = 1
+
+	return videoFile
+}
+
+func streamIndexes(streams []*FFProbeStream) []int {
+	var ret []int
+	for _, s := range streams {
+		ret = append(ret, s.Index)
+	}
+	return ret
+}
+
+func TestGetStreams(t *testing.T) {
+	videoFile := newTrackTestFile()
+
+	// cover art is not a video stream
+	assert.Equal(t, []int{0}, streamIndexes(videoFile.GetVideoStreams()))
+	assert.Equal(t, []int{1, 2, 3}, streamIndexes(videoFile.GetAudioStreams()))
+
+	assert.True(t, videoFile.HasVideoStream(0))
+	assert.False(t, videoFile.HasVideoStream(1))
+	assert.False(t, videoFile.HasVideoStream(5))
+	assert.True(t, videoFile.HasAudioStream(2))
+	assert.False(t, videoFile.HasAudioStream(4))
+}
+
+func TestSelectAudioStream(t *testing.T) {
+	videoFile := newTrackTestFile()
+
+	tests := []struct {
+		name      string
+		languages []string
+		want      int
+	}{
+		{"none", nil, -1},
+		{"first language", []string{"eng", "jpn"}, 1},
+		{"second language", []string{"fre", "jpn"}, 3},
+		{"case insensitive", []string{"ENG"}, 1},
+		{"no match", []string{"fre"}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := videoFile.SelectAudioStream(tt.languages)
+			if tt.want == -1 {
+				assert.Nil(t, got)
+				return
+			}
+
+			// the default stream of the language is preferred
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.want, got.Index)
+			}
+		})
+	}
+}
+
+func TestGetStreamArgsTracks(t *testing.T) {
+	videoTrack := 0
+	audioTrack := 2
+	o := TranscodeStreamOptions{
+		ProbeResult: *newTrackTestFile(),
+		Codec:       CodecVP9,
+		AudioTrack:  &audioTrack,
+	}
+
+	assert.Contains(t, strings.Join(o.getStreamArgs(), " "), "-i in.mkv -map 0:v:0 -map 0:2 -c:v")
+
+	o.VideoTrack = &videoTrack
+	o.AudioTrack = nil
+	assert.Contains(t, strings.Join(o.getStreamArgs(), " "), "-i in.mkv -map 0:0 -map 0:a:0? -c:v")
+
+	// streams are not mapped if no track is selected
+	o.VideoTrack = nil
+	assert.NotContains(t, strings.Join(o.getStreamArgs(), " "), "-map")
+}
+
+func TestHLSTranscodeArgsTracks(t *testing.T) {
+	audioTrack := 3
+	o := HLSTranscodeOptions{
+		ProbeResult: *newTrackTestFile(),
+		AudioTrack:  &audioTrack,
+		OutputDir:   "out",
+	}
+
+	assert.Contains(t, strings.Join(o.getArgs(), " "), "-map 0:v:0 -map 0:3 ")
+
+	// the audio track is ignored for video only streams
+	o.VideoOnly = true
+	assert.Contains(t, strings.Join(o.getArgs(), " "), "-map 0:v:0 -an")
+}
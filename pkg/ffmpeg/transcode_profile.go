@@ -0,0 +1,215 @@
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const defaultVAAPIDevice = "/dev/dri/renderD128"
+
+// ScalePlaceholder is replaced with the scale of the output video in the
+// video arguments of a custom transcode profile.
+const ScalePlaceholder = "{scale}"
+
+// testEncodeTimeout is the maximum duration of the test encode of a
+// transcode profile.
+const testEncodeTimeout = 30 * time.Second
+
+// hardwareEncoders maps the software encoders to the encoders of each
+// hardware acceleration method. Codecs that are not mapped are always
+// encoded in software.
+var hardwareEncoders = map[models.HardwareAcceleration]map[string]string{
+	models.HardwareAccelerationVaapi: {
+		"libx264": "h264_vaapi",
+		"libx265": "hevc_vaapi",
+	},
+	models.HardwareAccelerationQsv: {
+		"libx264": "h264_qsv",
+		"libx265": "hevc_qsv",
+	},
+	models.HardwareAccelerationNvenc: {
+		"libx264": "h264_nvenc",
+		"libx265": "hevc_nvenc",
+	},
+	models.HardwareAccelerationCustom: {
+		// the encoder is chosen by the video arguments of the profile
+		"libx264": "",
+	},
+}
+
+// TranscodeProfile describes how video is encoded when transcoding streams
+// and generating previews. The zero value encodes in software.
+type TranscodeProfile struct {
+	HWAccel models.HardwareAcceleration
+	// Device is the render device used by the VAAPI and QSV profiles, or the
+	// GPU index used by the NVENC profile. Empty for the default device.
+	Device string
+	// InputArgs are added before the input of the custom profile.
+	InputArgs []string
+	// VideoArgs encode H.264 video in the custom profile. ScalePlaceholder
+	// is replaced with the scale of the output video.
+	VideoArgs []string
+}
+
+// SoftwareTranscodeProfile encodes video with the software encoders.
+var SoftwareTranscodeProfile = TranscodeProfile{
+	HWAccel: models.HardwareAccelerationNone,
+}
+
+// IsSoftware returns true if the profile encodes all video in software.
+func (p TranscodeProfile) IsSoftware() bool {
+	return p.HWAccel == "" || p.HWAccel == models.HardwareAccelerationNone
+}
+
+func (p TranscodeProfile) validate() error {
+	switch p.HWAccel {
+	case "", models.HardwareAccelerationNone, models.HardwareAccelerationVaapi, models.HardwareAccelerationQsv, models.HardwareAccelerationNvenc:
+		return nil
+	case models.HardwareAccelerationCustom:
+		if len(p.VideoArgs) == 0 {
+			return errors.New("custom transcode profile has no video arguments")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("invalid hardware acceleration: %s", p.HWAccel)
+}
+
+// inputArgs returns the arguments that must be added before the input
+// file.
+func (p TranscodeProfile) inputArgs() []string {
+	switch p.HWAccel {
+	case models.HardwareAccelerationVaapi:
+		device := p.Device
+		if device == "" {
+			device = defaultVAAPIDevice
+		}
+		return []string{"-vaapi_device", device}
+	case models.HardwareAccelerationQsv:
+		device := "qsv=hw"
+		if p.Device != "" {
+			device += ",child_device=" + p.Device
+		}
+		return []string{"-init_hw_device", device, "-filter_hw_device", "hw"}
+	case models.HardwareAccelerationCustom:
+		return p.InputArgs
+	}
+
+	return nil
+}
+
+// videoArgs returns the arguments that encode the video stream, scaled to
+// scale, with the encoder of the profile that replaces the software encoder
+// codec. softwareArgs are the encoder arguments used if the profile encodes
+// codec in software.
+func (p TranscodeProfile) videoArgs(codec string, scale string, softwareArgs []string) []string {
+	encoder, ok := hardwareEncoders[p.HWAccel][codec]
+	if !ok {
+		args := []string{
+			"-c:v", codec,
+			"-vf", "scale=" + scale,
+		}
+		return append(args, softwareArgs...)
+	}
+
+	switch p.HWAccel {
+	case models.HardwareAccelerationVaapi:
+		return []string{
+			"-c:v", encoder,
+			"-vf", "scale=" + scale + ",format=nv12,hwupload",
+			"-qp", "25",
+		}
+	case models.HardwareAccelerationQsv:
+		return []string{
+			"-c:v", encoder,
+			"-vf", "scale=" + scale + ",format=nv12,hwupload=extra_hw_frames=64",
+			"-preset", "veryfast",
+			"-global_quality", "25",
+		}
+	case models.HardwareAccelerationNvenc:
+		args := []string{
+			"-c:v", encoder,
+			"-vf", "scale=" + scale + ",format=yuv420p",
+			"-preset", "fast",
+			"-rc", "vbr",
+			"-cq", "25",
+			"-b:v", "0",
+		}
+		if p.Device != "" {
+			args = append(args, "-gpu", p.Device)
+		}
+		return args
+	}
+
+	// custom profile
+	var args []string
+	for _, arg := range p.VideoArgs {
+		args = append(args, strings.ReplaceAll(arg, ScalePlaceholder, scale))
+	}
+	return args
+}
+
+// testEncodeArgs returns the arguments of a short encode of a generated
+// test video with the profile.
+func (p TranscodeProfile) testEncodeArgs() []string {
+	args := []string{
+		"-hide_banner",
+		"-v", "error",
+	}
+	args = append(args, p.inputArgs()...)
+	args = append(args,
+		"-f", "lavfi",
+		"-i", "testsrc=duration=1:size=1280x720:rate=30",
+	)
+	args = append(args, p.videoArgs("libx264", "-2:480", nil)...)
+	return append(args, "-f", "null", "-")
+}
+
+// TestTranscodeProfile encodes a short test video with the profile and
+// returns an error if the encode fails.
+func (e *Encoder) TestTranscodeProfile(p TranscodeProfile) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testEncodeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, e.Path, p.testEncodeArgs()...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %s", err.Error(), msg)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// SelectTranscodeProfile returns p if it passes a test encode. Otherwise it
+// returns the software profile.
+func (e *Encoder) SelectTranscodeProfile(p TranscodeProfile) TranscodeProfile {
+	return selectTranscodeProfile(p, e.TestTranscodeProfile)
+}
+
+func selectTranscodeProfile(p TranscodeProfile, test func(TranscodeProfile) error) TranscodeProfile {
+	if p.IsSoftware() {
+		return SoftwareTranscodeProfile
+	}
+
+	if err := test(p); err != nil {
+		logger.Warnf("[transcode] %s transcoding failed test encode, falling back to software encoding: %s", p.HWAccel, err.Error())
+		return SoftwareTranscodeProfile
+	}
+
+	logger.Infof("[transcode] using %s hardware transcoding", p.HWAccel)
+	return p
+}
@@ -0,0 +1,126 @@
+package ffmpeg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestSelectTranscodeProfile(t *testing.T) {
+	vaapi := TranscodeProfile{HWAccel: models.HardwareAccelerationVaapi}
+
+	pass := func(TranscodeProfile) error { return nil }
+	fail := func(TranscodeProfile) error { return errors.New("no device") }
+	noTest := func(TranscodeProfile) error {
+		t.Error("software profile should not be tested")
+		return nil
+	}
+
+	assert.Equal(t, SoftwareTranscodeProfile, selectTranscodeProfile(TranscodeProfile{}, noTest))
+	assert.Equal(t, SoftwareTranscodeProfile, selectTranscodeProfile(SoftwareTranscodeProfile, noTest))
+	assert.Equal(t, vaapi, selectTranscodeProfile(vaapi, pass))
+	assert.Equal(t, SoftwareTranscodeProfile, selectTranscodeProfile(vaapi, fail))
+}
+
+func TestTranscodeProfileValidate(t *testing.T) {
+	assert.Nil(t, TranscodeProfile{}.validate())
+	assert.Nil(t, TranscodeProfile{HWAccel: models.HardwareAccelerationNvenc}.validate())
+	assert.NotNil(t, TranscodeProfile{HWAccel: models.HardwareAccelerationCustom}.validate())
+	assert.Nil(t, TranscodeProfile{
+		HWAccel:   models.HardwareAccelerationCustom,
+		VideoArgs: []string{"-c:v", "h264_v4l2m2m"},
+	}.validate())
+	assert.NotNil(t, TranscodeProfile{HWAccel: "invalid"}.validate())
+}
+
+func TestGetStreamArgs(t *testing.T) {
+	probeResult := VideoFile{
+		Path:   "in.mkv",
+		Width:  3840,
+		Height: 2160,
+	}
+
+	tests := []struct {
+		name    string
+		codec   Codec
+		profile TranscodeProfile
+		want    string
+	}{
+		{
+			"software",
+			CodecH264,
+			TranscodeProfile{},
+			"-hide_banner -v error -i in.mkv -c:v libx264 -vf scale=-2:720 -pix_fmt yuv420p -preset veryfast -crf 25 -movflags frag_keyframe+empty_moov -ac 2 -f mp4 pipe:",
+		},
+		{
+			"vaapi",
+			CodecH264,
+			TranscodeProfile{HWAccel: models.HardwareAccelerationVaapi},
+			"-hide_banner -v error -vaapi_device /dev/dri/renderD128 -i in.mkv -c:v h264_vaapi -vf scale=-2:720,format=nv12,hwupload -qp 25 -movflags frag_keyframe+empty_moov -ac 2 -f mp4 pipe:",
+		},
+		{
+			"vaapi device",
+			CodecH264,
+			TranscodeProfile{HWAccel: models.HardwareAccelerationVaapi, Device: "/dev/dri/renderD129"},
+			"-hide_banner -v error -vaapi_device /dev/dri/renderD129 -i in.mkv -c:v h264_vaapi -vf scale=-2:720,format=nv12,hwupload -qp 25 -movflags frag_keyframe+empty_moov -ac 2 -f mp4 pipe:",
+		},
+		{
+			"qsv",
+			CodecH264,
+			TranscodeProfile{HWAccel: models.HardwareAccelerationQsv},
+			"-hide_banner -v error -init_hw_device qsv=hw -filter_hw_device hw -i in.mkv -c:v h264_qsv -vf scale=-2:720,format=nv12,hwupload=extra_hw_frames=64 -preset veryfast -global_quality 25 -movflags frag_keyframe+empty_moov -ac 2 -f mp4 pipe:",
+		},
+		{
+			"nvenc",
+			CodecH264,
+			TranscodeProfile{HWAccel: models.HardwareAccelerationNvenc, Device: "1"},
+			"-hide_banner -v error -i in.mkv -c:v h264_nvenc -vf scale=-2:720,format=yuv420p -preset fast -rc vbr -cq 25 -b:v 0 -gpu 1 -movflags frag_keyframe+empty_moov -ac 2 -f mp4 pipe:",
+		},
+		{
+			"custom",
+			CodecH264,
+			TranscodeProfile{
+				HWAccel:   models.HardwareAccelerationCustom,
+				InputArgs: []string{"-hwaccel", "drm"},
+				VideoArgs: []string{"-vf", "scale={scale}", "-c:v", "h264_v4l2m2m", "-b:v", "4M"},
+			},
+			"-hide_banner -v error -hwaccel drm -i in.mkv -vf scale=-2:720 -c:v h264_v4l2m2m -b:v 4M -movflags frag_keyframe+empty_moov -ac 2 -f mp4 pipe:",
+		},
+		{
+			"no hardware encoder",
+			CodecVP9,
+			TranscodeProfile{HWAccel: models.HardwareAccelerationNvenc},
+			"-hide_banner -v error -i in.mkv -c:v libvpx-vp9 -vf scale=-2:720 -deadline realtime -cpu-used 5 -row-mt 1 -crf 30 -b:v 0 -ac 2 -f webm pipe:",
+		},
+		{
+			"copy",
+			CodecMKVAudio,
+			TranscodeProfile{HWAccel: models.HardwareAccelerationVaapi},
+			"-hide_banner -v error -i in.mkv -c:v copy -c:a libopus -b:a 96k -vbr on -ac 2 -f matroska pipe:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := TranscodeStreamOptions{
+				ProbeResult:      probeResult,
+				Codec:            tt.codec,
+				MaxTranscodeSize: models.StreamingResolutionEnumStandardHd,
+				Profile:          tt.profile,
+			}
+
+			assert.Equal(t, tt.want, strings.Join(o.getStreamArgs(), " "))
+		})
+	}
+}
+
+func TestTestEncodeArgs(t *testing.T) {
+	p := TranscodeProfile{HWAccel: models.HardwareAccelerationQsv, Device: "/dev/dri/renderD128"}
+	want := "-hide_banner -v error -init_hw_device qsv=hw,child_device=/dev/dri/renderD128 -filter_hw_device hw -f lavfi -i testsrc=duration=1:size=1280x720:rate=30 -c:v h264_qsv -vf scale=-2:480,format=nv12,hwupload=extra_hw_frames=64 -preset veryfast -global_quality 25 -f null -"
+
+	assert.Equal(t, want, strings.Join(p.testEncodeArgs(), " "))
+}
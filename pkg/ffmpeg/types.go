@@ -82,6 +82,7 @@ type FFProbeStream struct {
 		HandlerName  string          `json:"handler_name"`
 		Language     string          `json:"language"`
 		Rotate       string          `json:"rotate"`
+		Title        string          `json:"title"`
 	} `json:"tags"`
 	TimeBase      string `json:"time_base"`
 	Width         int    `json:"width,omitempty"`
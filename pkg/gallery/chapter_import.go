@@ -0,0 +1,80 @@
+package gallery
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type ChapterImporter struct {
+	GalleryID           int
+	ReaderWriter        models.GalleryChapterReaderWriter
+	Input               jsonschema.GalleryChapter
+	MissingRefBehaviour models.ImportMissingRefEnum
+
+	chapter models.GalleryChapter
+}
+
+func (i *ChapterImporter) PreImport() error {
+	if i.Input.ImageIndex < 1 {
+		return fmt.Errorf("invalid image index %d", i.Input.ImageIndex)
+	}
+
+	i.chapter = models.GalleryChapter{
+		Title:      i.Input.Title,
+		ImageIndex: i.Input.ImageIndex,
+		GalleryID:  sql.NullInt64{Int64: int64(i.GalleryID), Valid: true},
+		CreatedAt:  models.SQLiteTimestamp{Timestamp: i.Input.CreatedAt.GetTime()},
+		UpdatedAt:  models.SQLiteTimestamp{Timestamp: i.Input.UpdatedAt.GetTime()},
+	}
+
+	return nil
+}
+
+func (i *ChapterImporter) PostImport(id int) error {
+	return nil
+}
+
+func (i *ChapterImporter) Name() string {
+	return fmt.Sprintf("%s (%d)", i.Input.Title, i.Input.ImageIndex)
+}
+
+func (i *ChapterImporter) FindExistingID() (*int, error) {
+	existingChapters, err := i.ReaderWriter.FindByGalleryID(i.GalleryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range existingChapters {
+		if c.ImageIndex == i.chapter.ImageIndex {
+			id := c.ID
+			return &id, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (i *ChapterImporter) Create() (*int, error) {
+	created, err := i.ReaderWriter.Create(i.chapter)
+	if err != nil {
+		return nil, fmt.Errorf("error creating chapter: %s", err.Error())
+	}
+
+	id := created.ID
+	return &id, nil
+}
+
+func (i *ChapterImporter) Update(id int) error {
+	chapter := i.chapter
+	chapter.ID = id
+	_, err := i.ReaderWriter.Update(chapter)
+	if err != nil {
+		return fmt.Errorf("error updating existing chapter: %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package gallery
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	imageIndex        = 5
+	existingChapterID = 110
+	errChapterID      = 999
+)
+
+func TestChapterImporterName(t *testing.T) {
+	i := ChapterImporter{
+		Input: jsonschema.GalleryChapter{
+			Title:      title,
+			ImageIndex: imageIndex,
+		},
+	}
+
+	assert.Equal(t, title+" (5)", i.Name())
+}
+
+func TestChapterImporterPreImport(t *testing.T) {
+	i := ChapterImporter{
+		GalleryID: galleryID,
+		Input: jsonschema.GalleryChapter{
+			Title:      title,
+			ImageIndex: imageIndex,
+			CreatedAt: models.JSONTime{
+				Time: createdAt,
+			},
+			UpdatedAt: models.JSONTime{
+				Time: updatedAt,
+			},
+		},
+	}
+
+	err := i.PreImport()
+	assert.Nil(t, err)
+	assert.Equal(t, models.GalleryChapter{
+		Title:      title,
+		ImageIndex: imageIndex,
+		GalleryID:  sql.NullInt64{Int64: galleryID, Valid: true},
+		CreatedAt:  models.SQLiteTimestamp{Timestamp: createdAt},
+		UpdatedAt:  models.SQLiteTimestamp{Timestamp: updatedAt},
+	}, i.chapter)
+
+	i.Input.ImageIndex = 0
+	err = i.PreImport()
+	assert.NotNil(t, err)
+}
+
+func TestChapterImporterFindExistingID(t *testing.T) {
+	readerWriter := &mocks.GalleryChapterReaderWriter{}
+
+	i := ChapterImporter{
+		ReaderWriter: readerWriter,
+		GalleryID:    galleryID,
+		chapter: models.GalleryChapter{
+			ImageIndex: imageIndex,
+		},
+	}
+
+	expectedErr := errors.New("FindBy* error")
+	readerWriter.On("FindByGalleryID", galleryID).Return([]*models.GalleryChapter{
+		{
+			ID:         existingChapterID,
+			ImageIndex: imageIndex,
+		},
+	}, nil).Times(2)
+	readerWriter.On("FindByGalleryID", errChapterID).Return(nil, expectedErr).Once()
+
+	id, err := i.FindExistingID()
+	assert.Equal(t, existingChapterID, *id)
+	assert.Nil(t, err)
+
+	i.chapter.ImageIndex++
+	id, err = i.FindExistingID()
+	assert.Nil(t, id)
+	assert.Nil(t, err)
+
+	i.GalleryID = errChapterID
+	id, err = i.FindExistingID()
+	assert.Nil(t, id)
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
+
+func TestChapterImporterCreate(t *testing.T) {
+	readerWriter := &mocks.GalleryChapterReaderWriter{}
+
+	chapter := models.GalleryChapter{
+		Title: title,
+	}
+
+	chapterErr := models.GalleryChapter{
+		Title: galleryNameErr,
+	}
+
+	i := ChapterImporter{
+		ReaderWriter: readerWriter,
+		chapter:      chapter,
+	}
+
+	errCreate := errors.New("Create error")
+	readerWriter.On("Create", chapter).Return(&models.GalleryChapter{
+		ID: existingChapterID,
+	}, nil).Once()
+	readerWriter.On("Create", chapterErr).Return(nil, errCreate).Once()
+
+	id, err := i.Create()
+	assert.Equal(t, existingChapterID, *id)
+	assert.Nil(t, err)
+
+	i.chapter = chapterErr
+	id, err = i.Create()
+	assert.Nil(t, id)
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
+
+func TestChapterImporterUpdate(t *testing.T) {
+	readerWriter := &mocks.GalleryChapterReaderWriter{}
+
+	chapter := models.GalleryChapter{
+		Title: title,
+	}
+
+	chapterErr := models.GalleryChapter{
+		Title: galleryNameErr,
+	}
+
+	i := ChapterImporter{
+		ReaderWriter: readerWriter,
+		chapter:      chapter,
+	}
+
+	errUpdate := errors.New("Update error")
+
+	// id needs to be set for the mock input
+	chapter.ID = existingChapterID
+	readerWriter.On("Update", chapter).Return(nil, nil).Once()
+
+	err := i.Update(existingChapterID)
+	assert.Nil(t, err)
+
+	i.chapter = chapterErr
+
+	// need to set id separately
+	chapterErr.ID = errChapterID
+	readerWriter.On("Update", chapterErr).Return(nil, errUpdate).Once()
+
+	err = i.Update(errChapterID)
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
@@ -1,6 +1,8 @@
 package gallery
 
 import (
+	"fmt"
+
 	"github.com/stashapp/stash/pkg/manager/jsonschema"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
@@ -66,6 +68,62 @@ func GetStudioName(reader models.StudioReader, gallery *models.Gallery) (string,
 	return "", nil
 }
 
+// GetImageOrder returns the checksums of the images of the gallery that have
+// a position, in position order, and the checksum of the cover image of the
+// gallery. The cover checksum is empty if the gallery has no cover image.
+func GetImageOrder(reader models.GalleryReader, imageReader models.ImageReader, gallery *models.Gallery) ([]string, string, error) {
+	imageIDs, err := reader.GetOrderedImageIDs(gallery.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var checksums []string
+	if len(imageIDs) > 0 {
+		images, err := imageReader.FindMany(imageIDs)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, i := range images {
+			checksums = append(checksums, i.Checksum)
+		}
+	}
+
+	cover := ""
+	if gallery.CoverImageID.Valid {
+		i, err := imageReader.Find(int(gallery.CoverImageID.Int64))
+		if err != nil {
+			return nil, "", err
+		}
+
+		if i != nil {
+			cover = i.Checksum
+		}
+	}
+
+	return checksums, cover, nil
+}
+
+// GetGalleryChaptersJSON returns the chapters of the gallery in JSON form.
+func GetGalleryChaptersJSON(chapterReader models.GalleryChapterReader, gallery *models.Gallery) ([]jsonschema.GalleryChapter, error) {
+	chapters, err := chapterReader.FindByGalleryID(gallery.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting gallery chapters: %s", err.Error())
+	}
+
+	var results []jsonschema.GalleryChapter
+	for _, chapter := range chapters {
+		results = append(results, jsonschema.GalleryChapter{
+			Title:      chapter.Title,
+			ImageIndex: chapter.ImageIndex,
+			CreatedAt:  models.JSONTime{Time: chapter.CreatedAt.Timestamp},
+			UpdatedAt:  models.JSONTime{Time: chapter.UpdatedAt.Timestamp},
+		})
+	}
+
+	return results, nil
+}
+
 func GetIDs(galleries []*models.Gallery) []int {
 	var results []int
 	for _, gallery := range galleries {
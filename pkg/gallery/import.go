@@ -18,6 +18,7 @@ type Importer struct {
 	Input               jsonschema.Gallery
 	MissingRefBehaviour models.ImportMissingRefEnum
 
+	ID         int
 	gallery    models.Gallery
 	performers []*models.Performer
 	tags       []*models.Tag
@@ -284,12 +285,14 @@ func (i *Importer) Create() (*int, error) {
 	}
 
 	id := created.ID
+	i.ID = id
 	return &id, nil
 }
 
 func (i *Importer) Update(id int) error {
 	gallery := i.gallery
 	gallery.ID = id
+	i.ID = id
 	_, err := i.ReaderWriter.Update(gallery)
 	if err != nil {
 		return fmt.Errorf("error updating existing gallery: %s", err.Error())
@@ -297,3 +300,60 @@ func (i *Importer) Update(id int) error {
 
 	return nil
 }
+
+// ImportImageOrder sets the image order and cover image of the gallery from
+// the gallery JSON. Galleries are imported before their images, so it must
+// be called after the images are imported. Images that are not in the
+// gallery are ignored.
+func ImportImageOrder(readerWriter models.GalleryReaderWriter, imageReader models.ImageReader, galleryID int, galleryJSON jsonschema.Gallery) error {
+	imageIDs, err := readerWriter.GetImageIDs(galleryID)
+	if err != nil {
+		return err
+	}
+
+	findImage := func(checksum string) (int, error) {
+		i, err := imageReader.FindByChecksum(checksum)
+		if err != nil {
+			return 0, fmt.Errorf("error finding image by checksum: %s", err.Error())
+		}
+
+		if i == nil || !utils.IntInclude(imageIDs, i.ID) {
+			return 0, nil
+		}
+
+		return i.ID, nil
+	}
+
+	var order []int
+	for _, checksum := range galleryJSON.Images {
+		id, err := findImage(checksum)
+		if err != nil {
+			return err
+		}
+
+		if id != 0 {
+			order = append(order, id)
+		}
+	}
+
+	if len(order) > 0 {
+		if err := readerWriter.UpdateImageOrder(galleryID, order); err != nil {
+			return fmt.Errorf("failed to set image order: %s", err.Error())
+		}
+	}
+
+	if galleryJSON.Cover != "" {
+		id, err := findImage(galleryJSON.Cover)
+		if err != nil {
+			return err
+		}
+
+		if id != 0 {
+			if _, err := SetCover(readerWriter, galleryID, &id); err != nil {
+				return fmt.Errorf("failed to set cover image: %s", err.Error())
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package gallery
 
 import (
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
@@ -494,3 +495,46 @@ func TestUpdate(t *testing.T) {
 
 	readerWriter.AssertExpectations(t)
 }
+
+func TestImportImageOrder(t *testing.T) {
+	readerWriter := &mocks.GalleryReaderWriter{}
+	imageReader := &mocks.ImageReaderWriter{}
+
+	const (
+		firstChecksum   = "firstChecksum"
+		secondChecksum  = "secondChecksum"
+		otherChecksum   = "otherChecksum"
+		firstImageID    = 1
+		secondImageID   = 2
+		otherImageID    = 3
+		missingChecksum = "missingImageChecksum"
+	)
+
+	readerWriter.On("GetImageIDs", galleryID).Return([]int{firstImageID, secondImageID}, nil)
+	imageReader.On("FindByChecksum", firstChecksum).Return(&models.Image{ID: firstImageID}, nil)
+	imageReader.On("FindByChecksum", secondChecksum).Return(&models.Image{ID: secondImageID}, nil)
+	imageReader.On("FindByChecksum", otherChecksum).Return(&models.Image{ID: otherImageID}, nil)
+	imageReader.On("FindByChecksum", missingChecksum).Return(nil, nil)
+
+	// images that are not in the gallery are ignored
+	readerWriter.On("UpdateImageOrder", galleryID, []int{secondImageID, firstImageID}).Return(nil).Once()
+	cover := sql.NullInt64{Int64: firstImageID, Valid: true}
+	readerWriter.On("UpdatePartial", models.GalleryPartial{
+		ID:           galleryID,
+		CoverImageID: &cover,
+	}).Return(nil, nil).Once()
+
+	err := ImportImageOrder(readerWriter, imageReader, galleryID, jsonschema.Gallery{
+		Images: []string{secondChecksum, missingChecksum, otherChecksum, firstChecksum},
+		Cover:  firstChecksum,
+	})
+	assert.Nil(t, err)
+
+	// a cover that is not in the gallery is ignored
+	err = ImportImageOrder(readerWriter, imageReader, galleryID, jsonschema.Gallery{
+		Cover: otherChecksum,
+	})
+	assert.Nil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
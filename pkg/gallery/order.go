@@ -0,0 +1,100 @@
+package gallery
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// getImageOrder returns the ids of the images of the gallery in order.
+func getImageOrder(iqb models.ImageReader, galleryID int) ([]int, error) {
+	images, err := iqb.FindByGalleryID(galleryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []int
+	for _, i := range images {
+		ret = append(ret, i.ID)
+	}
+
+	return ret, nil
+}
+
+// SetImageOrder orders the images of the gallery. The images in imageIDs
+// are ordered first, followed by the other images of the gallery in their
+// current order. Returns an error if an image is not in the gallery.
+func SetImageOrder(qb models.GalleryReaderWriter, iqb models.ImageReader, galleryID int, imageIDs []int) error {
+	current, err := getImageOrder(iqb, galleryID)
+	if err != nil {
+		return err
+	}
+
+	for _, imageID := range imageIDs {
+		if !utils.IntInclude(current, imageID) {
+			return fmt.Errorf("image %d is not in gallery %d", imageID, galleryID)
+		}
+	}
+
+	order := utils.IntAppendUniques(nil, imageIDs)
+	order = append(order, utils.IntExclude(current, order)...)
+
+	return qb.UpdateImageOrder(galleryID, order)
+}
+
+// InsertImages adds the images to the gallery at position, which is the
+// index of the first image in the new order. Images that are already in the
+// gallery are moved. The images are appended if position is past the last
+// image of the gallery.
+func InsertImages(qb models.GalleryReaderWriter, iqb models.ImageReader, galleryID int, imageIDs []int, position int) error {
+	current, err := getImageOrder(iqb, galleryID)
+	if err != nil {
+		return err
+	}
+
+	imageIDs = utils.IntAppendUniques(nil, imageIDs)
+	if err := qb.UpdateImages(galleryID, utils.IntAppendUniques(current, imageIDs)); err != nil {
+		return err
+	}
+
+	others := utils.IntExclude(current, imageIDs)
+	if position < 0 {
+		position = 0
+	}
+	if position > len(others) {
+		position = len(others)
+	}
+
+	var order []int
+	order = append(order, others[:position]...)
+	order = append(order, imageIDs...)
+	order = append(order, others[position:]...)
+
+	return qb.UpdateImageOrder(galleryID, order)
+}
+
+// SetCover sets the cover image of the gallery. The cover is reset to the
+// default cover if imageID is nil. Returns an error if the image is not in
+// the gallery.
+func SetCover(qb models.GalleryReaderWriter, galleryID int, imageID *int) (*models.Gallery, error) {
+	var cover sql.NullInt64
+	if imageID != nil {
+		imageIDs, err := qb.GetImageIDs(galleryID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !utils.IntInclude(imageIDs, *imageID) {
+			return nil, fmt.Errorf("image %d is not in gallery %d", *imageID, galleryID)
+		}
+
+		cover = sql.NullInt64{Int64: int64(*imageID), Valid: true}
+	}
+
+	return qb.UpdatePartial(models.GalleryPartial{
+		ID:           galleryID,
+		CoverImageID: &cover,
+	})
+}
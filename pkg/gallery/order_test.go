@@ -0,0 +1,111 @@
+package gallery
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createGalleryImages(ids ...int) []*models.Image {
+	var ret []*models.Image
+	for _, id := range ids {
+		ret = append(ret, &models.Image{ID: id})
+	}
+
+	return ret
+}
+
+func TestSetImageOrder(t *testing.T) {
+	readerWriter := &mocks.GalleryReaderWriter{}
+	imageReader := &mocks.ImageReaderWriter{}
+
+	imageReader.On("FindByGalleryID", galleryID).Return(createGalleryImages(1, 2, 3, 4), nil)
+	readerWriter.On("UpdateImageOrder", galleryID, []int{3, 1, 2, 4}).Return(nil).Once()
+
+	err := SetImageOrder(readerWriter, imageReader, galleryID, []int{3, 1, 3})
+	assert.Nil(t, err)
+
+	// images must be in the gallery
+	err = SetImageOrder(readerWriter, imageReader, galleryID, []int{5})
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
+
+var insertImagesScenarios = []struct {
+	imageIDs []int
+	position int
+	expected []int
+}{
+	{
+		[]int{5},
+		0,
+		[]int{5, 1, 2, 3},
+	},
+	{
+		[]int{5, 6},
+		2,
+		[]int{1, 2, 5, 6, 3},
+	},
+	{
+		[]int{5},
+		10,
+		[]int{1, 2, 3, 5},
+	},
+	{
+		// existing images are moved
+		[]int{3, 5},
+		1,
+		[]int{1, 3, 5, 2},
+	},
+}
+
+func TestInsertImages(t *testing.T) {
+	for i, s := range insertImagesScenarios {
+		readerWriter := &mocks.GalleryReaderWriter{}
+		imageReader := &mocks.ImageReaderWriter{}
+
+		imageReader.On("FindByGalleryID", galleryID).Return(createGalleryImages(1, 2, 3), nil).Once()
+		readerWriter.On("UpdateImages", galleryID, mock.AnythingOfType("[]int")).Return(nil).Once()
+		readerWriter.On("UpdateImageOrder", galleryID, s.expected).Return(nil).Once()
+
+		err := InsertImages(readerWriter, imageReader, galleryID, s.imageIDs, s.position)
+		assert.Nil(t, err, "[%d]", i)
+
+		readerWriter.AssertExpectations(t)
+	}
+}
+
+func TestSetCover(t *testing.T) {
+	readerWriter := &mocks.GalleryReaderWriter{}
+
+	const imageID = 2
+	const missingImageID = 3
+	cover := imageID
+	missingCover := missingImageID
+
+	readerWriter.On("GetImageIDs", galleryID).Return([]int{1, imageID}, nil)
+	readerWriter.On("UpdatePartial", models.GalleryPartial{
+		ID:           galleryID,
+		CoverImageID: &sql.NullInt64{Int64: imageID, Valid: true},
+	}).Return(nil, nil).Once()
+	readerWriter.On("UpdatePartial", models.GalleryPartial{
+		ID:           galleryID,
+		CoverImageID: &sql.NullInt64{},
+	}).Return(nil, nil).Once()
+
+	_, err := SetCover(readerWriter, galleryID, &cover)
+	assert.Nil(t, err)
+
+	_, err = SetCover(readerWriter, galleryID, nil)
+	assert.Nil(t, err)
+
+	_, err = SetCover(readerWriter, galleryID, &missingCover)
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
@@ -0,0 +1,272 @@
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// Animation is the frame count and duration of an animated image.
+type Animation struct {
+	Frames int
+	// Duration is the length of one loop of the animation in seconds
+	Duration float64
+}
+
+// IsAnimated returns true if the image has more than one frame.
+func (a Animation) IsAnimated() bool {
+	return a.Frames > 1
+}
+
+var (
+	gifSignatures = [][]byte{[]byte("GIF87a"), []byte("GIF89a")}
+	pngSignature  = []byte("\x89PNG\r\n\x1a\n")
+)
+
+var errInvalidAnimation = errors.New("invalid image data")
+
+// GetAnimation returns the frames and duration of a GIF, PNG (APNG) or WebP
+// image. Other formats and still images have a single frame. The frames are
+// not decoded.
+func GetAnimation(r io.Reader) (Animation, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(12)
+	if err != nil && err != io.EOF {
+		return Animation{}, err
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gifSignatures[0]) || bytes.HasPrefix(header, gifSignatures[1]):
+		return getGIFAnimation(br)
+	case bytes.HasPrefix(header, pngSignature):
+		return getPNGAnimation(br)
+	case len(header) == 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WEBP":
+		return getWebPAnimation(br)
+	}
+
+	return Animation{Frames: 1}, nil
+}
+
+func skip(r *bufio.Reader, n int64) error {
+	if _, err := io.CopyN(ioutil.Discard, r, n); err != nil {
+		if err == io.EOF {
+			return errInvalidAnimation
+		}
+		return err
+	}
+
+	return nil
+}
+
+// skipGIFSubBlocks skips data sub-blocks up to and including the block
+// terminator.
+func skipGIFSubBlocks(r *bufio.Reader) error {
+	for {
+		size, err := r.ReadByte()
+		if err != nil {
+			return errInvalidAnimation
+		}
+
+		if size == 0 {
+			return nil
+		}
+
+		if err := skip(r, int64(size)); err != nil {
+			return err
+		}
+	}
+}
+
+// getGIFAnimation counts the image descriptors of a GIF and sums the delays
+// of their graphic control extensions, which are in hundredths of a second.
+func getGIFAnimation(r *bufio.Reader) (Animation, error) {
+	// header and logical screen descriptor
+	var header [13]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return Animation{}, errInvalidAnimation
+	}
+
+	colorTableSize := func(flags byte) int64 {
+		if flags&0x80 == 0 {
+			return 0
+		}
+		return 3 << (flags&0x07 + 1)
+	}
+
+	if err := skip(r, colorTableSize(header[10])); err != nil {
+		return Animation{}, err
+	}
+
+	var ret Animation
+	delay := 0
+	for {
+		introducer, err := r.ReadByte()
+		if err != nil {
+			// treat truncated files as ending at the last complete frame
+			return ret, nil
+		}
+
+		switch introducer {
+		case 0x21: // extension
+			label, err := r.ReadByte()
+			if err != nil {
+				return ret, nil
+			}
+
+			if label == 0xf9 {
+				// graphic control extension
+				var gce [6]byte
+				if _, err := io.ReadFull(r, gce[:]); err != nil {
+					return ret, nil
+				}
+				delay += int(binary.LittleEndian.Uint16(gce[2:4]))
+
+				// the last byte is the block terminator, unless the
+				// extension has further sub-blocks
+				if gce[5] != 0 {
+					if err := skip(r, int64(gce[5])); err != nil {
+						return ret, nil
+					}
+					if err := skipGIFSubBlocks(r); err != nil {
+						return ret, nil
+					}
+				}
+				continue
+			}
+
+			if err := skipGIFSubBlocks(r); err != nil {
+				return ret, nil
+			}
+		case 0x2c: // image descriptor
+			var descriptor [9]byte
+			if _, err := io.ReadFull(r, descriptor[:]); err != nil {
+				return ret, nil
+			}
+
+			// local color table and LZW minimum code size
+			if err := skip(r, colorTableSize(descriptor[8])+1); err != nil {
+				return ret, nil
+			}
+
+			if err := skipGIFSubBlocks(r); err != nil {
+				return ret, nil
+			}
+
+			ret.Frames++
+			ret.Duration = float64(delay) / 100
+		case 0x3b: // trailer
+			return ret, nil
+		default:
+			return ret, errInvalidAnimation
+		}
+	}
+}
+
+// getPNGAnimation reads the chunks of a PNG. Animated PNGs have an acTL
+// chunk with the frame count, and a fcTL chunk with the delay of each frame.
+func getPNGAnimation(r *bufio.Reader) (Animation, error) {
+	if err := skip(r, int64(len(pngSignature))); err != nil {
+		return Animation{}, err
+	}
+
+	ret := Animation{Frames: 1}
+	duration := 0.0
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return ret, nil
+		}
+
+		length := int64(binary.BigEndian.Uint32(chunk[0:4]))
+		chunkType := string(chunk[4:8])
+
+		switch chunkType {
+		case "acTL":
+			var data [8]byte
+			if length < 8 {
+				return ret, errInvalidAnimation
+			}
+			if _, err := io.ReadFull(r, data[:]); err != nil {
+				return ret, nil
+			}
+			ret.Frames = int(binary.BigEndian.Uint32(data[0:4]))
+			length -= 8
+		case "fcTL":
+			var data [26]byte
+			if length < 26 {
+				return ret, errInvalidAnimation
+			}
+			if _, err := io.ReadFull(r, data[:]); err != nil {
+				return ret, nil
+			}
+
+			num := float64(binary.BigEndian.Uint16(data[20:22]))
+			den := float64(binary.BigEndian.Uint16(data[22:24]))
+			// a zero denominator means hundredths of a second
+			if den == 0 {
+				den = 100
+			}
+			duration += num / den
+			ret.Duration = duration
+			length -= 26
+		case "IEND":
+			return ret, nil
+		}
+
+		// the remaining data and the CRC
+		if err := skip(r, length+4); err != nil {
+			return ret, nil
+		}
+	}
+}
+
+// getWebPAnimation reads the chunks of a WebP. Animated WebPs have an ANMF
+// chunk per frame, with the frame duration in milliseconds.
+func getWebPAnimation(r *bufio.Reader) (Animation, error) {
+	// RIFF header
+	if err := skip(r, 12); err != nil {
+		return Animation{}, err
+	}
+
+	var ret Animation
+	duration := 0
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			break
+		}
+
+		length := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		// chunks are padded to an even size
+		padded := length + length&1
+
+		if string(chunk[0:4]) == "ANMF" {
+			var data [16]byte
+			if length < 16 {
+				return ret, errInvalidAnimation
+			}
+			if _, err := io.ReadFull(r, data[:]); err != nil {
+				break
+			}
+
+			ret.Frames++
+			duration += int(data[12]) | int(data[13])<<8 | int(data[14])<<16
+			padded -= 16
+		}
+
+		if err := skip(r, padded); err != nil {
+			break
+		}
+	}
+
+	if ret.Frames == 0 {
+		// still WebP
+		return Animation{Frames: 1}, nil
+	}
+
+	ret.Duration = float64(duration) / 1000
+	return ret, nil
+}
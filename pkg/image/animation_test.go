@@ -0,0 +1,125 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGIF(t *testing.T, delays ...int) []byte {
+	palette := color.Palette{color.Black, color.White}
+	g := &gif.GIF{}
+	for _, delay := range delays {
+		g.Image = append(g.Image, image.NewPaletted(image.Rect(0, 0, 4, 4), palette))
+		g.Delay = append(g.Delay, delay)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// newChunk returns a PNG chunk, with a zero CRC, or a WebP chunk.
+func newChunk(order binary.ByteOrder, chunkType string, data []byte) []byte {
+	var buf bytes.Buffer
+	length := make([]byte, 4)
+	order.PutUint32(length, uint32(len(data)))
+
+	if order == binary.BigEndian {
+		buf.Write(length)
+		buf.WriteString(chunkType)
+		buf.Write(data)
+		buf.Write(make([]byte, 4))
+		return buf.Bytes()
+	}
+
+	buf.WriteString(chunkType)
+	buf.Write(length)
+	buf.Write(data)
+	if len(data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+func newTestAPNG(delays ...uint16) []byte {
+	var buf bytes.Buffer
+	buf.Write(pngSignature)
+	buf.Write(newChunk(binary.BigEndian, "IHDR", make([]byte, 13)))
+
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl, uint32(len(delays)))
+	buf.Write(newChunk(binary.BigEndian, "acTL", actl))
+
+	for _, delay := range delays {
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint16(fctl[20:], delay)
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		buf.Write(newChunk(binary.BigEndian, "fcTL", fctl))
+		buf.Write(newChunk(binary.BigEndian, "IDAT", []byte{1, 2, 3}))
+	}
+
+	buf.Write(newChunk(binary.BigEndian, "IEND", nil))
+	return buf.Bytes()
+}
+
+func newTestWebP(delays ...int) []byte {
+	var chunks bytes.Buffer
+	chunks.Write(newChunk(binary.LittleEndian, "VP8X", make([]byte, 10)))
+	for _, delay := range delays {
+		anmf := make([]byte, 17)
+		anmf[12] = byte(delay)
+		anmf[13] = byte(delay >> 8)
+		chunks.Write(newChunk(binary.LittleEndian, "ANMF", anmf))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(chunks.Len()+4))
+	buf.Write(size)
+	buf.WriteString("WEBP")
+	buf.Write(chunks.Bytes())
+	return buf.Bytes()
+}
+
+func TestGetAnimation(t *testing.T) {
+	var stillPNG bytes.Buffer
+	if err := png.Encode(&stillPNG, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want Animation
+	}{
+		{"animated gif", newTestGIF(t, 10, 20, 5), Animation{Frames: 3, Duration: 0.35}},
+		{"still gif", newTestGIF(t, 0), Animation{Frames: 1}},
+		{"animated png", newTestAPNG(500, 250), Animation{Frames: 2, Duration: 0.75}},
+		{"still png", stillPNG.Bytes(), Animation{Frames: 1}},
+		{"animated webp", newTestWebP(100, 300), Animation{Frames: 2, Duration: 0.4}},
+		{"still webp", newTestWebP(), Animation{Frames: 1}},
+		{"other", []byte("\xff\xd8\xff\xe0"), Animation{Frames: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetAnimation(bytes.NewReader(tt.data))
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want.Frames, got.Frames)
+				assert.InDelta(t, tt.want.Duration, got.Duration, 0.0001)
+				assert.Equal(t, tt.want.Frames > 1, got.IsAnimated())
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package image
+
+import (
+	"database/sql"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// mimeTypes are the MIME types of image and video clip extensions that may
+// not be registered with the system.
+var mimeTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".apng": "image/apng",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".mp4":  ffmpeg.MimeMp4,
+	".m4v":  ffmpeg.MimeMp4,
+	".webm": ffmpeg.MimeWebm,
+	".mkv":  ffmpeg.MimeMkv,
+	".mov":  "video/quicktime",
+}
+
+// GetMimeType returns the MIME type of the image file, based on its
+// extension.
+func GetMimeType(i *models.Image) string {
+	_, fn := getFilePath(i.Path)
+	ext := strings.ToLower(filepath.Ext(fn))
+	if ret, ok := mimeTypes[ext]; ok {
+		return ret
+	}
+
+	if ret := mime.TypeByExtension(ext); ret != "" {
+		return ret
+	}
+
+	if i.IsVideo {
+		return "video/" + strings.TrimPrefix(ext, ".")
+	}
+
+	return "application/octet-stream"
+}
+
+// SetAnimationDetails sets the animation and duration of the image file.
+// Still images have a zero duration.
+func SetAnimationDetails(i *models.Image) {
+	f, err := openSourceImage(i.Path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	animation, err := GetAnimation(f)
+	if err != nil {
+		logger.Warnf("error reading animation of %s: %s", PathDisplayName(i.Path), err.Error())
+	}
+
+	i.IsAnimated = animation.IsAnimated()
+	i.Duration = sql.NullFloat64{Valid: true}
+	if i.IsAnimated {
+		i.Duration.Float64 = animation.Duration
+	}
+}
+
+// SetClipDetails sets the size, dimensions and duration of the video clip
+// from its probe result.
+func SetClipDetails(i *models.Image, videoFile *ffmpeg.VideoFile) {
+	i.IsVideo = true
+	i.IsAnimated = true
+	i.Size = sql.NullInt64{Int64: videoFile.Size, Valid: true}
+	i.Width = sql.NullInt64{Int64: int64(videoFile.Width), Valid: true}
+	i.Height = sql.NullInt64{Int64: int64(videoFile.Height), Valid: true}
+	i.Duration = sql.NullFloat64{Float64: videoFile.Duration, Valid: true}
+}
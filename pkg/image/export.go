@@ -3,6 +3,7 @@ package image
 import (
 	"github.com/stashapp/stash/pkg/manager/jsonschema"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 // ToBasicJSON converts a image object into its JSON object equivalent. It
@@ -19,12 +20,11 @@ func ToBasicJSON(image *models.Image) *jsonschema.Image {
 		newImageJSON.Title = image.Title.String
 	}
 
-	if image.Rating.Valid {
-		newImageJSON.Rating = int(image.Rating.Int64)
+	if image.Date.Valid {
+		newImageJSON.Date = utils.GetYMDFromDatabaseDate(image.Date.String)
 	}
 
 	newImageJSON.Organized = image.Organized
-	newImageJSON.OCounter = image.OCounter
 
 	newImageJSON.File = getImageFileJSON(image)
 
@@ -50,6 +50,10 @@ func getImageFileJSON(image *models.Image) *jsonschema.ImageFile {
 		ret.Height = int(image.Height.Int64)
 	}
 
+	ret.Duration = image.Duration.Float64
+	ret.IsAnimated = image.IsAnimated
+	ret.IsVideo = image.IsVideo
+
 	return ret
 }
 
@@ -40,9 +40,8 @@ const (
 const (
 	checksum  = "checksum"
 	title     = "title"
-	rating    = 5
+	date      = "2001-01-01"
 	organized = true
-	ocounter  = 2
 	size      = 123
 	width     = 100
 	height    = 100
@@ -58,12 +57,14 @@ var updateTime time.Time = time.Date(2002, 01, 01, 0, 0, 0, 0, time.UTC)
 
 func createFullImage(id int) models.Image {
 	return models.Image{
-		ID:        id,
-		Title:     models.NullString(title),
-		Checksum:  checksum,
+		ID:       id,
+		Title:    models.NullString(title),
+		Checksum: checksum,
+		Date: models.SQLiteDate{
+			String: date,
+			Valid:  true,
+		},
 		Height:    models.NullInt64(height),
-		OCounter:  ocounter,
-		Rating:    models.NullInt64(rating),
 		Size:      models.NullInt64(int64(size)),
 		Organized: organized,
 		Width:     models.NullInt64(width),
@@ -80,8 +81,7 @@ func createFullJSONImage() *jsonschema.Image {
 	return &jsonschema.Image{
 		Title:     title,
 		Checksum:  checksum,
-		OCounter:  ocounter,
-		Rating:    rating,
+		Date:      date,
 		Organized: organized,
 		File: &jsonschema.ImageFile{
 			Height: height,
@@ -1,7 +1,6 @@
 package image
 
 import (
-	"archive/zip"
 	"database/sql"
 	"fmt"
 	"image"
@@ -13,11 +12,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/stashapp/stash/pkg/archive"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
 	_ "golang.org/x/image/webp"
 )
 
+// zipSeparator separates the path of an archive file from the path of a
+// file in the archive. It is used for every archive format, not only zip.
 const zipSeparator = "\x00"
 
 func GetSourceImage(i *models.Image) (image.Image, error) {
@@ -60,15 +62,15 @@ func ZipFilename(zipFilename, filenameInZip string) string {
 }
 
 // IsZipPath returns true if the path includes the zip separator byte,
-// indicating it is within a zip file.
+// indicating it is within an archive file.
 // TODO - this should be moved to utils
 func IsZipPath(p string) bool {
 	return strings.Contains(p, zipSeparator)
 }
 
 type imageReadCloser struct {
-	src io.ReadCloser
-	zrc *zip.ReadCloser
+	src     io.ReadCloser
+	archive archive.Reader
 }
 
 func (i *imageReadCloser) Read(p []byte) (n int, err error) {
@@ -78,8 +80,8 @@ func (i *imageReadCloser) Read(p []byte) (n int, err error) {
 func (i *imageReadCloser) Close() error {
 	err := i.src.Close()
 	var err2 error
-	if i.zrc != nil {
-		err2 = i.zrc.Close()
+	if i.archive != nil {
+		err2 = i.archive.Close()
 	}
 
 	if err != nil {
@@ -88,35 +90,32 @@ func (i *imageReadCloser) Close() error {
 	return err2
 }
 
+// Open opens the image file at path, which may be in an archive file.
+func Open(path string) (io.ReadCloser, error) {
+	return openSourceImage(path)
+}
+
 func openSourceImage(path string) (io.ReadCloser, error) {
-	// may need to read from a zip file
+	// may need to read from an archive file
 	zipFilename, filename := getFilePath(path)
 	if zipFilename != "" {
-		r, err := zip.OpenReader(zipFilename)
+		r, err := archive.Open(zipFilename)
 		if err != nil {
 			return nil, err
 		}
 
-		// defer closing of zip to the calling function, unless an error
-		// is returned, in which case it should be closed immediately
-
-		// find the file matching the filename
-		for _, f := range r.File {
-			if f.Name == filename {
-				src, err := f.Open()
-				if err != nil {
-					r.Close()
-					return nil, err
-				}
-				return &imageReadCloser{
-					src: src,
-					zrc: r,
-				}, nil
-			}
+		// defer closing of the archive to the calling function, unless an
+		// error is returned, in which case it should be closed immediately
+		src, err := r.Open(filename)
+		if err != nil {
+			r.Close()
+			return nil, err
 		}
 
-		r.Close()
-		return nil, fmt.Errorf("file with name '%s' not found in zip file '%s'", filename, zipFilename)
+		return &imageReadCloser{
+			src:     src,
+			archive: r,
+		}, nil
 	}
 
 	return os.Open(filename)
@@ -148,6 +147,8 @@ func GetFileDetails(path string) (*models.Image, error) {
 	return i, nil
 }
 
+// SetFileDetails sets the size, dimensions and animation of the image file.
+// Video clips are set with SetClipDetails instead.
 func SetFileDetails(i *models.Image) error {
 	f, err := stat(i.Path)
 	if err != nil {
@@ -155,6 +156,7 @@ func SetFileDetails(i *models.Image) error {
 	}
 
 	src, _ := GetSourceImage(i)
+	SetAnimationDetails(i)
 
 	if src != nil {
 		i.Width = sql.NullInt64{
@@ -175,7 +177,8 @@ func SetFileDetails(i *models.Image) error {
 	return nil
 }
 
-// GetFileModTime gets the file modification time, handling files in zip files.
+// GetFileModTime gets the file modification time, handling files in archive
+// files.
 func GetFileModTime(path string) (time.Time, error) {
 	fi, err := stat(path)
 	if err != nil {
@@ -190,23 +193,23 @@ func GetFileModTime(path string) (time.Time, error) {
 }
 
 func stat(path string) (os.FileInfo, error) {
-	// may need to read from a zip file
+	// may need to read from an archive file
 	zipFilename, filename := getFilePath(path)
 	if zipFilename != "" {
-		r, err := zip.OpenReader(zipFilename)
+		r, err := archive.Open(zipFilename)
 		if err != nil {
 			return nil, err
 		}
 		defer r.Close()
 
 		// find the file matching the filename
-		for _, f := range r.File {
+		for _, f := range r.Files() {
 			if f.Name == filename {
 				return f.FileInfo(), nil
 			}
 		}
 
-		return nil, fmt.Errorf("file with name '%s' not found in zip file '%s'", filename, zipFilename)
+		return nil, fmt.Errorf("file with name '%s' not found in archive file '%s'", filename, zipFilename)
 	}
 
 	return os.Stat(filename)
@@ -214,7 +217,7 @@ func stat(path string) (os.FileInfo, error) {
 
 // PathDisplayName converts an image path for display. It translates the zip
 // file separator character into '/', since this character is also used for
-// path separators within zip files. It returns the original provided path
+// path separators within archive files. It returns the original provided path
 // if it does not contain the zip file separator character.
 func PathDisplayName(path string) string {
 	return strings.Replace(path, zipSeparator, "/", -1)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stashapp/stash/pkg/manager/jsonschema"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/user"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -16,6 +17,7 @@ type Importer struct {
 	GalleryWriter       models.GalleryReaderWriter
 	PerformerWriter     models.PerformerReaderWriter
 	TagWriter           models.TagReaderWriter
+	UserReader          models.UserReader
 	Input               jsonschema.Image
 	Path                string
 	MissingRefBehaviour models.ImportMissingRefEnum
@@ -25,6 +27,7 @@ type Importer struct {
 	galleries  []*models.Gallery
 	performers []*models.Performer
 	tags       []*models.Tag
+	userData   []*models.UserData
 }
 
 func (i *Importer) PreImport() error {
@@ -46,6 +49,10 @@ func (i *Importer) PreImport() error {
 		return err
 	}
 
+	if err := i.populateUserData(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -58,12 +65,11 @@ func (i *Importer) imageJSONToImage(imageJSON jsonschema.Image) models.Image {
 	if imageJSON.Title != "" {
 		newImage.Title = sql.NullString{String: imageJSON.Title, Valid: true}
 	}
-	if imageJSON.Rating != 0 {
-		newImage.Rating = sql.NullInt64{Int64: int64(imageJSON.Rating), Valid: true}
+	if imageJSON.Date != "" {
+		newImage.Date = models.SQLiteDate{String: imageJSON.Date, Valid: true}
 	}
 
 	newImage.Organized = imageJSON.Organized
-	newImage.OCounter = imageJSON.OCounter
 	newImage.CreatedAt = models.SQLiteTimestamp{Timestamp: imageJSON.CreatedAt.GetTime()}
 	newImage.UpdatedAt = models.SQLiteTimestamp{Timestamp: imageJSON.UpdatedAt.GetTime()}
 
@@ -77,6 +83,12 @@ func (i *Importer) imageJSONToImage(imageJSON jsonschema.Image) models.Image {
 		if imageJSON.File.Height != 0 {
 			newImage.Height = sql.NullInt64{Int64: int64(imageJSON.File.Height), Valid: true}
 		}
+		// the animation of still images is detected by the next scan
+		if imageJSON.File.Duration != 0 {
+			newImage.Duration = sql.NullFloat64{Float64: imageJSON.File.Duration, Valid: true}
+		}
+		newImage.IsAnimated = imageJSON.File.IsAnimated
+		newImage.IsVideo = imageJSON.File.IsVideo
 	}
 
 	return newImage
@@ -224,6 +236,21 @@ func (i *Importer) populateTags() error {
 	return nil
 }
 
+func (i *Importer) populateUserData() error {
+	input := i.Input.UserData
+	if len(input) == 0 && (i.Input.Rating != 0 || i.Input.OCounter != 0) {
+		// exported before values were stored per user
+		input = []jsonschema.UserData{{
+			Rating:   i.Input.Rating,
+			OCounter: i.Input.OCounter,
+		}}
+	}
+
+	var err error
+	i.userData, err = user.UserDataFromJSON(i.UserReader, input, i.MissingRefBehaviour)
+	return err
+}
+
 func (i *Importer) PostImport(id int) error {
 	if len(i.galleries) > 0 {
 		var galleryIDs []int
@@ -257,6 +284,12 @@ func (i *Importer) PostImport(id int) error {
 		}
 	}
 
+	if len(i.userData) > 0 {
+		if err := i.ReaderWriter.UpdateAllUserData(id, i.userData); err != nil {
+			return fmt.Errorf("failed to set user data: %s", err.Error())
+		}
+	}
+
 	return nil
 }
 
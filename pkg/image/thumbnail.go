@@ -4,10 +4,90 @@ import (
 	"bytes"
 	"image"
 	"image/jpeg"
+	"image/png"
+	"mime"
+	"strconv"
+	"strings"
 
 	"github.com/disintegration/imaging"
+	"github.com/stashapp/stash/pkg/models"
 )
 
+// ThumbnailSizes are the maximum dimensions of the thumbnail size variants,
+// in ascending order.
+var ThumbnailSizes = []int{160, 320, models.DefaultGthumbWidth, 1280}
+
+// GetThumbnailSize returns the smallest thumbnail size that is not smaller
+// than size, or the largest thumbnail size. Returns the default thumbnail
+// size if size is not positive.
+func GetThumbnailSize(size int) int {
+	if size <= 0 {
+		return models.DefaultGthumbWidth
+	}
+
+	for _, s := range ThumbnailSizes {
+		if s >= size {
+			return s
+		}
+	}
+
+	return ThumbnailSizes[len(ThumbnailSizes)-1]
+}
+
+type ThumbnailFormat string
+
+const (
+	ThumbnailFormatJPEG ThumbnailFormat = "jpg"
+	ThumbnailFormatWebP ThumbnailFormat = "webp"
+	ThumbnailFormatAVIF ThumbnailFormat = "avif"
+)
+
+// thumbnailFormatPreference are the thumbnail formats in order of
+// preference. JPEG is supported by every client.
+var thumbnailFormatPreference = []ThumbnailFormat{
+	ThumbnailFormatAVIF,
+	ThumbnailFormatWebP,
+	ThumbnailFormatJPEG,
+}
+
+func (f ThumbnailFormat) MimeType() string {
+	switch f {
+	case ThumbnailFormatWebP:
+		return "image/webp"
+	case ThumbnailFormatAVIF:
+		return "image/avif"
+	default:
+		return "image/jpeg"
+	}
+}
+
+// GetThumbnailFormats returns the thumbnail formats that are accepted by the
+// Accept header, in order of preference. JPEG is always accepted.
+func GetThumbnailFormats(accept string) []ThumbnailFormat {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+			continue
+		}
+
+		accepted[mediaType] = true
+	}
+
+	var ret []ThumbnailFormat
+	for _, f := range thumbnailFormatPreference {
+		if f == ThumbnailFormatJPEG || accepted[f.MimeType()] {
+			ret = append(ret, f)
+		}
+	}
+
+	return ret
+}
+
 func ThumbnailNeeded(srcImage image.Image, maxSize int) bool {
 	dim := srcImage.Bounds().Max
 	w := dim.X
@@ -16,24 +96,35 @@ func ThumbnailNeeded(srcImage image.Image, maxSize int) bool {
 	return w > maxSize || h > maxSize
 }
 
+func resizeThumbnail(srcImage image.Image, maxSize int) image.Image {
+	// if height is longer then resize by height instead of width
+	dim := srcImage.Bounds().Max
+	if dim.Y > dim.X {
+		return imaging.Resize(srcImage, 0, maxSize, imaging.Box)
+	}
+
+	return imaging.Resize(srcImage, maxSize, 0, imaging.Box)
+}
+
 // GetThumbnail returns the thumbnail image of the provided image resized to
 // the provided max size. It resizes based on the largest X/Y direction.
 // It returns nil and an error if an error occurs reading, decoding or encoding
 // the image.
 func GetThumbnail(srcImage image.Image, maxSize int) ([]byte, error) {
-	var resizedImage image.Image
-
-	// if height is longer then resize by height instead of width
-	dim := srcImage.Bounds().Max
-	if dim.Y > dim.X {
-		resizedImage = imaging.Resize(srcImage, 0, maxSize, imaging.Box)
-	} else {
-		resizedImage = imaging.Resize(srcImage, maxSize, 0, imaging.Box)
+	buf := new(bytes.Buffer)
+	err := jpeg.Encode(buf, resizeThumbnail(srcImage, maxSize), nil)
+	if err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
+}
 
+// GetThumbnailPNG returns the thumbnail of the image as an uncompressed PNG,
+// to be encoded to another format by ffmpeg.
+func GetThumbnailPNG(srcImage image.Image, maxSize int) ([]byte, error) {
 	buf := new(bytes.Buffer)
-	err := jpeg.Encode(buf, resizedImage, nil)
-	if err != nil {
+	encoder := png.Encoder{CompressionLevel: png.NoCompression}
+	if err := encoder.Encode(buf, resizeThumbnail(srcImage, maxSize)); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
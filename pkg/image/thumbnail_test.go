@@ -0,0 +1,67 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetThumbnailSize(t *testing.T) {
+	tests := []struct {
+		size int
+		want int
+	}{
+		{0, 640},
+		{-1, 640},
+		{100, 160},
+		{160, 160},
+		{161, 320},
+		{480, 640},
+		{1000, 1280},
+		{4000, 1280},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, GetThumbnailSize(tt.size), "size %d", tt.size)
+	}
+}
+
+func TestGetThumbnailFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   []ThumbnailFormat
+	}{
+		{
+			"empty",
+			"",
+			[]ThumbnailFormat{ThumbnailFormatJPEG},
+		},
+		{
+			"browser",
+			"image/avif,image/webp,image/apng,image/*,*/*;q=0.8",
+			[]ThumbnailFormat{ThumbnailFormatAVIF, ThumbnailFormatWebP, ThumbnailFormatJPEG},
+		},
+		{
+			"webp only",
+			"image/webp, */*",
+			[]ThumbnailFormat{ThumbnailFormatWebP, ThumbnailFormatJPEG},
+		},
+		{
+			"refused",
+			"image/avif;q=0, image/webp",
+			[]ThumbnailFormat{ThumbnailFormatWebP, ThumbnailFormatJPEG},
+		},
+		{
+			"invalid",
+			"image/avif;;;, image/",
+			[]ThumbnailFormat{ThumbnailFormatJPEG},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetThumbnailFormats(tt.accept))
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package job
+
+// Group is the resource group of a job. Jobs in different groups may run
+// concurrently, subject to the concurrency limit of each group.
+type Group string
+
+const (
+	// GroupScan is for I/O-heavy jobs that walk the filesystem.
+	GroupScan Group = "SCAN"
+	// GroupGenerate is for CPU-heavy jobs that generate content.
+	GroupGenerate Group = "GENERATE"
+	// GroupMetadata is for light jobs that operate on the database.
+	GroupMetadata Group = "METADATA"
+	// GroupScraping is for jobs that query external sites.
+	GroupScraping Group = "SCRAPING"
+	// GroupExclusive is for jobs that must not run concurrently with any
+	// other job, such as imports. Jobs in this group wait until all running
+	// jobs have finished, and no other jobs are started while they run.
+	GroupExclusive Group = "EXCLUSIVE"
+)
+
+// DefaultGroup is the group of jobs that are added without a group.
+const DefaultGroup = GroupMetadata
+
+// defaultGroupLimit is the concurrency limit of groups that do not have a
+// limit set.
+const defaultGroupLimit = 1
@@ -10,14 +10,27 @@ type JobExec interface {
 	Execute(ctx context.Context, progress *Progress)
 }
 
+// PausableJobExec is a JobExec that supports being paused and resumed.
+// Execute must call Progress.WaitIfPaused between work units, which blocks
+// while the job is paused.
+type PausableJobExec interface {
+	JobExec
+	Pausable() bool
+}
+
 type jobExecImpl struct {
-	fn func(ctx context.Context, progress *Progress)
+	fn       func(ctx context.Context, progress *Progress)
+	pausable bool
 }
 
 func (j *jobExecImpl) Execute(ctx context.Context, progress *Progress) {
 	j.fn(ctx, progress)
 }
 
+func (j *jobExecImpl) Pausable() bool {
+	return j.pausable
+}
+
 // MakeJobExec returns a simple JobExec implementation using the provided
 // function.
 func MakeJobExec(fn func(ctx context.Context, progress *Progress)) JobExec {
@@ -26,6 +39,21 @@ func MakeJobExec(fn func(ctx context.Context, progress *Progress)) JobExec {
 	}
 }
 
+// MakePausableJobExec returns a simple PausableJobExec implementation using
+// the provided function. The function must call Progress.WaitIfPaused
+// between work units.
+func MakePausableJobExec(fn func(ctx context.Context, progress *Progress)) JobExec {
+	return &jobExecImpl{
+		fn:       fn,
+		pausable: true,
+	}
+}
+
+func isPausable(e JobExec) bool {
+	p, ok := e.(PausableJobExec)
+	return ok && p.Pausable()
+}
+
 // Status is the status of a Job
 type Status string
 
@@ -34,12 +62,16 @@ const (
 	StatusReady Status = "READY"
 	// StatusRunning means that the job is currently running.
 	StatusRunning Status = "RUNNING"
+	// StatusPaused means that the job is paused and is waiting to be resumed.
+	StatusPaused Status = "PAUSED"
 	// StatusStopping means that the job is cancelled but is still running.
 	StatusStopping Status = "STOPPING"
 	// StatusFinished means that the job was completed.
 	StatusFinished Status = "FINISHED"
 	// StatusCancelled means that the job was cancelled and is now stopped.
 	StatusCancelled Status = "CANCELLED"
+	// StatusFailed means that the job was completed with an error.
+	StatusFailed Status = "FAILED"
 )
 
 // Job represents the status of a queued or running job.
@@ -54,19 +86,37 @@ type Job struct {
 	StartTime *time.Time
 	EndTime   *time.Time
 	AddTime   time.Time
+	// Processed and Total are the number of work units processed and the
+	// total number of work units, if known.
+	Processed int
+	Total     int
+	// Error is set if the job failed.
+	Error *string
+
+	// Pausable is true if the job supports being paused.
+	Pausable bool
+	// Group is the resource group that the job belongs to.
+	Group Group
 
 	outerCtx   context.Context
 	exec       JobExec
 	cancelFunc context.CancelFunc
+	// resume is closed when a paused job is resumed or cancelled.
+	resume chan struct{}
 }
 
 func (j *Job) cancel() {
 	if j.Status == StatusReady {
 		j.Status = StatusCancelled
-	} else if j.Status == StatusRunning {
+	} else if j.Status == StatusRunning || j.Status == StatusPaused {
 		j.Status = StatusStopping
 	}
 
+	if j.resume != nil {
+		close(j.resume)
+		j.resume = nil
+	}
+
 	if j.cancelFunc != nil {
 		j.cancelFunc()
 	}
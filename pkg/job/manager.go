@@ -2,6 +2,8 @@ package job
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -11,11 +13,26 @@ import (
 const maxGraveyardSize = 10
 const defaultThrottleLimit = time.Second
 
-// Manager maintains a queue of jobs. Jobs are executed one at a time.
+var (
+	// ErrJobNotFound is returned when a job with the provided ID is not in
+	// the queue.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotPausable is returned when attempting to pause a job that does
+	// not support being paused.
+	ErrJobNotPausable = errors.New("job cannot be paused")
+)
+
+// Manager maintains a queue of jobs. Jobs are assigned to groups, each with
+// its own concurrency limit. Jobs in different groups are executed
+// concurrently, while jobs within a group are executed in the order they were
+// added, up to the group's limit at a time.
 type Manager struct {
 	queue     []*Job
 	graveyard []*Job
 
+	groupLimits map[Group]int
+	running     map[Group]int
+
 	mutex    sync.Mutex
 	notEmpty *sync.Cond
 	stop     chan struct{}
@@ -24,6 +41,17 @@ type Manager struct {
 
 	subscriptions       []*ManagerSubscription
 	updateThrottleLimit time.Duration
+
+	history History
+}
+
+// History records the execution of jobs.
+type History interface {
+	// Started is called when a job starts running. It returns the context
+	// that the job is executed with, which is derived from ctx.
+	Started(ctx context.Context, j Job) context.Context
+	// Finished is called when a job stops running, with its final state.
+	Finished(j Job)
 }
 
 // NewManager initialises and returns a new Manager.
@@ -31,6 +59,8 @@ func NewManager() *Manager {
 	ret := &Manager{
 		stop:                make(chan struct{}),
 		updateThrottleLimit: defaultThrottleLimit,
+		groupLimits:         make(map[Group]int),
+		running:             make(map[Group]int),
 	}
 
 	ret.notEmpty = sync.NewCond(&ret.mutex)
@@ -45,10 +75,56 @@ func NewManager() *Manager {
 func (m *Manager) Stop() {
 	m.CancelAll()
 	close(m.stop)
+
+	// wake up the dispatcher so that it can stop
+	m.notEmpty.Broadcast()
+}
+
+// SetGroupLimit sets the maximum number of jobs in the group that may run
+// concurrently. Values less than 1 are treated as 1. The exclusive group
+// always runs one job at a time.
+func (m *Manager) SetGroupLimit(group Group, limit int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if limit < 1 {
+		limit = 1
+	}
+
+	m.groupLimits[group] = limit
+
+	// more jobs may be able to start
+	m.notEmpty.Broadcast()
 }
 
-// Add queues a job.
+func (m *Manager) groupLimit(group Group) int {
+	// assumes lock held
+	if group == GroupExclusive {
+		return 1
+	}
+
+	if limit, found := m.groupLimits[group]; found {
+		return limit
+	}
+
+	return defaultGroupLimit
+}
+
+// SetHistory sets the History that is notified when jobs start and finish.
+func (m *Manager) SetHistory(h History) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.history = h
+}
+
+// Add queues a job in the default group.
 func (m *Manager) Add(ctx context.Context, description string, e JobExec) int {
+	return m.AddToGroup(ctx, DefaultGroup, description, e)
+}
+
+// AddToGroup queues a job in the provided group.
+func (m *Manager) AddToGroup(ctx context.Context, group Group, description string, e JobExec) int {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -59,24 +135,24 @@ func (m *Manager) Add(ctx context.Context, description string, e JobExec) int {
 		Status:      StatusReady,
 		Description: description,
 		AddTime:     t,
+		Pausable:    isPausable(e),
+		Group:       group,
 		exec:        e,
 		outerCtx:    ctx,
 	}
 
 	m.queue = append(m.queue, &j)
 
-	if len(m.queue) == 1 {
-		// notify that there is now a job in the queue
-		m.notEmpty.Broadcast()
-	}
+	// notify that there is a new job in the queue
+	m.notEmpty.Broadcast()
 
 	m.notifyNewJob(&j)
 
 	return j.ID
 }
 
-// Start adds a job and starts it immediately, concurrently with any other
-// jobs.
+// Start adds a job to the default group and starts it immediately,
+// concurrently with any other jobs and regardless of the group limit.
 func (m *Manager) Start(ctx context.Context, description string, e JobExec) int {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -88,6 +164,8 @@ func (m *Manager) Start(ctx context.Context, description string, e JobExec) int
 		Status:      StatusReady,
 		Description: description,
 		AddTime:     t,
+		Pausable:    isPausable(e),
+		Group:       DefaultGroup,
 		exec:        e,
 		outerCtx:    ctx,
 	}
@@ -115,49 +193,65 @@ func (m *Manager) nextID() int {
 	return m.lastID
 }
 
-func (m *Manager) getReadyJob() *Job {
+// getReadyJobs returns the jobs that are ready and may be started, in queue
+// order.
+func (m *Manager) getReadyJobs() []*Job {
 	// assumes lock held
+	running := make(map[Group]int)
+	totalRunning := 0
+	for g, n := range m.running {
+		running[g] = n
+		totalRunning += n
+	}
+
+	// nothing may start while an exclusive job is running
+	if running[GroupExclusive] > 0 {
+		return nil
+	}
+
+	var ret []*Job
 	for _, j := range m.queue {
-		if j.Status == StatusReady {
-			return j
+		if j.Status != StatusReady {
+			continue
+		}
+
+		if j.Group == GroupExclusive {
+			// exclusive jobs wait for all running jobs to finish. Jobs added
+			// after it must not start in the meantime.
+			if totalRunning == 0 {
+				ret = append(ret, j)
+			}
+			return ret
+		}
+
+		if running[j.Group] < m.groupLimit(j.Group) {
+			ret = append(ret, j)
+			running[j.Group]++
+			totalRunning++
 		}
 	}
 
-	return nil
+	return ret
 }
 
 func (m *Manager) dispatcher() {
 	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
 	for {
-		// wait until we have something to process
-		j := m.getReadyJob()
-
-		for j == nil {
-			m.notEmpty.Wait()
-
-			// it's possible that we have been stopped - check here
-			select {
-			case <-m.stop:
-				m.mutex.Unlock()
-				return
-			default:
-				// keep going
-				j = m.getReadyJob()
-			}
+		for _, j := range m.getReadyJobs() {
+			m.dispatch(j)
 		}
 
-		done := m.dispatch(j)
-
-		// unlock the mutex and wait for the job to finish
-		m.mutex.Unlock()
-		<-done
-		m.mutex.Lock()
-
-		// remove the job from the queue
-		m.removeJob(j)
+		// wait until a job is added or finishes
+		m.notEmpty.Wait()
 
-		// process next job
+		// it's possible that we have been stopped - check here
+		select {
+		case <-m.stop:
+			return
+		default:
+		}
 	}
 }
 
@@ -171,7 +265,7 @@ func (m *Manager) newProgress(j *Job) *Progress {
 	}
 }
 
-func (m *Manager) dispatch(j *Job) (done chan struct{}) {
+func (m *Manager) dispatch(j *Job) {
 	// assumes lock held
 	t := time.Now()
 	j.StartTime = &t
@@ -180,33 +274,52 @@ func (m *Manager) dispatch(j *Job) (done chan struct{}) {
 	ctx, cancelFunc := context.WithCancel(utils.ValueOnlyContext(j.outerCtx))
 	j.cancelFunc = cancelFunc
 
-	done = make(chan struct{})
+	m.running[j.Group]++
+
+	history := m.history
+	if history != nil {
+		ctx = history.Started(ctx, *j)
+	}
+
 	go func() {
 		progress := m.newProgress(j)
 		j.exec.Execute(ctx, progress)
 
-		m.onJobFinish(j)
-
-		close(done)
+		finished := m.onJobFinish(j)
+		if history != nil {
+			history.Finished(finished)
+		}
 	}()
 
 	m.notifyJobUpdate(j)
-
-	return
 }
 
-func (m *Manager) onJobFinish(job *Job) {
+// onJobFinish sets the final status of the job, removes it from the queue
+// and returns a copy of it.
+func (m *Manager) onJobFinish(job *Job) Job {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
 	if job.Status == StatusStopping {
 		job.Status = StatusCancelled
+	} else if job.Error != nil {
+		job.Status = StatusFailed
 	} else {
 		job.Status = StatusFinished
 	}
 
 	t := time.Now()
 	job.EndTime = &t
+
+	ret := *job
+
+	m.running[job.Group]--
+	m.removeJob(job)
+
+	// another job may now be able to start
+	m.notEmpty.Broadcast()
+
+	return ret
 }
 
 func (m *Manager) removeJob(job *Job) {
@@ -268,6 +381,60 @@ func (m *Manager) CancelJob(id int) {
 	}
 }
 
+// PauseJob pauses the running job with the provided id. The job stops at the
+// next point where it checks whether it is paused, until it is resumed or
+// cancelled. Pausing a job that is already paused has no effect.
+func (m *Manager) PauseJob(id int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, j := m.getJob(m.queue, id)
+	if j == nil {
+		return ErrJobNotFound
+	}
+
+	if !j.Pausable {
+		return ErrJobNotPausable
+	}
+
+	switch j.Status {
+	case StatusPaused:
+		return nil
+	case StatusRunning:
+		j.Status = StatusPaused
+		j.resume = make(chan struct{})
+		m.notifyJobUpdate(j)
+		return nil
+	}
+
+	return fmt.Errorf("cannot pause job with status %s", j.Status)
+}
+
+// ResumeJob resumes the paused job with the provided id. Resuming a job that
+// is already running has no effect.
+func (m *Manager) ResumeJob(id int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, j := m.getJob(m.queue, id)
+	if j == nil {
+		return ErrJobNotFound
+	}
+
+	switch j.Status {
+	case StatusRunning:
+		return nil
+	case StatusPaused:
+		j.Status = StatusRunning
+		close(j.resume)
+		j.resume = nil
+		m.notifyJobUpdate(j)
+		return nil
+	}
+
+	return fmt.Errorf("cannot resume job with status %s", j.Status)
+}
+
 // CancelAll cancels all of the jobs in the queue. This is the same as
 // calling CancelJob on all jobs in the queue.
 func (m *Manager) CancelAll() {
@@ -346,9 +513,9 @@ func (m *Manager) Subscribe(ctx context.Context) *ManagerSubscription {
 }
 
 func (m *Manager) notifyJobUpdate(j *Job) {
-	// don't update if job is finished or cancelled - these are handled
-	// by removeJob
-	if j.Status == StatusCancelled || j.Status == StatusFinished {
+	// don't update if job is finished, failed or cancelled - these are
+	// handled by removeJob
+	if j.Status == StatusCancelled || j.Status == StatusFinished || j.Status == StatusFailed {
 		return
 	}
 
@@ -376,11 +543,45 @@ func (u *updater) notifyUpdate() {
 	u.updateTimer = nil
 }
 
-func (u *updater) updateProgress(progress float64, details []string) {
+// waitIfPaused blocks while the job is paused. Returns when the job is
+// resumed or the context is cancelled.
+func (u *updater) waitIfPaused(ctx context.Context) {
+	u.m.mutex.Lock()
+	resume := u.job.resume
+	u.m.mutex.Unlock()
+
+	if resume == nil {
+		return
+	}
+
+	select {
+	case <-resume:
+	case <-ctx.Done():
+	}
+}
+
+func (u *updater) isPaused() bool {
+	u.m.mutex.Lock()
+	defer u.m.mutex.Unlock()
+
+	return u.job.Status == StatusPaused
+}
+
+func (u *updater) setError(err error) {
+	u.m.mutex.Lock()
+	defer u.m.mutex.Unlock()
+
+	errStr := err.Error()
+	u.job.Error = &errStr
+}
+
+func (u *updater) updateProgress(progress float64, processed int, total int, details []string) {
 	u.m.mutex.Lock()
 	defer u.m.mutex.Unlock()
 
 	u.job.Progress = progress
+	u.job.Processed = processed
+	u.job.Total = total
 	u.job.Details = details
 
 	if time.Since(u.lastUpdate) < u.m.updateThrottleLimit {
@@ -2,6 +2,8 @@ package job
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -341,3 +343,287 @@ func TestSubscribe(t *testing.T) {
 
 	cancel()
 }
+
+type testHistory struct {
+	mutex    sync.Mutex
+	started  []Job
+	finished []Job
+}
+
+func (h *testHistory) Started(ctx context.Context, j Job) context.Context {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.started = append(h.started, j)
+	return ctx
+}
+
+func (h *testHistory) Finished(j Job) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.finished = append(h.finished, j)
+}
+
+func TestHistory(t *testing.T) {
+	m := NewManager()
+	h := &testHistory{}
+	m.SetHistory(h)
+
+	const jobName = "test job"
+	exec1 := newTestExec(make(chan struct{}))
+	jobID := m.Add(context.Background(), jobName, exec1)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	<-exec1.started
+	exec1.progress.SetTotal(10)
+	exec1.progress.SetProcessed(5)
+	exec1.progress.SetError(errors.New("test error"))
+	close(exec1.finish)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert := assert.New(t)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if assert.Len(h.started, 1) {
+		assert.Equal(jobID, h.started[0].ID)
+		assert.Equal(StatusRunning, h.started[0].Status)
+	}
+
+	if assert.Len(h.finished, 1) {
+		j := h.finished[0]
+		assert.Equal(jobID, j.ID)
+		assert.Equal(StatusFailed, j.Status)
+		assert.Equal(5, j.Processed)
+		assert.Equal(10, j.Total)
+		assert.NotNil(j.EndTime)
+		if assert.NotNil(j.Error) {
+			assert.Equal("test error", *j.Error)
+		}
+	}
+
+	// expect the job to be failed
+	assert.Equal(StatusFailed, m.GetJob(jobID).Status)
+}
+
+type testPausableExec struct {
+	*testExec
+	// work is sent a value for each work unit processed
+	work chan struct{}
+}
+
+func (e *testPausableExec) Pausable() bool {
+	return true
+}
+
+func (e *testPausableExec) Execute(ctx context.Context, p *Progress) {
+	e.progress = p
+	close(e.started)
+
+	for {
+		p.WaitIfPaused(ctx)
+
+		select {
+		case <-ctx.Done():
+			e.cancelled = true
+			return
+		case <-e.finish:
+			return
+		case e.work <- struct{}{}:
+		}
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	m := NewManager()
+
+	exec1 := &testPausableExec{
+		testExec: newTestExec(make(chan struct{})),
+		work:     make(chan struct{}),
+	}
+	jobID := m.Add(context.Background(), "test job", exec1)
+
+	<-exec1.started
+	<-exec1.work
+
+	assert := assert.New(t)
+
+	assert.True(m.GetJob(jobID).Pausable)
+	assert.Nil(m.PauseJob(jobID))
+	assert.Equal(StatusPaused, m.GetJob(jobID).Status)
+
+	// allow the current work unit to complete
+	select {
+	case <-exec1.work:
+	case <-time.After(sleepTime):
+	}
+
+	// expect no work to be processed while paused
+	select {
+	case <-exec1.work:
+		t.Error("work was processed while paused")
+	case <-time.After(sleepTime):
+	}
+
+	assert.Nil(m.ResumeJob(jobID))
+	assert.Equal(StatusRunning, m.GetJob(jobID).Status)
+
+	select {
+	case <-exec1.work:
+	case <-time.After(time.Second):
+		t.Error("work was not processed after resume")
+	}
+
+	// cancelling a paused job should stop it
+	assert.Nil(m.PauseJob(jobID))
+	m.CancelJob(jobID)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	j := m.GetJob(jobID)
+	assert.Equal(StatusCancelled, j.Status)
+	assert.True(exec1.cancelled)
+}
+
+func TestPauseNotPausable(t *testing.T) {
+	m := NewManager()
+
+	exec1 := newTestExec(make(chan struct{}))
+	jobID := m.Add(context.Background(), "test job", exec1)
+
+	<-exec1.started
+
+	assert := assert.New(t)
+	assert.False(m.GetJob(jobID).Pausable)
+	assert.Equal(ErrJobNotPausable, m.PauseJob(jobID))
+	assert.Equal(ErrJobNotFound, m.PauseJob(jobID+1))
+	assert.Equal(StatusRunning, m.GetJob(jobID).Status)
+
+	close(exec1.finish)
+}
+
+func isStarted(e *testExec) bool {
+	select {
+	case <-e.started:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestGroups(t *testing.T) {
+	m := NewManager()
+
+	scan1 := newTestExec(make(chan struct{}))
+	scan2 := newTestExec(make(chan struct{}))
+	generate := newTestExec(make(chan struct{}))
+	metadata := newTestExec(make(chan struct{}))
+
+	m.AddToGroup(context.Background(), GroupScan, "scan 1", scan1)
+	m.AddToGroup(context.Background(), GroupScan, "scan 2", scan2)
+	generateID := m.AddToGroup(context.Background(), GroupGenerate, "generate", generate)
+	m.Add(context.Background(), "metadata", metadata)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert := assert.New(t)
+
+	// jobs in different groups run concurrently, jobs in the same group
+	// run one at a time
+	assert.True(isStarted(scan1))
+	assert.False(isStarted(scan2))
+	assert.True(isStarted(generate))
+	assert.True(isStarted(metadata))
+
+	assert.Equal(GroupGenerate, m.GetJob(generateID).Group)
+
+	close(scan1.finish)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert.True(isStarted(scan2))
+
+	close(scan2.finish)
+	close(generate.finish)
+	close(metadata.finish)
+}
+
+func TestGroupLimit(t *testing.T) {
+	m := NewManager()
+	m.SetGroupLimit(GroupScan, 2)
+
+	scan1 := newTestExec(make(chan struct{}))
+	scan2 := newTestExec(make(chan struct{}))
+	scan3 := newTestExec(make(chan struct{}))
+
+	m.AddToGroup(context.Background(), GroupScan, "scan 1", scan1)
+	m.AddToGroup(context.Background(), GroupScan, "scan 2", scan2)
+	m.AddToGroup(context.Background(), GroupScan, "scan 3", scan3)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert := assert.New(t)
+	assert.True(isStarted(scan1))
+	assert.True(isStarted(scan2))
+	assert.False(isStarted(scan3))
+
+	// raising the limit should start the waiting job
+	m.SetGroupLimit(GroupScan, 3)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert.True(isStarted(scan3))
+
+	close(scan1.finish)
+	close(scan2.finish)
+	close(scan3.finish)
+}
+
+func TestGroupExclusive(t *testing.T) {
+	m := NewManager()
+
+	scan := newTestExec(make(chan struct{}))
+	exclusive := newTestExec(make(chan struct{}))
+	generate := newTestExec(make(chan struct{}))
+
+	m.AddToGroup(context.Background(), GroupScan, "scan", scan)
+	m.AddToGroup(context.Background(), GroupExclusive, "exclusive", exclusive)
+	m.AddToGroup(context.Background(), GroupGenerate, "generate", generate)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert := assert.New(t)
+
+	// exclusive job waits for the running job, and jobs added after it
+	// wait for the exclusive job
+	assert.True(isStarted(scan))
+	assert.False(isStarted(exclusive))
+	assert.False(isStarted(generate))
+
+	close(scan.finish)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert.True(isStarted(exclusive))
+	assert.False(isStarted(generate))
+
+	close(exclusive.finish)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert.True(isStarted(generate))
+
+	close(generate.finish)
+}
@@ -1,6 +1,9 @@
 package job
 
-import "sync"
+import (
+	"context"
+	"sync"
+)
 
 // ProgressIndefinite is the special percent value to indicate that the
 // percent progress is not known.
@@ -28,7 +31,7 @@ func (p *Progress) updated() {
 		details = append(details, t.description)
 	}
 
-	p.updater.updateProgress(p.percent, details)
+	p.updater.updateProgress(p.percent, p.processed, p.total, details)
 }
 
 // Indefinite sets the progress to an indefinite amount.
@@ -125,6 +128,24 @@ func (p *Progress) removeTask(t *task) {
 	}
 }
 
+// SetError marks the job as failed with the provided error. The job should
+// return once the error is set.
+func (p *Progress) SetError(err error) {
+	p.updater.setError(err)
+}
+
+// IsPaused returns true if the job has been paused.
+func (p *Progress) IsPaused() bool {
+	return p.updater.isPaused()
+}
+
+// WaitIfPaused blocks while the job is paused, returning when the job is
+// resumed or cancelled. Jobs that support pausing must call this between work
+// units.
+func (p *Progress) WaitIfPaused(ctx context.Context) {
+	p.updater.waitIfPaused(ctx)
+}
+
 // ExecuteTask executes a task as part of a job. The description is used to
 // populate the Details slice in the parent Job.
 func (p *Progress) ExecuteTask(description string, fn func()) {
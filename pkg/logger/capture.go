@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxCaptureItems is the maximum number of log items kept by a Capture.
+const maxCaptureItems = 1000
+
+type captureKey struct{}
+
+var captures []*Capture
+
+// Capture collects the log items that are logged through it, such as the
+// log items of a job. The items are also logged as normal. Only items at or
+// above the current log level are collected. Progress items are not
+// collected.
+//
+// The methods of a nil Capture log as normal without collecting, so that
+// code run outside of a job can log through FromContext.
+type Capture struct {
+	mutex sync.Mutex
+	items []LogItem
+	// all is true if the capture collects all log items
+	all bool
+}
+
+// NewCapture returns a Capture that collects the log items that are logged
+// through it.
+func NewCapture() *Capture {
+	return &Capture{}
+}
+
+// StartCapture returns a Capture that collects all log items, whether they
+// are logged through it or not, until Stop is called. It must only be used
+// when nothing else is logging, such as for jobs that do not run
+// concurrently with other jobs.
+func StartCapture() *Capture {
+	ret := &Capture{all: true}
+
+	mutex.Lock()
+	captures = append(captures, ret)
+	mutex.Unlock()
+
+	return ret
+}
+
+// Stop stops a capture returned by StartCapture from collecting log items.
+// It does nothing for other captures.
+func (c *Capture) Stop() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for i, cc := range captures {
+		if cc == c {
+			captures = append(captures[:i], captures[i+1:]...)
+			break
+		}
+	}
+}
+
+// WithCapture returns a copy of ctx that logs through the capture.
+func WithCapture(ctx context.Context, c *Capture) context.Context {
+	return context.WithValue(ctx, captureKey{}, c)
+}
+
+// FromContext returns the capture of ctx, or nil if ctx is nil or does not
+// have a capture.
+func FromContext(ctx context.Context) *Capture {
+	if ctx == nil {
+		return nil
+	}
+
+	c, _ := ctx.Value(captureKey{}).(*Capture)
+	return c
+}
+
+// Items returns the collected log items, oldest first.
+func (c *Capture) Items() []LogItem {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ret := make([]LogItem, len(c.items))
+	copy(ret, c.items)
+	return ret
+}
+
+func (c *Capture) add(l LogItem) {
+	level, ok := logItemLevel(l.Type)
+	if !ok || !logger.IsLevelEnabled(level) {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// keep the most recent items
+	if len(c.items) >= maxCaptureItems {
+		c.items = c.items[1:]
+	}
+	c.items = append(c.items, l)
+}
+
+// log collects an item that was logged through the capture.
+func (c *Capture) log(itemType string, message string) {
+	// captures of all items have collected the item already
+	if c == nil || c.all {
+		return
+	}
+
+	c.add(LogItem{
+		Time:    time.Now(),
+		Type:    itemType,
+		Message: message,
+	})
+}
+
+func (c *Capture) Tracef(format string, args ...interface{}) {
+	Tracef(format, args...)
+	c.log("trace", fmt.Sprintf(format, args...))
+}
+
+func (c *Capture) Debug(args ...interface{}) {
+	Debug(args...)
+	c.log("debug", fmt.Sprint(args...))
+}
+
+func (c *Capture) Debugf(format string, args ...interface{}) {
+	Debugf(format, args...)
+	c.log("debug", fmt.Sprintf(format, args...))
+}
+
+func (c *Capture) Info(args ...interface{}) {
+	Info(args...)
+	c.log("info", fmt.Sprint(args...))
+}
+
+func (c *Capture) Infof(format string, args ...interface{}) {
+	Infof(format, args...)
+	c.log("info", fmt.Sprintf(format, args...))
+}
+
+func (c *Capture) Warn(args ...interface{}) {
+	Warn(args...)
+	c.log("warn", fmt.Sprint(args...))
+}
+
+func (c *Capture) Warnf(format string, args ...interface{}) {
+	Warnf(format, args...)
+	c.log("warn", fmt.Sprintf(format, args...))
+}
+
+func (c *Capture) Error(args ...interface{}) {
+	Error(args...)
+	c.log("error", fmt.Sprint(args...))
+}
+
+func (c *Capture) Errorf(format string, args ...interface{}) {
+	Errorf(format, args...)
+	c.log("error", fmt.Sprintf(format, args...))
+}
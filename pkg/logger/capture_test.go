@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func messages(items []LogItem) []string {
+	var ret []string
+	for _, i := range items {
+		ret = append(ret, i.Message)
+	}
+	return ret
+}
+
+func TestCapture(t *testing.T) {
+	c1 := NewCapture()
+	c2 := NewCapture()
+	ctx1 := WithCapture(context.Background(), c1)
+	ctx2 := WithCapture(context.Background(), c2)
+
+	FromContext(ctx1).Infof("first %d", 1)
+	FromContext(ctx2).Warn("second")
+	FromContext(context.Background()).Info("not captured")
+	FromContext(nil).Info("not captured")
+	Info("not captured")
+
+	assert.Equal(t, []string{"first 1"}, messages(c1.Items()))
+	assert.Equal(t, []string{"second"}, messages(c2.Items()))
+	assert.Equal(t, "warn", c2.Items()[0].Type)
+
+	// items below the log level are not collected
+	FromContext(ctx1).Tracef("trace")
+	assert.Len(t, c1.Items(), 1)
+}
+
+func TestStartCapture(t *testing.T) {
+	c := StartCapture()
+	ctx := WithCapture(context.Background(), c)
+
+	Info("first")
+	FromContext(ctx).Info("second")
+	c.Stop()
+	Info("not captured")
+
+	assert.Equal(t, []string{"first", "second"}, messages(c.Items()))
+}
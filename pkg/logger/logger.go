@@ -83,6 +83,9 @@ func addLogItem(l *LogItem) {
 	if len(LogCache) > 30 {
 		LogCache = LogCache[:len(LogCache)-1]
 	}
+	for _, c := range captures {
+		c.add(*l)
+	}
 	mutex.Unlock()
 	go broadcastLogItem(l)
 }
@@ -98,6 +101,23 @@ func GetLogCache() []LogItem {
 	return ret
 }
 
+func logItemLevel(itemType string) (logrus.Level, bool) {
+	switch itemType {
+	case "trace":
+		return logrus.TraceLevel, true
+	case "debug":
+		return logrus.DebugLevel, true
+	case "info":
+		return logrus.InfoLevel, true
+	case "warn":
+		return logrus.WarnLevel, true
+	case "error":
+		return logrus.ErrorLevel, true
+	}
+
+	return 0, false
+}
+
 func SubscribeToLog(stop chan int) <-chan []LogItem {
 	ret := make(chan []LogItem, 100)
 
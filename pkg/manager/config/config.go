@@ -16,6 +16,7 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/stashapp/stash/pkg/archive"
 	"github.com/stashapp/stash/pkg/manager/paths"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
@@ -48,10 +49,15 @@ var defaultImageExtensions = []string{"png", "jpg", "jpeg", "gif", "webp"}
 
 const GalleryExtensions = "gallery_extensions"
 
-var defaultGalleryExtensions = []string{"zip", "cbz"}
+// defaultGalleryExtensions are the extensions of the supported archive formats
+var defaultGalleryExtensions = archive.Extensions()
 
 const CreateGalleriesFromFolders = "create_galleries_from_folders"
 
+// CreateImageClipsFromVideos is the config key used to determine if video
+// files in stash directories that exclude videos are scanned as images.
+const CreateImageClipsFromVideos = "create_image_clips_from_videos"
+
 // CalculateMD5 is the config key used to determine if MD5 should be calculated
 // for video files.
 const CalculateMD5 = "calculate_md5"
@@ -63,6 +69,17 @@ const VideoFileNamingAlgorithm = "video_file_naming_algorithm"
 const MaxTranscodeSize = "max_transcode_size"
 const MaxStreamingTranscodeSize = "max_streaming_transcode_size"
 
+// Transcode profile options. The custom arguments are only used with custom
+// hardware acceleration.
+const TranscodeHardwareAcceleration = "transcode.hardware_acceleration"
+const TranscodeHardwareDevice = "transcode.hardware_device"
+const TranscodeCustomInputArgs = "transcode.custom_input_args"
+const TranscodeCustomVideoArgs = "transcode.custom_video_args"
+
+// PreferredAudioLanguages are the language codes of the audio tracks that
+// are transcoded when no track is requested, in order of preference.
+const PreferredAudioLanguages = "transcode.preferred_audio_languages"
+
 const ParallelTasks = "parallel_tasks"
 const parallelTasksDefault = 1
 
@@ -140,6 +157,33 @@ const DLNADefaultEnabled = "dlna.default_enabled"
 const DLNADefaultIPWhitelist = "dlna.default_whitelist"
 const DLNAInterfaces = "dlna.interfaces"
 
+// Library watcher options
+const WatchEnabled = "watch.enabled"
+const WatchPollInterval = "watch.poll_interval"
+const watchPollIntervalDefault = 300
+
+const WatchGeneratePreviews = "watch.generate_previews"
+const WatchGenerateSprites = "watch.generate_sprites"
+const WatchGeneratePhashes = "watch.generate_phashes"
+
+// Scheduled tasks
+const Schedules = "schedules"
+
+// Job history options
+const JobHistoryRetentionDays = "job_history.retention_days"
+const jobHistoryRetentionDaysDefault = 30
+
+const JobHistoryMaxEntries = "job_history.max_entries"
+const jobHistoryMaxEntriesDefault = 500
+
+// Job concurrency limits. Jobs in each group may run concurrently with jobs
+// in other groups.
+const ScanJobLimit = "job_limits.scan"
+const GenerateJobLimit = "job_limits.generate"
+const MetadataJobLimit = "job_limits.metadata"
+const ScrapingJobLimit = "job_limits.scraping"
+const jobLimitDefault = 1
+
 // Logging options
 const LogFile = "logFile"
 const LogOut = "logOut"
@@ -369,6 +413,12 @@ func (i *Instance) GetCreateGalleriesFromFolders() bool {
 	return viper.GetBool(CreateGalleriesFromFolders)
 }
 
+func (i *Instance) GetCreateImageClipsFromVideos() bool {
+	i.RLock()
+	defer i.RUnlock()
+	return viper.GetBool(CreateImageClipsFromVideos)
+}
+
 func (i *Instance) GetLanguage() string {
 	i.RLock()
 	defer i.RUnlock()
@@ -591,6 +641,52 @@ func (i *Instance) GetMaxStreamingTranscodeSize() models.StreamingResolutionEnum
 	return models.StreamingResolutionEnum(ret)
 }
 
+// GetTranscodeHardwareAcceleration returns the hardware acceleration used
+// when transcoding streams and generating previews. Defaults to none.
+func (i *Instance) GetTranscodeHardwareAcceleration() models.HardwareAcceleration {
+	i.RLock()
+	defer i.RUnlock()
+	ret := models.HardwareAcceleration(viper.GetString(TranscodeHardwareAcceleration))
+
+	if !ret.IsValid() {
+		return models.HardwareAccelerationNone
+	}
+
+	return ret
+}
+
+// GetTranscodeHardwareDevice returns the device used by hardware
+// transcoding. An empty string uses the default device.
+func (i *Instance) GetTranscodeHardwareDevice() string {
+	i.RLock()
+	defer i.RUnlock()
+	return viper.GetString(TranscodeHardwareDevice)
+}
+
+// GetTranscodeCustomInputArgs returns the ffmpeg arguments added before the
+// input when using custom hardware acceleration.
+func (i *Instance) GetTranscodeCustomInputArgs() []string {
+	i.RLock()
+	defer i.RUnlock()
+	return viper.GetStringSlice(TranscodeCustomInputArgs)
+}
+
+// GetTranscodeCustomVideoArgs returns the ffmpeg arguments that encode H.264
+// video when using custom hardware acceleration.
+func (i *Instance) GetTranscodeCustomVideoArgs() []string {
+	i.RLock()
+	defer i.RUnlock()
+	return viper.GetStringSlice(TranscodeCustomVideoArgs)
+}
+
+// GetPreferredAudioLanguages returns the language codes of the audio tracks
+// that are transcoded when no track is requested, in order of preference.
+func (i *Instance) GetPreferredAudioLanguages() []string {
+	i.RLock()
+	defer i.RUnlock()
+	return viper.GetStringSlice(PreferredAudioLanguages)
+}
+
 func (i *Instance) GetAPIKey() string {
 	i.RLock()
 	defer i.RUnlock()
@@ -852,6 +948,131 @@ func (i *Instance) GetDLNAInterfaces() []string {
 	return viper.GetStringSlice(DLNAInterfaces)
 }
 
+// GetWatchEnabled returns true if the stash directories should be watched
+// for new and modified files.
+func (i *Instance) GetWatchEnabled() bool {
+	i.RLock()
+	defer i.RUnlock()
+	return viper.GetBool(WatchEnabled)
+}
+
+// GetWatchPollInterval returns the interval, in seconds, between polls of
+// stash directories that cannot be watched using filesystem notifications,
+// such as network mounts. Defaults to 300.
+func (i *Instance) GetWatchPollInterval() int {
+	i.RLock()
+	defer i.RUnlock()
+	ret := watchPollIntervalDefault
+	if viper.IsSet(WatchPollInterval) {
+		ret = viper.GetInt(WatchPollInterval)
+	}
+
+	if ret <= 0 {
+		ret = watchPollIntervalDefault
+	}
+
+	return ret
+}
+
+// GetWatchGeneratePreviews returns true if previews should be generated for
+// scenes found by the library watcher.
+func (i *Instance) GetWatchGeneratePreviews() bool {
+	i.RLock()
+	defer i.RUnlock()
+	return viper.GetBool(WatchGeneratePreviews)
+}
+
+// GetWatchGenerateSprites returns true if sprites should be generated for
+// scenes found by the library watcher.
+func (i *Instance) GetWatchGenerateSprites() bool {
+	i.RLock()
+	defer i.RUnlock()
+	return viper.GetBool(WatchGenerateSprites)
+}
+
+// GetWatchGeneratePhashes returns true if phashes should be generated for
+// scenes found by the library watcher.
+func (i *Instance) GetWatchGeneratePhashes() bool {
+	i.RLock()
+	defer i.RUnlock()
+	return viper.GetBool(WatchGeneratePhashes)
+}
+
+// GetSchedules returns the configured scheduled tasks.
+func (i *Instance) GetSchedules() []*Schedule {
+	i.RLock()
+	defer i.RUnlock()
+	var ret []*Schedule
+	viper.UnmarshalKey(Schedules, &ret)
+	return ret
+}
+
+// GetJobHistoryRetentionDays returns the number of days that finished jobs
+// are kept in the job history. Zero means that jobs are kept regardless of
+// age. Defaults to 30.
+func (i *Instance) GetJobHistoryRetentionDays() int {
+	i.RLock()
+	defer i.RUnlock()
+	ret := jobHistoryRetentionDaysDefault
+	if viper.IsSet(JobHistoryRetentionDays) {
+		ret = viper.GetInt(JobHistoryRetentionDays)
+	}
+
+	return ret
+}
+
+// GetJobHistoryMaxEntries returns the maximum number of finished jobs kept in
+// the job history. Zero means that there is no maximum. Defaults to 500.
+func (i *Instance) GetJobHistoryMaxEntries() int {
+	i.RLock()
+	defer i.RUnlock()
+	ret := jobHistoryMaxEntriesDefault
+	if viper.IsSet(JobHistoryMaxEntries) {
+		ret = viper.GetInt(JobHistoryMaxEntries)
+	}
+
+	return ret
+}
+
+func (i *Instance) getJobLimit(key string) int {
+	i.RLock()
+	defer i.RUnlock()
+	ret := jobLimitDefault
+	if viper.IsSet(key) {
+		ret = viper.GetInt(key)
+	}
+
+	if ret < 1 {
+		ret = 1
+	}
+
+	return ret
+}
+
+// GetScanJobLimit returns the maximum number of scan and clean jobs that may
+// run concurrently. Defaults to 1.
+func (i *Instance) GetScanJobLimit() int {
+	return i.getJobLimit(ScanJobLimit)
+}
+
+// GetGenerateJobLimit returns the maximum number of generate jobs that may
+// run concurrently. Defaults to 1.
+func (i *Instance) GetGenerateJobLimit() int {
+	return i.getJobLimit(GenerateJobLimit)
+}
+
+// GetMetadataJobLimit returns the maximum number of metadata jobs, such as
+// auto tag and plugin tasks, that may run concurrently. Defaults to 1.
+func (i *Instance) GetMetadataJobLimit() int {
+	return i.getJobLimit(MetadataJobLimit)
+}
+
+// GetScrapingJobLimit returns the maximum number of scraping jobs that may
+// run concurrently. Defaults to 1.
+func (i *Instance) GetScrapingJobLimit() int {
+	return i.getJobLimit(ScrapingJobLimit)
+}
+
 // GetLogFile returns the filename of the file to output logs to.
 // An empty string means that file logging will be disabled.
 func (i *Instance) GetLogFile() string {
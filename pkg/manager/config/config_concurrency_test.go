@@ -39,6 +39,7 @@ func TestConcurrentConfigAccess(t *testing.T) {
 				i.Set(ImageExtensions, i.GetImageExtensions())
 				i.Set(GalleryExtensions, i.GetGalleryExtensions())
 				i.Set(CreateGalleriesFromFolders, i.GetCreateGalleriesFromFolders())
+				i.Set(CreateImageClipsFromVideos, i.GetCreateImageClipsFromVideos())
 				i.Set(Language, i.GetLanguage())
 				i.Set(VideoFileNamingAlgorithm, i.GetVideoFileNamingAlgorithm())
 				i.Set(ScrapersPath, i.GetScrapersPath())
@@ -62,6 +63,11 @@ func TestConcurrentConfigAccess(t *testing.T) {
 				i.Set(PreviewPreset, i.GetPreviewPreset())
 				i.Set(MaxTranscodeSize, i.GetMaxTranscodeSize())
 				i.Set(MaxStreamingTranscodeSize, i.GetMaxStreamingTranscodeSize())
+				i.Set(TranscodeHardwareAcceleration, i.GetTranscodeHardwareAcceleration())
+				i.Set(TranscodeHardwareDevice, i.GetTranscodeHardwareDevice())
+				i.Set(TranscodeCustomInputArgs, i.GetTranscodeCustomInputArgs())
+				i.Set(TranscodeCustomVideoArgs, i.GetTranscodeCustomVideoArgs())
+				i.Set(PreferredAudioLanguages, i.GetPreferredAudioLanguages())
 				i.Set(ApiKey, i.GetAPIKey())
 				i.Set(Username, i.GetUsername())
 				i.Set(Password, i.GetPasswordHash())
@@ -86,6 +92,18 @@ func TestConcurrentConfigAccess(t *testing.T) {
 				i.Set(DLNADefaultEnabled, i.GetDLNADefaultEnabled())
 				i.Set(DLNADefaultIPWhitelist, i.GetDLNADefaultIPWhitelist())
 				i.Set(DLNAInterfaces, i.GetDLNAInterfaces())
+				i.Set(WatchEnabled, i.GetWatchEnabled())
+				i.Set(WatchPollInterval, i.GetWatchPollInterval())
+				i.Set(WatchGeneratePreviews, i.GetWatchGeneratePreviews())
+				i.Set(WatchGenerateSprites, i.GetWatchGenerateSprites())
+				i.Set(WatchGeneratePhashes, i.GetWatchGeneratePhashes())
+				i.Set(Schedules, i.GetSchedules())
+				i.Set(JobHistoryRetentionDays, i.GetJobHistoryRetentionDays())
+				i.Set(JobHistoryMaxEntries, i.GetJobHistoryMaxEntries())
+				i.Set(ScanJobLimit, i.GetScanJobLimit())
+				i.Set(GenerateJobLimit, i.GetGenerateJobLimit())
+				i.Set(MetadataJobLimit, i.GetMetadataJobLimit())
+				i.Set(ScrapingJobLimit, i.GetScrapingJobLimit())
 				i.Set(LogFile, i.GetLogFile())
 				i.Set(LogOut, i.GetLogOut())
 				i.Set(LogLevel, i.GetLogLevel())
@@ -0,0 +1,17 @@
+package config
+
+import "github.com/stashapp/stash/pkg/models"
+
+// Schedule is a task that is run according to a cron expression.
+type Schedule struct {
+	Name   string                   `yaml:"name"`
+	Cron   string                   `yaml:"cron"`
+	Task   models.ScheduledTaskType `yaml:"task"`
+	Paused bool                     `yaml:"paused"`
+
+	// Input for the task. Only the input matching the task type is used.
+	ScanInput     *models.ScanMetadataInput     `yaml:"scaninput,omitempty"`
+	GenerateInput *models.GenerateMetadataInput `yaml:"generateinput,omitempty"`
+	AutoTagInput  *models.AutoTagMetadataInput  `yaml:"autotaginput,omitempty"`
+	CleanInput    *models.CleanMetadataInput    `yaml:"cleaninput,omitempty"`
+}
@@ -0,0 +1,237 @@
+package manager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed cron expression, with the standard five fields:
+// minute, hour, day of month, month and day of week.
+type cronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// true if the day of month or day of week fields are "*"
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinute     = cronField{name: "minute", min: 0, max: 59}
+	cronHour       = cronField{name: "hour", min: 0, max: 23}
+	cronDayOfMonth = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth      = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as an alias for Sunday
+	cronDayOfWeek = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCron parses a cron expression. Each field may be "*", a value, a
+// range such as "1-5", or a comma-separated list of these, optionally
+// followed by a step such as "*/15". Month and day of week names may be
+// used in place of numbers. The macros "@yearly", "@monthly", "@weekly",
+// "@daily" and "@hourly" are also supported.
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, found := cronMacros[strings.ToLower(expr)]; found {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	ret := &cronSchedule{
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}
+
+	var err error
+	if ret.minute, err = cronMinute.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if ret.hour, err = cronHour.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if ret.dayOfMonth, err = cronDayOfMonth.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if ret.month, err = cronMonth.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if ret.dayOfWeek, err = cronDayOfWeek.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// treat 7 as Sunday
+	if ret.dayOfWeek&(1<<7) != 0 {
+		ret.dayOfWeek |= 1
+	}
+
+	return ret, nil
+}
+
+func (f cronField) parse(s string) (uint64, error) {
+	var ret uint64
+	for _, part := range strings.Split(s, ",") {
+		bits, err := f.parsePart(part)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", f.name, s, err)
+		}
+		ret |= bits
+	}
+
+	return ret, nil
+}
+
+func (f cronField) parsePart(s string) (uint64, error) {
+	step := 1
+	if i := strings.Index(s, "/"); i != -1 {
+		var err error
+		step, err = strconv.Atoi(s[i+1:])
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step %q", s[i+1:])
+		}
+		s = s[:i]
+	}
+
+	start, end := f.min, f.max
+	if s != "*" {
+		var err error
+		if i := strings.Index(s, "-"); i != -1 {
+			if start, err = f.parseValue(s[:i]); err != nil {
+				return 0, err
+			}
+			if end, err = f.parseValue(s[i+1:]); err != nil {
+				return 0, err
+			}
+		} else {
+			if start, err = f.parseValue(s); err != nil {
+				return 0, err
+			}
+
+			// a single value with a step runs from the value to the maximum
+			end = start
+			if step > 1 {
+				end = f.max
+			}
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("invalid range %q", s)
+	}
+
+	var ret uint64
+	for v := start; v <= end; v += step {
+		ret |= 1 << uint(v)
+	}
+
+	return ret, nil
+}
+
+func (f cronField) parseValue(s string) (int, error) {
+	if v, found := f.names[strings.ToLower(s)]; found {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d]", v, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+func (c *cronSchedule) matchDay(t time.Time) bool {
+	domMatch := c.dayOfMonth&(1<<uint(t.Day())) != 0
+	dowMatch := c.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	// if both day fields are restricted, then either may match
+	switch {
+	case c.anyDayOfMonth && c.anyDayOfWeek:
+		return true
+	case c.anyDayOfMonth:
+		return dowMatch
+	case c.anyDayOfWeek:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// match returns true if the schedule is due to run in the minute of t.
+func (c *cronSchedule) match(t time.Time) bool {
+	return c.minute&(1<<uint(t.Minute())) != 0 &&
+		c.hour&(1<<uint(t.Hour())) != 0 &&
+		c.month&(1<<uint(t.Month())) != 0 &&
+		c.matchDay(t)
+}
+
+// next returns the first time after t that the schedule is due to run.
+// Returns the zero time if the schedule never runs, for example on the 30th
+// of February.
+func (c *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// all possible combinations repeat within a few years
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
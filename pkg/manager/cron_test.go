@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@never",
+	}
+
+	for _, expr := range invalid {
+		_, err := parseCron(expr)
+		assert.Error(t, err, "expected error for %q", expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	date := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2021, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	// Friday
+	from := date(time.October, 1, 12, 30)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", date(time.October, 1, 12, 31)},
+		{"*/15 * * * *", date(time.October, 1, 12, 45)},
+		{"0 * * * *", date(time.October, 1, 13, 0)},
+		{"@hourly", date(time.October, 1, 13, 0)},
+		{"30 12 * * *", date(time.October, 2, 12, 30)},
+		{"0 3 * * *", date(time.October, 2, 3, 0)},
+		{"@daily", date(time.October, 2, 0, 0)},
+		{"0 3 * * mon", date(time.October, 4, 3, 0)},
+		{"0 3 * * 1-5", date(time.October, 4, 3, 0)},
+		{"0 3 * * 0", date(time.October, 3, 3, 0)},
+		{"0 3 * * 7", date(time.October, 3, 3, 0)},
+		{"0 3 15 * *", date(time.October, 15, 3, 0)},
+		{"0 0 1 jan *", time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0,20,40 9-17/4 * * *", date(time.October, 1, 13, 0)},
+		{"5/20 * * * *", date(time.October, 1, 12, 45)},
+		// day of month or day of week
+		{"0 0 15 * sun", date(time.October, 3, 0, 0)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		c, err := parseCron(tt.expr)
+		if err != nil {
+			t.Errorf("parseCron(%q) error: %s", tt.expr, err.Error())
+			continue
+		}
+
+		got := c.next(from)
+		assert.Equal(t, tt.want, got, "next for %q", tt.expr)
+		assert.True(t, c.match(got), "match for %q", tt.expr)
+	}
+}
+
+func TestCronNextNever(t *testing.T) {
+	c, err := parseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, c.next(time.Now()).IsZero())
+}
@@ -200,6 +200,7 @@ func newParseMapper(pattern string, ignoreFields []string) (*parseMapper, error)
 type sceneHolder struct {
 	scene      *models.Scene
 	result     *models.Scene
+	rating     sql.NullInt64
 	yyyy       string
 	mm         string
 	dd         string
@@ -324,7 +325,7 @@ func (h *sceneHolder) setField(field parserField, value interface{}) {
 	case "rating":
 		rating, _ := strconv.Atoi(value.(string))
 		if validateRating(rating) {
-			h.result.Rating = sql.NullInt64{
+			h.rating = sql.NullInt64{
 				Int64: int64(rating),
 				Valid: true,
 			}
@@ -668,8 +669,8 @@ func (p *SceneFilenameParser) setParserResult(repo models.ReaderRepository, h sc
 		result.Date = &h.result.Date.String
 	}
 
-	if h.result.Rating.Valid {
-		rating := int(h.result.Rating.Int64)
+	if h.rating.Valid {
+		rating := int(h.rating.Int64)
 		result.Rating = &rating
 	}
 
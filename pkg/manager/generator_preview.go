@@ -24,6 +24,10 @@ type PreviewGenerator struct {
 
 	PreviewPreset string
 
+	// TranscodeProfile selects the encoder of the preview video. The
+	// fallback generation always encodes in software.
+	TranscodeProfile ffmpeg.TranscodeProfile
+
 	Overwrite bool
 }
 
@@ -115,6 +119,11 @@ func (g *PreviewGenerator) generateVideo(encoder *ffmpeg.Encoder, fallback bool)
 
 	includeAudio := g.Info.Audio
 
+	profile := g.TranscodeProfile
+	if fallback {
+		profile = ffmpeg.SoftwareTranscodeProfile
+	}
+
 	for i := 0; i < g.Info.ChunkCount; i++ {
 		time := offset + (float64(i) * stepSize)
 		num := fmt.Sprintf("%.3d", i)
@@ -127,6 +136,7 @@ func (g *PreviewGenerator) generateVideo(encoder *ffmpeg.Encoder, fallback bool)
 			Width:      640,
 			OutputPath: chunkOutputPath,
 			Audio:      includeAudio,
+			Profile:    profile,
 		}
 		if err := encoder.ScenePreviewVideoChunk(g.Info.VideoFile, options, g.PreviewPreset, fallback); err != nil {
 			return err
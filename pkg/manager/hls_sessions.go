@@ -0,0 +1,356 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const (
+	// hlsSessionIdleTimeout is the time after the last segment request
+	// that a session is stopped and its segments are removed.
+	hlsSessionIdleTimeout = 2 * time.Minute
+
+	// hlsMaxSegmentsAhead is the number of segments that a transcode may
+	// produce ahead of the last requested segment before it is stopped.
+	// The transcode is restarted when the viewer catches up.
+	hlsMaxSegmentsAhead = 30
+
+	// hlsMaxSeekGap is the number of segments that a requested segment may
+	// be ahead of the transcode before the transcode is restarted at the
+	// requested segment.
+	hlsMaxSeekGap = 2
+
+	hlsSegmentPollInterval = 100 * time.Millisecond
+	hlsCleanupInterval     = 5 * time.Second
+)
+
+type hlsTranscode interface {
+	Done() <-chan struct{}
+	Err() error
+	Stop()
+}
+
+type startHLSTranscodeFunc func(options ffmpeg.HLSTranscodeOptions) (hlsTranscode, error)
+
+func startHLSTranscode(options ffmpeg.HLSTranscodeOptions) (hlsTranscode, error) {
+	encoder := ffmpeg.NewEncoder(instance.FFMPEGPath)
+	t, err := encoder.StartHLSTranscode(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// hlsSessionKey identifies the session of a rendition of a viewer.
+type hlsSessionKey struct {
+	id        string
+	rendition string
+}
+
+type hlsSession struct {
+	mutex sync.Mutex
+
+	options ffmpeg.HLSTranscodeOptions
+	start   startHLSTranscodeFunc
+
+	transcode hlsTranscode
+	// next is the first segment from the start segment of the transcode
+	// that has not been written
+	next int
+
+	lastSegment int
+	lastAccess  time.Time
+}
+
+func (s *hlsSession) segmentPath(segment int) string {
+	return filepath.Join(s.options.OutputDir, ffmpeg.HLSSegmentFilename(segment))
+}
+
+func (s *hlsSession) segmentExists(segment int) bool {
+	exists, _ := utils.FileExists(s.segmentPath(segment))
+	return exists
+}
+
+func (s *hlsSession) running() bool {
+	if s.transcode == nil {
+		return false
+	}
+
+	select {
+	case <-s.transcode.Done():
+		return false
+	default:
+		return true
+	}
+}
+
+// advance updates the next segment to be written by the transcode.
+func (s *hlsSession) advance() {
+	for s.segmentExists(s.next) {
+		s.next++
+	}
+}
+
+// transcoding returns true if the running transcode will write the segment
+// soon.
+func (s *hlsSession) transcoding(segment int) bool {
+	if !s.running() || segment < s.options.StartSegment {
+		return false
+	}
+
+	s.advance()
+	return segment <= s.next+hlsMaxSeekGap
+}
+
+func (s *hlsSession) restart(segment int) error {
+	s.stop()
+
+	options := s.options
+	options.StartSegment = segment
+
+	t, err := s.start(options)
+	if err != nil {
+		return err
+	}
+
+	s.options = options
+	s.transcode = t
+	s.next = segment
+	return nil
+}
+
+func (s *hlsSession) stop() {
+	if s.transcode != nil {
+		s.transcode.Stop()
+	}
+}
+
+// segment waits until the segment is written and returns its path. The
+// transcode is restarted at the segment if it will not write the segment
+// soon, such as when the viewer seeks.
+func (s *hlsSession) segment(ctx context.Context, segment int) (string, error) {
+	path := s.segmentPath(segment)
+
+	for {
+		s.mutex.Lock()
+		s.lastSegment = segment
+		s.lastAccess = time.Now()
+
+		if s.segmentExists(segment) {
+			s.mutex.Unlock()
+			return path, nil
+		}
+
+		if !s.transcoding(segment) {
+			logger.Debugf("[stream] starting HLS transcode of %s at segment %d", s.options.ProbeResult.Path, segment)
+			if err := s.restart(segment); err != nil {
+				s.mutex.Unlock()
+				return "", fmt.Errorf("error starting HLS transcode: %s", err.Error())
+			}
+		}
+
+		t := s.transcode
+		s.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-t.Done():
+			if s.segmentExists(segment) {
+				return path, nil
+			}
+
+			s.mutex.Lock()
+			restarted := s.transcode != t
+			s.mutex.Unlock()
+
+			// a request for another segment restarted the transcode
+			if restarted {
+				continue
+			}
+
+			if err := t.Err(); err != nil {
+				return "", fmt.Errorf("error transcoding HLS segment %d: %s", segment, err.Error())
+			}
+			return "", fmt.Errorf("HLS transcode ended before segment %d", segment)
+		case <-time.After(hlsSegmentPollInterval):
+		}
+	}
+}
+
+// HLSSessionManager runs the HLS transcodes of viewers. Each session
+// transcodes a rendition of a video file for one viewer into segments in
+// the cache directory. Only the rendition that the viewer last requested a
+// segment of is transcoded.
+type HLSSessionManager struct {
+	mutex    sync.Mutex
+	sessions map[hlsSessionKey]*hlsSession
+
+	dir   func() string
+	start startHLSTranscodeFunc
+
+	cleanupOnce sync.Once
+}
+
+func newHLSSessionManager(dir func() string, start startHLSTranscodeFunc) *HLSSessionManager {
+	return &HLSSessionManager{
+		sessions: make(map[hlsSessionKey]*hlsSession),
+		dir:      dir,
+		start:    start,
+	}
+}
+
+// Segment waits until the segment of the rendition of the session of the
+// video file at path is transcoded and returns the path of the segment file.
+// If the session does not exist, it is created with the options returned
+// by getOptions. The transcodes of the other renditions of the session are
+// stopped.
+func (m *HLSSessionManager) Segment(ctx context.Context, sessionID string, rendition string, path string, segment int, getOptions func() (*ffmpeg.HLSTranscodeOptions, error)) (string, error) {
+	key := hlsSessionKey{
+		id:        sessionID,
+		rendition: rendition,
+	}
+
+	s, err := m.getSession(key, path, getOptions)
+	if err != nil {
+		return "", err
+	}
+
+	if segment < 0 || segment >= ffmpeg.HLSSegmentCount(s.options.ProbeResult) {
+		return "", fmt.Errorf("invalid HLS segment %d", segment)
+	}
+
+	return s.segment(ctx, segment)
+}
+
+// findSession returns the session with the key. The manager must be
+// locked.
+func (m *HLSSessionManager) findSession(key hlsSessionKey, path string) (*hlsSession, error) {
+	s := m.sessions[key]
+	if s != nil && s.options.ProbeResult.Path != path {
+		return nil, errors.New("HLS session belongs to another file")
+	}
+
+	if s != nil {
+		m.stopOtherRenditions(key)
+	}
+
+	return s, nil
+}
+
+// stopOtherRenditions stops the transcodes of the renditions of the session
+// other than the rendition of the key. The manager must be locked.
+func (m *HLSSessionManager) stopOtherRenditions(key hlsSessionKey) {
+	for k, s := range m.sessions {
+		if k.id == key.id && k.rendition != key.rendition {
+			s.mutex.Lock()
+			s.stop()
+			s.mutex.Unlock()
+		}
+	}
+}
+
+func (m *HLSSessionManager) getSession(key hlsSessionKey, path string, getOptions func() (*ffmpeg.HLSTranscodeOptions, error)) (*hlsSession, error) {
+	m.mutex.Lock()
+	s, err := m.findSession(key, path)
+	m.mutex.Unlock()
+
+	if s != nil || err != nil {
+		return s, err
+	}
+
+	// probing the file may be slow, so don't hold the lock
+	options, err := getOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// another request may have created the session in the meantime
+	s, err = m.findSession(key, path)
+	if s != nil || err != nil {
+		return s, err
+	}
+
+	options.OutputDir = filepath.Join(m.dir(), key.id, key.rendition)
+	s = &hlsSession{
+		options:    *options,
+		start:      m.start,
+		lastAccess: time.Now(),
+	}
+	m.sessions[key] = s
+	m.stopOtherRenditions(key)
+
+	m.cleanupOnce.Do(func() {
+		go m.cleanupLoop()
+	})
+
+	return s, nil
+}
+
+func (m *HLSSessionManager) cleanupLoop() {
+	for range time.Tick(hlsCleanupInterval) {
+		m.cleanup(time.Now())
+	}
+}
+
+// cleanup removes the sessions that have been idle since before the idle
+// timeout, and stops the transcodes that are too far ahead of their viewer.
+func (m *HLSSessionManager) cleanup(now time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for key, s := range m.sessions {
+		s.mutex.Lock()
+		if now.Sub(s.lastAccess) > hlsSessionIdleTimeout {
+			logger.Debugf("[stream] removing idle HLS session %s", key.id)
+			m.removeSession(key, s)
+		} else if s.running() {
+			s.advance()
+			if s.next-s.lastSegment > hlsMaxSegmentsAhead {
+				s.stop()
+			}
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// removeSession stops the session and removes its segments. The session
+// must be locked.
+func (m *HLSSessionManager) removeSession(key hlsSessionKey, s *hlsSession) {
+	s.stop()
+	delete(m.sessions, key)
+
+	if err := os.RemoveAll(s.options.OutputDir); err != nil {
+		logger.Warnf("[stream] error removing HLS segments: %s", err.Error())
+	}
+
+	// remove the session directory once all renditions are removed
+	_ = os.Remove(filepath.Dir(s.options.OutputDir))
+}
+
+// Stop stops and removes the sessions of the video file at path.
+func (m *HLSSessionManager) Stop(path string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for key, s := range m.sessions {
+		s.mutex.Lock()
+		if s.options.ProbeResult.Path == path {
+			m.removeSession(key, s)
+		}
+		s.mutex.Unlock()
+	}
+}
@@ -0,0 +1,215 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+)
+
+// testHLSTranscode writes empty segments from the start segment until it is
+// stopped or all segments are written.
+type testHLSTranscode struct {
+	done chan struct{}
+	stop chan struct{}
+	err  error
+}
+
+func (t *testHLSTranscode) Done() <-chan struct{} {
+	return t.done
+}
+
+func (t *testHLSTranscode) Err() error {
+	return t.err
+}
+
+func (t *testHLSTranscode) Stop() {
+	select {
+	case <-t.done:
+		return
+	default:
+	}
+
+	close(t.stop)
+	<-t.done
+}
+
+type testHLSTranscoder struct {
+	mutex  sync.Mutex
+	starts []int
+}
+
+func (tr *testHLSTranscoder) start(options ffmpeg.HLSTranscodeOptions) (hlsTranscode, error) {
+	tr.mutex.Lock()
+	tr.starts = append(tr.starts, options.StartSegment)
+	tr.mutex.Unlock()
+
+	if err := os.MkdirAll(options.OutputDir, 0755); err != nil {
+		return nil, err
+	}
+
+	t := &testHLSTranscode{
+		done: make(chan struct{}),
+		stop: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(t.done)
+
+		count := ffmpeg.HLSSegmentCount(options.ProbeResult)
+		for i := options.StartSegment; i < count; i++ {
+			select {
+			case <-t.stop:
+				t.err = errors.New("killed")
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			path := filepath.Join(options.OutputDir, ffmpeg.HLSSegmentFilename(i))
+			if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+				t.err = err
+				return
+			}
+		}
+	}()
+
+	return t, nil
+}
+
+func (tr *testHLSTranscoder) getStarts() []int {
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+	return append([]int(nil), tr.starts...)
+}
+
+const (
+	testHLSPath      = "video.mp4"
+	testHLSRendition = "ORIGINAL"
+)
+
+func testHLSOptions() (*ffmpeg.HLSTranscodeOptions, error) {
+	return &ffmpeg.HLSTranscodeOptions{
+		ProbeResult: ffmpeg.VideoFile{
+			Path: testHLSPath,
+			// 100 segments
+			Duration: 1000,
+		},
+	}, nil
+}
+
+func newTestHLSSessionManager(t *testing.T) (*HLSSessionManager, *testHLSTranscoder, string) {
+	dir, err := ioutil.TempDir("", "hls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transcoder := &testHLSTranscoder{}
+	return newHLSSessionManager(func() string { return dir }, transcoder.start), transcoder, dir
+}
+
+func TestHLSSessionSegments(t *testing.T) {
+	m, transcoder, dir := newTestHLSSessionManager(t)
+	defer os.RemoveAll(dir)
+	defer m.Stop(testHLSPath)
+
+	ctx := context.Background()
+	segment := func(n int) string {
+		t.Helper()
+		path, err := m.Segment(ctx, "abc", testHLSRendition, testHLSPath, n, testHLSOptions)
+		if err != nil {
+			t.Fatalf("error getting segment %d: %s", n, err.Error())
+		}
+		return path
+	}
+
+	// sequential segments are served by the same transcode
+	assert.Equal(t, filepath.Join(dir, "abc", testHLSRendition, "0.ts"), segment(0))
+	segment(1)
+	segment(2)
+	assert.Equal(t, []int{0}, transcoder.getStarts())
+
+	// seeking forward restarts the transcode at the segment
+	assert.Equal(t, filepath.Join(dir, "abc", testHLSRendition, "50.ts"), segment(50))
+	assert.Equal(t, []int{0, 50}, transcoder.getStarts())
+
+	// seeking back to a transcoded segment uses the cached segment
+	segment(1)
+	assert.Equal(t, []int{0, 50}, transcoder.getStarts())
+
+	// invalid segments and files are rejected
+	_, err := m.Segment(ctx, "abc", testHLSRendition, testHLSPath, 100, testHLSOptions)
+	assert.NotNil(t, err)
+	_, err = m.Segment(ctx, "abc", testHLSRendition, "other.mp4", 0, testHLSOptions)
+	assert.NotNil(t, err)
+}
+
+func TestHLSSessionRenditions(t *testing.T) {
+	m, transcoder, dir := newTestHLSSessionManager(t)
+	defer os.RemoveAll(dir)
+	defer m.Stop(testHLSPath)
+
+	ctx := context.Background()
+	if _, err := m.Segment(ctx, "abc", testHLSRendition, testHLSPath, 0, testHLSOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	original := m.sessions[hlsSessionKey{"abc", testHLSRendition}]
+	assert.True(t, original.running())
+
+	// switching to another rendition stops the transcode of the previous
+	// rendition
+	path, err := m.Segment(ctx, "abc", "LOW", testHLSPath, 1, testHLSOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, filepath.Join(dir, "abc", "LOW", "1.ts"), path)
+	assert.Equal(t, []int{0, 1}, transcoder.getStarts())
+	assert.False(t, original.running())
+	assert.Len(t, m.sessions, 2)
+}
+
+func TestHLSSessionCleanup(t *testing.T) {
+	m, _, dir := newTestHLSSessionManager(t)
+	defer os.RemoveAll(dir)
+
+	if _, err := m.Segment(context.Background(), "abc", testHLSRendition, testHLSPath, 0, testHLSOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	sessionDir := filepath.Join(dir, "abc")
+
+	// active sessions are kept
+	m.cleanup(time.Now())
+	assert.Len(t, m.sessions, 1)
+	assert.DirExists(t, sessionDir)
+
+	// idle sessions are stopped and their segments removed
+	m.cleanup(time.Now().Add(hlsSessionIdleTimeout + time.Second))
+	assert.Len(t, m.sessions, 0)
+	assert.NoDirExists(t, sessionDir)
+}
+
+func TestHLSSessionStop(t *testing.T) {
+	m, _, dir := newTestHLSSessionManager(t)
+	defer os.RemoveAll(dir)
+
+	if _, err := m.Segment(context.Background(), "abc", testHLSRendition, testHLSPath, 0, testHLSOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Stop("other.mp4")
+	assert.Len(t, m.sessions, 1)
+
+	m.Stop(testHLSPath)
+	assert.Len(t, m.sessions, 0)
+	assert.NoDirExists(t, filepath.Join(dir, "abc"))
+}
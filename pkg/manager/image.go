@@ -1,20 +1,33 @@
 package manager
 
 import (
-	"archive/zip"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/stashapp/stash/pkg/archive"
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
-// DeleteGeneratedImageFiles deletes generated files for the provided image.
+// maxAnimatedThumbnailDuration is the maximum length in seconds of the
+// animated thumbnails of video clips.
+const maxAnimatedThumbnailDuration = 30
+
+// DeleteGeneratedImageFiles deletes generated files for the provided image,
+// including the thumbnails of every size and format.
 func DeleteGeneratedImageFiles(image *models.Image) {
-	thumbPath := GetInstance().Paths.Generated.GetThumbnailPath(image.Checksum, models.DefaultGthumbWidth)
-	exists, _ := utils.FileExists(thumbPath)
-	if exists {
+	thumbDir := GetInstance().Paths.Generated.GetThumbnailDir(image.Checksum)
+	thumbPaths, err := filepath.Glob(filepath.Join(thumbDir, image.Checksum+"_*"))
+	if err != nil {
+		logger.Warnf("Could not find thumbnails of %s: %s", image.Path, err.Error())
+		return
+	}
+
+	for _, thumbPath := range thumbPaths {
 		err := os.Remove(thumbPath)
 		if err != nil {
 			logger.Warnf("Could not delete file %s: %s", thumbPath, err.Error())
@@ -22,6 +35,45 @@ func DeleteGeneratedImageFiles(image *models.Image) {
 	}
 }
 
+// generateAnimatedThumbnail encodes the animated thumbnail of the animated
+// image or video clip to thumbPath. Video clips are cut to
+// maxAnimatedThumbnailDuration.
+func generateAnimatedThumbnail(i *models.Image, thumbPath string) error {
+	options := ffmpeg.AnimatedThumbnailOptions{
+		InputPath:    i.Path,
+		OutputPath:   thumbPath,
+		MaxDimension: models.DefaultGthumbWidth,
+	}
+
+	if i.IsVideo {
+		options.MaxDuration = maxAnimatedThumbnailDuration
+	}
+
+	// ffmpeg cannot read files in zip files
+	if image.IsZipPath(i.Path) {
+		f, err := image.Open(i.Path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		options.Input = f
+	}
+
+	if err := utils.EnsureDirAll(filepath.Dir(thumbPath)); err != nil {
+		return err
+	}
+
+	encoder := ffmpeg.NewEncoder(GetInstance().FFMPEGPath)
+	if err := encoder.AnimatedThumbnail(options); err != nil {
+		// remove partially written thumbnails
+		os.Remove(thumbPath)
+		return err
+	}
+
+	return nil
+}
+
 // DeleteImageFile deletes the image file from the filesystem.
 func DeleteImageFile(image *models.Image) {
 	err := os.Remove(image.Path)
@@ -30,18 +82,16 @@ func DeleteImageFile(image *models.Image) {
 	}
 }
 
-func walkGalleryZip(path string, walkFunc func(file *zip.File) error) error {
-	readCloser, err := zip.OpenReader(path)
+// walkGalleryArchive calls walkFunc for each image file in the gallery
+// archive file at path.
+func walkGalleryArchive(path string, walkFunc func(file archive.File) error) error {
+	r, err := archive.Open(path)
 	if err != nil {
 		return err
 	}
-	defer readCloser.Close()
-
-	for _, file := range readCloser.File {
-		if file.FileInfo().IsDir() {
-			continue
-		}
+	defer r.Close()
 
+	for _, file := range r.Files() {
 		if strings.Contains(file.Name, "__MACOSX") {
 			continue
 		}
@@ -59,9 +109,9 @@ func walkGalleryZip(path string, walkFunc func(file *zip.File) error) error {
 	return nil
 }
 
-func countImagesInZip(path string) int {
+func countImagesInArchive(path string) int {
 	ret := 0
-	walkGalleryZip(path, func(file *zip.File) error {
+	walkGalleryArchive(path, func(file archive.File) error {
 		ret++
 		return nil
 	})
@@ -0,0 +1,299 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// errThumbnailNotNeeded is returned by the thumbnail generator if the image
+// is not larger than the thumbnail.
+var errThumbnailNotNeeded = errors.New("thumbnail not needed")
+
+// thumbnailTmpExt is the extension of thumbnails that are being written.
+const thumbnailTmpExt = ".tmp"
+
+type generateThumbnailFunc func(i *models.Image, size int, format image.ThumbnailFormat, path string) error
+
+type thumbnailPathFunc func(checksum string, size int, format image.ThumbnailFormat) string
+
+type probeThumbnailFormatsFunc func() (map[image.ThumbnailFormat]bool, error)
+
+func thumbnailPath(checksum string, size int, format image.ThumbnailFormat) string {
+	return instance.Paths.Generated.GetThumbnailFormatPath(checksum, size, string(format))
+}
+
+// generateImageThumbnail writes the thumbnail of the image to path. JPEG
+// thumbnails are encoded directly, other formats are encoded by ffmpeg. The
+// thumbnail is written to a temporary file first, so that partially written
+// thumbnails are never served.
+func generateImageThumbnail(i *models.Image, size int, format image.ThumbnailFormat, path string) error {
+	srcImage, err := image.GetSourceImage(i)
+	if err != nil {
+		return err
+	}
+
+	if !image.ThumbnailNeeded(srcImage, size) {
+		return errThumbnailNotNeeded
+	}
+
+	if err := utils.EnsureDirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	tmpPath := path + thumbnailTmpExt
+	if format == image.ThumbnailFormatJPEG {
+		data, err := image.GetThumbnail(srcImage, size)
+		if err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+			return err
+		}
+	} else {
+		data, err := image.GetThumbnailPNG(srcImage, size)
+		if err != nil {
+			return err
+		}
+
+		encoder := ffmpeg.NewEncoder(instance.FFMPEGPath)
+		if err := encoder.EncodeThumbnail(ffmpeg.ThumbnailEncodeOptions{
+			Input:      bytes.NewReader(data),
+			OutputPath: tmpPath,
+			Format:     string(format),
+		}); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// probeThumbnailFormats returns the thumbnail formats that ffmpeg can
+// encode.
+func probeThumbnailFormats() (map[image.ThumbnailFormat]bool, error) {
+	encoder := ffmpeg.NewEncoder(instance.FFMPEGPath)
+	formats, err := encoder.ThumbnailFormats()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[image.ThumbnailFormat]bool)
+	for _, f := range formats {
+		ret[image.ThumbnailFormat(f)] = true
+	}
+
+	return ret, nil
+}
+
+type pendingThumbnail struct {
+	done chan struct{}
+	err  error
+}
+
+// ImageThumbnailCache generates the thumbnails of images on demand. The
+// thumbnails are cached in the generated thumbnails directory by image
+// checksum, size and format. The number of thumbnails that are generated
+// at the same time is limited, and concurrent requests for the same
+// thumbnail wait for a single generation.
+type ImageThumbnailCache struct {
+	mutex   sync.Mutex
+	pending map[string]*pendingThumbnail
+	// formats are the formats that ffmpeg can encode. ffmpeg is probed
+	// once, when the first thumbnail is requested.
+	formats map[image.ThumbnailFormat]bool
+
+	limit        chan struct{}
+	path         thumbnailPathFunc
+	generate     generateThumbnailFunc
+	probeFormats probeThumbnailFormatsFunc
+}
+
+func newImageThumbnailCache(limit int, path thumbnailPathFunc, generate generateThumbnailFunc, probeFormats probeThumbnailFormatsFunc) *ImageThumbnailCache {
+	if limit < 1 {
+		limit = 1
+	}
+
+	return &ImageThumbnailCache{
+		pending:      make(map[string]*pendingThumbnail),
+		limit:        make(chan struct{}, limit),
+		path:         path,
+		generate:     generate,
+		probeFormats: probeFormats,
+	}
+}
+
+func newDefaultImageThumbnailCache() *ImageThumbnailCache {
+	return newImageThumbnailCache(runtime.NumCPU(), thumbnailPath, generateImageThumbnail, probeThumbnailFormats)
+}
+
+func (c *ImageThumbnailCache) supported(format image.ThumbnailFormat) bool {
+	if format == image.ThumbnailFormatJPEG {
+		return true
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.formats == nil {
+		formats, err := c.probeFormats()
+		if err != nil {
+			logger.Warnf("error finding the thumbnail formats that ffmpeg can encode, using jpg thumbnails: %s", err.Error())
+			formats = make(map[image.ThumbnailFormat]bool)
+		}
+		c.formats = formats
+	}
+
+	return c.formats[format]
+}
+
+// Get returns the path and format of the thumbnail of the image with the
+// size, generating it if it is not cached. The first of the formats that
+// ffmpeg can encode is used, falling back to JPEG. Returns an empty path if
+// the image is not larger than the size, in which case the original image
+// should be served.
+func (c *ImageThumbnailCache) Get(ctx context.Context, i *models.Image, size int, formats []image.ThumbnailFormat) (string, image.ThumbnailFormat, error) {
+	if i.Width.Valid && i.Height.Valid && i.Width.Int64 <= int64(size) && i.Height.Int64 <= int64(size) {
+		return "", "", nil
+	}
+
+	format := image.ThumbnailFormatJPEG
+	for _, f := range formats {
+		if c.supported(f) {
+			format = f
+			break
+		}
+	}
+
+	path, err := c.get(ctx, i, size, format)
+	if err != nil {
+		return "", "", err
+	}
+
+	if path == "" {
+		format = ""
+	}
+
+	return path, format, nil
+}
+
+func (c *ImageThumbnailCache) get(ctx context.Context, i *models.Image, size int, format image.ThumbnailFormat) (string, error) {
+	path := c.path(i.Checksum, size, format)
+	if exists, _ := utils.FileExists(path); exists {
+		return path, nil
+	}
+
+	c.mutex.Lock()
+	p := c.pending[path]
+	if p == nil {
+		p = &pendingThumbnail{
+			done: make(chan struct{}),
+		}
+		c.pending[path] = p
+		go c.run(path, p, i, size, format)
+	}
+	c.mutex.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-p.done:
+	}
+
+	if errors.Is(p.err, errThumbnailNotNeeded) {
+		return "", nil
+	}
+
+	if p.err != nil {
+		return "", p.err
+	}
+
+	return path, nil
+}
+
+// run generates the thumbnail once a generation slot is free. The
+// generation is not cancelled if the requests are, so that the thumbnail is
+// cached for the next request.
+func (c *ImageThumbnailCache) run(path string, p *pendingThumbnail, i *models.Image, size int, format image.ThumbnailFormat) {
+	c.limit <- struct{}{}
+	p.err = c.generate(i, size, format, path)
+	<-c.limit
+
+	c.mutex.Lock()
+	delete(c.pending, path)
+	c.mutex.Unlock()
+
+	close(p.done)
+}
+
+// Prune removes the thumbnails in dir of images whose checksum is not in
+// checksums, and the temporary files of thumbnails that are not being
+// generated. Returns the number of files removed, or that would be removed
+// if dryRun is true.
+func (c *ImageThumbnailCache) Prune(ctx context.Context, dir string, checksums map[string]bool, dryRun bool) (int, error) {
+	removed := 0
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		name := info.Name()
+		orphaned := false
+		if underscore := strings.Index(name, "_"); underscore == -1 {
+			orphaned = true
+		} else {
+			orphaned = !checksums[name[:underscore]]
+		}
+
+		if !orphaned && strings.HasSuffix(name, thumbnailTmpExt) {
+			c.mutex.Lock()
+			orphaned = c.pending[strings.TrimSuffix(path, thumbnailTmpExt)] == nil
+			c.mutex.Unlock()
+		}
+
+		if !orphaned {
+			return nil
+		}
+
+		removed++
+		if dryRun {
+			logger.Infof("Would remove orphaned thumbnail %s", path)
+			return nil
+		}
+
+		logger.Debugf("Removing orphaned thumbnail %s", path)
+		if err := os.Remove(path); err != nil {
+			logger.Warnf("Could not delete file %s: %s", path, err.Error())
+		}
+
+		return nil
+	})
+
+	if os.IsNotExist(err) {
+		err = nil
+	}
+
+	return removed, err
+}
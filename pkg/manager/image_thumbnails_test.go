@@ -0,0 +1,235 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// testThumbnailGenerator writes empty thumbnails, failing for the
+// unsupported formats, and records the number of concurrent generations.
+type testThumbnailGenerator struct {
+	mutex       sync.Mutex
+	calls       int
+	running     int
+	maxRunning  int
+	unsupported map[image.ThumbnailFormat]bool
+	delay       time.Duration
+}
+
+func (g *testThumbnailGenerator) generate(i *models.Image, size int, format image.ThumbnailFormat, path string) error {
+	g.mutex.Lock()
+	g.calls++
+	g.running++
+	if g.running > g.maxRunning {
+		g.maxRunning = g.running
+	}
+	g.mutex.Unlock()
+
+	defer func() {
+		g.mutex.Lock()
+		g.running--
+		g.mutex.Unlock()
+	}()
+
+	time.Sleep(g.delay)
+
+	if g.unsupported[format] {
+		return errors.New("unsupported format")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, nil, 0644)
+}
+
+func testProbeFormats(formats ...image.ThumbnailFormat) probeThumbnailFormatsFunc {
+	return func() (map[image.ThumbnailFormat]bool, error) {
+		ret := make(map[image.ThumbnailFormat]bool)
+		for _, f := range formats {
+			ret[f] = true
+		}
+		return ret, nil
+	}
+}
+
+func testThumbnailPath(dir string) thumbnailPathFunc {
+	return func(checksum string, size int, format image.ThumbnailFormat) string {
+		return filepath.Join(dir, fmt.Sprintf("%s_%d.%s", checksum, size, format))
+	}
+}
+
+func TestImageThumbnailCacheGet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thumbnails")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := &testThumbnailGenerator{
+		delay: 20 * time.Millisecond,
+	}
+	c := newImageThumbnailCache(2, testThumbnailPath(dir), g.generate, testProbeFormats(image.ThumbnailFormatWebP))
+
+	const count = 10
+	var images []*models.Image
+	for i := 0; i < count; i++ {
+		images = append(images, &models.Image{
+			Checksum: fmt.Sprintf("checksum%d", i),
+		})
+	}
+
+	// each image is requested twice at the same time
+	var wg sync.WaitGroup
+	for _, img := range append(images, images...) {
+		wg.Add(1)
+		go func(img *models.Image) {
+			defer wg.Done()
+
+			path, format, err := c.Get(context.Background(), img, 320, []image.ThumbnailFormat{image.ThumbnailFormatWebP})
+			assert.Nil(t, err)
+			assert.Equal(t, image.ThumbnailFormatWebP, format)
+			assert.Equal(t, filepath.Join(dir, img.Checksum+"_320.webp"), path)
+		}(img)
+	}
+	wg.Wait()
+
+	assert.Equal(t, count, g.calls)
+	assert.LessOrEqual(t, g.maxRunning, 2)
+
+	// cached thumbnails are not generated again
+	_, _, err = c.Get(context.Background(), images[0], 320, []image.ThumbnailFormat{image.ThumbnailFormatWebP})
+	assert.Nil(t, err)
+	assert.Equal(t, count, g.calls)
+
+	// small images are served as is
+	small := &models.Image{
+		Checksum: "small",
+		Width:    sql.NullInt64{Int64: 300, Valid: true},
+		Height:   sql.NullInt64{Int64: 200, Valid: true},
+	}
+	path, _, err := c.Get(context.Background(), small, 320, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", path)
+	assert.Equal(t, count, g.calls)
+}
+
+func TestImageThumbnailCacheUnsupportedFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thumbnails")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := &testThumbnailGenerator{}
+	probes := 0
+	probe := func() (map[image.ThumbnailFormat]bool, error) {
+		probes++
+		return testProbeFormats(image.ThumbnailFormatWebP)()
+	}
+	c := newImageThumbnailCache(1, testThumbnailPath(dir), g.generate, probe)
+	formats := []image.ThumbnailFormat{image.ThumbnailFormatAVIF, image.ThumbnailFormatWebP, image.ThumbnailFormatJPEG}
+
+	// formats that ffmpeg cannot encode are skipped
+	img := &models.Image{Checksum: "a"}
+	path, format, err := c.Get(context.Background(), img, 160, formats)
+	assert.Nil(t, err)
+	assert.Equal(t, image.ThumbnailFormatWebP, format)
+	assert.Equal(t, filepath.Join(dir, "a_160.webp"), path)
+	assert.Equal(t, 1, g.calls)
+
+	// errors encoding an image are returned, without affecting other images
+	g.unsupported = map[image.ThumbnailFormat]bool{
+		image.ThumbnailFormatWebP: true,
+	}
+	img = &models.Image{Checksum: "b"}
+	_, _, err = c.Get(context.Background(), img, 160, formats)
+	assert.NotNil(t, err)
+
+	g.unsupported = nil
+	img = &models.Image{Checksum: "c"}
+	_, format, err = c.Get(context.Background(), img, 160, formats)
+	assert.Nil(t, err)
+	assert.Equal(t, image.ThumbnailFormatWebP, format)
+
+	// ffmpeg is only probed once
+	assert.Equal(t, 1, probes)
+
+	// jpg thumbnails are used if ffmpeg cannot be probed
+	c = newImageThumbnailCache(1, testThumbnailPath(dir), g.generate, func() (map[image.ThumbnailFormat]bool, error) {
+		return nil, errors.New("probe failed")
+	})
+	_, format, err = c.Get(context.Background(), img, 160, formats)
+	assert.Nil(t, err)
+	assert.Equal(t, image.ThumbnailFormatJPEG, format)
+}
+
+func TestImageThumbnailCachePrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thumbnails")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []string{
+		"ab/cd/abcd_640.jpg",
+		"ab/cd/abcd_160.webp",
+		"ab/cd/abcd_640_animated.webp",
+		"ab/cd/abcd_320.avif.tmp",
+		"ef/gh/efgh_640.jpg",
+		"ef/gh/efgh_1280.avif",
+		"ef/gh/invalid.jpg",
+	}
+
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := newImageThumbnailCache(1, testThumbnailPath(dir), nil, nil)
+	checksums := map[string]bool{
+		"abcd": true,
+	}
+
+	removed, err := c.Prune(context.Background(), dir, checksums, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, removed)
+
+	removed, err = c.Prune(context.Background(), dir, checksums, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, removed)
+
+	for i, f := range files {
+		exists := true
+		if _, err := os.Stat(filepath.Join(dir, f)); os.IsNotExist(err) {
+			exists = false
+		}
+
+		// the first three files belong to an image
+		assert.Equal(t, i < 3, exists, f)
+	}
+
+	// missing directories are empty
+	removed, err = c.Prune(context.Background(), filepath.Join(dir, "missing"), checksums, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, removed)
+}
@@ -0,0 +1,172 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const (
+	// checkpointFlushSize is the number of completed items that are buffered
+	// before they are written to the database.
+	checkpointFlushSize = 100
+	// checkpointFlushInterval is the maximum time that completed items are
+	// buffered before they are written to the database.
+	checkpointFlushInterval = 30 * time.Second
+	// checkpointMaxAge is the age after which checkpoint items are discarded.
+	checkpointMaxAge = 7 * 24 * time.Hour
+)
+
+// jobCheckpoint records the items that a job has completed, so that the job
+// can skip these items if it is run again with the same input after being
+// cancelled, failing or being interrupted by a restart. Checkpoints are
+// cleared once the job completes successfully.
+type jobCheckpoint struct {
+	txnManager models.TransactionManager
+	key        string
+
+	done      map[string]bool
+	pending   []string
+	lastFlush time.Time
+	mutex     sync.Mutex
+
+	// tracks items that have been started but are not yet completed
+	inProgress sync.WaitGroup
+}
+
+// newJobCheckpoint returns the checkpoint for the job of the provided type
+// and input. The checkpoint key is derived from the input, so that only jobs
+// run with the same input share checkpoints.
+func newJobCheckpoint(txnManager models.TransactionManager, jobType string, input interface{}) *jobCheckpoint {
+	data, err := json.Marshal(input)
+	if err != nil {
+		logger.Warnf("Error creating checkpoint for %s: %s", jobType, err.Error())
+	}
+
+	return &jobCheckpoint{
+		txnManager: txnManager,
+		key:        jobType + ":" + utils.MD5FromBytes(data),
+		done:       make(map[string]bool),
+		lastFlush:  time.Now(),
+	}
+}
+
+// load loads the completed items from the database, discarding expired
+// items. Returns the number of completed items.
+func (c *jobCheckpoint) load() int {
+	var items []string
+	if err := c.txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		qb := r.JobCheckpoint()
+		if err := qb.DestroyOlderThan(time.Now().Add(-checkpointMaxAge)); err != nil {
+			return err
+		}
+
+		var err error
+		items, err = qb.GetItems(c.key)
+		return err
+	}); err != nil {
+		logger.Warnf("Error loading job checkpoint: %s", err.Error())
+		return 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, item := range items {
+		c.done[item] = true
+	}
+
+	return len(items)
+}
+
+// isDone returns true if the item was completed by a previous run.
+func (c *jobCheckpoint) isDone(item string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.done[item]
+}
+
+// track starts tracking an item that consists of n tasks. The returned
+// function must be called once for each task when it completes. The item is
+// recorded as completed when all of its tasks have completed.
+func (c *jobCheckpoint) track(item string, n int) func() {
+	c.inProgress.Add(1)
+
+	var mutex sync.Mutex
+	remaining := n
+	return func() {
+		mutex.Lock()
+		remaining--
+		finished := remaining == 0
+		mutex.Unlock()
+
+		if finished {
+			c.markDone(item)
+			c.inProgress.Done()
+		}
+	}
+}
+
+func (c *jobCheckpoint) markDone(item string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.done[item] = true
+	c.pending = append(c.pending, item)
+
+	if len(c.pending) >= checkpointFlushSize || time.Since(c.lastFlush) >= checkpointFlushInterval {
+		c.flushLocked()
+	}
+}
+
+// flush writes the completed items to the database.
+func (c *jobCheckpoint) flush() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.flushLocked()
+}
+
+func (c *jobCheckpoint) flushLocked() {
+	c.lastFlush = time.Now()
+	if len(c.pending) == 0 {
+		return
+	}
+
+	if err := c.txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		return r.JobCheckpoint().AddItems(c.key, c.pending)
+	}); err != nil {
+		logger.Warnf("Error writing job checkpoint: %s", err.Error())
+		return
+	}
+
+	c.pending = nil
+}
+
+// finish waits for all tracked items to complete. If the job completed
+// successfully, then the checkpoint is cleared. Otherwise, the completed
+// items are written to the database.
+func (c *jobCheckpoint) finish(success bool) {
+	c.inProgress.Wait()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !success {
+		c.flushLocked()
+		return
+	}
+
+	c.pending = nil
+	if err := c.txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		return r.JobCheckpoint().Destroy(c.key)
+	}); err != nil {
+		logger.Warnf("Error clearing job checkpoint: %s", err.Error())
+	}
+}
@@ -0,0 +1,128 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// jobHistory persists jobs to the database once they have stopped running,
+// along with the log items that they logged while they were running.
+//
+// Jobs in the exclusive group run alone, so all items logged while they run
+// are recorded. Other jobs may run concurrently, so only the items that they
+// log through the capture of their context are recorded.
+type jobHistory struct {
+	txnManager models.TransactionManager
+	config     *config.Instance
+
+	captures map[int]*logger.Capture
+	mutex    sync.Mutex
+}
+
+func newJobHistory(txnManager models.TransactionManager, config *config.Instance) *jobHistory {
+	return &jobHistory{
+		txnManager: txnManager,
+		config:     config,
+		captures:   make(map[int]*logger.Capture),
+	}
+}
+
+func (h *jobHistory) Started(ctx context.Context, j job.Job) context.Context {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var capture *logger.Capture
+	if j.Group == job.GroupExclusive {
+		capture = logger.StartCapture()
+	} else {
+		capture = logger.NewCapture()
+	}
+	h.captures[j.ID] = capture
+	return logger.WithCapture(ctx, capture)
+}
+
+func (h *jobHistory) Finished(j job.Job) {
+	h.mutex.Lock()
+	capture := h.captures[j.ID]
+	delete(h.captures, j.ID)
+	h.mutex.Unlock()
+
+	var items []logger.LogItem
+	if capture != nil {
+		capture.Stop()
+		items = capture.Items()
+	}
+
+	if err := h.write(j, items); err != nil {
+		logger.Errorf("Error writing job history: %s", err.Error())
+	}
+}
+
+func (h *jobHistory) write(j job.Job, items []logger.LogItem) error {
+	newJob := models.JobHistory{
+		Description: j.Description,
+		Status:      string(j.Status),
+		Processed:   j.Processed,
+		Total:       j.Total,
+		AddTime:     models.SQLiteTimestamp{Timestamp: j.AddTime},
+	}
+
+	if j.Error != nil {
+		newJob.Error = sql.NullString{String: *j.Error, Valid: true}
+	}
+	if j.StartTime != nil {
+		newJob.StartTime = models.NullSQLiteTimestamp{Timestamp: *j.StartTime, Valid: true}
+	}
+
+	endTime := time.Now()
+	if j.EndTime != nil {
+		endTime = *j.EndTime
+	}
+	newJob.EndTime = models.SQLiteTimestamp{Timestamp: endTime}
+
+	var logs []*models.JobHistoryLog
+	for _, item := range items {
+		logs = append(logs, &models.JobHistoryLog{
+			Time:    models.SQLiteTimestamp{Timestamp: item.Time},
+			Level:   item.Type,
+			Message: item.Message,
+		})
+	}
+
+	retentionDays := h.config.GetJobHistoryRetentionDays()
+	maxEntries := h.config.GetJobHistoryMaxEntries()
+
+	return h.txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		qb := r.JobHistory()
+		created, err := qb.Create(newJob)
+		if err != nil {
+			return err
+		}
+
+		if err := qb.CreateLogs(created.ID, logs); err != nil {
+			return err
+		}
+
+		// remove jobs that are outside of the retention settings
+		if retentionDays > 0 {
+			if err := qb.DestroyOlderThan(endTime.AddDate(0, 0, -retentionDays)); err != nil {
+				return err
+			}
+		}
+
+		if maxEntries > 0 {
+			if err := qb.DestroyExcess(maxEntries); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
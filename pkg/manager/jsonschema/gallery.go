@@ -8,22 +8,32 @@ import (
 	"github.com/stashapp/stash/pkg/models"
 )
 
+type GalleryChapter struct {
+	Title      string          `json:"title,omitempty"`
+	ImageIndex int             `json:"image_index,omitempty"`
+	CreatedAt  models.JSONTime `json:"created_at,omitempty"`
+	UpdatedAt  models.JSONTime `json:"updated_at,omitempty"`
+}
+
 type Gallery struct {
-	Path        string          `json:"path,omitempty"`
-	Checksum    string          `json:"checksum,omitempty"`
-	Zip         bool            `json:"zip,omitempty"`
-	Title       string          `json:"title,omitempty"`
-	URL         string          `json:"url,omitempty"`
-	Date        string          `json:"date,omitempty"`
-	Details     string          `json:"details,omitempty"`
-	Rating      int             `json:"rating,omitempty"`
-	Organized   bool            `json:"organized,omitempty"`
-	Studio      string          `json:"studio,omitempty"`
-	Performers  []string        `json:"performers,omitempty"`
-	Tags        []string        `json:"tags,omitempty"`
-	FileModTime models.JSONTime `json:"file_mod_time,omitempty"`
-	CreatedAt   models.JSONTime `json:"created_at,omitempty"`
-	UpdatedAt   models.JSONTime `json:"updated_at,omitempty"`
+	Path        string           `json:"path,omitempty"`
+	Checksum    string           `json:"checksum,omitempty"`
+	Zip         bool             `json:"zip,omitempty"`
+	Title       string           `json:"title,omitempty"`
+	URL         string           `json:"url,omitempty"`
+	Date        string           `json:"date,omitempty"`
+	Details     string           `json:"details,omitempty"`
+	Rating      int              `json:"rating,omitempty"`
+	Organized   bool             `json:"organized,omitempty"`
+	Studio      string           `json:"studio,omitempty"`
+	Performers  []string         `json:"performers,omitempty"`
+	Tags        []string         `json:"tags,omitempty"`
+	Images      []string         `json:"images,omitempty"`
+	Cover       string           `json:"cover,omitempty"`
+	Chapters    []GalleryChapter `json:"chapters,omitempty"`
+	FileModTime models.JSONTime  `json:"file_mod_time,omitempty"`
+	CreatedAt   models.JSONTime  `json:"created_at,omitempty"`
+	UpdatedAt   models.JSONTime  `json:"updated_at,omitempty"`
 }
 
 func LoadGalleryFile(filePath string) (*Gallery, error) {
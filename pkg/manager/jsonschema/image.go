@@ -9,25 +9,33 @@ import (
 )
 
 type ImageFile struct {
-	ModTime models.JSONTime `json:"mod_time,omitempty"`
-	Size    int             `json:"size"`
-	Width   int             `json:"width"`
-	Height  int             `json:"height"`
+	ModTime    models.JSONTime `json:"mod_time,omitempty"`
+	Size       int             `json:"size"`
+	Width      int             `json:"width"`
+	Height     int             `json:"height"`
+	Duration   float64         `json:"duration,omitempty"`
+	IsAnimated bool            `json:"is_animated,omitempty"`
+	IsVideo    bool            `json:"is_video,omitempty"`
 }
 
 type Image struct {
 	Title      string          `json:"title,omitempty"`
 	Checksum   string          `json:"checksum,omitempty"`
+	Date       string          `json:"date,omitempty"`
 	Studio     string          `json:"studio,omitempty"`
-	Rating     int             `json:"rating,omitempty"`
 	Organized  bool            `json:"organized,omitempty"`
-	OCounter   int             `json:"o_counter,omitempty"`
 	Galleries  []string        `json:"galleries,omitempty"`
 	Performers []string        `json:"performers,omitempty"`
 	Tags       []string        `json:"tags,omitempty"`
 	File       *ImageFile      `json:"file,omitempty"`
+	UserData   []UserData      `json:"user_data,omitempty"`
 	CreatedAt  models.JSONTime `json:"created_at,omitempty"`
 	UpdatedAt  models.JSONTime `json:"updated_at,omitempty"`
+
+	// Rating and OCounter are only read from files exported before values
+	// were stored per user.
+	Rating   int `json:"rating,omitempty"`
+	OCounter int `json:"o_counter,omitempty"`
 }
 
 func LoadImageFile(filePath string) (*Image, error) {
@@ -11,6 +11,7 @@ import (
 type SceneMarker struct {
 	Title      string          `json:"title,omitempty"`
 	Seconds    string          `json:"seconds,omitempty"`
+	EndSeconds string          `json:"end_seconds,omitempty"`
 	PrimaryTag string          `json:"primary_tag,omitempty"`
 	Tags       []string        `json:"tags,omitempty"`
 	CreatedAt  models.JSONTime `json:"created_at,omitempty"`
@@ -43,10 +44,9 @@ type Scene struct {
 	Studio     string          `json:"studio,omitempty"`
 	URL        string          `json:"url,omitempty"`
 	Date       string          `json:"date,omitempty"`
-	Rating     int             `json:"rating,omitempty"`
 	Organized  bool            `json:"organized,omitempty"`
-	OCounter   int             `json:"o_counter,omitempty"`
 	Details    string          `json:"details,omitempty"`
+	UserData   []UserData      `json:"user_data,omitempty"`
 	Galleries  []string        `json:"galleries,omitempty"`
 	Performers []string        `json:"performers,omitempty"`
 	Movies     []SceneMovie    `json:"movies,omitempty"`
@@ -56,6 +56,11 @@ type Scene struct {
 	Cover      string          `json:"cover,omitempty"`
 	CreatedAt  models.JSONTime `json:"created_at,omitempty"`
 	UpdatedAt  models.JSONTime `json:"updated_at,omitempty"`
+
+	// Rating and OCounter are only read from files exported before values
+	// were stored per user.
+	Rating   int `json:"rating,omitempty"`
+	OCounter int `json:"o_counter,omitempty"`
 }
 
 func LoadSceneFile(filePath string) (*Scene, error) {
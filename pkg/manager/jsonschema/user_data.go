@@ -0,0 +1,10 @@
+package jsonschema
+
+// UserData holds the values of a scene or image for a single user. An empty
+// User refers to the values stored while authentication was not enabled.
+type UserData struct {
+	User     string `json:"user,omitempty"`
+	Rating   int    `json:"rating,omitempty"`
+	OCounter int    `json:"o_counter,omitempty"`
+	Watched  bool   `json:"watched,omitempty"`
+}
@@ -0,0 +1,153 @@
+package manager
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stashapp/stash/pkg/watcher"
+)
+
+// RefreshWatcher stops the library watcher if it is running, and starts a new
+// one over the configured stash paths if the watcher is enabled. Call this
+// when the stash paths or watcher configuration changes.
+func (s *singleton) RefreshWatcher() {
+	s.watcherMutex.Lock()
+	defer s.watcherMutex.Unlock()
+
+	if s.watcher != nil {
+		s.watcher.Stop()
+		s.watcher = nil
+	}
+
+	config := s.Config
+	if !config.GetWatchEnabled() || config.Validate() != nil {
+		return
+	}
+
+	var roots []string
+	for _, sp := range config.GetStashPaths() {
+		roots = append(roots, sp.Path)
+	}
+
+	if len(roots) == 0 {
+		return
+	}
+
+	filter := newScanFilter(config)
+	w := watcher.New(roots, watcher.Options{
+		PollInterval: time.Duration(config.GetWatchPollInterval()) * time.Second,
+		Filter: func(path string, isDir bool) bool {
+			if isDir {
+				stash := getStashFromDirPath(path)
+				return stash != nil && !filter.skipDir(stash, path)
+			}
+
+			stash := getStashFromPath(path)
+			return stash != nil && filter.includeFile(stash, path)
+		},
+	}, s.queueWatchedFiles)
+
+	if err := w.Start(); err != nil {
+		logger.Errorf("Error starting library watcher: %s", err.Error())
+		return
+	}
+
+	logger.Infof("Watching %d stash directories for changes", len(roots))
+	s.watcher = w
+}
+
+func (s *singleton) stopWatcher() {
+	s.watcherMutex.Lock()
+	defer s.watcherMutex.Unlock()
+
+	if s.watcher != nil {
+		s.watcher.Stop()
+		s.watcher = nil
+	}
+}
+
+// queueWatchedFiles queues a scan of the provided paths, followed by
+// generation of the configured generated content for the scanned scenes.
+func (s *singleton) queueWatchedFiles(paths []string) {
+	if err := s.validateFFMPEG(); err != nil {
+		logger.Warnf("Not scanning %d changed files: %s", len(paths), err.Error())
+		return
+	}
+
+	logger.Infof("Queueing scan of %d changed files", len(paths))
+
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		scanJob := ScanJob{
+			txnManager: s.TxnManager,
+			input: models.ScanMetadataInput{
+				Paths: paths,
+			},
+			subscriptions: s.scanSubs,
+		}
+
+		scanJob.Execute(ctx, progress)
+
+		if job.IsCancelled(ctx) {
+			return
+		}
+
+		s.queueWatchGenerate(paths)
+	})
+
+	s.JobManager.AddToGroup(context.Background(), job.GroupScan, "Scanning changed files...", j)
+}
+
+func (s *singleton) queueWatchGenerate(paths []string) {
+	config := s.Config
+	input := models.GenerateMetadataInput{
+		Previews: config.GetWatchGeneratePreviews(),
+		Sprites:  config.GetWatchGenerateSprites(),
+		Phashes:  config.GetWatchGeneratePhashes(),
+	}
+
+	if !input.Previews && !input.Sprites && !input.Phashes {
+		return
+	}
+
+	var sceneIDs []int
+	if err := s.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		qb := r.Scene()
+		for _, path := range paths {
+			if !isVideo(path) {
+				continue
+			}
+
+			f, err := qb.FindFileByPath(path)
+			if err != nil {
+				return err
+			}
+
+			if f != nil {
+				sceneIDs = utils.IntAppendUnique(sceneIDs, f.SceneID)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf("Error finding scanned scenes: %s", err.Error())
+		return
+	}
+
+	// an empty list of scene IDs generates for all scenes
+	if len(sceneIDs) == 0 {
+		return
+	}
+
+	for _, id := range sceneIDs {
+		input.SceneIDs = append(input.SceneIDs, strconv.Itoa(id))
+	}
+
+	if _, err := s.Generate(context.Background(), input); err != nil {
+		logger.Errorf("Error queueing generate for changed files: %s", err.Error())
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/stashapp/stash/pkg/session"
 	"github.com/stashapp/stash/pkg/sqlite"
 	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stashapp/stash/pkg/watcher"
 )
 
 type singleton struct {
@@ -32,6 +33,9 @@ type singleton struct {
 	FFMPEGPath  string
 	FFProbePath string
 
+	transcodeProfile      ffmpeg.TranscodeProfile
+	transcodeProfileMutex sync.RWMutex
+
 	SessionStore *session.Store
 
 	JobManager *job.Manager
@@ -43,9 +47,18 @@ type singleton struct {
 
 	DLNAService *dlna.Service
 
+	HLSSessions *HLSSessionManager
+
+	ImageThumbnails *ImageThumbnailCache
+
+	Scheduler *Scheduler
+
 	TxnManager models.TransactionManager
 
 	scanSubs *subscriptionManager
+
+	watcher      *watcher.Watcher
+	watcherMutex sync.Mutex
 }
 
 var instance *singleton
@@ -82,6 +95,9 @@ func Initialize() *singleton {
 			TXNManager: instance.TxnManager,
 		}
 		instance.DLNAService = dlna.NewService(instance.TxnManager, instance.Config, &sceneServer)
+		instance.HLSSessions = newHLSSessionManager(instance.hlsCacheDir, startHLSTranscode)
+		instance.ImageThumbnails = newDefaultImageThumbnailCache()
+		instance.Scheduler = newScheduler(instance.Config, instance.runScheduledTask)
 
 		if !cfg.IsNewSystem() {
 			logger.Infof("using config file: %s", cfg.GetConfigFile())
@@ -105,7 +121,7 @@ func Initialize() *singleton {
 
 			// create temporary session store - this will be re-initialised
 			// after config is complete
-			instance.SessionStore = session.NewStore(cfg)
+			instance.SessionStore = session.NewStore(cfg, instance.TxnManager)
 
 			logger.Warnf("config file %snot found. Assuming new system...", cfgFile)
 		}
@@ -168,6 +184,7 @@ func initFFMPEG() error {
 
 		instance.FFMPEGPath = ffmpegPath
 		instance.FFProbePath = ffprobePath
+		instance.RefreshTranscodeProfile()
 	}
 
 	return nil
@@ -186,7 +203,7 @@ func (s *singleton) PostInit() error {
 
 	s.Paths = paths.NewPaths(s.Config.GetGeneratedPath())
 	s.RefreshConfig()
-	s.SessionStore = session.NewStore(s.Config)
+	s.SessionStore = session.NewStore(s.Config, s.TxnManager)
 	s.PluginCache.RegisterSessionStore(s.SessionStore)
 
 	if err := s.PluginCache.LoadPlugins(); err != nil {
@@ -203,6 +220,7 @@ func (s *singleton) PostInit() error {
 		utils.Timeout(func() {
 			utils.EmptyDir(instance.Paths.Generated.Downloads)
 			utils.EmptyDir(instance.Paths.Generated.Tmp)
+			utils.RemoveDir(instance.hlsCacheDir())
 		}, deleteTimeout, func(done chan struct{}) {
 			logger.Info("Please wait. Deleting temporary files...") // print
 			<-done                                                  // and wait for deletion
@@ -242,6 +260,11 @@ func (s *singleton) RefreshConfig() {
 		utils.EnsureDir(s.Paths.Generated.Transcodes)
 		utils.EnsureDir(s.Paths.Generated.Downloads)
 	}
+
+	s.JobManager.SetGroupLimit(job.GroupScan, config.GetScanJobLimit())
+	s.JobManager.SetGroupLimit(job.GroupGenerate, config.GetGenerateJobLimit())
+	s.JobManager.SetGroupLimit(job.GroupMetadata, config.GetMetadataJobLimit())
+	s.JobManager.SetGroupLimit(job.GroupScraping, config.GetScrapingJobLimit())
 }
 
 // RefreshScraperCache refreshes the scraper cache. Call this when scraper
@@ -250,6 +273,54 @@ func (s *singleton) RefreshScraperCache() {
 	s.ScraperCache = s.initScraperCache()
 }
 
+// hlsCacheDir returns the directory that HLS segments are written to. This
+// is in the cache directory if it is set, or the generated tmp directory
+// otherwise.
+func (s *singleton) hlsCacheDir() string {
+	if cachePath := s.Config.GetCachePath(); cachePath != "" {
+		return filepath.Join(cachePath, "hls")
+	}
+
+	return filepath.Join(s.Paths.Generated.Tmp, "hls")
+}
+
+// RefreshTranscodeProfile tests the configured transcode profile and selects
+// it, or the software profile if the test fails. Call this when the
+// transcode configuration changes.
+func (s *singleton) RefreshTranscodeProfile() {
+	if s.FFMPEGPath == "" {
+		return
+	}
+
+	config := s.Config
+	profile := ffmpeg.TranscodeProfile{
+		HWAccel:   config.GetTranscodeHardwareAcceleration(),
+		Device:    config.GetTranscodeHardwareDevice(),
+		InputArgs: config.GetTranscodeCustomInputArgs(),
+		VideoArgs: config.GetTranscodeCustomVideoArgs(),
+	}
+
+	encoder := ffmpeg.NewEncoder(s.FFMPEGPath)
+	profile = encoder.SelectTranscodeProfile(profile)
+
+	s.transcodeProfileMutex.Lock()
+	defer s.transcodeProfileMutex.Unlock()
+	s.transcodeProfile = profile
+}
+
+// GetTranscodeProfile returns the transcode profile used when transcoding
+// streams and generating previews.
+func (s *singleton) GetTranscodeProfile() ffmpeg.TranscodeProfile {
+	s.transcodeProfileMutex.RLock()
+	defer s.transcodeProfileMutex.RUnlock()
+
+	if s.transcodeProfile.HWAccel == "" {
+		return ffmpeg.SoftwareTranscodeProfile
+	}
+
+	return s.transcodeProfile
+}
+
 func setSetupDefaults(input *models.SetupInput) {
 	if input.ConfigLocation == "" {
 		input.ConfigLocation = filepath.Join(utils.GetHomeDirectory(), ".stash", "config.yml")
@@ -382,6 +453,9 @@ func (s *singleton) GetSystemStatus() *models.SystemStatus {
 // Shutdown gracefully stops the manager
 func (s *singleton) Shutdown() error {
 	// TODO: Each part of the manager needs to gracefully stop at some point
-	// for now, we just close the database.
+	// for now, we just stop the library watcher and scheduler, and close the
+	// database.
+	s.stopWatcher()
+	s.Scheduler.Stop()
 	return database.Close()
 }
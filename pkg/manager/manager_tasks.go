@@ -10,6 +10,7 @@ import (
 
 	"github.com/remeh/sizedwaitgroup"
 
+	"github.com/stashapp/stash/pkg/database"
 	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/manager/config"
@@ -32,6 +33,18 @@ func isImage(pathname string) bool {
 	return matchExtension(pathname, imgExt)
 }
 
+// isImageClip returns true if the video file is scanned as an image. Video
+// files are scanned as image clips if enabled, and if their stash directory
+// excludes videos but not images.
+func isImageClip(pathname string) bool {
+	if !config.GetInstance().GetCreateImageClipsFromVideos() || !isVideo(pathname) {
+		return false
+	}
+
+	stash := getStashFromPath(pathname)
+	return stash != nil && stash.ExcludeVideo && !stash.ExcludeImage
+}
+
 func getScanPaths(inputPaths []string) []*models.StashConfig {
 	if len(inputPaths) == 0 {
 		return config.GetInstance().GetStashPaths()
@@ -71,7 +84,7 @@ func (s *singleton) Scan(ctx context.Context, input models.ScanMetadataInput) (i
 		subscriptions: s.scanSubs,
 	}
 
-	return s.JobManager.Add(ctx, "Scanning...", &scanJob), nil
+	return s.JobManager.AddToGroup(ctx, job.GroupScan, "Scanning...", &scanJob), nil
 }
 
 func (s *singleton) Import(ctx context.Context) (int, error) {
@@ -96,7 +109,7 @@ func (s *singleton) Import(ctx context.Context) (int, error) {
 		task.Start(&wg)
 	})
 
-	return s.JobManager.Add(ctx, "Importing...", j), nil
+	return s.JobManager.AddToGroup(ctx, job.GroupExclusive, "Importing...", j), nil
 }
 
 func (s *singleton) Export(ctx context.Context) (int, error) {
@@ -117,7 +130,7 @@ func (s *singleton) Export(ctx context.Context) (int, error) {
 		task.Start(&wg)
 	})
 
-	return s.JobManager.Add(ctx, "Exporting...", j), nil
+	return s.JobManager.AddToGroup(ctx, job.GroupExclusive, "Exporting...", j), nil
 }
 
 func (s *singleton) RunSingleTask(ctx context.Context, t Task) int {
@@ -128,7 +141,7 @@ func (s *singleton) RunSingleTask(ctx context.Context, t Task) int {
 		t.Start(&wg)
 	})
 
-	return s.JobManager.Add(ctx, t.GetDescription(), j)
+	return s.JobManager.AddToGroup(ctx, job.GroupExclusive, t.GetDescription(), j)
 }
 
 func setGeneratePreviewOptionsInput(optionsInput *models.GeneratePreviewOptionsInput) {
@@ -175,7 +188,7 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 	}
 
 	// TODO - formalise this
-	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+	j := job.MakePausableJobExec(func(ctx context.Context, progress *job.Progress) {
 		var scenes []*models.Scene
 		var err error
 		var markers []*models.SceneMarker
@@ -201,14 +214,15 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 
 			return nil
 		}); err != nil {
-			logger.Error(err.Error())
+			logger.FromContext(ctx).Error(err.Error())
+			progress.SetError(err)
 			return
 		}
 
 		config := config.GetInstance()
 		parallelTasks := config.GetParallelTasksWithAutoDetection()
 
-		logger.Infof("Generate started with %d parallel tasks", parallelTasks)
+		logger.FromContext(ctx).Infof("Generate started with %d parallel tasks", parallelTasks)
 		wg := sizedwaitgroup.New(parallelTasks)
 
 		lenScenes := len(scenes)
@@ -216,7 +230,7 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 		progress.SetTotal(total)
 
 		if job.IsCancelled(ctx) {
-			logger.Info("Stopping due to user request")
+			logger.FromContext(ctx).Info("Stopping due to user request")
 			return
 		}
 
@@ -228,10 +242,10 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 			totalsNeeded = s.neededGenerate(scenes, input)
 
 			if totalsNeeded == nil {
-				logger.Infof("Taking too long to count content. Skipping...")
-				logger.Infof("Generating content")
+				logger.FromContext(ctx).Infof("Taking too long to count content. Skipping...")
+				logger.FromContext(ctx).Infof("Generating content")
 			} else {
-				logger.Infof("Generating %d sprites %d previews %d image previews %d markers %d transcodes %d phashes", totalsNeeded.sprites, totalsNeeded.previews, totalsNeeded.imagePreviews, totalsNeeded.markers, totalsNeeded.transcodes, totalsNeeded.phashes)
+				logger.FromContext(ctx).Infof("Generating %d sprites %d previews %d image previews %d markers %d transcodes %d phashes", totalsNeeded.sprites, totalsNeeded.previews, totalsNeeded.imagePreviews, totalsNeeded.markers, totalsNeeded.transcodes, totalsNeeded.phashes)
 			}
 		})
 
@@ -248,26 +262,52 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 		}
 		setGeneratePreviewOptionsInput(generatePreviewOptions)
 
+		checkpoint := newJobCheckpoint(s.TxnManager, "generate", input)
+		if n := checkpoint.load(); n > 0 {
+			logger.FromContext(ctx).Infof("Skipping %d items generated by a previous run", n)
+		}
+
+		// number of tasks run for each scene
+		sceneTasks := 0
+		for _, enabled := range []bool{input.Sprites, input.Previews, input.Markers, input.Transcodes, input.Phashes} {
+			if enabled {
+				sceneTasks++
+			}
+		}
+
 		// Start measuring how long the generate has taken. (consider moving this up)
 		start := time.Now()
 		instance.Paths.Generated.EnsureTmpDir()
 
 		for _, scene := range scenes {
 			progress.Increment()
+			if progress.IsPaused() {
+				checkpoint.flush()
+			}
+			progress.WaitIfPaused(ctx)
+
 			if job.IsCancelled(ctx) {
-				logger.Info("Stopping due to user request")
+				logger.FromContext(ctx).Info("Stopping due to user request")
 				wg.Wait()
+				checkpoint.finish(false)
 				instance.Paths.Generated.EmptyTmpDir()
 				return
 			}
 
 			if scene == nil {
-				logger.Errorf("nil scene, skipping generate")
+				logger.FromContext(ctx).Errorf("nil scene, skipping generate")
 				continue
 			}
 
+			item := fmt.Sprintf("scene:%d", scene.ID)
+			if sceneTasks == 0 || checkpoint.isDone(item) {
+				continue
+			}
+			done := checkpoint.track(item, sceneTasks)
+
 			if input.Sprites {
 				task := GenerateSpriteTask{
+					ctx:                 ctx,
 					Scene:               *scene,
 					Overwrite:           overwrite,
 					fileNamingAlgorithm: fileNamingAlgo,
@@ -275,11 +315,13 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 				wg.Add()
 				go progress.ExecuteTask(fmt.Sprintf("Generating sprites for %s", scene.Path), func() {
 					task.Start(&wg)
+					done()
 				})
 			}
 
 			if input.Previews {
 				task := GeneratePreviewTask{
+					ctx:                 ctx,
 					Scene:               *scene,
 					ImagePreview:        input.ImagePreviews,
 					Options:             *generatePreviewOptions,
@@ -289,12 +331,14 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 				wg.Add()
 				go progress.ExecuteTask(fmt.Sprintf("Generating preview for %s", scene.Path), func() {
 					task.Start(&wg)
+					done()
 				})
 			}
 
 			if input.Markers {
 				wg.Add()
 				task := GenerateMarkersTask{
+					ctx:                 ctx,
 					TxnManager:          s.TxnManager,
 					Scene:               scene,
 					Overwrite:           overwrite,
@@ -304,23 +348,27 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 				}
 				go progress.ExecuteTask(fmt.Sprintf("Generating markers for %s", scene.Path), func() {
 					task.Start(&wg)
+					done()
 				})
 			}
 
 			if input.Transcodes {
 				wg.Add()
 				task := GenerateTranscodeTask{
+					ctx:                 ctx,
 					Scene:               *scene,
 					Overwrite:           overwrite,
 					fileNamingAlgorithm: fileNamingAlgo,
 				}
 				go progress.ExecuteTask(fmt.Sprintf("Generating transcode for %s", scene.Path), func() {
 					task.Start(&wg)
+					done()
 				})
 			}
 
 			if input.Phashes {
 				task := GeneratePhashTask{
+					ctx:                 ctx,
 					Scene:               *scene,
 					fileNamingAlgorithm: fileNamingAlgo,
 					txnManager:          s.TxnManager,
@@ -329,6 +377,7 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 				wg.Add()
 				go progress.ExecuteTask(fmt.Sprintf("Generating phash for %s", scene.Path), func() {
 					task.Start(&wg)
+					done()
 				})
 			}
 		}
@@ -337,22 +386,35 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 
 		for _, marker := range markers {
 			progress.Increment()
+			if progress.IsPaused() {
+				checkpoint.flush()
+			}
+			progress.WaitIfPaused(ctx)
+
 			if job.IsCancelled(ctx) {
-				logger.Info("Stopping due to user request")
+				logger.FromContext(ctx).Info("Stopping due to user request")
 				wg.Wait()
+				checkpoint.finish(false)
 				instance.Paths.Generated.EmptyTmpDir()
 				elapsed := time.Since(start)
-				logger.Info(fmt.Sprintf("Generate finished (%s)", elapsed))
+				logger.FromContext(ctx).Info(fmt.Sprintf("Generate finished (%s)", elapsed))
 				return
 			}
 
 			if marker == nil {
-				logger.Errorf("nil marker, skipping generate")
+				logger.FromContext(ctx).Errorf("nil marker, skipping generate")
 				continue
 			}
 
+			item := fmt.Sprintf("marker:%d", marker.ID)
+			if checkpoint.isDone(item) {
+				continue
+			}
+			done := checkpoint.track(item, 1)
+
 			wg.Add()
 			task := GenerateMarkersTask{
+				ctx:                 ctx,
 				TxnManager:          s.TxnManager,
 				Marker:              marker,
 				Overwrite:           overwrite,
@@ -360,17 +422,19 @@ func (s *singleton) Generate(ctx context.Context, input models.GenerateMetadataI
 			}
 			go progress.ExecuteTask(fmt.Sprintf("Generating marker preview for marker ID %d", marker.ID), func() {
 				task.Start(&wg)
+				done()
 			})
 		}
 
 		wg.Wait()
+		checkpoint.finish(true)
 
 		instance.Paths.Generated.EmptyTmpDir()
 		elapsed := time.Since(start)
-		logger.Info(fmt.Sprintf("Generate finished (%s)", elapsed))
+		logger.FromContext(ctx).Info(fmt.Sprintf("Generate finished (%s)", elapsed))
 	})
 
-	return s.JobManager.Add(ctx, "Generating...", j), nil
+	return s.JobManager.AddToGroup(ctx, job.GroupGenerate, "Generating...", j), nil
 }
 
 func (s *singleton) GenerateDefaultScreenshot(ctx context.Context, sceneId string) int {
@@ -388,7 +452,8 @@ func (s *singleton) generateScreenshot(ctx context.Context, sceneId string, at *
 	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
 		sceneIdInt, err := strconv.Atoi(sceneId)
 		if err != nil {
-			logger.Errorf("Error parsing scene id %s: %s", sceneId, err.Error())
+			logger.FromContext(ctx).Errorf("Error parsing scene id %s: %s", sceneId, err.Error())
+			progress.SetError(err)
 			return
 		}
 
@@ -396,13 +461,18 @@ func (s *singleton) generateScreenshot(ctx context.Context, sceneId string, at *
 		if err := s.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
 			var err error
 			scene, err = r.Scene().Find(sceneIdInt)
+			if err == nil && scene == nil {
+				err = fmt.Errorf("scene with id %s not found", sceneId)
+			}
 			return err
-		}); err != nil || scene == nil {
-			logger.Errorf("failed to get scene for generate: %s", err.Error())
+		}); err != nil {
+			logger.FromContext(ctx).Errorf("failed to get scene for generate: %s", err.Error())
+			progress.SetError(err)
 			return
 		}
 
 		task := GenerateScreenshotTask{
+			ctx:                 ctx,
 			txnManager:          s.TxnManager,
 			Scene:               *scene,
 			ScreenshotAt:        at,
@@ -413,10 +483,10 @@ func (s *singleton) generateScreenshot(ctx context.Context, sceneId string, at *
 		wg.Add(1)
 		task.Start(&wg)
 
-		logger.Infof("Generate screenshot finished")
+		logger.FromContext(ctx).Infof("Generate screenshot finished")
 	})
 
-	return s.JobManager.Add(ctx, fmt.Sprintf("Generating screenshot for scene id %s", sceneId), j)
+	return s.JobManager.AddToGroup(ctx, job.GroupGenerate, fmt.Sprintf("Generating screenshot for scene id %s", sceneId), j)
 }
 
 func (s *singleton) AutoTag(ctx context.Context, input models.AutoTagMetadataInput) int {
@@ -439,9 +509,9 @@ func (s *singleton) Clean(ctx context.Context, input models.CleanMetadataInput)
 			iqb := r.Image()
 			gqb := r.Gallery()
 
-			logger.Infof("Starting cleaning of tracked files")
+			logger.FromContext(ctx).Infof("Starting cleaning of tracked files")
 			if input.DryRun {
-				logger.Infof("Running in Dry Mode")
+				logger.FromContext(ctx).Infof("Running in Dry Mode")
 			}
 			var err error
 
@@ -463,12 +533,13 @@ func (s *singleton) Clean(ctx context.Context, input models.CleanMetadataInput)
 
 			return nil
 		}); err != nil {
-			logger.Error(err.Error())
+			logger.FromContext(ctx).Error(err.Error())
+			progress.SetError(err)
 			return
 		}
 
 		if job.IsCancelled(ctx) {
-			logger.Info("Stopping due to user request")
+			logger.FromContext(ctx).Info("Stopping due to user request")
 			return
 		}
 
@@ -479,12 +550,12 @@ func (s *singleton) Clean(ctx context.Context, input models.CleanMetadataInput)
 		for _, scene := range scenes {
 			progress.Increment()
 			if job.IsCancelled(ctx) {
-				logger.Info("Stopping due to user request")
+				logger.FromContext(ctx).Info("Stopping due to user request")
 				return
 			}
 
 			if scene == nil {
-				logger.Errorf("nil scene, skipping Clean")
+				logger.FromContext(ctx).Errorf("nil scene, skipping Clean")
 				continue
 			}
 
@@ -506,12 +577,12 @@ func (s *singleton) Clean(ctx context.Context, input models.CleanMetadataInput)
 		for _, img := range images {
 			progress.Increment()
 			if job.IsCancelled(ctx) {
-				logger.Info("Stopping due to user request")
+				logger.FromContext(ctx).Info("Stopping due to user request")
 				return
 			}
 
 			if img == nil {
-				logger.Errorf("nil image, skipping Clean")
+				logger.FromContext(ctx).Errorf("nil image, skipping Clean")
 				continue
 			}
 
@@ -531,12 +602,12 @@ func (s *singleton) Clean(ctx context.Context, input models.CleanMetadataInput)
 		for _, gallery := range galleries {
 			progress.Increment()
 			if job.IsCancelled(ctx) {
-				logger.Info("Stopping due to user request")
+				logger.FromContext(ctx).Info("Stopping due to user request")
 				return
 			}
 
 			if gallery == nil {
-				logger.Errorf("nil gallery, skipping Clean")
+				logger.FromContext(ctx).Errorf("nil gallery, skipping Clean")
 				continue
 			}
 
@@ -553,18 +624,43 @@ func (s *singleton) Clean(ctx context.Context, input models.CleanMetadataInput)
 			wg.Wait()
 		}
 
-		logger.Info("Finished Cleaning")
+		if job.IsCancelled(ctx) {
+			logger.FromContext(ctx).Info("Stopping due to user request")
+			return
+		}
+
+		progress.ExecuteTask("Pruning orphaned thumbnails", func() {
+			s.pruneImageThumbnails(ctx, images, input.DryRun)
+		})
+
+		logger.FromContext(ctx).Info("Finished Cleaning")
 
 		s.scanSubs.notify()
 	})
 
-	return s.JobManager.Add(ctx, "Cleaning...", j)
+	return s.JobManager.AddToGroup(ctx, job.GroupScan, "Cleaning...", j)
+}
+
+// Backup queues a job to backup the database to the default backup path.
+func (s *singleton) Backup(ctx context.Context) int {
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		backupPath := database.DatabaseBackupPath()
+		if err := database.Backup(database.DB, backupPath); err != nil {
+			logger.FromContext(ctx).Errorf("Error backing up database: %s", err.Error())
+			progress.SetError(err)
+			return
+		}
+
+		logger.FromContext(ctx).Infof("Successfully backed up database to: %s", backupPath)
+	})
+
+	return s.JobManager.AddToGroup(ctx, job.GroupExclusive, "Backing up database...", j)
 }
 
 func (s *singleton) MigrateHash(ctx context.Context) int {
 	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
 		fileNamingAlgo := config.GetInstance().GetVideoFileNamingAlgorithm()
-		logger.Infof("Migrating generated files for %s naming hash", fileNamingAlgo.String())
+		logger.FromContext(ctx).Infof("Migrating generated files for %s naming hash", fileNamingAlgo.String())
 
 		var scenes []*models.Scene
 		if err := s.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
@@ -572,7 +668,8 @@ func (s *singleton) MigrateHash(ctx context.Context) int {
 			scenes, err = r.Scene().All()
 			return err
 		}); err != nil {
-			logger.Errorf("failed to fetch list of scenes for migration: %s", err.Error())
+			logger.FromContext(ctx).Errorf("failed to fetch list of scenes for migration: %s", err.Error())
+			progress.SetError(err)
 			return
 		}
 
@@ -583,12 +680,12 @@ func (s *singleton) MigrateHash(ctx context.Context) int {
 		for _, scene := range scenes {
 			progress.Increment()
 			if job.IsCancelled(ctx) {
-				logger.Info("Stopping due to user request")
+				logger.FromContext(ctx).Info("Stopping due to user request")
 				return
 			}
 
 			if scene == nil {
-				logger.Errorf("nil scene, skipping migrate")
+				logger.FromContext(ctx).Errorf("nil scene, skipping migrate")
 				continue
 			}
 
@@ -599,10 +696,10 @@ func (s *singleton) MigrateHash(ctx context.Context) int {
 			wg.Wait()
 		}
 
-		logger.Info("Finished migrating")
+		logger.FromContext(ctx).Info("Finished migrating")
 	})
 
-	return s.JobManager.Add(ctx, "Migrating scene hashes...", j)
+	return s.JobManager.AddToGroup(ctx, job.GroupExclusive, "Migrating scene hashes...", j)
 }
 
 type totalsGenerate struct {
@@ -710,11 +807,13 @@ func (s *singleton) neededGenerate(scenes []*models.Scene, input models.Generate
 
 func (s *singleton) StashBoxBatchPerformerTag(ctx context.Context, input models.StashBoxBatchPerformerTagInput) int {
 	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
-		logger.Infof("Initiating stash-box batch performer tag")
+		logger.FromContext(ctx).Infof("Initiating stash-box batch performer tag")
 
 		boxes := config.GetInstance().GetStashBoxes()
 		if input.Endpoint < 0 || input.Endpoint >= len(boxes) {
-			logger.Error(fmt.Errorf("invalid stash_box_index %d", input.Endpoint))
+			err := fmt.Errorf("invalid stash_box_index %d", input.Endpoint)
+			logger.FromContext(ctx).Error(err)
+			progress.SetError(err)
 			return
 		}
 		box := boxes[input.Endpoint]
@@ -743,7 +842,7 @@ func (s *singleton) StashBoxBatchPerformerTag(ctx context.Context, input models.
 				}
 				return nil
 			}); err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(ctx).Error(err.Error())
 			}
 		} else if len(input.PerformerNames) > 0 {
 			for i := range input.PerformerNames {
@@ -782,7 +881,8 @@ func (s *singleton) StashBoxBatchPerformerTag(ctx context.Context, input models.
 				}
 				return nil
 			}); err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(ctx).Error(err.Error())
+				progress.SetError(err)
 				return
 			}
 		}
@@ -793,7 +893,7 @@ func (s *singleton) StashBoxBatchPerformerTag(ctx context.Context, input models.
 
 		progress.SetTotal(len(tasks))
 
-		logger.Infof("Starting stash-box batch operation for %d performers", len(tasks))
+		logger.FromContext(ctx).Infof("Starting stash-box batch operation for %d performers", len(tasks))
 
 		var wg sync.WaitGroup
 		for _, task := range tasks {
@@ -806,5 +906,26 @@ func (s *singleton) StashBoxBatchPerformerTag(ctx context.Context, input models.
 		}
 	})
 
-	return s.JobManager.Add(ctx, "Batch stash-box performer tag...", j)
+	return s.JobManager.AddToGroup(ctx, job.GroupScraping, "Batch stash-box performer tag...", j)
+}
+
+// pruneImageThumbnails removes the generated thumbnails that do not belong
+// to any of the images.
+func (s *singleton) pruneImageThumbnails(ctx context.Context, images []*models.Image, dryRun bool) {
+	checksums := make(map[string]bool)
+	for _, img := range images {
+		if img != nil {
+			checksums[img.Checksum] = true
+		}
+	}
+
+	removed, err := s.ImageThumbnails.Prune(ctx, s.Paths.Generated.Thumbnails, checksums, dryRun)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("error pruning thumbnails: %s", err.Error())
+		return
+	}
+
+	if removed > 0 {
+		logger.FromContext(ctx).Infof("Pruned %d orphaned thumbnails", removed)
+	}
 }
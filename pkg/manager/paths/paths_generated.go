@@ -61,7 +61,26 @@ func (gp *generatedPaths) TempDir(pattern string) (string, error) {
 	return ret, nil
 }
 
+// GetThumbnailDir returns the directory of the thumbnails of the checksum.
+// Thumbnail filenames start with the checksum followed by an underscore.
+func (gp *generatedPaths) GetThumbnailDir(checksum string) string {
+	return filepath.Join(gp.Thumbnails, utils.GetIntraDir(checksum, thumbDirDepth, thumbDirLength))
+}
+
 func (gp *generatedPaths) GetThumbnailPath(checksum string, width int) string {
-	fname := fmt.Sprintf("%s_%d.jpg", checksum, width)
-	return filepath.Join(gp.Thumbnails, utils.GetIntraDir(checksum, thumbDirDepth, thumbDirLength), fname)
+	return gp.GetThumbnailFormatPath(checksum, width, "jpg")
+}
+
+// GetThumbnailFormatPath returns the path of the thumbnail with the file
+// extension of the format.
+func (gp *generatedPaths) GetThumbnailFormatPath(checksum string, width int, ext string) string {
+	fname := fmt.Sprintf("%s_%d.%s", checksum, width, ext)
+	return filepath.Join(gp.GetThumbnailDir(checksum), fname)
+}
+
+// GetAnimatedThumbnailPath returns the path of the animated thumbnail of
+// animated images and video clips.
+func (gp *generatedPaths) GetAnimatedThumbnailPath(checksum string, width int) string {
+	fname := fmt.Sprintf("%s_%d_animated.webp", checksum, width)
+	return filepath.Join(gp.GetThumbnailDir(checksum), fname)
 }
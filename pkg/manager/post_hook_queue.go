@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/plugin"
+)
+
+// postHookQueueSize is the number of post hooks that may be waiting for
+// execution before adding a hook blocks.
+const postHookQueueSize = 100
+
+type postHookExecutor interface {
+	ExecutePostHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, inputFields []string)
+}
+
+type queuedPostHook struct {
+	id       int
+	hookType plugin.HookTriggerEnum
+	input    interface{}
+}
+
+// postHookQueue executes post hooks sequentially in a single background
+// goroutine. It is used by long-running jobs such as the scan, so that
+// hooks for many objects do not start many plugin processes at once, and
+// so that hook execution does not block the job.
+type postHookQueue struct {
+	ctx      context.Context
+	executor postHookExecutor
+	hooks    chan queuedPostHook
+	done     chan struct{}
+}
+
+// newPostHookQueue creates a postHookQueue and starts executing the hooks
+// added to it. The queue must be closed when the job has finished.
+func newPostHookQueue(ctx context.Context, executor postHookExecutor) *postHookQueue {
+	ret := &postHookQueue{
+		ctx:      ctx,
+		executor: executor,
+		hooks:    make(chan queuedPostHook, postHookQueueSize),
+		done:     make(chan struct{}),
+	}
+
+	go ret.run()
+
+	return ret
+}
+
+func (q *postHookQueue) run() {
+	defer close(q.done)
+
+	for h := range q.hooks {
+		// drain the queue without executing if the job was cancelled
+		if job.IsCancelled(q.ctx) {
+			continue
+		}
+
+		q.executor.ExecutePostHooks(q.ctx, h.id, h.hookType, h.input, nil)
+	}
+}
+
+// add queues the post hooks of hookType for the object with the provided id.
+// Blocks if the queue is full.
+func (q *postHookQueue) add(id int, hookType plugin.HookTriggerEnum, input interface{}) {
+	q.hooks <- queuedPostHook{
+		id:       id,
+		hookType: hookType,
+		input:    input,
+	}
+}
+
+// close stops accepting new hooks and waits for the queued hooks to finish
+// executing.
+func (q *postHookQueue) close() {
+	close(q.hooks)
+	<-q.done
+}
@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingHookExecutor struct {
+	mutex     sync.Mutex
+	running   int
+	overlap   bool
+	cancel    context.CancelFunc
+	cancelAt  int
+	triggered []int
+}
+
+func (e *recordingHookExecutor) ExecutePostHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, inputFields []string) {
+	e.mutex.Lock()
+	e.running++
+	e.triggered = append(e.triggered, id)
+	if e.cancel != nil && id == e.cancelAt {
+		e.cancel()
+	}
+	e.mutex.Unlock()
+
+	// give a concurrently executed hook the chance to start
+	time.Sleep(time.Millisecond)
+
+	e.mutex.Lock()
+	if e.running > 1 {
+		e.overlap = true
+	}
+	e.running--
+	e.mutex.Unlock()
+}
+
+func TestPostHookQueue(t *testing.T) {
+	const total = postHookQueueSize * 2
+
+	executor := &recordingHookExecutor{}
+	q := newPostHookQueue(context.Background(), executor)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for id := offset; id < total; id += 4 {
+				q.add(id, plugin.SceneCreatePost, nil)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	q.close()
+
+	assert.False(t, executor.overlap, "hooks executed concurrently")
+	assert.Len(t, executor.triggered, total)
+}
+
+func TestPostHookQueueCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	const cancelAt = 2
+	executor := &recordingHookExecutor{
+		cancel:   cancel,
+		cancelAt: cancelAt,
+	}
+	q := newPostHookQueue(ctx, executor)
+
+	for id := 0; id < 10; id++ {
+		q.add(id, plugin.SceneCreatePost, nil)
+	}
+
+	q.close()
+
+	assert.Equal(t, []int{0, 1, 2}, executor.triggered)
+}
@@ -3,4 +3,8 @@ package manager
 // PostMigrate is executed after migrations have been executed.
 func (s *singleton) PostMigrate() {
 	setInitialMD5Config(s.TxnManager)
+	migrateConfigCredentials(s.TxnManager, s.Config)
+	s.JobManager.SetHistory(newJobHistory(s.TxnManager, s.Config))
+	s.RefreshWatcher()
+	s.Scheduler.Start()
 }
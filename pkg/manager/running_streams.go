@@ -47,6 +47,10 @@ func WaitAndDeregisterStream(filepath string, w *http.ResponseWriter, r *http.Re
 func KillRunningStreams(path string) {
 	ffmpeg.KillRunningEncoders(path)
 
+	if instance != nil {
+		instance.HLSSessions.Stop(path)
+	}
+
 	streamingFilesMutex.RLock()
 	streams := streamingFiles[path]
 	streamingFilesMutex.RUnlock()
@@ -82,6 +86,40 @@ func (s *SceneServer) StreamSceneDirect(scene *models.Scene, w http.ResponseWrit
 	WaitAndDeregisterStream(filepath, &w, r)
 }
 
+// ServeCaption serves the caption of the scene with the language and type
+// of the lang and type query parameters as WebVTT. Parameters that are not
+// set match any caption.
+func (s *SceneServer) ServeCaption(scene *models.Scene, w http.ResponseWriter, r *http.Request) {
+	lang := r.URL.Query().Get("lang")
+	captionType := r.URL.Query().Get("type")
+
+	var captions []*models.SceneCaption
+	if err := s.TXNManager.WithReadTxn(r.Context(), func(repo models.ReaderRepository) error {
+		var err error
+		captions, err = repo.Scene().GetCaptions(scene.ID)
+		return err
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	caption := models.SceneCaptions(captions).Find(lang, captionType)
+	if caption == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	vtt, err := GetCaptionVTT(scene.Path, caption)
+	if err != nil {
+		logger.Errorf("[stream] error serving caption: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ffmpeg.MimeVTT)
+	_, _ = w.Write([]byte(vtt))
+}
+
 func (s *SceneServer) ServeScreenshot(scene *models.Scene, w http.ResponseWriter, r *http.Request) {
 	filepath := GetInstance().Paths.Scene.GetScreenshotPath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
 
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -60,6 +61,9 @@ func DestroySceneMarker(scene *models.Scene, sceneMarker *models.SceneMarker, qb
 
 // DeleteGeneratedSceneFiles deletes generated files for the provided scene.
 func DeleteGeneratedSceneFiles(scene *models.Scene, fileNamingAlgo models.HashAlgorithm) {
+	// stop any HLS sessions and remove their segments
+	GetInstance().HLSSessions.Stop(scene.Path)
+
 	sceneHash := scene.GetHash(fileNamingAlgo)
 
 	if sceneHash == "" {
@@ -175,14 +179,17 @@ func DeleteSceneMarkerFiles(scene *models.Scene, seconds int, fileNamingAlgo mod
 	}
 }
 
-// DeleteSceneFile deletes the scene video file from the filesystem.
-func DeleteSceneFile(scene *models.Scene) {
-	// kill any running encoders
-	KillRunningStreams(scene.Path)
+// DeleteSceneFiles deletes the provided scene video files from the
+// filesystem.
+func DeleteSceneFiles(files []*models.SceneFile) {
+	for _, f := range files {
+		// kill any running encoders
+		KillRunningStreams(f.Path)
 
-	err := os.Remove(scene.Path)
-	if err != nil {
-		logger.Warnf("Could not delete file %s: %s", scene.Path, err.Error())
+		err := os.Remove(f.Path)
+		if err != nil {
+			logger.Warnf("Could not delete file %s: %s", f.Path, err.Error())
+		}
 	}
 }
 
@@ -276,6 +283,15 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL string, maxStreami
 		})
 	}
 
+	// the adaptive stream lists renditions of several resolutions, so that
+	// the player can choose one that suits the connection
+	labelAdaptiveHLS := "HLS (adaptive)"
+	ret = append(ret, &models.SceneStreamEndpoint{
+		URL:      directStreamURL + "/master.m3u8",
+		MimeType: &mimeHLS,
+		Label:    &labelAdaptiveHLS,
+	})
+
 	hls := models.SceneStreamEndpoint{
 		URL:      directStreamURL + ".m3u8",
 		MimeType: &mimeHLS,
@@ -362,3 +378,41 @@ func HasTranscode(scene *models.Scene, fileNamingAlgo models.HashAlgorithm) bool
 	ret, _ := utils.FileExists(transcodePath)
 	return ret
 }
+
+// GetCaptionVTT returns the caption of the video file at videoPath as
+// WebVTT. Subtitle streams are converted by ffmpeg.
+func GetCaptionVTT(videoPath string, caption *models.SceneCaption) (string, error) {
+	if caption.IsEmbedded() {
+		encoder := ffmpeg.NewEncoder(instance.FFMPEGPath)
+		return encoder.ExtractCaption(videoPath, int(caption.StreamIndex.Int64))
+	}
+
+	data, err := ioutil.ReadFile(caption.Path.String)
+	if err != nil {
+		return "", err
+	}
+
+	switch caption.CaptionType {
+	case models.CaptionTypeSRT:
+		return utils.ConvertSRTToVTT(string(data)), nil
+	case models.CaptionTypeASS:
+		return utils.ConvertASSToVTT(string(data)), nil
+	default:
+		return utils.ConvertVTT(string(data)), nil
+	}
+}
+
+// GetTranscodeCaptions returns the captions to add to a transcode of the
+// video file.
+func GetTranscodeCaptions(captions []*models.SceneCaption) []ffmpeg.Caption {
+	var ret []ffmpeg.Caption
+	for _, c := range captions {
+		ret = append(ret, ffmpeg.Caption{
+			Path:         c.Path.String,
+			StreamIndex:  int(c.StreamIndex.Int64),
+			LanguageCode: c.LanguageCode,
+		})
+	}
+
+	return ret
+}
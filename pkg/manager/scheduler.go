@@ -0,0 +1,343 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ErrScheduleNotFound is returned when a schedule with the provided name does
+// not exist.
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+type scheduledTaskRunner func(ctx context.Context, schedule *config.Schedule) (int, error)
+
+// Scheduler runs the scheduled tasks stored in the configuration. Tasks are
+// added to the job queue when they are due to run.
+type Scheduler struct {
+	config  *config.Instance
+	runTask scheduledTaskRunner
+
+	lastRun map[string]time.Time
+	mutex   sync.Mutex
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newScheduler(config *config.Instance, runTask scheduledTaskRunner) *Scheduler {
+	return &Scheduler{
+		config:  config,
+		runTask: runTask,
+		lastRun: make(map[string]time.Time),
+	}
+}
+
+// Start starts running scheduled tasks. Has no effect if the scheduler is
+// already running.
+func (s *Scheduler) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go s.loop(s.stop)
+}
+
+// Stop stops running scheduled tasks. Tasks that have already been queued
+// are not affected.
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	if s.stop == nil {
+		s.mutex.Unlock()
+		return
+	}
+
+	close(s.stop)
+	s.stop = nil
+	s.mutex.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(stop chan struct{}) {
+	defer s.wg.Done()
+
+	for {
+		// wake up at the start of each minute
+		now := time.Now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		timer := time.NewTimer(next.Sub(now))
+
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.runDue(next)
+		}
+	}
+}
+
+// runDue runs the schedules that are due to run in the minute of t.
+func (s *Scheduler) runDue(t time.Time) {
+	for _, schedule := range s.config.GetSchedules() {
+		if schedule.Paused {
+			continue
+		}
+
+		cron, err := parseCron(schedule.Cron)
+		if err != nil {
+			logger.Errorf("Schedule %s: %s", schedule.Name, err.Error())
+			continue
+		}
+
+		if !cron.match(t) {
+			continue
+		}
+
+		logger.Infof("Running scheduled task %s", schedule.Name)
+		if _, err := s.run(context.Background(), schedule); err != nil {
+			logger.Errorf("Error running scheduled task %s: %s", schedule.Name, err.Error())
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, schedule *config.Schedule) (int, error) {
+	jobID, err := s.runTask(ctx, schedule)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	s.lastRun[schedule.Name] = time.Now()
+	s.mutex.Unlock()
+
+	return jobID, nil
+}
+
+// List returns all of the configured schedules.
+func (s *Scheduler) List() []*models.Schedule {
+	var ret []*models.Schedule
+	for _, schedule := range s.config.GetSchedules() {
+		ret = append(ret, s.toModel(schedule))
+	}
+
+	return ret
+}
+
+// Create adds a new schedule to the configuration.
+func (s *Scheduler) Create(input models.ScheduleCreateInput) (*models.Schedule, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("name must not be empty")
+	}
+
+	if !input.Task.IsValid() {
+		return nil, fmt.Errorf("invalid task type %s", input.Task)
+	}
+
+	if _, err := parseCron(input.Cron); err != nil {
+		return nil, err
+	}
+
+	schedule := &config.Schedule{
+		Name:   name,
+		Cron:   strings.TrimSpace(input.Cron),
+		Task:   input.Task,
+		Paused: input.Paused != nil && *input.Paused,
+	}
+
+	// only store the input for the task type
+	switch input.Task {
+	case models.ScheduledTaskTypeScan:
+		schedule.ScanInput = input.ScanInput
+	case models.ScheduledTaskTypeGenerate:
+		schedule.GenerateInput = input.GenerateInput
+	case models.ScheduledTaskTypeAutoTag:
+		schedule.AutoTagInput = input.AutoTagInput
+	case models.ScheduledTaskTypeClean:
+		schedule.CleanInput = input.CleanInput
+	}
+
+	if err := s.update(func(schedules []*config.Schedule) ([]*config.Schedule, error) {
+		if findSchedule(schedules, name) != -1 {
+			return nil, fmt.Errorf("schedule with name %s already exists", name)
+		}
+
+		return append(schedules, schedule), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.toModel(schedule), nil
+}
+
+// SetPaused pauses or resumes the schedule with the provided name.
+func (s *Scheduler) SetPaused(name string, paused bool) (*models.Schedule, error) {
+	var ret *config.Schedule
+	if err := s.update(func(schedules []*config.Schedule) ([]*config.Schedule, error) {
+		i := findSchedule(schedules, name)
+		if i == -1 {
+			return nil, ErrScheduleNotFound
+		}
+
+		schedules[i].Paused = paused
+		ret = schedules[i]
+		return schedules, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.toModel(ret), nil
+}
+
+// Destroy removes the schedule with the provided name from the
+// configuration.
+func (s *Scheduler) Destroy(name string) error {
+	return s.update(func(schedules []*config.Schedule) ([]*config.Schedule, error) {
+		i := findSchedule(schedules, name)
+		if i == -1 {
+			return nil, ErrScheduleNotFound
+		}
+
+		return append(schedules[:i], schedules[i+1:]...), nil
+	})
+}
+
+// Run immediately runs the task of the schedule with the provided name,
+// regardless of whether it is paused. Returns the ID of the queued job.
+func (s *Scheduler) Run(ctx context.Context, name string) (int, error) {
+	schedules := s.config.GetSchedules()
+	i := findSchedule(schedules, name)
+	if i == -1 {
+		return 0, ErrScheduleNotFound
+	}
+
+	return s.run(ctx, schedules[i])
+}
+
+// update applies fn to the configured schedules and writes the result to
+// the configuration file.
+func (s *Scheduler) update(fn func(schedules []*config.Schedule) ([]*config.Schedule, error)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	schedules, err := fn(s.config.GetSchedules())
+	if err != nil {
+		return err
+	}
+
+	s.config.Set(config.Schedules, schedules)
+	return s.config.Write()
+}
+
+func findSchedule(schedules []*config.Schedule, name string) int {
+	for i, schedule := range schedules {
+		if schedule.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (s *Scheduler) toModel(schedule *config.Schedule) *models.Schedule {
+	ret := &models.Schedule{
+		Name:   schedule.Name,
+		Cron:   schedule.Cron,
+		Task:   schedule.Task,
+		Paused: schedule.Paused,
+		Input:  scheduleInputToMap(schedule),
+	}
+
+	if !schedule.Paused {
+		if cron, err := parseCron(schedule.Cron); err == nil {
+			if next := cron.next(time.Now()); !next.IsZero() {
+				ret.NextRun = &next
+			}
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if lastRun, found := s.lastRun[schedule.Name]; found {
+		ret.LastRun = &lastRun
+	}
+
+	return ret
+}
+
+func scheduleInputToMap(schedule *config.Schedule) map[string]interface{} {
+	var input interface{}
+	switch schedule.Task {
+	case models.ScheduledTaskTypeScan:
+		input = schedule.ScanInput
+	case models.ScheduledTaskTypeGenerate:
+		input = schedule.GenerateInput
+	case models.ScheduledTaskTypeAutoTag:
+		input = schedule.AutoTagInput
+	case models.ScheduledTaskTypeClean:
+		input = schedule.CleanInput
+	}
+
+	// convert to the graphql representation of the input
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil
+	}
+
+	var ret map[string]interface{}
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil
+	}
+
+	return ret
+}
+
+// runScheduledTask queues the task of the provided schedule, returning the
+// job ID.
+func (s *singleton) runScheduledTask(ctx context.Context, schedule *config.Schedule) (int, error) {
+	switch schedule.Task {
+	case models.ScheduledTaskTypeScan:
+		var input models.ScanMetadataInput
+		if schedule.ScanInput != nil {
+			input = *schedule.ScanInput
+		}
+		return s.Scan(ctx, input)
+	case models.ScheduledTaskTypeGenerate:
+		var input models.GenerateMetadataInput
+		if schedule.GenerateInput != nil {
+			input = *schedule.GenerateInput
+		}
+		return s.Generate(ctx, input)
+	case models.ScheduledTaskTypeAutoTag:
+		var input models.AutoTagMetadataInput
+		if schedule.AutoTagInput != nil {
+			input = *schedule.AutoTagInput
+		}
+		return s.AutoTag(ctx, input), nil
+	case models.ScheduledTaskTypeClean:
+		var input models.CleanMetadataInput
+		if schedule.CleanInput != nil {
+			input = *schedule.CleanInput
+		}
+		return s.Clean(ctx, input), nil
+	case models.ScheduledTaskTypeBackup:
+		return s.Backup(ctx), nil
+	}
+
+	return 0, fmt.Errorf("unsupported task type %s", schedule.Task)
+}
@@ -91,20 +91,21 @@ func (j *autoTagJob) autoTagSpecific(ctx context.Context, progress *job.Progress
 
 		return nil
 	}); err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(ctx).Error(err.Error())
+		progress.SetError(err)
 		return
 	}
 
 	total := performerCount + studioCount + tagCount
 	progress.SetTotal(total)
 
-	logger.Infof("Starting autotag of %d performers, %d studios, %d tags", performerCount, studioCount, tagCount)
+	logger.FromContext(ctx).Infof("Starting autotag of %d performers, %d studios, %d tags", performerCount, studioCount, tagCount)
 
 	j.autoTagPerformers(ctx, progress, input.Paths, performerIds)
 	j.autoTagStudios(ctx, progress, input.Paths, studioIds)
 	j.autoTagTags(ctx, progress, input.Paths, tagIds)
 
-	logger.Info("Finished autotag")
+	logger.FromContext(ctx).Info("Finished autotag")
 }
 
 func (j *autoTagJob) autoTagPerformers(ctx context.Context, progress *job.Progress, paths []string, performerIds []string) {
@@ -143,7 +144,7 @@ func (j *autoTagJob) autoTagPerformers(ctx context.Context, progress *job.Progre
 
 			for _, performer := range performers {
 				if job.IsCancelled(ctx) {
-					logger.Info("Stopping due to user request")
+					logger.FromContext(ctx).Info("Stopping due to user request")
 					return nil
 				}
 
@@ -168,7 +169,8 @@ func (j *autoTagJob) autoTagPerformers(ctx context.Context, progress *job.Progre
 
 			return nil
 		}); err != nil {
-			logger.Error(err.Error())
+			logger.FromContext(ctx).Error(err.Error())
+			progress.SetError(err)
 			continue
 		}
 	}
@@ -210,7 +212,7 @@ func (j *autoTagJob) autoTagStudios(ctx context.Context, progress *job.Progress,
 
 			for _, studio := range studios {
 				if job.IsCancelled(ctx) {
-					logger.Info("Stopping due to user request")
+					logger.FromContext(ctx).Info("Stopping due to user request")
 					return nil
 				}
 
@@ -240,7 +242,8 @@ func (j *autoTagJob) autoTagStudios(ctx context.Context, progress *job.Progress,
 
 			return nil
 		}); err != nil {
-			logger.Error(err.Error())
+			logger.FromContext(ctx).Error(err.Error())
+			progress.SetError(err)
 			continue
 		}
 	}
@@ -276,7 +279,7 @@ func (j *autoTagJob) autoTagTags(ctx context.Context, progress *job.Progress, pa
 
 			for _, tag := range tags {
 				if job.IsCancelled(ctx) {
-					logger.Info("Stopping due to user request")
+					logger.FromContext(ctx).Info("Stopping due to user request")
 					return nil
 				}
 
@@ -306,7 +309,8 @@ func (j *autoTagJob) autoTagTags(ctx context.Context, progress *job.Progress, pa
 
 			return nil
 		}); err != nil {
-			logger.Error(err.Error())
+			logger.FromContext(ctx).Error(err.Error())
+			progress.SetError(err)
 			continue
 		}
 	}
@@ -598,7 +602,7 @@ func (t *autoTagFilesTask) process() {
 
 		t.progress.SetTotal(total)
 
-		logger.Infof("Starting autotag of %d files", total)
+		logger.FromContext(t.ctx).Infof("Starting autotag of %d files", total)
 
 		if err := t.processScenes(r); err != nil {
 			return err
@@ -613,15 +617,16 @@ func (t *autoTagFilesTask) process() {
 		}
 
 		if job.IsCancelled(t.ctx) {
-			logger.Info("Stopping due to user request")
+			logger.FromContext(t.ctx).Info("Stopping due to user request")
 		}
 
 		return nil
 	}); err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
+		t.progress.SetError(err)
 	}
 
-	logger.Info("Finished autotag")
+	logger.FromContext(t.ctx).Info("Finished autotag")
 }
 
 type autoTagSceneTask struct {
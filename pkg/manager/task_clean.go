@@ -2,7 +2,6 @@ package manager
 
 import (
 	"context"
-	"os"
 	"path/filepath"
 	"sync"
 
@@ -26,8 +25,8 @@ type CleanTask struct {
 func (t *CleanTask) Start(wg *sync.WaitGroup, dryRun bool) {
 	defer wg.Done()
 
-	if t.Scene != nil && t.shouldCleanScene(t.Scene) && !dryRun {
-		t.deleteScene(t.Scene.ID)
+	if t.Scene != nil {
+		t.cleanScene(dryRun)
 	}
 
 	if t.Gallery != nil && t.shouldCleanGallery(t.Gallery) && !dryRun {
@@ -46,32 +45,66 @@ func (t *CleanTask) shouldClean(path string) bool {
 	// #1102 - clean anything in generated path
 	generatedPath := config.GetInstance().GetGeneratedPath()
 	if !fileExists || getStashFromPath(path) == nil || utils.IsPathInDir(generatedPath, path) {
-		logger.Infof("File not found. Cleaning: \"%s\"", path)
+		logger.FromContext(t.ctx).Infof("File not found. Cleaning: \"%s\"", path)
 		return true
 	}
 
 	return false
 }
 
-func (t *CleanTask) shouldCleanScene(s *models.Scene) bool {
-	if t.shouldClean(s.Path) {
+// cleanScene removes the files of the scene that should be cleaned. The scene
+// itself is only deleted once none of its files remain.
+func (t *CleanTask) cleanScene(dryRun bool) {
+	var files []*models.SceneFile
+	if err := t.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		var err error
+		files, err = r.Scene().GetFiles(t.Scene.ID)
+		return err
+	}); err != nil {
+		logger.FromContext(t.ctx).Errorf("Error getting files for scene %d: %s", t.Scene.ID, err.Error())
+		return
+	}
+
+	var toClean []*models.SceneFile
+	for _, f := range files {
+		if t.shouldCleanSceneFile(f.Path) {
+			toClean = append(toClean, f)
+		}
+	}
+
+	if dryRun {
+		return
+	}
+
+	if len(toClean) == len(files) {
+		t.deleteScene(t.Scene.ID)
+		return
+	}
+
+	if len(toClean) > 0 {
+		t.deleteSceneFiles(toClean)
+	}
+}
+
+func (t *CleanTask) shouldCleanSceneFile(path string) bool {
+	if t.shouldClean(path) {
 		return true
 	}
 
-	stash := getStashFromPath(s.Path)
+	stash := getStashFromPath(path)
 	if stash.ExcludeVideo {
-		logger.Infof("File in stash library that excludes video. Cleaning: \"%s\"", s.Path)
+		logger.FromContext(t.ctx).Infof("File in stash library that excludes video. Cleaning: \"%s\"", path)
 		return true
 	}
 
 	config := config.GetInstance()
-	if !matchExtension(s.Path, config.GetVideoExtensions()) {
-		logger.Infof("File extension does not match video extensions. Cleaning: \"%s\"", s.Path)
+	if !matchExtension(path, config.GetVideoExtensions()) {
+		logger.FromContext(t.ctx).Infof("File extension does not match video extensions. Cleaning: \"%s\"", path)
 		return true
 	}
 
-	if matchFile(s.Path, config.GetExcludes()) {
-		logger.Infof("File matched regex. Cleaning: \"%s\"", s.Path)
+	if matchFile(path, config.GetExcludes()) {
+		logger.FromContext(t.ctx).Infof("File matched regex. Cleaning: \"%s\"", path)
 		return true
 	}
 
@@ -91,23 +124,23 @@ func (t *CleanTask) shouldCleanGallery(g *models.Gallery) bool {
 
 	stash := getStashFromPath(path)
 	if stash.ExcludeImage {
-		logger.Infof("File in stash library that excludes images. Cleaning: \"%s\"", path)
+		logger.FromContext(t.ctx).Infof("File in stash library that excludes images. Cleaning: \"%s\"", path)
 		return true
 	}
 
 	config := config.GetInstance()
 	if !matchExtension(path, config.GetGalleryExtensions()) {
-		logger.Infof("File extension does not match gallery extensions. Cleaning: \"%s\"", path)
+		logger.FromContext(t.ctx).Infof("File extension does not match gallery extensions. Cleaning: \"%s\"", path)
 		return true
 	}
 
 	if matchFile(path, config.GetImageExcludes()) {
-		logger.Infof("File matched regex. Cleaning: \"%s\"", path)
+		logger.FromContext(t.ctx).Infof("File matched regex. Cleaning: \"%s\"", path)
 		return true
 	}
 
-	if countImagesInZip(path) == 0 {
-		logger.Infof("Gallery has 0 images. Cleaning: \"%s\"", path)
+	if countImagesInArchive(path) == 0 {
+		logger.FromContext(t.ctx).Infof("Gallery has 0 images. Cleaning: \"%s\"", path)
 		return true
 	}
 
@@ -121,18 +154,23 @@ func (t *CleanTask) shouldCleanImage(s *models.Image) bool {
 
 	stash := getStashFromPath(s.Path)
 	if stash.ExcludeImage {
-		logger.Infof("File in stash library that excludes images. Cleaning: \"%s\"", s.Path)
+		logger.FromContext(t.ctx).Infof("File in stash library that excludes images. Cleaning: \"%s\"", s.Path)
 		return true
 	}
 
 	config := config.GetInstance()
-	if !matchExtension(s.Path, config.GetImageExtensions()) {
-		logger.Infof("File extension does not match image extensions. Cleaning: \"%s\"", s.Path)
+	if s.IsVideo {
+		if !isImageClip(s.Path) {
+			logger.FromContext(t.ctx).Infof("Video clip is not in a stash library that creates image clips. Cleaning: \"%s\"", s.Path)
+			return true
+		}
+	} else if !matchExtension(s.Path, config.GetImageExtensions()) {
+		logger.FromContext(t.ctx).Infof("File extension does not match image extensions. Cleaning: \"%s\"", s.Path)
 		return true
 	}
 
 	if matchFile(s.Path, config.GetImageExcludes()) {
-		logger.Infof("File matched regex. Cleaning: \"%s\"", s.Path)
+		logger.FromContext(t.ctx).Infof("File matched regex. Cleaning: \"%s\"", s.Path)
 		return true
 	}
 
@@ -153,7 +191,7 @@ func (t *CleanTask) deleteScene(sceneID int) {
 		postCommitFunc, err = DestroyScene(scene, repo)
 		return err
 	}); err != nil {
-		logger.Errorf("Error deleting scene from database: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("Error deleting scene from database: %s", err.Error())
 		return
 	}
 
@@ -164,12 +202,44 @@ func (t *CleanTask) deleteScene(sceneID int) {
 	GetInstance().PluginCache.ExecutePostHooks(t.ctx, sceneID, plugin.SceneDestroyPost, nil, nil)
 }
 
+// deleteSceneFiles removes the provided files from the scene. If the primary
+// file is removed, then the generated files are migrated to the hash of the
+// new primary file.
+func (t *CleanTask) deleteSceneFiles(files []*models.SceneFile) {
+	sceneID := t.Scene.ID
+	var updated *models.Scene
+	if err := t.TxnManager.WithTxn(context.TODO(), func(repo models.Repository) error {
+		qb := repo.Scene()
+
+		for _, f := range files {
+			if err := qb.DestroyFile(f.ID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		updated, err = qb.Find(sceneID)
+		return err
+	}); err != nil {
+		logger.FromContext(t.ctx).Errorf("Error deleting scene files from database: %s", err.Error())
+		return
+	}
+
+	oldHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	newHash := updated.GetHash(t.fileNamingAlgorithm)
+	if oldHash != newHash {
+		MigrateHash(oldHash, newHash)
+	}
+
+	GetInstance().PluginCache.ExecutePostHooks(t.ctx, sceneID, plugin.SceneUpdatePost, nil, nil)
+}
+
 func (t *CleanTask) deleteGallery(galleryID int) {
 	if err := t.TxnManager.WithTxn(context.TODO(), func(repo models.Repository) error {
 		qb := repo.Gallery()
 		return qb.Destroy(galleryID)
 	}); err != nil {
-		logger.Errorf("Error deleting gallery from database: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("Error deleting gallery from database: %s", err.Error())
 		return
 	}
 
@@ -183,14 +253,11 @@ func (t *CleanTask) deleteImage(imageID int) {
 
 		return qb.Destroy(imageID)
 	}); err != nil {
-		logger.Errorf("Error deleting image from database: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("Error deleting image from database: %s", err.Error())
 		return
 	}
 
-	pathErr := os.Remove(GetInstance().Paths.Generated.GetThumbnailPath(t.Image.Checksum, models.DefaultGthumbWidth)) // remove cache dir of gallery
-	if pathErr != nil {
-		logger.Errorf("Error deleting thumbnail image from cache: %s", pathErr)
-	}
+	DeleteGeneratedImageFiles(t.Image)
 
 	GetInstance().PluginCache.ExecutePostHooks(t.ctx, imageID, plugin.ImageDestroyPost, nil, nil)
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/studio"
 	"github.com/stashapp/stash/pkg/tag"
+	"github.com/stashapp/stash/pkg/user"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -47,6 +48,9 @@ type ExportTask struct {
 
 	includeDependencies bool
 
+	// usernames maps user ids to usernames for the exported user data
+	usernames map[int]string
+
 	DownloadHash string
 }
 
@@ -127,6 +131,12 @@ func (t *ExportTask) Start(wg *sync.WaitGroup) {
 	paths.EnsureJSONDirs(t.baseDir)
 
 	t.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		var err error
+		t.usernames, err = user.GetUsernames(r.User())
+		if err != nil {
+			logger.Errorf("error getting usernames: %s", err.Error())
+		}
+
 		// include movie scenes and gallery images
 		if !t.full {
 			// only include movie scenes if includeDependencies is also set
@@ -412,6 +422,14 @@ func exportScene(wg *sync.WaitGroup, jobChan <-chan *models.Scene, repo models.R
 			continue
 		}
 
+		userData, err := sceneReader.GetAllUserData(s.ID)
+		if err != nil {
+			logger.Errorf("[scenes] <%s> error getting scene user data: %s", sceneHash, err.Error())
+			continue
+		}
+
+		newSceneJSON.UserData = user.ToUserDataJSON(userData, t.usernames)
+
 		if t.includeDependencies {
 			if s.StudioID.Valid {
 				t.studios.IDs = utils.IntAppendUnique(t.studios.IDs, int(s.StudioID.Int64))
@@ -493,6 +511,7 @@ func (t *ExportTask) ExportImages(workers int, repo models.ReaderRepository) {
 
 func exportImage(wg *sync.WaitGroup, jobChan <-chan *models.Image, repo models.ReaderRepository, t *ExportTask) {
 	defer wg.Done()
+	imageReader := repo.Image()
 	studioReader := repo.Studio()
 	galleryReader := repo.Gallery()
 	performerReader := repo.Performer()
@@ -534,6 +553,14 @@ func exportImage(wg *sync.WaitGroup, jobChan <-chan *models.Image, repo models.R
 
 		newImageJSON.Tags = tag.GetNames(tags)
 
+		userData, err := imageReader.GetAllUserData(s.ID)
+		if err != nil {
+			logger.Errorf("[images] <%s> error getting image user data: %s", imageHash, err.Error())
+			continue
+		}
+
+		newImageJSON.UserData = user.ToUserDataJSON(userData, t.usernames)
+
 		if t.includeDependencies {
 			if s.StudioID.Valid {
 				t.studios.IDs = utils.IntAppendUnique(t.studios.IDs, int(s.StudioID.Int64))
@@ -616,6 +643,9 @@ func exportGallery(wg *sync.WaitGroup, jobChan <-chan *models.Gallery, repo mode
 	studioReader := repo.Studio()
 	performerReader := repo.Performer()
 	tagReader := repo.Tag()
+	galleryReader := repo.Gallery()
+	imageReader := repo.Image()
+	chapterReader := repo.GalleryChapter()
 
 	for g := range jobChan {
 		galleryHash := g.Checksum
@@ -648,6 +678,18 @@ func exportGallery(wg *sync.WaitGroup, jobChan <-chan *models.Gallery, repo mode
 
 		newGalleryJSON.Tags = tag.GetNames(tags)
 
+		newGalleryJSON.Images, newGalleryJSON.Cover, err = gallery.GetImageOrder(galleryReader, imageReader, g)
+		if err != nil {
+			logger.Errorf("[galleries] <%s> error getting gallery image order: %s", galleryHash, err.Error())
+			continue
+		}
+
+		newGalleryJSON.Chapters, err = gallery.GetGalleryChaptersJSON(chapterReader, g)
+		if err != nil {
+			logger.Errorf("[galleries] <%s> error getting gallery chapters JSON: %s", galleryHash, err.Error())
+			continue
+		}
+
 		if t.includeDependencies {
 			if g.StudioID.Valid {
 				t.studios.IDs = utils.IntAppendUnique(t.studios.IDs, int(g.StudioID.Int64))
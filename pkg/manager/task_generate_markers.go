@@ -14,6 +14,7 @@ import (
 )
 
 type GenerateMarkersTask struct {
+	ctx                 context.Context
 	TxnManager          models.TransactionManager
 	Scene               *models.Scene
 	Marker              *models.SceneMarker
@@ -38,18 +39,18 @@ func (t *GenerateMarkersTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 			scene, err = r.Scene().Find(int(t.Marker.SceneID.Int64))
 			return err
 		}); err != nil {
-			logger.Errorf("error finding scene for marker: %s", err.Error())
+			logger.FromContext(t.ctx).Errorf("error finding scene for marker: %s", err.Error())
 			return
 		}
 
 		if scene == nil {
-			logger.Errorf("scene not found for id %d", t.Marker.SceneID.Int64)
+			logger.FromContext(t.ctx).Errorf("scene not found for id %d", t.Marker.SceneID.Int64)
 			return
 		}
 
 		videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.Scene.Path, false)
 		if err != nil {
-			logger.Errorf("error reading video file: %s", err.Error())
+			logger.FromContext(t.ctx).Errorf("error reading video file: %s", err.Error())
 			return
 		}
 
@@ -64,7 +65,7 @@ func (t *GenerateMarkersTask) generateSceneMarkers() {
 		sceneMarkers, err = r.SceneMarker().FindBySceneID(t.Scene.ID)
 		return err
 	}); err != nil {
-		logger.Errorf("error getting scene markers: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error getting scene markers: %s", err.Error())
 		return
 	}
 
@@ -74,7 +75,7 @@ func (t *GenerateMarkersTask) generateSceneMarkers() {
 
 	videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.Scene.Path, false)
 	if err != nil {
-		logger.Errorf("error reading video file: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error reading video file: %s", err.Error())
 		return
 	}
 
@@ -108,6 +109,11 @@ func (t *GenerateMarkersTask) generateMarker(videoFile *ffmpeg.VideoFile, scene
 		Width:     640,
 	}
 
+	// cover the whole range of ranged markers
+	if sceneMarker.EndSeconds.Valid {
+		options.Duration = sceneMarker.EndSeconds.Float64 - float64(seconds)
+	}
+
 	encoder := ffmpeg.NewEncoder(instance.FFMPEGPath)
 
 	if t.Overwrite || !videoExists {
@@ -116,10 +122,10 @@ func (t *GenerateMarkersTask) generateMarker(videoFile *ffmpeg.VideoFile, scene
 
 		options.OutputPath = instance.Paths.Generated.GetTmpPath(videoFilename) // tmp output in case the process ends abruptly
 		if err := encoder.SceneMarkerVideo(*videoFile, options); err != nil {
-			logger.Errorf("[generator] failed to generate marker video: %s", err)
+			logger.FromContext(t.ctx).Errorf("[generator] failed to generate marker video: %s", err)
 		} else {
 			_ = utils.SafeMove(options.OutputPath, videoPath)
-			logger.Debug("created marker video: ", videoPath)
+			logger.FromContext(t.ctx).Debug("created marker video: ", videoPath)
 		}
 	}
 
@@ -129,10 +135,10 @@ func (t *GenerateMarkersTask) generateMarker(videoFile *ffmpeg.VideoFile, scene
 
 		options.OutputPath = instance.Paths.Generated.GetTmpPath(imageFilename) // tmp output in case the process ends abruptly
 		if err := encoder.SceneMarkerImage(*videoFile, options); err != nil {
-			logger.Errorf("[generator] failed to generate marker image: %s", err)
+			logger.FromContext(t.ctx).Errorf("[generator] failed to generate marker image: %s", err)
 		} else {
 			_ = utils.SafeMove(options.OutputPath, imagePath)
-			logger.Debug("created marker image: ", imagePath)
+			logger.FromContext(t.ctx).Debug("created marker image: ", imagePath)
 		}
 	}
 
@@ -147,10 +153,10 @@ func (t *GenerateMarkersTask) generateMarker(videoFile *ffmpeg.VideoFile, scene
 			Time:       float64(seconds),
 		}
 		if err := encoder.Screenshot(*videoFile, screenshotOptions); err != nil {
-			logger.Errorf("[generator] failed to generate marker screenshot: %s", err)
+			logger.FromContext(t.ctx).Errorf("[generator] failed to generate marker screenshot: %s", err)
 		} else {
 			_ = utils.SafeMove(screenshotOptions.OutputPath, screenshotPath)
-			logger.Debug("created marker screenshot: ", screenshotPath)
+			logger.FromContext(t.ctx).Debug("created marker screenshot: ", screenshotPath)
 		}
 	}
 }
@@ -163,7 +169,7 @@ func (t *GenerateMarkersTask) isMarkerNeeded() int {
 		sceneMarkers, err = r.SceneMarker().FindBySceneID(t.Scene.ID)
 		return err
 	}); err != nil {
-		logger.Errorf("errror finding scene markers: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("errror finding scene markers: %s", err.Error())
 		return 0
 	}
 
@@ -5,13 +5,16 @@ import (
 
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
 )
 
 type GeneratePhashTask struct {
+	ctx                 context.Context
 	Scene               models.Scene
 	Overwrite           bool
 	fileNamingAlgorithm models.HashAlgorithm
@@ -27,7 +30,7 @@ func (t *GeneratePhashTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 
 	videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.Scene.Path, false)
 	if err != nil {
-		logger.Errorf("error reading video file: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error reading video file: %s", err.Error())
 		return
 	}
 
@@ -35,26 +38,35 @@ func (t *GeneratePhashTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 	generator, err := NewPhashGenerator(*videoFile, sceneHash)
 
 	if err != nil {
-		logger.Errorf("error creating phash generator: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error creating phash generator: %s", err.Error())
 		return
 	}
 	hash, err := generator.Generate()
 	if err != nil {
-		logger.Errorf("error generating phash: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error generating phash: %s", err.Error())
 		return
 	}
 
 	if err := t.txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
 		qb := r.Scene()
+		primaryFile, err := scene.GetPrimaryFile(qb, t.Scene.ID)
+		if err != nil {
+			return err
+		}
+
+		if primaryFile == nil {
+			return fmt.Errorf("scene %d has no primary file", t.Scene.ID)
+		}
+
 		hashValue := sql.NullInt64{Int64: int64(*hash), Valid: true}
-		scenePartial := models.ScenePartial{
-			ID:    t.Scene.ID,
+		filePartial := models.SceneFilePartial{
+			ID:    primaryFile.ID,
 			Phash: &hashValue,
 		}
-		_, err := qb.Update(scenePartial)
+		_, err = qb.UpdateFile(filePartial)
 		return err
 	}); err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 	}
 }
 
@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"context"
+
 	"github.com/remeh/sizedwaitgroup"
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
@@ -11,6 +13,7 @@ import (
 )
 
 type GeneratePreviewTask struct {
+	ctx          context.Context
 	Scene        models.Scene
 	ImagePreview bool
 
@@ -33,7 +36,7 @@ func (t *GeneratePreviewTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 
 	videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.Scene.Path, false)
 	if err != nil {
-		logger.Errorf("error reading video file: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error reading video file: %s", err.Error())
 		return
 	}
 
@@ -41,10 +44,11 @@ func (t *GeneratePreviewTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 	generator, err := NewPreviewGenerator(*videoFile, videoChecksum, videoFilename, imageFilename, instance.Paths.Generated.Screenshots, generateVideo, t.ImagePreview, t.Options.PreviewPreset.String())
 
 	if err != nil {
-		logger.Errorf("error creating preview generator: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error creating preview generator: %s", err.Error())
 		return
 	}
 	generator.Overwrite = t.Overwrite
+	generator.TranscodeProfile = instance.GetTranscodeProfile()
 
 	// set the preview generation configuration from the global config
 	generator.Info.ChunkCount = *t.Options.PreviewSegments
@@ -54,7 +58,7 @@ func (t *GeneratePreviewTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 	generator.Info.Audio = config.GetInstance().GetPreviewAudio()
 
 	if err := generator.Generate(); err != nil {
-		logger.Errorf("error generating preview: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error generating preview: %s", err.Error())
 		return
 	}
 }
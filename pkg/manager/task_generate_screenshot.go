@@ -14,6 +14,7 @@ import (
 )
 
 type GenerateScreenshotTask struct {
+	ctx                 context.Context
 	Scene               models.Scene
 	ScreenshotAt        *float64
 	fileNamingAlgorithm models.HashAlgorithm
@@ -27,7 +28,7 @@ func (t *GenerateScreenshotTask) Start(wg *sync.WaitGroup) {
 	probeResult, err := ffmpeg.NewVideoFile(instance.FFProbePath, scenePath, false)
 
 	if err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 		return
 	}
 
@@ -45,19 +46,19 @@ func (t *GenerateScreenshotTask) Start(wg *sync.WaitGroup) {
 	// in the database. We'll use SetSceneScreenshot to set the data
 	// which also generates the thumbnail
 
-	logger.Debugf("Creating screenshot for %s", scenePath)
+	logger.FromContext(t.ctx).Debugf("Creating screenshot for %s", scenePath)
 	makeScreenshot(*probeResult, normalPath, 2, probeResult.Width, at)
 
 	f, err := os.Open(normalPath)
 	if err != nil {
-		logger.Errorf("Error reading screenshot: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("Error reading screenshot: %s", err.Error())
 		return
 	}
 	defer f.Close()
 
 	coverImageData, err := ioutil.ReadAll(f)
 	if err != nil {
-		logger.Errorf("Error reading screenshot: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("Error reading screenshot: %s", err.Error())
 		return
 	}
 
@@ -86,6 +87,6 @@ func (t *GenerateScreenshotTask) Start(wg *sync.WaitGroup) {
 
 		return nil
 	}); err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 	}
 }
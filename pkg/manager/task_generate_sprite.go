@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"context"
+
 	"github.com/remeh/sizedwaitgroup"
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
@@ -10,6 +12,7 @@ import (
 )
 
 type GenerateSpriteTask struct {
+	ctx                 context.Context
 	Scene               models.Scene
 	Overwrite           bool
 	fileNamingAlgorithm models.HashAlgorithm
@@ -24,7 +27,7 @@ func (t *GenerateSpriteTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 
 	videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.Scene.Path, false)
 	if err != nil {
-		logger.Errorf("error reading video file: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error reading video file: %s", err.Error())
 		return
 	}
 
@@ -34,13 +37,13 @@ func (t *GenerateSpriteTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 	generator, err := NewSpriteGenerator(*videoFile, sceneHash, imagePath, vttPath, 9, 9)
 
 	if err != nil {
-		logger.Errorf("error creating sprite generator: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error creating sprite generator: %s", err.Error())
 		return
 	}
 	generator.Overwrite = t.Overwrite
 
 	if err := generator.Generate(); err != nil {
-		logger.Errorf("error generating sprite: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("error generating sprite: %s", err.Error())
 		return
 	}
 }
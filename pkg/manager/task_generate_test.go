@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/remeh/sizedwaitgroup"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateTaskCapturesErrors(t *testing.T) {
+	if instance == nil {
+		instance = &singleton{}
+		defer func() {
+			instance = nil
+		}()
+	}
+
+	capture := logger.NewCapture()
+	ctx := logger.WithCapture(context.Background(), capture)
+
+	// the video file cannot be read
+	task := GenerateSpriteTask{
+		ctx:       ctx,
+		Scene:     models.Scene{Path: "missing.mp4"},
+		Overwrite: true,
+	}
+
+	wg := sizedwaitgroup.New(1)
+	wg.Add()
+	task.Start(&wg)
+
+	found := false
+	for _, item := range capture.Items() {
+		if item.Type == "error" && strings.Contains(item.Message, "error reading video file") {
+			found = true
+		}
+	}
+
+	assert.True(t, found, "generate error not captured")
+}
@@ -139,6 +139,7 @@ func (t *ImportTask) Start(wg *sync.WaitGroup) {
 	t.ImportScrapedItems(ctx)
 	t.ImportScenes(ctx)
 	t.ImportImages(ctx)
+	t.ImportGalleryImageOrder(ctx)
 }
 
 func (t *ImportTask) unzipFile() error {
@@ -356,6 +357,8 @@ func (t *ImportTask) ImportGalleries(ctx context.Context) {
 			performerWriter := r.Performer()
 			studioWriter := r.Studio()
 
+			chapterWriter := r.GalleryChapter()
+
 			galleryImporter := &gallery.Importer{
 				ReaderWriter:        readerWriter,
 				PerformerWriter:     performerWriter,
@@ -365,7 +368,30 @@ func (t *ImportTask) ImportGalleries(ctx context.Context) {
 				MissingRefBehaviour: t.MissingRefBehaviour,
 			}
 
-			return performImport(galleryImporter, t.DuplicateBehaviour)
+			if err := performImport(galleryImporter, t.DuplicateBehaviour); err != nil {
+				return err
+			}
+
+			// the chapters of a skipped existing gallery are not imported
+			if galleryImporter.ID == 0 {
+				return nil
+			}
+
+			// import the gallery chapters
+			for _, c := range galleryJSON.Chapters {
+				chapterImporter := &gallery.ChapterImporter{
+					GalleryID:           galleryImporter.ID,
+					Input:               c,
+					MissingRefBehaviour: t.MissingRefBehaviour,
+					ReaderWriter:        chapterWriter,
+				}
+
+				if err := performImport(chapterImporter, t.DuplicateBehaviour); err != nil {
+					return err
+				}
+			}
+
+			return nil
 		}); err != nil {
 			logger.Errorf("[galleries] <%s> import failed to commit: %s", mappingJSON.Checksum, err.Error())
 			continue
@@ -375,6 +401,42 @@ func (t *ImportTask) ImportGalleries(ctx context.Context) {
 	logger.Info("[galleries] import complete")
 }
 
+// ImportGalleryImageOrder sets the image order and cover image of the
+// galleries. It is run after the images are imported.
+func (t *ImportTask) ImportGalleryImageOrder(ctx context.Context) {
+	logger.Info("[galleries] importing image order")
+
+	for _, mappingJSON := range t.mappings.Galleries {
+		galleryJSON, err := t.json.getGallery(mappingJSON.Checksum)
+		if err != nil {
+			logger.Errorf("[galleries] failed to read json: %s", err.Error())
+			continue
+		}
+
+		if len(galleryJSON.Images) == 0 && galleryJSON.Cover == "" {
+			continue
+		}
+
+		if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
+			readerWriter := r.Gallery()
+			g, err := readerWriter.FindByChecksum(galleryJSON.Checksum)
+			if err != nil {
+				return err
+			}
+
+			if g == nil {
+				return nil
+			}
+
+			return gallery.ImportImageOrder(readerWriter, r.Image(), g.ID, *galleryJSON)
+		}); err != nil {
+			logger.Errorf("[galleries] <%s> image order import failed: %s", mappingJSON.Checksum, err.Error())
+		}
+	}
+
+	logger.Info("[galleries] image order import complete")
+}
+
 func (t *ImportTask) ImportTags(ctx context.Context) {
 	pendingParent := make(map[string][]*jsonschema.Tag)
 	logger.Info("[tags] importing")
@@ -536,6 +598,7 @@ func (t *ImportTask) ImportScenes(ctx context.Context) {
 				PerformerWriter: performerWriter,
 				StudioWriter:    studioWriter,
 				TagWriter:       tagWriter,
+				UserReader:      r.User(),
 			}
 
 			if err := performImport(sceneImporter, t.DuplicateBehaviour); err != nil {
@@ -600,6 +663,7 @@ func (t *ImportTask) ImportImages(ctx context.Context) {
 				PerformerWriter: performerWriter,
 				StudioWriter:    studioWriter,
 				TagWriter:       tagWriter,
+				UserReader:      r.User(),
 			}
 
 			return performImport(imageImporter, t.DuplicateBehaviour)
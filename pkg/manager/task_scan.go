@@ -1,19 +1,20 @@
 package manager
 
 import (
-	"archive/zip"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/remeh/sizedwaitgroup"
 
+	"github.com/stashapp/stash/pkg/archive"
 	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
@@ -22,6 +23,7 @@ import (
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/plugin/common"
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/utils"
 )
@@ -32,10 +34,27 @@ type ScanJob struct {
 	subscriptions *subscriptionManager
 }
 
+// Pausable returns true. Scan jobs may be paused between files.
+func (j *ScanJob) Pausable() bool {
+	return true
+}
+
+// scanCheckpointItem returns the checkpoint item for a scanned file. The
+// modification time is included so that files that are modified after
+// being scanned are scanned again.
+func scanCheckpointItem(path string, info os.FileInfo) string {
+	return fmt.Sprintf("%s:%d", path, info.ModTime().Unix())
+}
+
 func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 	input := j.input
 	paths := getScanPaths(input.Paths)
 
+	checkpoint := newJobCheckpoint(j.txnManager, "scan", input)
+	if n := checkpoint.load(); n > 0 {
+		logger.FromContext(ctx).Infof("Skipping %d files scanned by a previous run", n)
+	}
+
 	var total *int
 	var newFiles *int
 	progress.ExecuteTask("Counting files to scan...", func() {
@@ -43,21 +62,21 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 	})
 
 	if job.IsCancelled(ctx) {
-		logger.Info("Stopping due to user request")
+		logger.FromContext(ctx).Info("Stopping due to user request")
 		return
 	}
 
 	if total == nil || newFiles == nil {
-		logger.Infof("Taking too long to count content. Skipping...")
-		logger.Infof("Starting scan")
+		logger.FromContext(ctx).Infof("Taking too long to count content. Skipping...")
+		logger.FromContext(ctx).Infof("Starting scan")
 	} else {
-		logger.Infof("Starting scan of %d files. %d New files found", *total, *newFiles)
+		logger.FromContext(ctx).Infof("Starting scan of %d files. %d New files found", *total, *newFiles)
 	}
 
 	start := time.Now()
 	config := config.GetInstance()
 	parallelTasks := config.GetParallelTasksWithAutoDetection()
-	logger.Infof("Scan started with %d parallel tasks", parallelTasks)
+	logger.FromContext(ctx).Infof("Scan started with %d parallel tasks", parallelTasks)
 	wg := sizedwaitgroup.New(parallelTasks)
 
 	if total != nil {
@@ -67,6 +86,10 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 	fileNamingAlgo := config.GetVideoFileNamingAlgorithm()
 	calculateMD5 := config.IsCalculateMD5()
 
+	// queue hooks so that plugins are executed one at a time
+	postHooks := newPostHookQueue(ctx, GetInstance().PluginCache)
+	defer postHooks.close()
+
 	stoppingErr := errors.New("stopping")
 	var err error
 
@@ -75,10 +98,15 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 	for _, sp := range paths {
 		csFs, er := utils.IsFsPathCaseSensitive(sp.Path)
 		if er != nil {
-			logger.Warnf("Cannot determine fs case sensitivity: %s", er.Error())
+			logger.FromContext(ctx).Warnf("Cannot determine fs case sensitivity: %s", er.Error())
 		}
 
 		err = walkFilesToScan(sp, func(path string, info os.FileInfo, err error) error {
+			if progress.IsPaused() {
+				checkpoint.flush()
+			}
+			progress.WaitIfPaused(ctx)
+
 			if job.IsCancelled(ctx) {
 				return stoppingErr
 			}
@@ -87,6 +115,12 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 				galleries = append(galleries, path)
 			}
 
+			item := scanCheckpointItem(path, info)
+			if checkpoint.isDone(item) {
+				progress.Increment()
+				return nil
+			}
+
 			instance.Paths.Generated.EnsureTmpDir()
 
 			wg.Add()
@@ -104,31 +138,36 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 				progress:             progress,
 				CaseSensitiveFs:      csFs,
 				ctx:                  ctx,
+				postHooks:            postHooks,
 			}
 
+			done := checkpoint.track(item, 1)
 			go func() {
 				task.Start(&wg)
 				progress.Increment()
+				done()
 			}()
 
 			return nil
 		})
 
 		if err == stoppingErr {
-			logger.Info("Stopping due to user request")
+			logger.FromContext(ctx).Info("Stopping due to user request")
 			break
 		}
 
 		if err != nil {
-			logger.Errorf("Error encountered scanning files: %s", err.Error())
+			logger.FromContext(ctx).Errorf("Error encountered scanning files: %s", err.Error())
+			progress.SetError(err)
 			break
 		}
 	}
 
 	wg.Wait()
+	checkpoint.finish(!job.IsCancelled(ctx) && err == nil)
 	instance.Paths.Generated.EmptyTmpDir()
 	elapsed := time.Since(start)
-	logger.Info(fmt.Sprintf("Scan finished (%s)", elapsed))
+	logger.FromContext(ctx).Info(fmt.Sprintf("Scan finished (%s)", elapsed))
 
 	if job.IsCancelled(ctx) || err != nil {
 		return
@@ -141,12 +180,13 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 				TxnManager:      j.txnManager,
 				FilePath:        path,
 				UseFileMetadata: false,
+				ctx:             ctx,
 			}
 
 			go task.associateGallery(&wg)
 			wg.Wait()
 		}
-		logger.Info("Finished gallery association")
+		logger.FromContext(ctx).Info("Finished gallery association")
 	})
 
 	j.subscriptions.notify()
@@ -158,7 +198,7 @@ func (j *ScanJob) neededScan(ctx context.Context, paths []*models.StashConfig) (
 	// create a control channel through which to signal the counting loop when the timeout is reached
 	chTimeout := time.After(timeout)
 
-	logger.Infof("Counting files to scan...")
+	logger.FromContext(ctx).Infof("Counting files to scan...")
 
 	t := 0
 	n := 0
@@ -168,7 +208,7 @@ func (j *ScanJob) neededScan(ctx context.Context, paths []*models.StashConfig) (
 	for _, sp := range paths {
 		err := walkFilesToScan(sp, func(path string, info os.FileInfo, err error) error {
 			t++
-			task := ScanTask{FilePath: path, TxnManager: j.txnManager}
+			task := ScanTask{FilePath: path, TxnManager: j.txnManager, ctx: ctx}
 			if !task.doesPathExist() {
 				n++
 			}
@@ -194,7 +234,7 @@ func (j *ScanJob) neededScan(ctx context.Context, paths []*models.StashConfig) (
 		}
 
 		if err != nil {
-			logger.Errorf("Error encountered counting files to scan: %s", err.Error())
+			logger.FromContext(ctx).Errorf("Error encountered counting files to scan: %s", err.Error())
 			return nil, nil
 		}
 	}
@@ -217,6 +257,24 @@ type ScanTask struct {
 	zipGallery           *models.Gallery
 	progress             *job.Progress
 	CaseSensitiveFs      bool
+	postHooks            *postHookQueue
+}
+
+// executePostHooks executes the post hooks of hookType for the scanned
+// object, passing the scanned path as the hook input. The hooks are queued if
+// the task has a hook queue. oldPath should be set for FileMoved hooks.
+func (t *ScanTask) executePostHooks(id int, hookType plugin.HookTriggerEnum, oldPath string) {
+	input := common.ScanHookInput{
+		Path:    t.FilePath,
+		OldPath: oldPath,
+	}
+
+	if t.postHooks != nil {
+		t.postHooks.add(id, hookType, input)
+		return
+	}
+
+	GetInstance().PluginCache.ExecutePostHooks(t.ctx, id, hookType, input, nil)
 }
 
 func (t *ScanTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
@@ -227,6 +285,8 @@ func (t *ScanTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 	t.progress.ExecuteTask("Scanning "+t.FilePath, func() {
 		if isGallery(t.FilePath) {
 			t.scanGallery()
+		} else if isImageClip(t.FilePath) {
+			t.scanImage()
 		} else if isVideo(t.FilePath) {
 			s = t.scanScene()
 		} else if isImage(t.FilePath) {
@@ -316,13 +376,13 @@ func (t *ScanTask) scanGallery() {
 
 		return err
 	}); err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 		return
 	}
 
 	fileModTime, err := t.getFileModTime()
 	if err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 		return
 	}
 
@@ -333,7 +393,7 @@ func (t *ScanTask) scanGallery() {
 		if !g.FileModTime.Valid {
 			// we will also need to rescan the zip contents
 			scanImages = true
-			logger.Infof("setting file modification time on %s", t.FilePath)
+			logger.FromContext(t.ctx).Infof("setting file modification time on %s", t.FilePath)
 
 			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
 				qb := r.Gallery()
@@ -349,7 +409,7 @@ func (t *ScanTask) scanGallery() {
 				g, err = qb.Find(g.ID)
 				return err
 			}); err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(t.ctx).Error(err.Error())
 				return
 			}
 		}
@@ -359,12 +419,12 @@ func (t *ScanTask) scanGallery() {
 		modified := t.isFileModified(fileModTime, g.FileModTime)
 		if modified {
 			scanImages = true
-			logger.Infof("%s has been updated: rescanning", t.FilePath)
+			logger.FromContext(t.ctx).Infof("%s has been updated: rescanning", t.FilePath)
 
 			// update the checksum and the modification time
 			checksum, err := t.calculateChecksum()
 			if err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(t.ctx).Error(err.Error())
 				return
 			}
 
@@ -383,9 +443,11 @@ func (t *ScanTask) scanGallery() {
 				_, err := r.Gallery().UpdatePartial(galleryPartial)
 				return err
 			}); err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(t.ctx).Error(err.Error())
 				return
 			}
+
+			t.executePostHooks(g.ID, plugin.GalleryUpdatePost, "")
 		}
 
 		// scan the zip files if the gallery has no images
@@ -398,10 +460,12 @@ func (t *ScanTask) scanGallery() {
 
 		checksum, err := t.calculateChecksum()
 		if err != nil {
-			logger.Error(err.Error())
+			logger.FromContext(t.ctx).Error(err.Error())
 			return
 		}
 
+		var hookType plugin.HookTriggerEnum
+		oldPath := ""
 		if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
 			qb := r.Gallery()
 			g, _ = qb.FindByChecksum(checksum)
@@ -416,9 +480,10 @@ func (t *ScanTask) scanGallery() {
 				}
 
 				if exists {
-					logger.Infof("%s already exists.  Duplicate of %s ", t.FilePath, g.Path.String)
+					logger.FromContext(t.ctx).Infof("%s already exists.  Duplicate of %s ", t.FilePath, g.Path.String)
 				} else {
-					logger.Infof("%s already exists.  Updating path...", t.FilePath)
+					logger.FromContext(t.ctx).Infof("%s already exists.  Updating path...", t.FilePath)
+					oldPath = g.Path.String
 					g.Path = sql.NullString{
 						String: t.FilePath,
 						Valid:  true,
@@ -428,7 +493,7 @@ func (t *ScanTask) scanGallery() {
 						return err
 					}
 
-					GetInstance().PluginCache.ExecutePostHooks(t.ctx, g.ID, plugin.GalleryUpdatePost, nil, nil)
+					hookType = plugin.GalleryFileMovedPost
 				}
 			} else {
 				currentTime := time.Now()
@@ -453,29 +518,33 @@ func (t *ScanTask) scanGallery() {
 				}
 
 				// don't create gallery if it has no images
-				if countImagesInZip(t.FilePath) > 0 {
+				if countImagesInArchive(t.FilePath) > 0 {
 					// only warn when creating the gallery
 					ok, err := utils.IsZipFileUncompressed(t.FilePath)
 					if err == nil && !ok {
-						logger.Warnf("%s is using above store (0) level compression.", t.FilePath)
+						logger.FromContext(t.ctx).Warnf("%s is using above store (0) level compression.", t.FilePath)
 					}
 
-					logger.Infof("%s doesn't exist.  Creating new item...", t.FilePath)
+					logger.FromContext(t.ctx).Infof("%s doesn't exist.  Creating new item...", t.FilePath)
 					g, err = qb.Create(newGallery)
 					if err != nil {
 						return err
 					}
 					scanImages = true
 
-					GetInstance().PluginCache.ExecutePostHooks(t.ctx, g.ID, plugin.GalleryCreatePost, nil, nil)
+					hookType = plugin.GalleryCreatePost
 				}
 			}
 
 			return nil
 		}); err != nil {
-			logger.Error(err.Error())
+			logger.FromContext(t.ctx).Error(err.Error())
 			return
 		}
+
+		if hookType != "" {
+			t.executePostHooks(g.ID, hookType, oldPath)
+		}
 	}
 
 	if g != nil {
@@ -524,7 +593,7 @@ func (t *ScanTask) associateGallery(wg *sizedwaitgroup.SizedWaitGroup) {
 		if g == nil {
 			// associate is run after scan is finished
 			// should only happen if gallery is a directory or an io error occurs during hashing
-			logger.Warnf("associate: gallery %s not found in DB", t.FilePath)
+			logger.FromContext(t.ctx).Warnf("associate: gallery %s not found in DB", t.FilePath)
 			return nil
 		}
 
@@ -552,7 +621,7 @@ func (t *ScanTask) associateGallery(wg *sizedwaitgroup.SizedWaitGroup) {
 					}
 				}
 				if !isAssoc {
-					logger.Infof("associate: Gallery %s is related to scene: %d", t.FilePath, scene.ID)
+					logger.FromContext(t.ctx).Infof("associate: Gallery %s is related to scene: %d", t.FilePath, scene.ID)
 					if err := sqb.UpdateGalleries(scene.ID, []int{g.ID}); err != nil {
 						return err
 					}
@@ -561,26 +630,33 @@ func (t *ScanTask) associateGallery(wg *sizedwaitgroup.SizedWaitGroup) {
 		}
 		return nil
 	}); err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 	}
 	wg.Done()
 }
 
 func (t *ScanTask) scanScene() *models.Scene {
 	logError := func(err error) *models.Scene {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 		return nil
 	}
 
 	var retScene *models.Scene
 	var s *models.Scene
+	var f *models.SceneFile
 
 	if err := t.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		qb := r.Scene()
 		var err error
-		s, err = r.Scene().FindByPath(t.FilePath)
+		f, err = qb.FindFileByPath(t.FilePath)
+		if err != nil || f == nil {
+			return err
+		}
+
+		s, err = qb.Find(f.SceneID)
 		return err
 	}); err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 		return nil
 	}
 
@@ -590,125 +666,9 @@ func (t *ScanTask) scanScene() *models.Scene {
 	}
 	interactive := t.getInteractive()
 
-	if s != nil {
-		// if file mod time is not set, set it now
-		if !s.FileModTime.Valid {
-			logger.Infof("setting file modification time on %s", t.FilePath)
-
-			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-				qb := r.Scene()
-				if _, err := scene.UpdateFileModTime(qb, s.ID, models.NullSQLiteTimestamp{
-					Timestamp: fileModTime,
-					Valid:     true,
-				}); err != nil {
-					return err
-				}
-
-				// update our copy of the scene
-				var err error
-				s, err = qb.Find(s.ID)
-				return err
-			}); err != nil {
-				return logError(err)
-			}
-		}
-
-		// if the mod time of the file is different than that of the associated
-		// scene, then recalculate the checksum and regenerate the thumbnail
-		modified := t.isFileModified(fileModTime, s.FileModTime)
-		config := config.GetInstance()
-		if modified || !s.Size.Valid {
-			oldHash := s.GetHash(config.GetVideoFileNamingAlgorithm())
-			s, err = t.rescanScene(s, fileModTime)
-			if err != nil {
-				return logError(err)
-			}
-
-			// Migrate any generated files if the hash has changed
-			newHash := s.GetHash(config.GetVideoFileNamingAlgorithm())
-			if newHash != oldHash {
-				MigrateHash(oldHash, newHash)
-			}
-		}
-
-		// We already have this item in the database
-		// check for thumbnails,screenshots
-		t.makeScreenshots(nil, s.GetHash(t.fileNamingAlgorithm))
-
-		// check for container
-		if !s.Format.Valid {
-			videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
-			if err != nil {
-				return logError(err)
-			}
-			container := ffmpeg.MatchContainer(videoFile.Container, t.FilePath)
-			logger.Infof("Adding container %s to file %s", container, t.FilePath)
-
-			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-				_, err := scene.UpdateFormat(r.Scene(), s.ID, string(container))
-				return err
-			}); err != nil {
-				return logError(err)
-			}
-		}
-
-		// check if oshash is set
-		if !s.OSHash.Valid {
-			logger.Infof("Calculating oshash for existing file %s ...", t.FilePath)
-			oshash, err := utils.OSHashFromFilePath(t.FilePath)
-			if err != nil {
-				return nil
-			}
-
-			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-				qb := r.Scene()
-				// check if oshash clashes with existing scene
-				dupe, _ := qb.FindByOSHash(oshash)
-				if dupe != nil {
-					return fmt.Errorf("OSHash for file %s is the same as that of %s", t.FilePath, dupe.Path)
-				}
-
-				_, err := scene.UpdateOSHash(qb, s.ID, oshash)
-				return err
-			}); err != nil {
-				return logError(err)
-			}
-		}
-
-		// check if MD5 is set, if calculateMD5 is true
-		if t.calculateMD5 && !s.Checksum.Valid {
-			checksum, err := t.calculateChecksum()
-			if err != nil {
-				return logError(err)
-			}
-
-			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-				qb := r.Scene()
-				// check if checksum clashes with existing scene
-				dupe, _ := qb.FindByChecksum(checksum)
-				if dupe != nil {
-					return fmt.Errorf("MD5 for file %s is the same as that of %s", t.FilePath, dupe.Path)
-				}
-
-				_, err := scene.UpdateChecksum(qb, s.ID, checksum)
-				return err
-			}); err != nil {
-				return logError(err)
-			}
-		}
-
-		if s.Interactive != interactive {
-			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-				qb := r.Scene()
-				scenePartial := models.ScenePartial{
-					ID:          s.ID,
-					Interactive: &interactive,
-				}
-				_, err := qb.Update(scenePartial)
-				return err
-			}); err != nil {
-				return logError(err)
-			}
+	if f != nil {
+		if err := t.scanExistingSceneFile(s, f, fileModTime, interactive); err != nil {
+			return logError(err)
 		}
 
 		return nil
@@ -721,7 +681,7 @@ func (t *ScanTask) scanScene() *models.Scene {
 
 	videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 		return nil
 	}
 	container := ffmpeg.MatchContainer(videoFile.Container, t.FilePath)
@@ -733,7 +693,7 @@ func (t *ScanTask) scanScene() *models.Scene {
 
 	var checksum string
 
-	logger.Infof("%s not found. Calculating oshash...", t.FilePath)
+	logger.FromContext(t.ctx).Infof("%s not found. Calculating oshash...", t.FilePath)
 	oshash, err := utils.OSHashFromFilePath(t.FilePath)
 	if err != nil {
 		return logError(err)
@@ -746,80 +706,129 @@ func (t *ScanTask) scanScene() *models.Scene {
 		}
 	}
 
-	// check for scene by checksum and oshash - MD5 should be
+	// check for files by checksum and oshash - MD5 should be
 	// redundant, but check both
+	var existingFiles []*models.SceneFile
 	t.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
 		qb := r.Scene()
 		if checksum != "" {
-			s, _ = qb.FindByChecksum(checksum)
+			existingFiles, _ = qb.FindFilesByChecksum(checksum)
 		}
 
-		if s == nil {
-			s, _ = qb.FindByOSHash(oshash)
+		if len(existingFiles) == 0 {
+			existingFiles, _ = qb.FindFilesByOSHash(oshash)
 		}
 
 		return nil
 	})
 
-	sceneHash := oshash
-
-	if t.fileNamingAlgorithm == models.HashAlgorithmMd5 {
-		sceneHash = checksum
+	currentTime := time.Now()
+	newFile := models.SceneFile{
+		Checksum:    sql.NullString{String: checksum, Valid: checksum != ""},
+		OSHash:      sql.NullString{String: oshash, Valid: oshash != ""},
+		Path:        t.FilePath,
+		Duration:    sql.NullFloat64{Float64: videoFile.Duration, Valid: true},
+		VideoCodec:  sql.NullString{String: videoFile.VideoCodec, Valid: true},
+		AudioCodec:  sql.NullString{String: videoFile.AudioCodec, Valid: true},
+		Format:      sql.NullString{String: string(container), Valid: true},
+		Width:       sql.NullInt64{Int64: int64(videoFile.Width), Valid: true},
+		Height:      sql.NullInt64{Int64: int64(videoFile.Height), Valid: true},
+		Framerate:   sql.NullFloat64{Float64: videoFile.FrameRate, Valid: true},
+		Bitrate:     sql.NullInt64{Int64: videoFile.Bitrate, Valid: true},
+		Size:        sql.NullString{String: strconv.FormatInt(videoFile.Size, 10), Valid: true},
+		Interactive: interactive,
+		FileModTime: models.NullSQLiteTimestamp{
+			Timestamp: fileModTime,
+			Valid:     true,
+		},
+		CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
 	}
 
-	t.makeScreenshots(videoFile, sceneHash)
+	if len(existingFiles) > 0 {
+		// a file with the same hash belongs to an existing scene. If that file
+		// is no longer present, then this file has been moved. Otherwise, it
+		// is another copy of the same scene.
+		var moved *models.SceneFile
+		for _, ef := range existingFiles {
+			exists, _ := utils.FileExists(ef.Path)
+			if !t.CaseSensitiveFs {
+				// #1426 - if file exists but is a case-insensitive match for the
+				// original filename, then treat it as a move
+				if exists && strings.EqualFold(t.FilePath, ef.Path) {
+					exists = false
+				}
+			}
 
-	if s != nil {
-		exists, _ := utils.FileExists(s.Path)
-		if !t.CaseSensitiveFs {
-			// #1426 - if file exists but is a case-insensitive match for the
-			// original filename, then treat it as a move
-			if exists && strings.EqualFold(t.FilePath, s.Path) {
-				exists = false
+			if !exists {
+				moved = ef
+				break
 			}
 		}
 
-		if exists {
-			logger.Infof("%s already exists. Duplicate of %s", t.FilePath, s.Path)
-		} else {
-			logger.Infof("%s already exists. Updating path...", t.FilePath)
-			scenePartial := models.ScenePartial{
-				ID:          s.ID,
+		sceneID := existingFiles[0].SceneID
+		hookType := plugin.SceneUpdatePost
+		oldPath := ""
+
+		if moved != nil {
+			logger.FromContext(t.ctx).Infof("%s already exists. Updating path...", t.FilePath)
+			sceneID = moved.SceneID
+			hookType = plugin.SceneFileMovedPost
+			oldPath = moved.Path
+			filePartial := models.SceneFilePartial{
+				ID:          moved.ID,
 				Path:        &t.FilePath,
 				Interactive: &interactive,
 			}
 			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-				_, err := r.Scene().Update(scenePartial)
+				_, err := r.Scene().UpdateFile(filePartial)
 				return err
 			}); err != nil {
 				return logError(err)
 			}
+		} else {
+			logger.FromContext(t.ctx).Infof("%s already exists. Adding as a file of %s", t.FilePath, existingFiles[0].Path)
+			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+				const primary = false
+				_, err := scene.AddFile(r.Scene(), sceneID, newFile, primary)
+				return err
+			}); err != nil {
+				return logError(err)
+			}
+		}
 
-			GetInstance().PluginCache.ExecutePostHooks(t.ctx, s.ID, plugin.SceneUpdatePost, nil, nil)
+		if err := t.scanFileStreams(videoFile); err != nil {
+			logger.FromContext(t.ctx).Errorf("error scanning captions and tracks of %s: %s", t.FilePath, err.Error())
 		}
+
+		t.executePostHooks(sceneID, hookType, oldPath)
 	} else {
-		logger.Infof("%s doesn't exist. Creating new item...", t.FilePath)
-		currentTime := time.Now()
+		sceneHash := oshash
+
+		if t.fileNamingAlgorithm == models.HashAlgorithmMd5 {
+			sceneHash = checksum
+		}
+
+		t.makeScreenshots(videoFile, sceneHash)
+
+		logger.FromContext(t.ctx).Infof("%s doesn't exist. Creating new item...", t.FilePath)
 		newScene := models.Scene{
-			Checksum:   sql.NullString{String: checksum, Valid: checksum != ""},
-			OSHash:     sql.NullString{String: oshash, Valid: oshash != ""},
-			Path:       t.FilePath,
-			Title:      sql.NullString{String: videoFile.Title, Valid: true},
-			Duration:   sql.NullFloat64{Float64: videoFile.Duration, Valid: true},
-			VideoCodec: sql.NullString{String: videoFile.VideoCodec, Valid: true},
-			AudioCodec: sql.NullString{String: videoFile.AudioCodec, Valid: true},
-			Format:     sql.NullString{String: string(container), Valid: true},
-			Width:      sql.NullInt64{Int64: int64(videoFile.Width), Valid: true},
-			Height:     sql.NullInt64{Int64: int64(videoFile.Height), Valid: true},
-			Framerate:  sql.NullFloat64{Float64: videoFile.FrameRate, Valid: true},
-			Bitrate:    sql.NullInt64{Int64: videoFile.Bitrate, Valid: true},
-			Size:       sql.NullString{String: strconv.FormatInt(videoFile.Size, 10), Valid: true},
-			FileModTime: models.NullSQLiteTimestamp{
-				Timestamp: fileModTime,
-				Valid:     true,
-			},
-			CreatedAt:   models.SQLiteTimestamp{Timestamp: currentTime},
-			UpdatedAt:   models.SQLiteTimestamp{Timestamp: currentTime},
+			Checksum:    newFile.Checksum,
+			OSHash:      newFile.OSHash,
+			Path:        newFile.Path,
+			Title:       sql.NullString{String: videoFile.Title, Valid: true},
+			Duration:    newFile.Duration,
+			VideoCodec:  newFile.VideoCodec,
+			AudioCodec:  newFile.AudioCodec,
+			Format:      newFile.Format,
+			Width:       newFile.Width,
+			Height:      newFile.Height,
+			Framerate:   newFile.Framerate,
+			Bitrate:     newFile.Bitrate,
+			Size:        newFile.Size,
+			FileModTime: newFile.FileModTime,
+			CreatedAt:   newFile.CreatedAt,
+			UpdatedAt:   newFile.UpdatedAt,
 			Interactive: interactive,
 		}
 
@@ -836,27 +845,197 @@ func (t *ScanTask) scanScene() *models.Scene {
 			return logError(err)
 		}
 
-		GetInstance().PluginCache.ExecutePostHooks(t.ctx, retScene.ID, plugin.SceneCreatePost, nil, nil)
+		if err := t.scanFileStreams(videoFile); err != nil {
+			logger.FromContext(t.ctx).Errorf("error scanning captions and tracks of %s: %s", t.FilePath, err.Error())
+		}
+
+		t.executePostHooks(retScene.ID, plugin.SceneCreatePost, "")
 	}
 
 	return retScene
 }
 
-func (t *ScanTask) rescanScene(s *models.Scene, fileModTime time.Time) (*models.Scene, error) {
-	logger.Infof("%s has been updated: rescanning", t.FilePath)
+// scanExistingSceneFile updates the details of a file that is already in the
+// database.
+func (t *ScanTask) scanExistingSceneFile(s *models.Scene, f *models.SceneFile, fileModTime time.Time, interactive bool) error {
+	// set if the file is probed
+	var videoFile *ffmpeg.VideoFile
+
+	// if file mod time is not set, set it now
+	if !f.FileModTime.Valid {
+		logger.FromContext(t.ctx).Infof("setting file modification time on %s", t.FilePath)
+
+		if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+			var err error
+			f, err = scene.UpdateFileModTime(r.Scene(), f.ID, models.NullSQLiteTimestamp{
+				Timestamp: fileModTime,
+				Valid:     true,
+			})
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	// if the mod time of the file is different than that of the associated
+	// file, then recalculate the checksum and regenerate the thumbnail
+	modified := t.isFileModified(fileModTime, f.FileModTime)
+	config := config.GetInstance()
+	if modified || !f.Size.Valid {
+		oldHash := f.GetHash(config.GetVideoFileNamingAlgorithm())
+		var err error
+		f, videoFile, err = t.rescanSceneFile(f, fileModTime)
+		if err != nil {
+			return err
+		}
+
+		// Migrate any generated files if the hash of the primary file has
+		// changed
+		newHash := f.GetHash(config.GetVideoFileNamingAlgorithm())
+		if f.Primary && newHash != oldHash {
+			MigrateHash(oldHash, newHash)
+		}
+	}
+
+	// We already have this item in the database
+	// check for thumbnails,screenshots. These are only generated for
+	// the primary file of the scene.
+	if f.Primary {
+		t.makeScreenshots(nil, f.GetHash(t.fileNamingAlgorithm))
+	}
+
+	// check for container
+	if !f.Format.Valid {
+		var err error
+		videoFile, err = ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
+		if err != nil {
+			return err
+		}
+		container := ffmpeg.MatchContainer(videoFile.Container, t.FilePath)
+		logger.FromContext(t.ctx).Infof("Adding container %s to file %s", container, t.FilePath)
+
+		if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+			_, err := scene.UpdateFormat(r.Scene(), f.ID, string(container))
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	// check if oshash is set
+	if !f.OSHash.Valid {
+		logger.FromContext(t.ctx).Infof("Calculating oshash for existing file %s ...", t.FilePath)
+		oshash, err := utils.OSHashFromFilePath(t.FilePath)
+		if err != nil {
+			return nil
+		}
+
+		if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+			qb := r.Scene()
+			// check if oshash clashes with a file of another scene
+			dupes, _ := qb.FindFilesByOSHash(oshash)
+			for _, dupe := range dupes {
+				if dupe.SceneID != s.ID {
+					return fmt.Errorf("OSHash for file %s is the same as that of %s", t.FilePath, dupe.Path)
+				}
+			}
+
+			_, err := scene.UpdateOSHash(qb, f.ID, oshash)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	// check if MD5 is set, if calculateMD5 is true
+	if t.calculateMD5 && !f.Checksum.Valid {
+		checksum, err := t.calculateChecksum()
+		if err != nil {
+			return err
+		}
+
+		if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+			qb := r.Scene()
+			// check if checksum clashes with a file of another scene
+			dupes, _ := qb.FindFilesByChecksum(checksum)
+			for _, dupe := range dupes {
+				if dupe.SceneID != s.ID {
+					return fmt.Errorf("MD5 for file %s is the same as that of %s", t.FilePath, dupe.Path)
+				}
+			}
+
+			_, err := scene.UpdateChecksum(qb, f.ID, checksum)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	if f.Interactive != interactive {
+		if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+			filePartial := models.SceneFilePartial{
+				ID:          f.ID,
+				Interactive: &interactive,
+			}
+			_, err := r.Scene().UpdateFile(filePartial)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	// the streams of the file are only updated if the file was probed
+	return t.scanFileStreams(videoFile)
+}
+
+// scanFileStreams updates the captions of the scene file from the caption
+// files next to it. The subtitle streams and the tracks are only updated if
+// videoFile is not nil.
+func (t *ScanTask) scanFileStreams(videoFile *ffmpeg.VideoFile) error {
+	captionFiles, err := scene.FindCaptionFiles(t.FilePath)
+	if err != nil {
+		return err
+	}
+
+	var streams []*models.SceneCaption
+	if videoFile != nil {
+		streams = scene.GetEmbeddedCaptions(videoFile)
+	}
+
+	return t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		qb := r.Scene()
+		f, err := qb.FindFileByPath(t.FilePath)
+		if err != nil || f == nil {
+			return err
+		}
+
+		if err := scene.UpdateCaptions(qb, f.ID, captionFiles, streams); err != nil {
+			return err
+		}
+
+		if videoFile != nil {
+			return qb.UpdateFileTracks(f.ID, scene.GetTracks(videoFile))
+		}
+
+		return nil
+	})
+}
+
+func (t *ScanTask) rescanSceneFile(f *models.SceneFile, fileModTime time.Time) (*models.SceneFile, *ffmpeg.VideoFile, error) {
+	logger.FromContext(t.ctx).Infof("%s has been updated: rescanning", t.FilePath)
 
 	// update the oshash/checksum and the modification time
-	logger.Infof("Calculating oshash for existing file %s ...", t.FilePath)
+	logger.FromContext(t.ctx).Infof("Calculating oshash for existing file %s ...", t.FilePath)
 	oshash, err := utils.OSHashFromFilePath(t.FilePath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var checksum *sql.NullString
 	if t.calculateMD5 {
 		cs, err := t.calculateChecksum()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		checksum = &sql.NullString{
@@ -868,13 +1047,13 @@ func (t *ScanTask) rescanScene(s *models.Scene, fileModTime time.Time) (*models.
 	// regenerate the file details as well
 	videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	container := ffmpeg.MatchContainer(videoFile.Container, t.FilePath)
 
 	currentTime := time.Now()
-	scenePartial := models.ScenePartial{
-		ID:       s.ID,
+	filePartial := models.SceneFilePartial{
+		ID:       f.ID,
 		Checksum: checksum,
 		OSHash: &sql.NullString{
 			String: oshash,
@@ -896,22 +1075,22 @@ func (t *ScanTask) rescanScene(s *models.Scene, fileModTime time.Time) (*models.
 		UpdatedAt: &models.SQLiteTimestamp{Timestamp: currentTime},
 	}
 
-	var ret *models.Scene
+	var ret *models.SceneFile
 	if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
 		var err error
-		ret, err = r.Scene().Update(scenePartial)
+		ret, err = r.Scene().UpdateFile(filePartial)
 		return err
 	}); err != nil {
-		logger.Error(err.Error())
-		return nil, err
+		logger.FromContext(t.ctx).Error(err.Error())
+		return nil, nil, err
 	}
 
-	GetInstance().PluginCache.ExecutePostHooks(t.ctx, ret.ID, plugin.SceneUpdatePost, nil, nil)
+	t.executePostHooks(ret.SceneID, plugin.SceneUpdatePost, "")
 
 	// leave the generated files as is - the scene file may have been moved
 	// elsewhere
 
-	return ret, nil
+	return ret, videoFile, nil
 }
 func (t *ScanTask) makeScreenshots(probeResult *ffmpeg.VideoFile, checksum string) {
 	thumbPath := instance.Paths.Scene.GetThumbnailScreenshotPath(checksum)
@@ -929,31 +1108,33 @@ func (t *ScanTask) makeScreenshots(probeResult *ffmpeg.VideoFile, checksum strin
 		probeResult, err = ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
 
 		if err != nil {
-			logger.Error(err.Error())
+			logger.FromContext(t.ctx).Error(err.Error())
 			return
 		}
-		logger.Infof("Regenerating images for %s", t.FilePath)
+		logger.FromContext(t.ctx).Infof("Regenerating images for %s", t.FilePath)
 	}
 
 	at := float64(probeResult.Duration) * 0.2
 
 	if !thumbExists {
-		logger.Debugf("Creating thumbnail for %s", t.FilePath)
+		logger.FromContext(t.ctx).Debugf("Creating thumbnail for %s", t.FilePath)
 		makeScreenshot(*probeResult, thumbPath, 5, 320, at)
 	}
 
 	if !normalExists {
-		logger.Debugf("Creating screenshot for %s", t.FilePath)
+		logger.FromContext(t.ctx).Debugf("Creating screenshot for %s", t.FilePath)
 		makeScreenshot(*probeResult, normalPath, 2, probeResult.Width, at)
 	}
 }
 
 func (t *ScanTask) scanZipImages(zipGallery *models.Gallery) {
-	err := walkGalleryZip(zipGallery.Path.String, func(file *zip.File) error {
+	// the images are scanned in archive order, so that archives that can
+	// only be read in order are decompressed once
+	err := walkGalleryArchive(zipGallery.Path.String, func(file archive.File) error {
 		// copy this task and change the filename
 		subTask := *t
 
-		// filepath is the zip file and the internal file name, separated by a null byte
+		// filepath is the archive file and the internal file name, separated by a null byte
 		subTask.FilePath = image.ZipFilename(zipGallery.Path.String, file.Name)
 		subTask.zipGallery = zipGallery
 
@@ -964,7 +1145,7 @@ func (t *ScanTask) scanZipImages(zipGallery *models.Gallery) {
 		return nil
 	})
 	if err != nil {
-		logger.Warnf("failed to scan zip file images for %s: %s", zipGallery.Path.String, err.Error())
+		logger.FromContext(t.ctx).Warnf("failed to scan archive file images for %s: %s", zipGallery.Path.String, err.Error())
 	}
 }
 
@@ -977,7 +1158,7 @@ func (t *ScanTask) regenerateZipImages(zipGallery *models.Gallery) {
 		images, err = iqb.FindByGalleryID(zipGallery.ID)
 		return err
 	}); err != nil {
-		logger.Warnf("failed to find gallery images: %s", err.Error())
+		logger.FromContext(t.ctx).Warnf("failed to find gallery images: %s", err.Error())
 		return
 	}
 
@@ -994,20 +1175,20 @@ func (t *ScanTask) scanImage() {
 		i, err = r.Image().FindByPath(t.FilePath)
 		return err
 	}); err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 		return
 	}
 
 	fileModTime, err := image.GetFileModTime(t.FilePath)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.FromContext(t.ctx).Error(err.Error())
 		return
 	}
 
 	if i != nil {
 		// if file mod time is not set, set it now
 		if !i.FileModTime.Valid {
-			logger.Infof("setting file modification time on %s", t.FilePath)
+			logger.FromContext(t.ctx).Infof("setting file modification time on %s", t.FilePath)
 
 			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
 				qb := r.Image()
@@ -1023,7 +1204,15 @@ func (t *ScanTask) scanImage() {
 				i, err = qb.Find(i.ID)
 				return err
 			}); err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(t.ctx).Error(err.Error())
+				return
+			}
+		}
+
+		// if the animation is not set, set it now
+		if !i.Duration.Valid && !i.IsVideo {
+			if err := t.setImageAnimation(i); err != nil {
+				logger.FromContext(t.ctx).Error(err.Error())
 				return
 			}
 		}
@@ -1034,7 +1223,7 @@ func (t *ScanTask) scanImage() {
 		if modified {
 			i, err = t.rescanImage(i, fileModTime)
 			if err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(t.ctx).Error(err.Error())
 				return
 			}
 		}
@@ -1050,10 +1239,10 @@ func (t *ScanTask) scanImage() {
 
 		var checksum string
 
-		logger.Infof("%s not found.  Calculating checksum...", t.FilePath)
+		logger.FromContext(t.ctx).Infof("%s not found.  Calculating checksum...", t.FilePath)
 		checksum, err = t.calculateImageChecksum()
 		if err != nil {
-			logger.Errorf("error calculating checksum for %s: %s", t.FilePath, err.Error())
+			logger.FromContext(t.ctx).Errorf("error calculating checksum for %s: %s", t.FilePath, err.Error())
 			return
 		}
 
@@ -1064,7 +1253,7 @@ func (t *ScanTask) scanImage() {
 			i, err = r.Image().FindByChecksum(checksum)
 			return err
 		}); err != nil {
-			logger.Error(err.Error())
+			logger.FromContext(t.ctx).Error(err.Error())
 			return
 		}
 
@@ -1079,9 +1268,10 @@ func (t *ScanTask) scanImage() {
 			}
 
 			if exists {
-				logger.Infof("%s already exists.  Duplicate of %s ", image.PathDisplayName(t.FilePath), image.PathDisplayName(i.Path))
+				logger.FromContext(t.ctx).Infof("%s already exists.  Duplicate of %s ", image.PathDisplayName(t.FilePath), image.PathDisplayName(i.Path))
 			} else {
-				logger.Infof("%s already exists.  Updating path...", image.PathDisplayName(t.FilePath))
+				logger.FromContext(t.ctx).Infof("%s already exists.  Updating path...", image.PathDisplayName(t.FilePath))
+				oldPath := i.Path
 				imagePartial := models.ImagePartial{
 					ID:   i.ID,
 					Path: &t.FilePath,
@@ -1091,14 +1281,14 @@ func (t *ScanTask) scanImage() {
 					_, err := r.Image().Update(imagePartial)
 					return err
 				}); err != nil {
-					logger.Error(err.Error())
+					logger.FromContext(t.ctx).Error(err.Error())
 					return
 				}
 
-				GetInstance().PluginCache.ExecutePostHooks(t.ctx, i.ID, plugin.ImageUpdatePost, nil, nil)
+				t.executePostHooks(i.ID, plugin.ImageFileMovedPost, oldPath)
 			}
 		} else {
-			logger.Infof("%s doesn't exist.  Creating new item...", image.PathDisplayName(t.FilePath))
+			logger.FromContext(t.ctx).Infof("%s doesn't exist.  Creating new item...", image.PathDisplayName(t.FilePath))
 			currentTime := time.Now()
 			newImage := models.Image{
 				Checksum: checksum,
@@ -1113,8 +1303,8 @@ func (t *ScanTask) scanImage() {
 			newImage.Title.String = image.GetFilename(&newImage, t.StripFileExtension)
 			newImage.Title.Valid = true
 
-			if err := image.SetFileDetails(&newImage); err != nil {
-				logger.Error(err.Error())
+			if err := setImageFileDetails(&newImage); err != nil {
+				logger.FromContext(t.ctx).Error(err.Error())
 				return
 			}
 
@@ -1123,11 +1313,11 @@ func (t *ScanTask) scanImage() {
 				i, err = r.Image().Create(newImage)
 				return err
 			}); err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(t.ctx).Error(err.Error())
 				return
 			}
 
-			GetInstance().PluginCache.ExecutePostHooks(t.ctx, i.ID, plugin.ImageCreatePost, nil, nil)
+			t.executePostHooks(i.ID, plugin.ImageCreatePost, "")
 		}
 
 		if t.zipGallery != nil {
@@ -1135,18 +1325,25 @@ func (t *ScanTask) scanImage() {
 			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
 				return gallery.AddImage(r.Gallery(), t.zipGallery.ID, i.ID)
 			}); err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(t.ctx).Error(err.Error())
 				return
 			}
 		} else if config.GetInstance().GetCreateGalleriesFromFolders() {
 			// create gallery from folder or associate with existing gallery
-			logger.Infof("Associating image %s with folder gallery", i.Path)
+			logger.FromContext(t.ctx).Infof("Associating image %s with folder gallery", i.Path)
+			var created *models.Gallery
 			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-				return t.associateImageWithFolderGallery(i.ID, r.Gallery())
+				var err error
+				created, err = t.associateImageWithFolderGallery(i.ID, r.Gallery())
+				return err
 			}); err != nil {
-				logger.Error(err.Error())
+				logger.FromContext(t.ctx).Error(err.Error())
 				return
 			}
+
+			if created != nil {
+				t.executePostHooks(created.ID, plugin.GalleryCreatePost, "")
+			}
 		}
 	}
 
@@ -1156,7 +1353,7 @@ func (t *ScanTask) scanImage() {
 }
 
 func (t *ScanTask) rescanImage(i *models.Image, fileModTime time.Time) (*models.Image, error) {
-	logger.Infof("%s has been updated: rescanning", t.FilePath)
+	logger.FromContext(t.ctx).Infof("%s has been updated: rescanning", t.FilePath)
 
 	oldChecksum := i.Checksum
 
@@ -1167,18 +1364,21 @@ func (t *ScanTask) rescanImage(i *models.Image, fileModTime time.Time) (*models.
 	}
 
 	// regenerate the file details as well
-	fileDetails, err := image.GetFileDetails(t.FilePath)
-	if err != nil {
+	fileDetails := &models.Image{Path: t.FilePath}
+	if err := setImageFileDetails(fileDetails); err != nil {
 		return nil, err
 	}
 
 	currentTime := time.Now()
 	imagePartial := models.ImagePartial{
-		ID:       i.ID,
-		Checksum: &checksum,
-		Width:    &fileDetails.Width,
-		Height:   &fileDetails.Height,
-		Size:     &fileDetails.Size,
+		ID:         i.ID,
+		Checksum:   &checksum,
+		Width:      &fileDetails.Width,
+		Height:     &fileDetails.Height,
+		Size:       &fileDetails.Size,
+		Duration:   &fileDetails.Duration,
+		IsAnimated: &fileDetails.IsAnimated,
+		IsVideo:    &fileDetails.IsVideo,
 		FileModTime: &models.NullSQLiteTimestamp{
 			Timestamp: fileModTime,
 			Valid:     true,
@@ -1197,25 +1397,59 @@ func (t *ScanTask) rescanImage(i *models.Image, fileModTime time.Time) (*models.
 
 	// remove the old thumbnail if the checksum changed - we'll regenerate it
 	if oldChecksum != checksum {
-		err = os.Remove(GetInstance().Paths.Generated.GetThumbnailPath(oldChecksum, models.DefaultGthumbWidth)) // remove cache dir of gallery
-		if err != nil {
-			logger.Errorf("Error deleting thumbnail image: %s", err)
-		}
+		DeleteGeneratedImageFiles(&models.Image{Checksum: oldChecksum})
 	}
 
-	GetInstance().PluginCache.ExecutePostHooks(t.ctx, ret.ID, plugin.ImageUpdatePost, nil, nil)
+	t.executePostHooks(ret.ID, plugin.ImageUpdatePost, "")
 
 	return ret, nil
 }
 
-func (t *ScanTask) associateImageWithFolderGallery(imageID int, qb models.GalleryReaderWriter) error {
+// setImageFileDetails sets the file details of the image. Video clips are
+// probed with ffprobe.
+func setImageFileDetails(i *models.Image) error {
+	if !isVideo(i.Path) || image.IsZipPath(i.Path) {
+		return image.SetFileDetails(i)
+	}
+
+	videoFile, err := ffmpeg.NewVideoFile(GetInstance().FFProbePath, i.Path, false)
+	if err != nil {
+		return err
+	}
+
+	image.SetClipDetails(i, videoFile)
+	return nil
+}
+
+// setImageAnimation sets the animation of an image that was scanned before
+// animations were detected.
+func (t *ScanTask) setImageAnimation(i *models.Image) error {
+	logger.FromContext(t.ctx).Infof("setting animation on %s", image.PathDisplayName(t.FilePath))
+	image.SetAnimationDetails(i)
+
+	return t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		_, err := r.Image().Update(models.ImagePartial{
+			ID:         i.ID,
+			Duration:   &i.Duration,
+			IsAnimated: &i.IsAnimated,
+		})
+		return err
+	})
+}
+
+// associateImageWithFolderGallery adds the image to the gallery of its
+// folder, creating the gallery if it does not exist. Returns the gallery if it
+// was created.
+func (t *ScanTask) associateImageWithFolderGallery(imageID int, qb models.GalleryReaderWriter) (*models.Gallery, error) {
 	// find a gallery with the path specified
 	path := filepath.Dir(t.FilePath)
 	g, err := qb.FindByPath(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var created *models.Gallery
+
 	if g == nil {
 		checksum := utils.MD5FromString(path)
 
@@ -1236,63 +1470,79 @@ func (t *ScanTask) associateImageWithFolderGallery(imageID int, qb models.Galler
 			},
 		}
 
-		logger.Infof("Creating gallery for folder %s", path)
+		logger.FromContext(t.ctx).Infof("Creating gallery for folder %s", path)
 		g, err = qb.Create(newGallery)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		created = g
 	}
 
 	// associate image with gallery
-	err = gallery.AddImage(qb, g.ID, imageID)
-	return err
+	if err := gallery.AddImage(qb, g.ID, imageID); err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
 func (t *ScanTask) generateThumbnail(i *models.Image) {
-	thumbPath := GetInstance().Paths.Generated.GetThumbnailPath(i.Checksum, models.DefaultGthumbWidth)
+	if i.IsAnimated && t.generateAnimatedThumbnail(i) {
+		return
+	}
+
+	// the default thumbnail is generated up front, other sizes and formats
+	// are generated when they are first requested
+	formats := []image.ThumbnailFormat{image.ThumbnailFormatJPEG}
+	if _, _, err := GetInstance().ImageThumbnails.Get(t.ctx, i, models.DefaultGthumbWidth, formats); err != nil {
+		logger.FromContext(t.ctx).Errorf("error generating thumbnail for image %s: %s", i.Path, err.Error())
+	}
+}
+
+// generateAnimatedThumbnail generates the animated thumbnail of an animated
+// image or video clip. Returns false if a still thumbnail should be
+// generated instead. Animated images that are not larger than the thumbnail
+// do not need a thumbnail.
+func (t *ScanTask) generateAnimatedThumbnail(i *models.Image) bool {
+	thumbPath := GetInstance().Paths.Generated.GetAnimatedThumbnailPath(i.Checksum, models.DefaultGthumbWidth)
 	exists, _ := utils.FileExists(thumbPath)
 	if exists {
-		return
+		return true
 	}
 
-	srcImage, err := image.GetSourceImage(i)
-	if err != nil {
-		logger.Errorf("error reading image %s: %s", i.Path, err.Error())
-		return
+	maxSize := int64(models.DefaultGthumbWidth)
+	if !i.IsVideo && i.Width.Int64 <= maxSize && i.Height.Int64 <= maxSize {
+		return true
 	}
 
-	if image.ThumbnailNeeded(srcImage, models.DefaultGthumbWidth) {
-		data, err := image.GetThumbnail(srcImage, models.DefaultGthumbWidth)
-		if err != nil {
-			logger.Errorf("error getting thumbnail for image %s: %s", i.Path, err.Error())
-			return
-		}
+	if err := generateAnimatedThumbnail(i, thumbPath); err != nil {
+		logger.FromContext(t.ctx).Warnf("error generating animated thumbnail for %s: %s", image.PathDisplayName(i.Path), err.Error())
 
-		err = utils.WriteFile(thumbPath, data)
-		if err != nil {
-			logger.Errorf("error writing thumbnail for image %s: %s", i.Path, err)
-		}
+		// the first frame of animated images is decoded instead
+		return i.IsVideo
 	}
+
+	return true
 }
 
 func (t *ScanTask) calculateChecksum() (string, error) {
-	logger.Infof("Calculating checksum for %s...", t.FilePath)
+	logger.FromContext(t.ctx).Infof("Calculating checksum for %s...", t.FilePath)
 	checksum, err := utils.MD5FromFilePath(t.FilePath)
 	if err != nil {
 		return "", err
 	}
-	logger.Debugf("Checksum calculated: %s", checksum)
+	logger.FromContext(t.ctx).Debugf("Checksum calculated: %s", checksum)
 	return checksum, nil
 }
 
 func (t *ScanTask) calculateImageChecksum() (string, error) {
-	logger.Infof("Calculating checksum for %s...", image.PathDisplayName(t.FilePath))
+	logger.FromContext(t.ctx).Infof("Calculating checksum for %s...", image.PathDisplayName(t.FilePath))
 	// uses image.CalculateMD5 to read files in zips
 	checksum, err := image.CalculateMD5(t.FilePath)
 	if err != nil {
 		return "", err
 	}
-	logger.Debugf("Checksum calculated: %s", checksum)
+	logger.FromContext(t.ctx).Debugf("Checksum calculated: %s", checksum)
 	return checksum, nil
 }
 
@@ -1309,12 +1559,12 @@ func (t *ScanTask) doesPathExist() bool {
 			if gallery != nil {
 				ret = true
 			}
-		} else if matchExtension(t.FilePath, vidExt) {
+		} else if matchExtension(t.FilePath, vidExt) && !isImageClip(t.FilePath) {
 			s, _ := r.Scene().FindByPath(t.FilePath)
 			if s != nil {
 				ret = true
 			}
-		} else if matchExtension(t.FilePath, imgExt) {
+		} else if matchExtension(t.FilePath, imgExt) || matchExtension(t.FilePath, vidExt) {
 			i, _ := r.Image().FindByPath(t.FilePath)
 			if i != nil {
 				ret = true
@@ -1327,13 +1577,65 @@ func (t *ScanTask) doesPathExist() bool {
 	return ret
 }
 
+// scanFilter determines which files and directories are scanned, based on
+// the configured extensions and exclusion patterns.
+type scanFilter struct {
+	vidExt          []string
+	imgExt          []string
+	gExt            []string
+	excludeVidRegex []*regexp.Regexp
+	excludeImgRegex []*regexp.Regexp
+	generatedPath   string
+	imageClips      bool
+}
+
+func newScanFilter(c *config.Instance) *scanFilter {
+	return &scanFilter{
+		vidExt:          c.GetVideoExtensions(),
+		imgExt:          c.GetImageExtensions(),
+		gExt:            c.GetGalleryExtensions(),
+		excludeVidRegex: generateRegexps(c.GetExcludes()),
+		excludeImgRegex: generateRegexps(c.GetImageExcludes()),
+		generatedPath:   c.GetGeneratedPath(),
+		imageClips:      c.GetCreateImageClipsFromVideos(),
+	}
+}
+
+// skipDir returns true if the directory and its contents should not be
+// scanned.
+func (f *scanFilter) skipDir(s *models.StashConfig, path string) bool {
+	// #1102 - ignore files in generated path
+	if utils.IsPathInDir(f.generatedPath, path) {
+		return true
+	}
+
+	// shortcut: skip the directory entirely if it matches both exclusion patterns
+	// add a trailing separator so that it correctly matches against patterns like path/.*
+	pathExcludeTest := path + string(filepath.Separator)
+	return (s.ExcludeVideo || matchFileRegex(pathExcludeTest, f.excludeVidRegex)) && (s.ExcludeImage || matchFileRegex(pathExcludeTest, f.excludeImgRegex))
+}
+
+// includeFile returns true if the file should be scanned.
+func (f *scanFilter) includeFile(s *models.StashConfig, path string) bool {
+	if !s.ExcludeVideo && matchExtension(path, f.vidExt) && !matchFileRegex(path, f.excludeVidRegex) {
+		return true
+	}
+
+	if !s.ExcludeImage {
+		imgExt := matchExtension(path, f.imgExt) || matchExtension(path, f.gExt)
+		// video files are scanned as image clips in directories that
+		// exclude videos
+		clip := f.imageClips && s.ExcludeVideo && matchExtension(path, f.vidExt)
+		if (imgExt || clip) && !matchFileRegex(path, f.excludeImgRegex) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func walkFilesToScan(s *models.StashConfig, f filepath.WalkFunc) error {
-	config := config.GetInstance()
-	vidExt := config.GetVideoExtensions()
-	imgExt := config.GetImageExtensions()
-	gExt := config.GetGalleryExtensions()
-	excludeVidRegex := generateRegexps(config.GetExcludes())
-	excludeImgRegex := generateRegexps(config.GetImageExcludes())
+	filter := newScanFilter(config.GetInstance())
 
 	// don't scan zip images directly
 	if image.IsZipPath(s.Path) {
@@ -1341,8 +1643,6 @@ func walkFilesToScan(s *models.StashConfig, f filepath.WalkFunc) error {
 		return nil
 	}
 
-	generatedPath := config.GetGeneratedPath()
-
 	return utils.SymWalk(s.Path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			logger.Warnf("error scanning %s: %s", path, err.Error())
@@ -1350,31 +1650,17 @@ func walkFilesToScan(s *models.StashConfig, f filepath.WalkFunc) error {
 		}
 
 		if info.IsDir() {
-			// #1102 - ignore files in generated path
-			if utils.IsPathInDir(generatedPath, path) {
-				return filepath.SkipDir
-			}
-
-			// shortcut: skip the directory entirely if it matches both exclusion patterns
-			// add a trailing separator so that it correctly matches against patterns like path/.*
-			pathExcludeTest := path + string(filepath.Separator)
-			if (s.ExcludeVideo || matchFileRegex(pathExcludeTest, excludeVidRegex)) && (s.ExcludeImage || matchFileRegex(pathExcludeTest, excludeImgRegex)) {
+			if filter.skipDir(s, path) {
 				return filepath.SkipDir
 			}
 
 			return nil
 		}
 
-		if !s.ExcludeVideo && matchExtension(path, vidExt) && !matchFileRegex(path, excludeVidRegex) {
+		if filter.includeFile(s, path) {
 			return f(path, info, err)
 		}
 
-		if !s.ExcludeImage {
-			if (matchExtension(path, imgExt) || matchExtension(path, gExt)) && !matchFileRegex(path, excludeImgRegex) {
-				return f(path, info, err)
-			}
-		}
-
 		return nil
 	})
 }
@@ -0,0 +1,30 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanFilterImageClips(t *testing.T) {
+	filter := &scanFilter{
+		vidExt:          []string{"mp4"},
+		imgExt:          []string{"gif"},
+		excludeImgRegex: generateRegexps([]string{`/excluded/`}),
+	}
+
+	videos := &models.StashConfig{Path: "/stash"}
+	images := &models.StashConfig{Path: "/stash", ExcludeVideo: true}
+
+	// video files are not scanned in directories that exclude videos
+	assert.True(t, filter.includeFile(videos, "/stash/clip.mp4"))
+	assert.False(t, filter.includeFile(images, "/stash/clip.mp4"))
+	assert.True(t, filter.includeFile(images, "/stash/loop.gif"))
+
+	// unless they are scanned as image clips
+	filter.imageClips = true
+	assert.True(t, filter.includeFile(images, "/stash/clip.mp4"))
+	assert.False(t, filter.includeFile(images, "/stash/excluded/clip.mp4"))
+	assert.False(t, filter.includeFile(&models.StashConfig{Path: "/stash", ExcludeVideo: true, ExcludeImage: true}, "/stash/clip.mp4"))
+}
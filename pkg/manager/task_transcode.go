@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"context"
+
 	"github.com/remeh/sizedwaitgroup"
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
@@ -11,6 +13,7 @@ import (
 )
 
 type GenerateTranscodeTask struct {
+	ctx                 context.Context
 	Scene               models.Scene
 	Overwrite           bool
 	fileNamingAlgorithm models.HashAlgorithm
@@ -32,7 +35,7 @@ func (t *GenerateTranscodeTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 		// shouldn't happen unless user hasn't scanned after updating to PR#384+ version
 		tmpVideoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.Scene.Path, false)
 		if err != nil {
-			logger.Errorf("[transcode] error reading video file: %s", err.Error())
+			logger.FromContext(t.ctx).Errorf("[transcode] error reading video file: %s", err.Error())
 			return
 		}
 
@@ -51,7 +54,7 @@ func (t *GenerateTranscodeTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 
 	videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.Scene.Path, false)
 	if err != nil {
-		logger.Errorf("[transcode] error reading video file: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("[transcode] error reading video file: %s", err.Error())
 		return
 	}
 
@@ -80,11 +83,11 @@ func (t *GenerateTranscodeTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 	}
 
 	if err := utils.SafeMove(outputPath, instance.Paths.Scene.GetTranscodePath(sceneHash)); err != nil {
-		logger.Errorf("[transcode] error generating transcode: %s", err.Error())
+		logger.FromContext(t.ctx).Errorf("[transcode] error generating transcode: %s", err.Error())
 		return
 	}
 
-	logger.Debugf("[transcode] <%s> created transcode: %s", sceneHash, outputPath)
+	logger.FromContext(t.ctx).Debugf("[transcode] <%s> created transcode: %s", sceneHash, outputPath)
 }
 
 // return true if transcode is needed
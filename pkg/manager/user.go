@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// migrateConfigCredentials creates an admin user from the username, password
+// and API key in the configuration file, if there are no users in the
+// database. The credentials are then removed from the configuration file.
+func migrateConfigCredentials(txnManager models.TransactionManager, c *config.Instance) {
+	if !c.HasCredentials() {
+		return
+	}
+
+	username, passwordHash := c.GetCredentials()
+	apiKey := c.GetAPIKey()
+
+	migrated := false
+	if err := txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		qb := r.User()
+
+		count, err := qb.Count()
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return nil
+		}
+
+		currentTime := time.Now()
+		u, err := qb.Create(models.User{
+			Username:  username,
+			Password:  passwordHash,
+			Role:      models.UserRoleAdmin,
+			APIKey:    sql.NullString{String: apiKey, Valid: apiKey != ""},
+			CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+			UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		})
+		if err != nil {
+			return err
+		}
+
+		// the existing ratings and o-counters belong to the new admin
+		if err := qb.TransferUserData(0, u.ID); err != nil {
+			return err
+		}
+
+		migrated = true
+		return nil
+	}); err != nil {
+		logger.Errorf("Error migrating credentials to admin user: %s", err.Error())
+		return
+	}
+
+	if migrated {
+		logger.Infof("Migrated credentials of %s to admin user", username)
+	}
+
+	// credentials in the configuration file are not used once users exist
+
+	c.Set(config.Username, "")
+	c.Set(config.Password, "")
+	c.Set(config.ApiKey, "")
+	if err := c.Write(); err != nil {
+		logger.Errorf("Error while writing configuration file: %s", err.Error())
+	}
+}
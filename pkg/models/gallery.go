@@ -16,6 +16,9 @@ type GalleryReader interface {
 	GetTagIDs(galleryID int) ([]int, error)
 	GetSceneIDs(galleryID int) ([]int, error)
 	GetImageIDs(galleryID int) ([]int, error)
+	// GetOrderedImageIDs returns the ids of the images of the gallery that
+	// have a position, in position order.
+	GetOrderedImageIDs(galleryID int) ([]int, error)
 }
 
 type GalleryWriter interface {
@@ -28,6 +31,10 @@ type GalleryWriter interface {
 	UpdateTags(galleryID int, tagIDs []int) error
 	UpdateScenes(galleryID int, sceneIDs []int) error
 	UpdateImages(galleryID int, imageIDs []int) error
+	// UpdateImageOrder sets the positions of the images of the gallery to
+	// their order in imageIDs. The images not in imageIDs have no position,
+	// and are ordered after the other images by path.
+	UpdateImageOrder(galleryID int, imageIDs []int) error
 }
 
 type GalleryReaderWriter interface {
@@ -0,0 +1,20 @@
+package models
+
+type GalleryChapterReader interface {
+	Find(id int) (*GalleryChapter, error)
+	FindMany(ids []int) ([]*GalleryChapter, error)
+	// FindByGalleryID returns the chapters of the gallery, ordered by image
+	// index.
+	FindByGalleryID(galleryID int) ([]*GalleryChapter, error)
+}
+
+type GalleryChapterWriter interface {
+	Create(newGalleryChapter GalleryChapter) (*GalleryChapter, error)
+	Update(updatedGalleryChapter GalleryChapter) (*GalleryChapter, error)
+	Destroy(id int) error
+}
+
+type GalleryChapterReaderWriter interface {
+	GalleryChapterReader
+	GalleryChapterWriter
+}
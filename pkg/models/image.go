@@ -21,15 +21,22 @@ type ImageReader interface {
 	GetGalleryIDs(imageID int) ([]int, error)
 	GetTagIDs(imageID int) ([]int, error)
 	GetPerformerIDs(imageID int) ([]int, error)
+	// GetUserData returns the values of the image for the user. Returns
+	// default values if the user has not set any values.
+	GetUserData(imageID int, userID int) (*UserData, error)
+	GetAllUserData(imageID int) ([]*UserData, error)
 }
 
 type ImageWriter interface {
 	Create(newImage Image) (*Image, error)
 	Update(updatedImage ImagePartial) (*Image, error)
 	UpdateFull(updatedImage Image) (*Image, error)
-	IncrementOCounter(id int) (int, error)
-	DecrementOCounter(id int) (int, error)
-	ResetOCounter(id int) (int, error)
+	IncrementOCounter(id int, userID int) (int, error)
+	DecrementOCounter(id int, userID int) (int, error)
+	ResetOCounter(id int, userID int) (int, error)
+	UpdateUserData(imageID int, userID int, updatedData UserDataPartial) error
+	// UpdateAllUserData replaces the values of the image for all users.
+	UpdateAllUserData(imageID int, data []*UserData) error
 	Destroy(id int) error
 	UpdateGalleries(imageID int, galleryIDs []int) error
 	UpdatePerformers(imageID int, performerIDs []int) error
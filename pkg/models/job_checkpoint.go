@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type JobCheckpointReader interface {
+	// GetItems returns the items recorded as completed for the checkpoint key.
+	GetItems(key string) ([]string, error)
+}
+
+type JobCheckpointWriter interface {
+	// AddItems records the items as completed for the checkpoint key.
+	AddItems(key string, items []string) error
+	// Destroy destroys all items recorded for the checkpoint key.
+	Destroy(key string) error
+	// DestroyOlderThan destroys the items that were recorded before t.
+	DestroyOlderThan(t time.Time) error
+}
+
+type JobCheckpointReaderWriter interface {
+	JobCheckpointReader
+	JobCheckpointWriter
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type JobHistoryReader interface {
+	Find(id int) (*JobHistory, error)
+	Query(jobFilter *JobHistoryFilterType, findFilter *FindFilterType) ([]*JobHistory, int, error)
+	GetLogs(jobID int) ([]*JobHistoryLog, error)
+}
+
+type JobHistoryWriter interface {
+	Create(newObject JobHistory) (*JobHistory, error)
+	CreateLogs(jobID int, logs []*JobHistoryLog) error
+	// DestroyOlderThan destroys the jobs that ended before t.
+	DestroyOlderThan(t time.Time) error
+	// DestroyExcess destroys all but the most recently ended keep jobs.
+	DestroyExcess(keep int) error
+}
+
+type JobHistoryReaderWriter interface {
+	JobHistoryReader
+	JobHistoryWriter
+}
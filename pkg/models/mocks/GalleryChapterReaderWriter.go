@@ -0,0 +1,142 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "github.com/stashapp/stash/pkg/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GalleryChapterReaderWriter is an autogenerated mock type for the GalleryChapterReaderWriter type
+type GalleryChapterReaderWriter struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: newGalleryChapter
+func (_m *GalleryChapterReaderWriter) Create(newGalleryChapter models.GalleryChapter) (*models.GalleryChapter, error) {
+	ret := _m.Called(newGalleryChapter)
+
+	var r0 *models.GalleryChapter
+	if rf, ok := ret.Get(0).(func(models.GalleryChapter) *models.GalleryChapter); ok {
+		r0 = rf(newGalleryChapter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GalleryChapter)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.GalleryChapter) error); ok {
+		r1 = rf(newGalleryChapter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Destroy provides a mock function with given fields: id
+func (_m *GalleryChapterReaderWriter) Destroy(id int) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: id
+func (_m *GalleryChapterReaderWriter) Find(id int) (*models.GalleryChapter, error) {
+	ret := _m.Called(id)
+
+	var r0 *models.GalleryChapter
+	if rf, ok := ret.Get(0).(func(int) *models.GalleryChapter); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GalleryChapter)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByGalleryID provides a mock function with given fields: galleryID
+func (_m *GalleryChapterReaderWriter) FindByGalleryID(galleryID int) ([]*models.GalleryChapter, error) {
+	ret := _m.Called(galleryID)
+
+	var r0 []*models.GalleryChapter
+	if rf, ok := ret.Get(0).(func(int) []*models.GalleryChapter); ok {
+		r0 = rf(galleryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.GalleryChapter)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(galleryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMany provides a mock function with given fields: ids
+func (_m *GalleryChapterReaderWriter) FindMany(ids []int) ([]*models.GalleryChapter, error) {
+	ret := _m.Called(ids)
+
+	var r0 []*models.GalleryChapter
+	if rf, ok := ret.Get(0).(func([]int) []*models.GalleryChapter); ok {
+		r0 = rf(ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.GalleryChapter)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]int) error); ok {
+		r1 = rf(ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: updatedGalleryChapter
+func (_m *GalleryChapterReaderWriter) Update(updatedGalleryChapter models.GalleryChapter) (*models.GalleryChapter, error) {
+	ret := _m.Called(updatedGalleryChapter)
+
+	var r0 *models.GalleryChapter
+	if rf, ok := ret.Get(0).(func(models.GalleryChapter) *models.GalleryChapter); ok {
+		r0 = rf(updatedGalleryChapter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GalleryChapter)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.GalleryChapter) error); ok {
+		r1 = rf(updatedGalleryChapter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
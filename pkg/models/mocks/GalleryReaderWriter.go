@@ -277,6 +277,29 @@ func (_m *GalleryReaderWriter) GetImageIDs(galleryID int) ([]int, error) {
 	return r0, r1
 }
 
+// GetOrderedImageIDs provides a mock function with given fields: galleryID
+func (_m *GalleryReaderWriter) GetOrderedImageIDs(galleryID int) ([]int, error) {
+	ret := _m.Called(galleryID)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(int) []int); ok {
+		r0 = rf(galleryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(galleryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPerformerIDs provides a mock function with given fields: galleryID
 func (_m *GalleryReaderWriter) GetPerformerIDs(galleryID int) ([]int, error) {
 	ret := _m.Called(galleryID)
@@ -434,6 +457,20 @@ func (_m *GalleryReaderWriter) UpdateFileModTime(id int, modTime models.NullSQLi
 	return r0
 }
 
+// UpdateImageOrder provides a mock function with given fields: galleryID, imageIDs
+func (_m *GalleryReaderWriter) UpdateImageOrder(galleryID int, imageIDs []int) error {
+	ret := _m.Called(galleryID, imageIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []int) error); ok {
+		r0 = rf(galleryID, imageIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateImages provides a mock function with given fields: galleryID, imageIDs
 func (_m *GalleryReaderWriter) UpdateImages(galleryID int, imageIDs []int) error {
 	ret := _m.Called(galleryID, imageIDs)
@@ -100,20 +100,20 @@ func (_m *ImageReaderWriter) Create(newImage models.Image) (*models.Image, error
 	return r0, r1
 }
 
-// DecrementOCounter provides a mock function with given fields: id
-func (_m *ImageReaderWriter) DecrementOCounter(id int) (int, error) {
-	ret := _m.Called(id)
+// DecrementOCounter provides a mock function with given fields: id, userID
+func (_m *ImageReaderWriter) DecrementOCounter(id int, userID int) (int, error) {
+	ret := _m.Called(id, userID)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(id, userID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(id, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -250,6 +250,29 @@ func (_m *ImageReaderWriter) FindMany(ids []int) ([]*models.Image, error) {
 	return r0, r1
 }
 
+// GetAllUserData provides a mock function with given fields: imageID
+func (_m *ImageReaderWriter) GetAllUserData(imageID int) ([]*models.UserData, error) {
+	ret := _m.Called(imageID)
+
+	var r0 []*models.UserData
+	if rf, ok := ret.Get(0).(func(int) []*models.UserData); ok {
+		r0 = rf(imageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.UserData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(imageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGalleryIDs provides a mock function with given fields: imageID
 func (_m *ImageReaderWriter) GetGalleryIDs(imageID int) ([]int, error) {
 	ret := _m.Called(imageID)
@@ -319,20 +342,43 @@ func (_m *ImageReaderWriter) GetTagIDs(imageID int) ([]int, error) {
 	return r0, r1
 }
 
-// IncrementOCounter provides a mock function with given fields: id
-func (_m *ImageReaderWriter) IncrementOCounter(id int) (int, error) {
-	ret := _m.Called(id)
+// GetUserData provides a mock function with given fields: imageID, userID
+func (_m *ImageReaderWriter) GetUserData(imageID int, userID int) (*models.UserData, error) {
+	ret := _m.Called(imageID, userID)
+
+	var r0 *models.UserData
+	if rf, ok := ret.Get(0).(func(int, int) *models.UserData); ok {
+		r0 = rf(imageID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(imageID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementOCounter provides a mock function with given fields: id, userID
+func (_m *ImageReaderWriter) IncrementOCounter(id int, userID int) (int, error) {
+	ret := _m.Called(id, userID)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(id, userID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(id, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -391,20 +437,20 @@ func (_m *ImageReaderWriter) QueryCount(imageFilter *models.ImageFilterType, fin
 	return r0, r1
 }
 
-// ResetOCounter provides a mock function with given fields: id
-func (_m *ImageReaderWriter) ResetOCounter(id int) (int, error) {
-	ret := _m.Called(id)
+// ResetOCounter provides a mock function with given fields: id, userID
+func (_m *ImageReaderWriter) ResetOCounter(id int, userID int) (int, error) {
+	ret := _m.Called(id, userID)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(id, userID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(id, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -456,6 +502,20 @@ func (_m *ImageReaderWriter) Update(updatedImage models.ImagePartial) (*models.I
 	return r0, r1
 }
 
+// UpdateAllUserData provides a mock function with given fields: imageID, data
+func (_m *ImageReaderWriter) UpdateAllUserData(imageID int, data []*models.UserData) error {
+	ret := _m.Called(imageID, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []*models.UserData) error); ok {
+		r0 = rf(imageID, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateFull provides a mock function with given fields: updatedImage
 func (_m *ImageReaderWriter) UpdateFull(updatedImage models.Image) (*models.Image, error) {
 	ret := _m.Called(updatedImage)
@@ -520,3 +580,17 @@ func (_m *ImageReaderWriter) UpdateTags(imageID int, tagIDs []int) error {
 
 	return r0
 }
+
+// UpdateUserData provides a mock function with given fields: imageID, userID, updatedData
+func (_m *ImageReaderWriter) UpdateUserData(imageID int, userID int, updatedData models.UserDataPartial) error {
+	ret := _m.Called(imageID, userID, updatedData)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, models.UserDataPartial) error); ok {
+		r0 = rf(imageID, userID, updatedData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,79 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// JobCheckpointReaderWriter is an autogenerated mock type for the JobCheckpointReaderWriter type
+type JobCheckpointReaderWriter struct {
+	mock.Mock
+}
+
+// AddItems provides a mock function with given fields: key, items
+func (_m *JobCheckpointReaderWriter) AddItems(key string, items []string) error {
+	ret := _m.Called(key, items)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
+		r0 = rf(key, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Destroy provides a mock function with given fields: key
+func (_m *JobCheckpointReaderWriter) Destroy(key string) error {
+	ret := _m.Called(key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DestroyOlderThan provides a mock function with given fields: t
+func (_m *JobCheckpointReaderWriter) DestroyOlderThan(t time.Time) error {
+	ret := _m.Called(t)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(t)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetItems provides a mock function with given fields: key
+func (_m *JobCheckpointReaderWriter) GetItems(key string) ([]string, error) {
+	ret := _m.Called(key)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
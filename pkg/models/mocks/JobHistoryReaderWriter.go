@@ -0,0 +1,156 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	models "github.com/stashapp/stash/pkg/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// JobHistoryReaderWriter is an autogenerated mock type for the JobHistoryReaderWriter type
+type JobHistoryReaderWriter struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: newObject
+func (_m *JobHistoryReaderWriter) Create(newObject models.JobHistory) (*models.JobHistory, error) {
+	ret := _m.Called(newObject)
+
+	var r0 *models.JobHistory
+	if rf, ok := ret.Get(0).(func(models.JobHistory) *models.JobHistory); ok {
+		r0 = rf(newObject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.JobHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.JobHistory) error); ok {
+		r1 = rf(newObject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateLogs provides a mock function with given fields: jobID, logs
+func (_m *JobHistoryReaderWriter) CreateLogs(jobID int, logs []*models.JobHistoryLog) error {
+	ret := _m.Called(jobID, logs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []*models.JobHistoryLog) error); ok {
+		r0 = rf(jobID, logs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DestroyExcess provides a mock function with given fields: keep
+func (_m *JobHistoryReaderWriter) DestroyExcess(keep int) error {
+	ret := _m.Called(keep)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(keep)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DestroyOlderThan provides a mock function with given fields: t
+func (_m *JobHistoryReaderWriter) DestroyOlderThan(t time.Time) error {
+	ret := _m.Called(t)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(t)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: id
+func (_m *JobHistoryReaderWriter) Find(id int) (*models.JobHistory, error) {
+	ret := _m.Called(id)
+
+	var r0 *models.JobHistory
+	if rf, ok := ret.Get(0).(func(int) *models.JobHistory); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.JobHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLogs provides a mock function with given fields: jobID
+func (_m *JobHistoryReaderWriter) GetLogs(jobID int) ([]*models.JobHistoryLog, error) {
+	ret := _m.Called(jobID)
+
+	var r0 []*models.JobHistoryLog
+	if rf, ok := ret.Get(0).(func(int) []*models.JobHistoryLog); ok {
+		r0 = rf(jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.JobHistoryLog)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Query provides a mock function with given fields: jobFilter, findFilter
+func (_m *JobHistoryReaderWriter) Query(jobFilter *models.JobHistoryFilterType, findFilter *models.FindFilterType) ([]*models.JobHistory, int, error) {
+	ret := _m.Called(jobFilter, findFilter)
+
+	var r0 []*models.JobHistory
+	if rf, ok := ret.Get(0).(func(*models.JobHistoryFilterType, *models.FindFilterType) []*models.JobHistory); ok {
+		r0 = rf(jobFilter, findFilter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.JobHistory)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(*models.JobHistoryFilterType, *models.FindFilterType) int); ok {
+		r1 = rf(jobFilter, findFilter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*models.JobHistoryFilterType, *models.FindFilterType) error); ok {
+		r2 = rf(jobFilter, findFilter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
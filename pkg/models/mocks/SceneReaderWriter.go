@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	time "time"
+
 	models "github.com/stashapp/stash/pkg/models"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,6 +14,27 @@ type SceneReaderWriter struct {
 	mock.Mock
 }
 
+// AddPlay provides a mock function with given fields: sceneID, userID, playedAt
+func (_m *SceneReaderWriter) AddPlay(sceneID int, userID int, playedAt time.Time) (int, error) {
+	ret := _m.Called(sceneID, userID, playedAt)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, int, time.Time) int); ok {
+		r0 = rf(sceneID, userID, playedAt)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, time.Time) error); ok {
+		r1 = rf(sceneID, userID, playedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // All provides a mock function with given fields:
 func (_m *SceneReaderWriter) All() ([]*models.Scene, error) {
 	ret := _m.Called()
@@ -205,20 +228,43 @@ func (_m *SceneReaderWriter) Create(newScene models.Scene) (*models.Scene, error
 	return r0, r1
 }
 
-// DecrementOCounter provides a mock function with given fields: id
-func (_m *SceneReaderWriter) DecrementOCounter(id int) (int, error) {
-	ret := _m.Called(id)
+// CreateFile provides a mock function with given fields: newFile
+func (_m *SceneReaderWriter) CreateFile(newFile models.SceneFile) (*models.SceneFile, error) {
+	ret := _m.Called(newFile)
+
+	var r0 *models.SceneFile
+	if rf, ok := ret.Get(0).(func(models.SceneFile) *models.SceneFile); ok {
+		r0 = rf(newFile)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SceneFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.SceneFile) error); ok {
+		r1 = rf(newFile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DecrementOCounter provides a mock function with given fields: id, userID
+func (_m *SceneReaderWriter) DecrementOCounter(id int, userID int) (int, error) {
+	ret := _m.Called(id, userID)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(id, userID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(id, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -254,6 +300,20 @@ func (_m *SceneReaderWriter) DestroyCover(sceneID int) error {
 	return r0
 }
 
+// DestroyFile provides a mock function with given fields: fileID
+func (_m *SceneReaderWriter) DestroyFile(fileID int) error {
+	ret := _m.Called(fileID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(fileID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Duration provides a mock function with given fields:
 func (_m *SceneReaderWriter) Duration() (float64, error) {
 	ret := _m.Called()
@@ -459,6 +519,98 @@ func (_m *SceneReaderWriter) FindDuplicates(distance int) ([][]*models.Scene, er
 	return r0, r1
 }
 
+// FindFile provides a mock function with given fields: fileID
+func (_m *SceneReaderWriter) FindFile(fileID int) (*models.SceneFile, error) {
+	ret := _m.Called(fileID)
+
+	var r0 *models.SceneFile
+	if rf, ok := ret.Get(0).(func(int) *models.SceneFile); ok {
+		r0 = rf(fileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SceneFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(fileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindFileByPath provides a mock function with given fields: path
+func (_m *SceneReaderWriter) FindFileByPath(path string) (*models.SceneFile, error) {
+	ret := _m.Called(path)
+
+	var r0 *models.SceneFile
+	if rf, ok := ret.Get(0).(func(string) *models.SceneFile); ok {
+		r0 = rf(path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SceneFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindFilesByChecksum provides a mock function with given fields: checksum
+func (_m *SceneReaderWriter) FindFilesByChecksum(checksum string) ([]*models.SceneFile, error) {
+	ret := _m.Called(checksum)
+
+	var r0 []*models.SceneFile
+	if rf, ok := ret.Get(0).(func(string) []*models.SceneFile); ok {
+		r0 = rf(checksum)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SceneFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(checksum)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindFilesByOSHash provides a mock function with given fields: oshash
+func (_m *SceneReaderWriter) FindFilesByOSHash(oshash string) ([]*models.SceneFile, error) {
+	ret := _m.Called(oshash)
+
+	var r0 []*models.SceneFile
+	if rf, ok := ret.Get(0).(func(string) []*models.SceneFile); ok {
+		r0 = rf(oshash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SceneFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(oshash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindMany provides a mock function with given fields: ids
 func (_m *SceneReaderWriter) FindMany(ids []int) ([]*models.Scene, error) {
 	ret := _m.Called(ids)
@@ -482,6 +634,52 @@ func (_m *SceneReaderWriter) FindMany(ids []int) ([]*models.Scene, error) {
 	return r0, r1
 }
 
+// GetAllUserData provides a mock function with given fields: sceneID
+func (_m *SceneReaderWriter) GetAllUserData(sceneID int) ([]*models.UserData, error) {
+	ret := _m.Called(sceneID)
+
+	var r0 []*models.UserData
+	if rf, ok := ret.Get(0).(func(int) []*models.UserData); ok {
+		r0 = rf(sceneID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.UserData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(sceneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCaptions provides a mock function with given fields: sceneID
+func (_m *SceneReaderWriter) GetCaptions(sceneID int) ([]*models.SceneCaption, error) {
+	ret := _m.Called(sceneID)
+
+	var r0 []*models.SceneCaption
+	if rf, ok := ret.Get(0).(func(int) []*models.SceneCaption); ok {
+		r0 = rf(sceneID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SceneCaption)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(sceneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCover provides a mock function with given fields: sceneID
 func (_m *SceneReaderWriter) GetCover(sceneID int) ([]byte, error) {
 	ret := _m.Called(sceneID)
@@ -505,6 +703,52 @@ func (_m *SceneReaderWriter) GetCover(sceneID int) ([]byte, error) {
 	return r0, r1
 }
 
+// GetFileCaptions provides a mock function with given fields: fileID
+func (_m *SceneReaderWriter) GetFileCaptions(fileID int) ([]*models.SceneCaption, error) {
+	ret := _m.Called(fileID)
+
+	var r0 []*models.SceneCaption
+	if rf, ok := ret.Get(0).(func(int) []*models.SceneCaption); ok {
+		r0 = rf(fileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SceneCaption)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(fileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFiles provides a mock function with given fields: sceneID
+func (_m *SceneReaderWriter) GetFiles(sceneID int) ([]*models.SceneFile, error) {
+	ret := _m.Called(sceneID)
+
+	var r0 []*models.SceneFile
+	if rf, ok := ret.Get(0).(func(int) []*models.SceneFile); ok {
+		r0 = rf(sceneID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SceneFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(sceneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGalleryIDs provides a mock function with given fields: sceneID
 func (_m *SceneReaderWriter) GetGalleryIDs(sceneID int) ([]int, error) {
 	ret := _m.Called(sceneID)
@@ -574,6 +818,29 @@ func (_m *SceneReaderWriter) GetPerformerIDs(sceneID int) ([]int, error) {
 	return r0, r1
 }
 
+// GetPlayHistory provides a mock function with given fields: sceneID, userID
+func (_m *SceneReaderWriter) GetPlayHistory(sceneID int, userID int) (*models.ScenePlayHistory, error) {
+	ret := _m.Called(sceneID, userID)
+
+	var r0 *models.ScenePlayHistory
+	if rf, ok := ret.Get(0).(func(int, int) *models.ScenePlayHistory); ok {
+		r0 = rf(sceneID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ScenePlayHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(sceneID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStashIDs provides a mock function with given fields: sceneID
 func (_m *SceneReaderWriter) GetStashIDs(sceneID int) ([]*models.StashID, error) {
 	ret := _m.Called(sceneID)
@@ -620,20 +887,66 @@ func (_m *SceneReaderWriter) GetTagIDs(sceneID int) ([]int, error) {
 	return r0, r1
 }
 
-// IncrementOCounter provides a mock function with given fields: id
-func (_m *SceneReaderWriter) IncrementOCounter(id int) (int, error) {
-	ret := _m.Called(id)
+// GetTracks provides a mock function with given fields: sceneID
+func (_m *SceneReaderWriter) GetTracks(sceneID int) ([]*models.SceneTrack, error) {
+	ret := _m.Called(sceneID)
+
+	var r0 []*models.SceneTrack
+	if rf, ok := ret.Get(0).(func(int) []*models.SceneTrack); ok {
+		r0 = rf(sceneID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SceneTrack)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(sceneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserData provides a mock function with given fields: sceneID, userID
+func (_m *SceneReaderWriter) GetUserData(sceneID int, userID int) (*models.UserData, error) {
+	ret := _m.Called(sceneID, userID)
+
+	var r0 *models.UserData
+	if rf, ok := ret.Get(0).(func(int, int) *models.UserData); ok {
+		r0 = rf(sceneID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(sceneID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementOCounter provides a mock function with given fields: id, userID
+func (_m *SceneReaderWriter) IncrementOCounter(id int, userID int) (int, error) {
+	ret := _m.Called(id, userID)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(id, userID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(id, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -671,20 +984,20 @@ func (_m *SceneReaderWriter) Query(sceneFilter *models.SceneFilterType, findFilt
 	return r0, r1, r2
 }
 
-// ResetOCounter provides a mock function with given fields: id
-func (_m *SceneReaderWriter) ResetOCounter(id int) (int, error) {
-	ret := _m.Called(id)
+// ResetOCounter provides a mock function with given fields: id, userID
+func (_m *SceneReaderWriter) ResetOCounter(id int, userID int) (int, error) {
+	ret := _m.Called(id, userID)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(id, userID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(id, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -692,6 +1005,34 @@ func (_m *SceneReaderWriter) ResetOCounter(id int) (int, error) {
 	return r0, r1
 }
 
+// SaveActivity provides a mock function with given fields: sceneID, userID, resumeTime, playDuration
+func (_m *SceneReaderWriter) SaveActivity(sceneID int, userID int, resumeTime *float64, playDuration *float64) error {
+	ret := _m.Called(sceneID, userID, resumeTime, playDuration)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, *float64, *float64) error); ok {
+		r0 = rf(sceneID, userID, resumeTime, playDuration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPrimaryFile provides a mock function with given fields: sceneID, fileID
+func (_m *SceneReaderWriter) SetPrimaryFile(sceneID int, fileID int) error {
+	ret := _m.Called(sceneID, fileID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(sceneID, fileID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Size provides a mock function with given fields:
 func (_m *SceneReaderWriter) Size() (float64, error) {
 	ret := _m.Called()
@@ -736,6 +1077,20 @@ func (_m *SceneReaderWriter) Update(updatedScene models.ScenePartial) (*models.S
 	return r0, r1
 }
 
+// UpdateAllUserData provides a mock function with given fields: sceneID, data
+func (_m *SceneReaderWriter) UpdateAllUserData(sceneID int, data []*models.UserData) error {
+	ret := _m.Called(sceneID, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []*models.UserData) error); ok {
+		r0 = rf(sceneID, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateCover provides a mock function with given fields: sceneID, cover
 func (_m *SceneReaderWriter) UpdateCover(sceneID int, cover []byte) error {
 	ret := _m.Called(sceneID, cover)
@@ -750,6 +1105,43 @@ func (_m *SceneReaderWriter) UpdateCover(sceneID int, cover []byte) error {
 	return r0
 }
 
+// UpdateFile provides a mock function with given fields: updatedFile
+func (_m *SceneReaderWriter) UpdateFile(updatedFile models.SceneFilePartial) (*models.SceneFile, error) {
+	ret := _m.Called(updatedFile)
+
+	var r0 *models.SceneFile
+	if rf, ok := ret.Get(0).(func(models.SceneFilePartial) *models.SceneFile); ok {
+		r0 = rf(updatedFile)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SceneFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.SceneFilePartial) error); ok {
+		r1 = rf(updatedFile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateFileCaptions provides a mock function with given fields: fileID, captions
+func (_m *SceneReaderWriter) UpdateFileCaptions(fileID int, captions []*models.SceneCaption) error {
+	ret := _m.Called(fileID, captions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []*models.SceneCaption) error); ok {
+		r0 = rf(fileID, captions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateFileModTime provides a mock function with given fields: id, modTime
 func (_m *SceneReaderWriter) UpdateFileModTime(id int, modTime models.NullSQLiteTimestamp) error {
 	ret := _m.Called(id, modTime)
@@ -764,6 +1156,20 @@ func (_m *SceneReaderWriter) UpdateFileModTime(id int, modTime models.NullSQLite
 	return r0
 }
 
+// UpdateFileTracks provides a mock function with given fields: fileID, tracks
+func (_m *SceneReaderWriter) UpdateFileTracks(fileID int, tracks []*models.SceneTrack) error {
+	ret := _m.Called(fileID, tracks)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []*models.SceneTrack) error); ok {
+		r0 = rf(fileID, tracks)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateFull provides a mock function with given fields: updatedScene
 func (_m *SceneReaderWriter) UpdateFull(updatedScene models.Scene) (*models.Scene, error) {
 	ret := _m.Called(updatedScene)
@@ -857,6 +1263,20 @@ func (_m *SceneReaderWriter) UpdateTags(sceneID int, tagIDs []int) error {
 	return r0
 }
 
+// UpdateUserData provides a mock function with given fields: sceneID, userID, updatedData
+func (_m *SceneReaderWriter) UpdateUserData(sceneID int, userID int, updatedData models.UserDataPartial) error {
+	ret := _m.Called(sceneID, userID, updatedData)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, models.UserDataPartial) error); ok {
+		r0 = rf(sceneID, userID, updatedData)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Wall provides a mock function with given fields: q
 func (_m *SceneReaderWriter) Wall(q *string) ([]*models.Scene, error) {
 	ret := _m.Called(q)
@@ -0,0 +1,43 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "github.com/stashapp/stash/pkg/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SearchReader is an autogenerated mock type for the SearchReader type
+type SearchReader struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: query, types, limit
+func (_m *SearchReader) Search(query string, types []models.SearchResultType, limit int) ([]*models.SearchResult, int, error) {
+	ret := _m.Called(query, types, limit)
+
+	var r0 []*models.SearchResult
+	if rf, ok := ret.Get(0).(func(string, []models.SearchResultType, int) []*models.SearchResult); ok {
+		r0 = rf(query, types, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SearchResult)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string, []models.SearchResultType, int) int); ok {
+		r1 = rf(query, types, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, []models.SearchResultType, int) error); ok {
+		r2 = rf(query, types, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
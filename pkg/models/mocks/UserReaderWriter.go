@@ -0,0 +1,221 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "github.com/stashapp/stash/pkg/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserReaderWriter is an autogenerated mock type for the UserReaderWriter type
+type UserReaderWriter struct {
+	mock.Mock
+}
+
+// All provides a mock function with given fields:
+func (_m *UserReaderWriter) All() ([]*models.User, error) {
+	ret := _m.Called()
+
+	var r0 []*models.User
+	if rf, ok := ret.Get(0).(func() []*models.User); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Count provides a mock function with given fields:
+func (_m *UserReaderWriter) Count() (int, error) {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountByRole provides a mock function with given fields: role
+func (_m *UserReaderWriter) CountByRole(role models.UserRole) (int, error) {
+	ret := _m.Called(role)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(models.UserRole) int); ok {
+		r0 = rf(role)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.UserRole) error); ok {
+		r1 = rf(role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: newObject
+func (_m *UserReaderWriter) Create(newObject models.User) (*models.User, error) {
+	ret := _m.Called(newObject)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(models.User) *models.User); ok {
+		r0 = rf(newObject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.User) error); ok {
+		r1 = rf(newObject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Destroy provides a mock function with given fields: id
+func (_m *UserReaderWriter) Destroy(id int) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: id
+func (_m *UserReaderWriter) Find(id int) (*models.User, error) {
+	ret := _m.Called(id)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(int) *models.User); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByAPIKey provides a mock function with given fields: apiKey
+func (_m *UserReaderWriter) FindByAPIKey(apiKey string) (*models.User, error) {
+	ret := _m.Called(apiKey)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(string) *models.User); ok {
+		r0 = rf(apiKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(apiKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByUsername provides a mock function with given fields: username
+func (_m *UserReaderWriter) FindByUsername(username string) (*models.User, error) {
+	ret := _m.Called(username)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(string) *models.User); ok {
+		r0 = rf(username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransferUserData provides a mock function with given fields: fromUserID, toUserID
+func (_m *UserReaderWriter) TransferUserData(fromUserID int, toUserID int) error {
+	ret := _m.Called(fromUserID, toUserID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(fromUserID, toUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: updatedObject
+func (_m *UserReaderWriter) Update(updatedObject models.User) (*models.User, error) {
+	ret := _m.Called(updatedObject)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(models.User) *models.User); ok {
+		r0 = rf(updatedObject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.User) error); ok {
+		r1 = rf(updatedObject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -8,6 +8,7 @@ import (
 
 type TransactionManager struct {
 	gallery     models.GalleryReaderWriter
+	chapter     models.GalleryChapterReaderWriter
 	image       models.ImageReaderWriter
 	movie       models.MovieReaderWriter
 	performer   models.PerformerReaderWriter
@@ -17,11 +18,16 @@ type TransactionManager struct {
 	studio      models.StudioReaderWriter
 	tag         models.TagReaderWriter
 	savedFilter models.SavedFilterReaderWriter
+	jobHistory  models.JobHistoryReaderWriter
+	checkpoint  models.JobCheckpointReaderWriter
+	user        models.UserReaderWriter
+	search      models.SearchReader
 }
 
 func NewTransactionManager() *TransactionManager {
 	return &TransactionManager{
 		gallery:     &GalleryReaderWriter{},
+		chapter:     &GalleryChapterReaderWriter{},
 		image:       &ImageReaderWriter{},
 		movie:       &MovieReaderWriter{},
 		performer:   &PerformerReaderWriter{},
@@ -31,6 +37,10 @@ func NewTransactionManager() *TransactionManager {
 		studio:      &StudioReaderWriter{},
 		tag:         &TagReaderWriter{},
 		savedFilter: &SavedFilterReaderWriter{},
+		jobHistory:  &JobHistoryReaderWriter{},
+		checkpoint:  &JobCheckpointReaderWriter{},
+		user:        &UserReaderWriter{},
+		search:      &SearchReader{},
 	}
 }
 
@@ -42,6 +52,10 @@ func (t *TransactionManager) Gallery() models.GalleryReaderWriter {
 	return t.gallery
 }
 
+func (t *TransactionManager) GalleryChapter() models.GalleryChapterReaderWriter {
+	return t.chapter
+}
+
 func (t *TransactionManager) Image() models.ImageReaderWriter {
 	return t.image
 }
@@ -78,6 +92,22 @@ func (t *TransactionManager) SavedFilter() models.SavedFilterReaderWriter {
 	return t.savedFilter
 }
 
+func (t *TransactionManager) JobHistory() models.JobHistoryReaderWriter {
+	return t.jobHistory
+}
+
+func (t *TransactionManager) JobCheckpoint() models.JobCheckpointReaderWriter {
+	return t.checkpoint
+}
+
+func (t *TransactionManager) User() models.UserReaderWriter {
+	return t.user
+}
+
+func (t *TransactionManager) Search() models.SearchReader {
+	return t.search
+}
+
 type ReadTransaction struct {
 	t *TransactionManager
 }
@@ -90,6 +120,10 @@ func (r *ReadTransaction) Gallery() models.GalleryReader {
 	return r.t.gallery
 }
 
+func (r *ReadTransaction) GalleryChapter() models.GalleryChapterReader {
+	return r.t.chapter
+}
+
 func (r *ReadTransaction) Image() models.ImageReader {
 	return r.t.image
 }
@@ -125,3 +159,19 @@ func (r *ReadTransaction) Tag() models.TagReader {
 func (r *ReadTransaction) SavedFilter() models.SavedFilterReader {
 	return r.t.savedFilter
 }
+
+func (r *ReadTransaction) JobHistory() models.JobHistoryReader {
+	return r.t.jobHistory
+}
+
+func (r *ReadTransaction) JobCheckpoint() models.JobCheckpointReader {
+	return r.t.checkpoint
+}
+
+func (r *ReadTransaction) User() models.UserReader {
+	return r.t.user
+}
+
+func (r *ReadTransaction) Search() models.SearchReader {
+	return r.t.search
+}
@@ -5,18 +5,22 @@ import (
 	"path/filepath"
 )
 
-// Image stores the metadata for a single image.
+// Image stores the metadata for a single image. Animated images and video
+// clips have a duration. Video clips are video files that are scanned as
+// images.
 type Image struct {
 	ID          int                 `db:"id" json:"id"`
 	Checksum    string              `db:"checksum" json:"checksum"`
 	Path        string              `db:"path" json:"path"`
 	Title       sql.NullString      `db:"title" json:"title"`
-	Rating      sql.NullInt64       `db:"rating" json:"rating"`
+	Date        SQLiteDate          `db:"date" json:"date"`
 	Organized   bool                `db:"organized" json:"organized"`
-	OCounter    int                 `db:"o_counter" json:"o_counter"`
 	Size        sql.NullInt64       `db:"size" json:"size"`
 	Width       sql.NullInt64       `db:"width" json:"width"`
 	Height      sql.NullInt64       `db:"height" json:"height"`
+	Duration    sql.NullFloat64     `db:"duration" json:"duration"`
+	IsAnimated  bool                `db:"is_animated" json:"is_animated"`
+	IsVideo     bool                `db:"is_video" json:"is_video"`
 	StudioID    sql.NullInt64       `db:"studio_id,omitempty" json:"studio_id"`
 	FileModTime NullSQLiteTimestamp `db:"file_mod_time" json:"file_mod_time"`
 	CreatedAt   SQLiteTimestamp     `db:"created_at" json:"created_at"`
@@ -30,11 +34,14 @@ type ImagePartial struct {
 	Checksum    *string              `db:"checksum" json:"checksum"`
 	Path        *string              `db:"path" json:"path"`
 	Title       *sql.NullString      `db:"title" json:"title"`
-	Rating      *sql.NullInt64       `db:"rating" json:"rating"`
+	Date        *SQLiteDate          `db:"date" json:"date"`
 	Organized   *bool                `db:"organized" json:"organized"`
 	Size        *sql.NullInt64       `db:"size" json:"size"`
 	Width       *sql.NullInt64       `db:"width" json:"width"`
 	Height      *sql.NullInt64       `db:"height" json:"height"`
+	Duration    *sql.NullFloat64     `db:"duration" json:"duration"`
+	IsAnimated  *bool                `db:"is_animated" json:"is_animated"`
+	IsVideo     *bool                `db:"is_video" json:"is_video"`
 	StudioID    *sql.NullInt64       `db:"studio_id,omitempty" json:"studio_id"`
 	FileModTime *NullSQLiteTimestamp `db:"file_mod_time" json:"file_mod_time"`
 	CreatedAt   *SQLiteTimestamp     `db:"created_at" json:"created_at"`
@@ -0,0 +1,44 @@
+package models
+
+import "database/sql"
+
+// JobHistory is a record of a job that has stopped running.
+type JobHistory struct {
+	ID          int                 `db:"id" json:"id"`
+	Description string              `db:"description" json:"description"`
+	Status      string              `db:"status" json:"status"`
+	Error       sql.NullString      `db:"error" json:"error"`
+	Processed   int                 `db:"processed" json:"processed"`
+	Total       int                 `db:"total" json:"total"`
+	AddTime     SQLiteTimestamp     `db:"add_time" json:"add_time"`
+	StartTime   NullSQLiteTimestamp `db:"start_time" json:"start_time"`
+	EndTime     SQLiteTimestamp     `db:"end_time" json:"end_time"`
+}
+
+type JobHistories []*JobHistory
+
+func (m *JobHistories) Append(o interface{}) {
+	*m = append(*m, o.(*JobHistory))
+}
+
+func (m *JobHistories) New() interface{} {
+	return &JobHistory{}
+}
+
+// JobHistoryLog is a log item that was logged while a job was running.
+type JobHistoryLog struct {
+	JobID   int             `db:"job_id" json:"job_id"`
+	Time    SQLiteTimestamp `db:"time" json:"time"`
+	Level   string          `db:"level" json:"level"`
+	Message string          `db:"message" json:"message"`
+}
+
+type JobHistoryLogs []*JobHistoryLog
+
+func (m *JobHistoryLogs) Append(o interface{}) {
+	*m = append(*m, o.(*JobHistoryLog))
+}
+
+func (m *JobHistoryLogs) New() interface{} {
+	return &JobHistoryLog{}
+}
@@ -5,7 +5,9 @@ import (
 	"path/filepath"
 )
 
-// Scene stores the metadata for a single video scene.
+// Scene stores the metadata for a single video scene. The file fields (Path,
+// hashes and media information) reflect the scene's primary file. The full
+// set of files is stored separately as SceneFile objects.
 type Scene struct {
 	ID          int                 `db:"id" json:"id"`
 	Checksum    sql.NullString      `db:"checksum" json:"checksum"`
@@ -15,9 +17,7 @@ type Scene struct {
 	Details     sql.NullString      `db:"details" json:"details"`
 	URL         sql.NullString      `db:"url" json:"url"`
 	Date        SQLiteDate          `db:"date" json:"date"`
-	Rating      sql.NullInt64       `db:"rating" json:"rating"`
 	Organized   bool                `db:"organized" json:"organized"`
-	OCounter    int                 `db:"o_counter" json:"o_counter"`
 	Size        sql.NullString      `db:"size" json:"size"`
 	Duration    sql.NullFloat64     `db:"duration" json:"duration"`
 	VideoCodec  sql.NullString      `db:"video_codec" json:"video_codec"`
@@ -46,7 +46,6 @@ type ScenePartial struct {
 	Details     *sql.NullString      `db:"details" json:"details"`
 	URL         *sql.NullString      `db:"url" json:"url"`
 	Date        *SQLiteDate          `db:"date" json:"date"`
-	Rating      *sql.NullInt64       `db:"rating" json:"rating"`
 	Organized   *bool                `db:"organized" json:"organized"`
 	Size        *sql.NullString      `db:"size" json:"size"`
 	Duration    *sql.NullFloat64     `db:"duration" json:"duration"`
@@ -98,14 +97,16 @@ func (s Scene) GetMinResolution() int64 {
 
 // SceneFileType represents the file metadata for a scene.
 type SceneFileType struct {
-	Size       *string  `graphql:"size" json:"size"`
-	Duration   *float64 `graphql:"duration" json:"duration"`
-	VideoCodec *string  `graphql:"video_codec" json:"video_codec"`
-	AudioCodec *string  `graphql:"audio_codec" json:"audio_codec"`
-	Width      *int     `graphql:"width" json:"width"`
-	Height     *int     `graphql:"height" json:"height"`
-	Framerate  *float64 `graphql:"framerate" json:"framerate"`
-	Bitrate    *int     `graphql:"bitrate" json:"bitrate"`
+	Size        *string       `graphql:"size" json:"size"`
+	Duration    *float64      `graphql:"duration" json:"duration"`
+	VideoCodec  *string       `graphql:"video_codec" json:"video_codec"`
+	AudioCodec  *string       `graphql:"audio_codec" json:"audio_codec"`
+	Width       *int          `graphql:"width" json:"width"`
+	Height      *int          `graphql:"height" json:"height"`
+	Framerate   *float64      `graphql:"framerate" json:"framerate"`
+	Bitrate     *int          `graphql:"bitrate" json:"bitrate"`
+	VideoTracks []*SceneTrack `graphql:"video_tracks" json:"video_tracks"`
+	AudioTracks []*SceneTrack `graphql:"audio_tracks" json:"audio_tracks"`
 }
 
 type Scenes []*Scene
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"database/sql"
+)
+
+const (
+	CaptionTypeSRT = "srt"
+	CaptionTypeVTT = "vtt"
+	CaptionTypeASS = "ass"
+)
+
+// SceneCaption is a text subtitle track of a scene file. Captions are either
+// caption files next to the video file, or subtitle streams of the video
+// file itself.
+type SceneCaption struct {
+	FileID       int    `db:"file_id" json:"file_id"`
+	LanguageCode string `db:"language_code" json:"language_code"`
+	// CaptionType is the format of the caption: srt, vtt or ass
+	CaptionType string `db:"caption_type" json:"caption_type"`
+	// Path is the path of the caption file. Not set for subtitle streams.
+	Path sql.NullString `db:"path" json:"path"`
+	// StreamIndex is the index of the subtitle stream in the video file.
+	// Not set for caption files.
+	StreamIndex sql.NullInt64 `db:"stream_index" json:"stream_index"`
+}
+
+// IsEmbedded returns true if the caption is a subtitle stream of the video
+// file.
+func (c SceneCaption) IsEmbedded() bool {
+	return c.StreamIndex.Valid
+}
+
+type SceneCaptions []*SceneCaption
+
+func (s *SceneCaptions) Append(o interface{}) {
+	*s = append(*s, o.(*SceneCaption))
+}
+
+func (s *SceneCaptions) New() interface{} {
+	return &SceneCaption{}
+}
+
+// Find returns the first caption with the language and type. Empty values
+// match any language or type.
+func (s SceneCaptions) Find(lang string, captionType string) *SceneCaption {
+	for _, c := range s {
+		if (lang == "" || c.LanguageCode == lang) && (captionType == "" || c.CaptionType == captionType) {
+			return c
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"database/sql"
+)
+
+// SceneFile stores the fingerprints and media information of a single video
+// file belonging to a scene. A scene may own several files, exactly one of
+// which is its primary file.
+type SceneFile struct {
+	ID          int                 `db:"id" json:"id"`
+	SceneID     int                 `db:"scene_id" json:"scene_id"`
+	Primary     bool                `db:"is_primary" json:"primary"`
+	Path        string              `db:"path" json:"path"`
+	Checksum    sql.NullString      `db:"checksum" json:"checksum"`
+	OSHash      sql.NullString      `db:"oshash" json:"oshash"`
+	Phash       sql.NullInt64       `db:"phash,omitempty" json:"phash"`
+	Size        sql.NullString      `db:"size" json:"size"`
+	Duration    sql.NullFloat64     `db:"duration" json:"duration"`
+	VideoCodec  sql.NullString      `db:"video_codec" json:"video_codec"`
+	AudioCodec  sql.NullString      `db:"audio_codec" json:"audio_codec"`
+	Format      sql.NullString      `db:"format" json:"format_name"`
+	Width       sql.NullInt64       `db:"width" json:"width"`
+	Height      sql.NullInt64       `db:"height" json:"height"`
+	Framerate   sql.NullFloat64     `db:"framerate" json:"framerate"`
+	Bitrate     sql.NullInt64       `db:"bitrate" json:"bitrate"`
+	Interactive bool                `db:"interactive" json:"interactive"`
+	FileModTime NullSQLiteTimestamp `db:"file_mod_time" json:"file_mod_time"`
+	CreatedAt   SQLiteTimestamp     `db:"created_at" json:"created_at"`
+	UpdatedAt   SQLiteTimestamp     `db:"updated_at" json:"updated_at"`
+}
+
+// SceneFilePartial represents part of a SceneFile object. It is used to
+// update the database entry. Only non-nil fields will be updated.
+type SceneFilePartial struct {
+	ID          int                  `db:"id" json:"id"`
+	SceneID     *int                 `db:"scene_id" json:"scene_id"`
+	Path        *string              `db:"path" json:"path"`
+	Checksum    *sql.NullString      `db:"checksum" json:"checksum"`
+	OSHash      *sql.NullString      `db:"oshash" json:"oshash"`
+	Phash       *sql.NullInt64       `db:"phash,omitempty" json:"phash"`
+	Size        *sql.NullString      `db:"size" json:"size"`
+	Duration    *sql.NullFloat64     `db:"duration" json:"duration"`
+	VideoCodec  *sql.NullString      `db:"video_codec" json:"video_codec"`
+	AudioCodec  *sql.NullString      `db:"audio_codec" json:"audio_codec"`
+	Format      *sql.NullString      `db:"format" json:"format_name"`
+	Width       *sql.NullInt64       `db:"width" json:"width"`
+	Height      *sql.NullInt64       `db:"height" json:"height"`
+	Framerate   *sql.NullFloat64     `db:"framerate" json:"framerate"`
+	Bitrate     *sql.NullInt64       `db:"bitrate" json:"bitrate"`
+	Interactive *bool                `db:"interactive" json:"interactive"`
+	FileModTime *NullSQLiteTimestamp `db:"file_mod_time" json:"file_mod_time"`
+	UpdatedAt   *SQLiteTimestamp     `db:"updated_at" json:"updated_at"`
+}
+
+// NewSceneFile returns a SceneFile populated with the file fields of the
+// provided scene. The returned file is not associated with a scene ID.
+func NewSceneFile(s Scene) SceneFile {
+	return SceneFile{
+		Path:        s.Path,
+		Checksum:    s.Checksum,
+		OSHash:      s.OSHash,
+		Phash:       s.Phash,
+		Size:        s.Size,
+		Duration:    s.Duration,
+		VideoCodec:  s.VideoCodec,
+		AudioCodec:  s.AudioCodec,
+		Format:      s.Format,
+		Width:       s.Width,
+		Height:      s.Height,
+		Framerate:   s.Framerate,
+		Bitrate:     s.Bitrate,
+		Interactive: s.Interactive,
+		FileModTime: s.FileModTime,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+// GetHash returns the hash of the file, based on the hash algorithm provided.
+// If hash algorithm is MD5, then Checksum is returned. Otherwise, OSHash is
+// returned.
+func (f SceneFile) GetHash(hashAlgorithm HashAlgorithm) string {
+	if hashAlgorithm == HashAlgorithmMd5 {
+		return f.Checksum.String
+	} else if hashAlgorithm == HashAlgorithmOshash {
+		return f.OSHash.String
+	}
+
+	panic("unknown hash algorithm")
+}
+
+type SceneFiles []*SceneFile
+
+func (s *SceneFiles) Append(o interface{}) {
+	*s = append(*s, o.(*SceneFile))
+}
+
+func (s *SceneFiles) New() interface{} {
+	return &SceneFile{}
+}
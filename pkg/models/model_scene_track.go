@@ -0,0 +1,35 @@
+package models
+
+const (
+	TrackTypeVideo = "video"
+	TrackTypeAudio = "audio"
+
+	// LanguageUnknown is the language code of captions and tracks without a
+	// language.
+	LanguageUnknown = "und"
+)
+
+// SceneTrack is a video or audio stream of a scene file.
+type SceneTrack struct {
+	FileID int `db:"file_id" json:"file_id"`
+	// Index is the index of the stream in the file
+	Index     int    `db:"stream_index" json:"index"`
+	TrackType string `db:"track_type" json:"track_type"`
+	Codec     string `db:"codec" json:"codec"`
+	Language  string `db:"language" json:"language"`
+	Title     string `db:"title" json:"title"`
+	// Channels is the number of audio channels. Zero for video tracks.
+	Channels int `db:"channels" json:"channels"`
+	// Default is true if the track is the default track of its type
+	Default bool `db:"is_default" json:"default"`
+}
+
+type SceneTracks []*SceneTrack
+
+func (s *SceneTracks) Append(o interface{}) {
+	*s = append(*s, o.(*SceneTrack))
+}
+
+func (s *SceneTracks) New() interface{} {
+	return &SceneTrack{}
+}
@@ -0,0 +1,24 @@
+package models
+
+import "database/sql"
+
+type User struct {
+	ID       int    `db:"id" json:"id"`
+	Username string `db:"username" json:"username"`
+	// bcrypt hash of the password
+	Password  string          `db:"password" json:"password"`
+	Role      UserRole        `db:"role" json:"role"`
+	APIKey    sql.NullString  `db:"api_key" json:"api_key"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+}
+
+type Users []*User
+
+func (m *Users) Append(o interface{}) {
+	*m = append(*m, o.(*User))
+}
+
+func (m *Users) New() interface{} {
+	return &User{}
+}
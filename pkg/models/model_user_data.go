@@ -0,0 +1,30 @@
+package models
+
+import "database/sql"
+
+// UserData stores the values of a scene or image that are specific to a
+// user. UserID is 0 for values that were set while authentication was not
+// enabled.
+type UserData struct {
+	UserID   int           `db:"user_id" json:"user_id"`
+	Rating   sql.NullInt64 `db:"rating" json:"rating"`
+	OCounter int           `db:"o_counter" json:"o_counter"`
+	Watched  bool          `db:"watched" json:"watched"`
+}
+
+// UserDataPartial represents part of a UserData object. Only non-nil fields
+// will be updated.
+type UserDataPartial struct {
+	Rating  *sql.NullInt64 `db:"rating" json:"rating"`
+	Watched *bool          `db:"watched" json:"watched"`
+}
+
+// ScenePlayHistory stores the playback activity of a user for a scene.
+// PlayDuration and ResumeTime are in seconds.
+type ScenePlayHistory struct {
+	UserID       int                 `db:"user_id" json:"user_id"`
+	PlayCount    int                 `db:"play_count" json:"play_count"`
+	PlayDuration float64             `db:"play_duration" json:"play_duration"`
+	LastPlayedAt NullSQLiteTimestamp `db:"last_played_at" json:"last_played_at"`
+	ResumeTime   float64             `db:"resume_time" json:"resume_time"`
+}
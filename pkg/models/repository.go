@@ -2,6 +2,7 @@ package models
 
 type Repository interface {
 	Gallery() GalleryReaderWriter
+	GalleryChapter() GalleryChapterReaderWriter
 	Image() ImageReaderWriter
 	Movie() MovieReaderWriter
 	Performer() PerformerReaderWriter
@@ -11,10 +12,15 @@ type Repository interface {
 	Studio() StudioReaderWriter
 	Tag() TagReaderWriter
 	SavedFilter() SavedFilterReaderWriter
+	JobHistory() JobHistoryReaderWriter
+	JobCheckpoint() JobCheckpointReaderWriter
+	User() UserReaderWriter
+	Search() SearchReader
 }
 
 type ReaderRepository interface {
 	Gallery() GalleryReader
+	GalleryChapter() GalleryChapterReader
 	Image() ImageReader
 	Movie() MovieReader
 	Performer() PerformerReader
@@ -24,4 +30,8 @@ type ReaderRepository interface {
 	Studio() StudioReader
 	Tag() TagReader
 	SavedFilter() SavedFilterReader
+	JobHistory() JobHistoryReader
+	JobCheckpoint() JobCheckpointReader
+	User() UserReader
+	Search() SearchReader
 }
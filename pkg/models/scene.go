@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type SceneReader interface {
 	Find(id int) (*Scene, error)
 	FindMany(ids []int) ([]*Scene, error)
@@ -30,15 +32,42 @@ type SceneReader interface {
 	GetGalleryIDs(sceneID int) ([]int, error)
 	GetPerformerIDs(sceneID int) ([]int, error)
 	GetStashIDs(sceneID int) ([]*StashID, error)
+	GetFiles(sceneID int) ([]*SceneFile, error)
+	FindFile(fileID int) (*SceneFile, error)
+	FindFileByPath(path string) (*SceneFile, error)
+	FindFilesByChecksum(checksum string) ([]*SceneFile, error)
+	FindFilesByOSHash(oshash string) ([]*SceneFile, error)
+	// GetCaptions returns the captions of the primary file of the scene.
+	GetCaptions(sceneID int) ([]*SceneCaption, error)
+	GetFileCaptions(fileID int) ([]*SceneCaption, error)
+	// GetTracks returns the video and audio tracks of the primary file of
+	// the scene.
+	GetTracks(sceneID int) ([]*SceneTrack, error)
+	// GetUserData returns the values of the scene for the user. Returns
+	// default values if the user has not set any values.
+	GetUserData(sceneID int, userID int) (*UserData, error)
+	GetAllUserData(sceneID int) ([]*UserData, error)
+	// GetPlayHistory returns the playback activity of the user for the
+	// scene. Returns default values if the user has not played the scene.
+	GetPlayHistory(sceneID int, userID int) (*ScenePlayHistory, error)
 }
 
 type SceneWriter interface {
 	Create(newScene Scene) (*Scene, error)
 	Update(updatedScene ScenePartial) (*Scene, error)
 	UpdateFull(updatedScene Scene) (*Scene, error)
-	IncrementOCounter(id int) (int, error)
-	DecrementOCounter(id int) (int, error)
-	ResetOCounter(id int) (int, error)
+	IncrementOCounter(id int, userID int) (int, error)
+	DecrementOCounter(id int, userID int) (int, error)
+	ResetOCounter(id int, userID int) (int, error)
+	UpdateUserData(sceneID int, userID int, updatedData UserDataPartial) error
+	// UpdateAllUserData replaces the values of the scene for all users.
+	UpdateAllUserData(sceneID int, data []*UserData) error
+	// AddPlay increments the play count of the scene for the user and sets
+	// the last played time. Returns the new play count.
+	AddPlay(sceneID int, userID int, playedAt time.Time) (int, error)
+	// SaveActivity sets the resume time of the scene for the user, and adds
+	// the played duration to the total. Nil values are not changed.
+	SaveActivity(sceneID int, userID int, resumeTime *float64, playDuration *float64) error
 	UpdateFileModTime(id int, modTime NullSQLiteTimestamp) error
 	Destroy(id int) error
 	UpdateCover(sceneID int, cover []byte) error
@@ -48,6 +77,14 @@ type SceneWriter interface {
 	UpdateGalleries(sceneID int, galleryIDs []int) error
 	UpdateMovies(sceneID int, movies []MoviesScenes) error
 	UpdateStashIDs(sceneID int, stashIDs []StashID) error
+	CreateFile(newFile SceneFile) (*SceneFile, error)
+	UpdateFile(updatedFile SceneFilePartial) (*SceneFile, error)
+	DestroyFile(fileID int) error
+	SetPrimaryFile(sceneID int, fileID int) error
+	// UpdateFileCaptions replaces the captions of the file.
+	UpdateFileCaptions(fileID int, captions []*SceneCaption) error
+	// UpdateFileTracks replaces the video and audio tracks of the file.
+	UpdateFileTracks(fileID int, tracks []*SceneTrack) error
 }
 
 type SceneReaderWriter interface {
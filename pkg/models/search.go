@@ -0,0 +1,19 @@
+package models
+
+// SearchResult is an object matching a full-text search.
+type SearchResult struct {
+	Type SearchResultType `json:"type"`
+	ID   int              `json:"id"`
+	// Score is the relevance of the result. Higher values are more relevant.
+	Score float64 `json:"score"`
+	// Snippet is an HTML excerpt of the matching text, with the matching
+	// terms in mark elements.
+	Snippet string `json:"snippet"`
+}
+
+type SearchReader interface {
+	// Search returns the objects of the provided types that match query,
+	// ordered by relevance, along with the total number of matches. All
+	// types are searched if types is empty.
+	Search(query string, types []SearchResultType, limit int) ([]*SearchResult, int, error)
+}
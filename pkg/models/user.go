@@ -0,0 +1,27 @@
+package models
+
+type UserReader interface {
+	Find(id int) (*User, error)
+	FindByUsername(username string) (*User, error)
+	FindByAPIKey(apiKey string) (*User, error)
+	All() ([]*User, error)
+	Count() (int, error)
+	// CountByRole returns the number of users with the provided role.
+	CountByRole(role UserRole) (int, error)
+}
+
+type UserWriter interface {
+	Create(newObject User) (*User, error)
+	Update(updatedObject User) (*User, error)
+	// Destroy removes the user, along with the user's scene and image
+	// values.
+	Destroy(id int) error
+	// TransferUserData moves the scene and image values of a user to another
+	// user, replacing the existing values of the other user.
+	TransferUserData(fromUserID int, toUserID int) error
+}
+
+type UserReaderWriter interface {
+	UserReader
+	UserWriter
+}
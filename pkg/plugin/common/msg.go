@@ -110,3 +110,13 @@ type HookContext struct {
 	Input       interface{} `json:"input"`
 	InputFields []string    `json:"inputFields,omitempty"`
 }
+
+// ScanHookInput is passed as the Input of the HookContext for hooks triggered
+// by the scan task.
+type ScanHookInput struct {
+	// Path of the scanned file.
+	Path string `json:"path"`
+
+	// OldPath is the previous path of the file. Only set for FileMoved hooks.
+	OldPath string `json:"oldPath,omitempty"`
+}
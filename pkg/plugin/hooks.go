@@ -1,77 +1,126 @@
 package plugin
 
 import (
+	"encoding/json"
+
 	"github.com/stashapp/stash/pkg/plugin/common"
 )
 
 type HookTriggerEnum string
 
-// Scan-related hooks are current disabled until post-hook execution is
-// integrated.
+// Pre hooks are executed synchronously before the operation is committed, and
+// may modify the operation input or abort the operation. Post hooks are
+// executed after the operation has been committed.
 
 const (
+	SceneMarkerCreatePre   HookTriggerEnum = "SceneMarker.Create.Pre"
+	SceneMarkerUpdatePre   HookTriggerEnum = "SceneMarker.Update.Pre"
 	SceneMarkerCreatePost  HookTriggerEnum = "SceneMarker.Create.Post"
 	SceneMarkerUpdatePost  HookTriggerEnum = "SceneMarker.Update.Post"
 	SceneMarkerDestroyPost HookTriggerEnum = "SceneMarker.Destroy.Post"
 
-	SceneCreatePost  HookTriggerEnum = "Scene.Create.Post"
-	SceneUpdatePost  HookTriggerEnum = "Scene.Update.Post"
-	SceneDestroyPost HookTriggerEnum = "Scene.Destroy.Post"
-
-	ImageCreatePost  HookTriggerEnum = "Image.Create.Post"
-	ImageUpdatePost  HookTriggerEnum = "Image.Update.Post"
-	ImageDestroyPost HookTriggerEnum = "Image.Destroy.Post"
-
-	GalleryCreatePost  HookTriggerEnum = "Gallery.Create.Post"
-	GalleryUpdatePost  HookTriggerEnum = "Gallery.Update.Post"
-	GalleryDestroyPost HookTriggerEnum = "Gallery.Destroy.Post"
-
+	SceneUpdatePre     HookTriggerEnum = "Scene.Update.Pre"
+	SceneCreatePost    HookTriggerEnum = "Scene.Create.Post"
+	SceneUpdatePost    HookTriggerEnum = "Scene.Update.Post"
+	SceneDestroyPost   HookTriggerEnum = "Scene.Destroy.Post"
+	SceneFileMovedPost HookTriggerEnum = "Scene.FileMoved.Post"
+
+	ImageUpdatePre     HookTriggerEnum = "Image.Update.Pre"
+	ImageCreatePost    HookTriggerEnum = "Image.Create.Post"
+	ImageUpdatePost    HookTriggerEnum = "Image.Update.Post"
+	ImageDestroyPost   HookTriggerEnum = "Image.Destroy.Post"
+	ImageFileMovedPost HookTriggerEnum = "Image.FileMoved.Post"
+
+	GalleryCreatePre     HookTriggerEnum = "Gallery.Create.Pre"
+	GalleryUpdatePre     HookTriggerEnum = "Gallery.Update.Pre"
+	GalleryCreatePost    HookTriggerEnum = "Gallery.Create.Post"
+	GalleryUpdatePost    HookTriggerEnum = "Gallery.Update.Post"
+	GalleryDestroyPost   HookTriggerEnum = "Gallery.Destroy.Post"
+	GalleryFileMovedPost HookTriggerEnum = "Gallery.FileMoved.Post"
+
+	GalleryChapterCreatePre   HookTriggerEnum = "GalleryChapter.Create.Pre"
+	GalleryChapterUpdatePre   HookTriggerEnum = "GalleryChapter.Update.Pre"
+	GalleryChapterCreatePost  HookTriggerEnum = "GalleryChapter.Create.Post"
+	GalleryChapterUpdatePost  HookTriggerEnum = "GalleryChapter.Update.Post"
+	GalleryChapterDestroyPost HookTriggerEnum = "GalleryChapter.Destroy.Post"
+
+	MovieCreatePre   HookTriggerEnum = "Movie.Create.Pre"
+	MovieUpdatePre   HookTriggerEnum = "Movie.Update.Pre"
 	MovieCreatePost  HookTriggerEnum = "Movie.Create.Post"
 	MovieUpdatePost  HookTriggerEnum = "Movie.Update.Post"
 	MovieDestroyPost HookTriggerEnum = "Movie.Destroy.Post"
 
+	PerformerCreatePre   HookTriggerEnum = "Performer.Create.Pre"
+	PerformerUpdatePre   HookTriggerEnum = "Performer.Update.Pre"
 	PerformerCreatePost  HookTriggerEnum = "Performer.Create.Post"
 	PerformerUpdatePost  HookTriggerEnum = "Performer.Update.Post"
 	PerformerDestroyPost HookTriggerEnum = "Performer.Destroy.Post"
 
+	StudioCreatePre   HookTriggerEnum = "Studio.Create.Pre"
+	StudioUpdatePre   HookTriggerEnum = "Studio.Update.Pre"
 	StudioCreatePost  HookTriggerEnum = "Studio.Create.Post"
 	StudioUpdatePost  HookTriggerEnum = "Studio.Update.Post"
 	StudioDestroyPost HookTriggerEnum = "Studio.Destroy.Post"
 
+	TagCreatePre   HookTriggerEnum = "Tag.Create.Pre"
+	TagUpdatePre   HookTriggerEnum = "Tag.Update.Pre"
 	TagCreatePost  HookTriggerEnum = "Tag.Create.Post"
 	TagUpdatePost  HookTriggerEnum = "Tag.Update.Post"
 	TagDestroyPost HookTriggerEnum = "Tag.Destroy.Post"
 )
 
 var AllHookTriggerEnum = []HookTriggerEnum{
+	SceneMarkerCreatePre,
+	SceneMarkerUpdatePre,
 	SceneMarkerCreatePost,
 	SceneMarkerUpdatePost,
 	SceneMarkerDestroyPost,
 
+	SceneUpdatePre,
 	SceneCreatePost,
 	SceneUpdatePost,
 	SceneDestroyPost,
+	SceneFileMovedPost,
 
+	ImageUpdatePre,
 	ImageCreatePost,
 	ImageUpdatePost,
 	ImageDestroyPost,
+	ImageFileMovedPost,
 
+	GalleryCreatePre,
+	GalleryUpdatePre,
 	GalleryCreatePost,
 	GalleryUpdatePost,
 	GalleryDestroyPost,
+	GalleryFileMovedPost,
 
+	GalleryChapterCreatePre,
+	GalleryChapterUpdatePre,
+	GalleryChapterCreatePost,
+	GalleryChapterUpdatePost,
+	GalleryChapterDestroyPost,
+
+	MovieCreatePre,
+	MovieUpdatePre,
 	MovieCreatePost,
 	MovieUpdatePost,
 	MovieDestroyPost,
 
+	PerformerCreatePre,
+	PerformerUpdatePre,
 	PerformerCreatePost,
 	PerformerUpdatePost,
 	PerformerDestroyPost,
 
+	StudioCreatePre,
+	StudioUpdatePre,
 	StudioCreatePost,
 	StudioUpdatePost,
 	StudioDestroyPost,
 
+	TagCreatePre,
+	TagUpdatePre,
 	TagCreatePost,
 	TagUpdatePost,
 	TagDestroyPost,
@@ -80,34 +129,57 @@ var AllHookTriggerEnum = []HookTriggerEnum{
 func (e HookTriggerEnum) IsValid() bool {
 
 	switch e {
-	case SceneMarkerCreatePost,
+	case SceneMarkerCreatePre,
+		SceneMarkerUpdatePre,
+		SceneMarkerCreatePost,
 		SceneMarkerUpdatePost,
 		SceneMarkerDestroyPost,
 
+		SceneUpdatePre,
 		SceneCreatePost,
 		SceneUpdatePost,
 		SceneDestroyPost,
+		SceneFileMovedPost,
 
+		ImageUpdatePre,
 		ImageCreatePost,
 		ImageUpdatePost,
 		ImageDestroyPost,
+		ImageFileMovedPost,
 
+		GalleryCreatePre,
+		GalleryUpdatePre,
 		GalleryCreatePost,
 		GalleryUpdatePost,
 		GalleryDestroyPost,
+		GalleryFileMovedPost,
+
+		GalleryChapterCreatePre,
+		GalleryChapterUpdatePre,
+		GalleryChapterCreatePost,
+		GalleryChapterUpdatePost,
+		GalleryChapterDestroyPost,
 
+		MovieCreatePre,
+		MovieUpdatePre,
 		MovieCreatePost,
 		MovieUpdatePost,
 		MovieDestroyPost,
 
+		PerformerCreatePre,
+		PerformerUpdatePre,
 		PerformerCreatePost,
 		PerformerUpdatePost,
 		PerformerDestroyPost,
 
+		StudioCreatePre,
+		StudioUpdatePre,
 		StudioCreatePost,
 		StudioUpdatePost,
 		StudioDestroyPost,
 
+		TagCreatePre,
+		TagUpdatePre,
 		TagCreatePost,
 		TagUpdatePost,
 		TagDestroyPost:
@@ -123,3 +195,23 @@ func (e HookTriggerEnum) String() string {
 func addHookContext(argsMap common.ArgsMap, hookContext common.HookContext) {
 	argsMap[common.HookContextKey] = hookContext
 }
+
+// toInputMap converts a graphql input object into a map keyed by its graphql
+// field names.
+func toInputMap(input interface{}) (map[string]interface{}, error) {
+	ret := make(map[string]interface{})
+	if input == nil {
+		return ret, nil
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
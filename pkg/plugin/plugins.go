@@ -192,40 +192,11 @@ func (c Cache) executePostHooks(ctx context.Context, hookType HookTriggerEnum, h
 		}
 
 		for _, h := range hooks {
-			newCtx := session.AddVisitedPlugin(ctx, p.id)
-			serverConnection := c.makeServerConnection(newCtx)
-
-			pluginInput := buildPluginInput(&p, &h.OperationConfig, serverConnection, nil)
-			addHookContext(pluginInput.Args, hookContext)
-
-			pt := pluginTask{
-				plugin:     &p,
-				operation:  &h.OperationConfig,
-				input:      pluginInput,
-				gqlHandler: c.gqlHandler,
-			}
-
-			task := pt.createTask()
-			if err := task.Start(); err != nil {
+			output, err := c.executeHook(ctx, &p, h, hookContext)
+			if err != nil {
 				return err
 			}
 
-			// handle cancel from context
-			c := make(chan struct{})
-			go func() {
-				task.Wait()
-				close(c)
-			}()
-
-			select {
-			case <-ctx.Done():
-				task.Stop()
-				return fmt.Errorf("operation cancelled")
-			case <-c:
-				// task finished normally
-			}
-
-			output := task.GetResult()
 			if output == nil {
 				logger.Debugf("%s [%s]: returned no result", hookType.String(), p.Name)
 			} else {
@@ -241,6 +212,110 @@ func (c Cache) executePostHooks(ctx context.Context, hookType HookTriggerEnum, h
 	return nil
 }
 
+// ExecutePreHooks synchronously executes the pre-commit hooks registered for
+// hookType. The input is passed to each hook in turn. A hook may rewrite the
+// input by returning an object containing the changed input fields, which are
+// applied before the next hook is executed. A hook may veto the operation by
+// returning an error.
+//
+// Returns the input fields changed by the hooks, keyed by their graphql
+// field name. Returns an error if any of the hooks failed or returned an
+// error, in which case the operation should be aborted.
+func (c Cache) ExecutePreHooks(ctx context.Context, id int, hookType HookTriggerEnum, input interface{}, inputFields []string) (map[string]interface{}, error) {
+	inputMap, err := toInputMap(input)
+	if err != nil {
+		return nil, fmt.Errorf("error converting hook input: %w", err)
+	}
+
+	visitedPlugins := session.GetVisitedPlugins(ctx)
+	changes := make(map[string]interface{})
+
+	for _, p := range c.plugins {
+		hooks := p.getHooks(hookType)
+		if len(hooks) > 0 && utils.StrInclude(visitedPlugins, p.id) {
+			logger.Debugf("plugin ID '%s' already triggered, not re-triggering", p.id)
+			continue
+		}
+
+		for _, h := range hooks {
+			output, err := c.executeHook(ctx, &p, h, common.HookContext{
+				ID:          id,
+				Type:        hookType.String(),
+				Input:       inputMap,
+				InputFields: inputFields,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if output != nil && output.Error != nil {
+				return nil, fmt.Errorf("%s [%s]: %s", hookType.String(), p.Name, *output.Error)
+			}
+
+			if output == nil || output.Output == nil {
+				logger.Debugf("%s [%s]: returned no result", hookType.String(), p.Name)
+				continue
+			}
+
+			changed, ok := output.Output.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s [%s]: expected object output, got %T", hookType.String(), p.Name, output.Output)
+			}
+
+			logger.Debugf("%s [%s]: changed input: %v", hookType.String(), p.Name, changed)
+			for k, v := range changed {
+				inputMap[k] = v
+				changes[k] = v
+
+				if !utils.StrInclude(inputFields, k) {
+					inputFields = append(inputFields, k)
+				}
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// executeHook runs a single hook operation and waits for it to complete,
+// returning its output.
+func (c Cache) executeHook(ctx context.Context, p *Config, h *HookConfig, hookContext common.HookContext) (*common.PluginOutput, error) {
+	newCtx := session.AddVisitedPlugin(ctx, p.id)
+	serverConnection := c.makeServerConnection(newCtx)
+
+	pluginInput := buildPluginInput(p, &h.OperationConfig, serverConnection, nil)
+	addHookContext(pluginInput.Args, hookContext)
+
+	pt := pluginTask{
+		plugin:     p,
+		operation:  &h.OperationConfig,
+		input:      pluginInput,
+		gqlHandler: c.gqlHandler,
+	}
+
+	task := pt.createTask()
+	if err := task.Start(); err != nil {
+		return nil, err
+	}
+
+	// handle cancel from context
+	done := make(chan struct{})
+	go func() {
+		task.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		task.Stop()
+		return nil, fmt.Errorf("operation cancelled")
+	case <-done:
+		// task finished normally
+	}
+
+	return task.GetResult(), nil
+}
+
 func (c Cache) getPlugin(pluginID string) *Config {
 	for _, s := range c.plugins {
 		if s.id == pluginID {
@@ -0,0 +1,195 @@
+package scene
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// captionFileTypes are the caption types of the caption file extensions.
+var captionFileTypes = map[string]string{
+	".srt": models.CaptionTypeSRT,
+	".vtt": models.CaptionTypeVTT,
+	".ass": models.CaptionTypeASS,
+	".ssa": models.CaptionTypeASS,
+}
+
+// subtitleCodecTypes are the caption types of the text subtitle codecs.
+var subtitleCodecTypes = map[string]string{
+	"subrip":   models.CaptionTypeSRT,
+	"srt":      models.CaptionTypeSRT,
+	"text":     models.CaptionTypeSRT,
+	"mov_text": models.CaptionTypeSRT,
+	"webvtt":   models.CaptionTypeVTT,
+	"ass":      models.CaptionTypeASS,
+	"ssa":      models.CaptionTypeASS,
+}
+
+// captionLanguageRE matches language codes such as en, eng and pt-BR.
+var captionLanguageRE = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})?$`)
+
+// getCaptionFile returns the caption of the caption file with the filename,
+// or nil if the file is not a caption file of the video file with the
+// filename base, without its extension. Caption files are named after the
+// video file, optionally followed by a language code, such as video.en.srt.
+func getCaptionFile(base string, filename string) *models.SceneCaption {
+	ext := filepath.Ext(filename)
+	captionType, ok := captionFileTypes[strings.ToLower(ext)]
+	if !ok {
+		return nil
+	}
+
+	name := strings.TrimSuffix(filename, ext)
+	if !strings.HasPrefix(name, base) {
+		return nil
+	}
+
+	lang := models.LanguageUnknown
+	if suffix := name[len(base):]; suffix != "" {
+		if !strings.HasPrefix(suffix, ".") || !captionLanguageRE.MatchString(suffix[1:]) {
+			return nil
+		}
+		lang = suffix[1:]
+	}
+
+	return &models.SceneCaption{
+		LanguageCode: lang,
+		CaptionType:  captionType,
+	}
+}
+
+// FindCaptionFiles returns the captions of the caption files next to the
+// video file at path, ordered by filename.
+func FindCaptionFiles(path string) ([]*models.SceneCaption, error) {
+	dir := filepath.Dir(path)
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	// Glob sorts the matches
+	matches, err := filepath.Glob(filepath.Join(dir, escapeGlob(base)) + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*models.SceneCaption
+	for _, match := range matches {
+		if c := getCaptionFile(base, filepath.Base(match)); c != nil {
+			if info, err := os.Stat(match); err != nil || info.IsDir() {
+				continue
+			}
+
+			c.Path = sql.NullString{String: match, Valid: true}
+			ret = append(ret, c)
+		}
+	}
+
+	return ret, nil
+}
+
+// escapeGlob escapes the glob metacharacters of s. Characters are escaped
+// with character classes rather than backslashes, since backslash is the
+// path separator on Windows.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[':
+			b.WriteRune('[')
+			b.WriteRune(r)
+			b.WriteRune(']')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// GetEmbeddedCaptions returns the captions of the text subtitle streams of
+// the video file.
+func GetEmbeddedCaptions(videoFile *ffmpeg.VideoFile) []*models.SceneCaption {
+	// not nil, so that UpdateCaptions removes the streams of a file without
+	// subtitle streams
+	ret := []*models.SceneCaption{}
+	for _, stream := range videoFile.GetTextSubtitleStreams() {
+		lang := stream.Tags.Language
+		if lang == "" {
+			lang = models.LanguageUnknown
+		}
+
+		ret = append(ret, &models.SceneCaption{
+			LanguageCode: lang,
+			CaptionType:  subtitleCodecTypes[stream.CodecName],
+			StreamIndex:  sql.NullInt64{Int64: int64(stream.Index), Valid: true},
+		})
+	}
+
+	return ret
+}
+
+// mergeCaptions returns the caption files followed by the subtitle streams.
+// Captions are identified by their language and type, so captions with the
+// same language and type as a previous caption are omitted.
+func mergeCaptions(files []*models.SceneCaption, streams []*models.SceneCaption) []*models.SceneCaption {
+	type key struct {
+		lang        string
+		captionType string
+	}
+
+	seen := make(map[key]bool)
+	var ret []*models.SceneCaption
+	for _, c := range append(append([]*models.SceneCaption{}, files...), streams...) {
+		k := key{c.LanguageCode, c.CaptionType}
+		if !seen[k] {
+			seen[k] = true
+			ret = append(ret, c)
+		}
+	}
+
+	return ret
+}
+
+func captionsEqual(a []*models.SceneCaption, b []*models.SceneCaption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		x, y := *a[i], *b[i]
+		x.FileID, y.FileID = 0, 0
+		if x != y {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UpdateCaptions sets the captions of the scene file to the caption files
+// and subtitle streams. If streams is nil, the existing subtitle streams of
+// the file are kept. The captions are only written if they have changed.
+func UpdateCaptions(qb models.SceneReaderWriter, fileID int, files []*models.SceneCaption, streams []*models.SceneCaption) error {
+	existing, err := qb.GetFileCaptions(fileID)
+	if err != nil {
+		return err
+	}
+
+	if streams == nil {
+		for _, c := range existing {
+			if c.IsEmbedded() {
+				streams = append(streams, c)
+			}
+		}
+	}
+
+	captions := mergeCaptions(files, streams)
+	if captionsEqual(existing, captions) {
+		return nil
+	}
+
+	return qb.UpdateFileCaptions(fileID, captions)
+}
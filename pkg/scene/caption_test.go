@@ -0,0 +1,168 @@
+package scene
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetCaptionFile(t *testing.T) {
+	tests := []struct {
+		filename string
+		lang     string
+		typ      string
+	}{
+		{"video.srt", models.LanguageUnknown, models.CaptionTypeSRT},
+		{"video.en.srt", "en", models.CaptionTypeSRT},
+		{"video.pt-BR.VTT", "pt-BR", models.CaptionTypeVTT},
+		{"video.eng.ass", "eng", models.CaptionTypeASS},
+		{"video.de.ssa", "de", models.CaptionTypeASS},
+		{"video.mp4", "", ""},
+		{"video.english subtitles.srt", "", ""},
+		{"video2.srt", "", ""},
+		{"other.en.srt", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got := getCaptionFile("video", tt.filename)
+			if tt.lang == "" {
+				assert.Nil(t, got)
+				return
+			}
+
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.lang, got.LanguageCode)
+				assert.Equal(t, tt.typ, got.CaptionType)
+			}
+		})
+	}
+}
+
+func TestFindCaptionFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "captions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"video.mp4", "video.fr.srt", "video.en.vtt", "video2.en.srt", "notes.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	captions, err := FindCaptionFiles(filepath.Join(dir, "video.mp4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []*models.SceneCaption{
+		{
+			LanguageCode: "en",
+			CaptionType:  models.CaptionTypeVTT,
+			Path:         sql.NullString{String: filepath.Join(dir, "video.en.vtt"), Valid: true},
+		},
+		{
+			LanguageCode: "fr",
+			CaptionType:  models.CaptionTypeSRT,
+			Path:         sql.NullString{String: filepath.Join(dir, "video.fr.srt"), Valid: true},
+		},
+	}, captions)
+
+	// glob metacharacters in the filename are matched literally
+	for _, name := range []string{"video [1].mp4", "video [1].srt", "video 1.srt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	captions, err = FindCaptionFiles(filepath.Join(dir, "video [1].mp4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []*models.SceneCaption{
+		{
+			LanguageCode: models.LanguageUnknown,
+			CaptionType:  models.CaptionTypeSRT,
+			Path:         sql.NullString{String: filepath.Join(dir, "video [1].srt"), Valid: true},
+		},
+	}, captions)
+}
+
+func TestGetEmbeddedCaptions(t *testing.T) {
+	videoFile := &ffmpeg.VideoFile{}
+	videoFile.JSON.Streams = []ffmpeg.FFProbeStream{
+		{Index: 0, CodecType: "video", CodecName: "h264"},
+		{Index: 1, CodecType: "subtitle", CodecName: "subrip"},
+		{Index: 2, CodecType: "subtitle", CodecName: "hdmv_pgs_subtitle"},
+		{Index: 3, CodecType: "subtitle", CodecName: "ass"},
+	}
+	videoFile.JSON.Streams[3].Tags.Language = "jpn"
+
+	assert.Equal(t, []*models.SceneCaption{
+		{
+			LanguageCode: models.LanguageUnknown,
+			CaptionType:  models.CaptionTypeSRT,
+			StreamIndex:  sql.NullInt64{Int64: 1, Valid: true},
+		},
+		{
+			LanguageCode: "jpn",
+			CaptionType:  models.CaptionTypeASS,
+			StreamIndex:  sql.NullInt64{Int64: 3, Valid: true},
+		},
+	}, GetEmbeddedCaptions(videoFile))
+
+	assert.NotNil(t, GetEmbeddedCaptions(&ffmpeg.VideoFile{}))
+}
+
+func TestUpdateCaptions(t *testing.T) {
+	const fileID = 1
+
+	enFile := &models.SceneCaption{
+		LanguageCode: "en",
+		CaptionType:  models.CaptionTypeSRT,
+		Path:         sql.NullString{String: "video.en.srt", Valid: true},
+	}
+	enStream := &models.SceneCaption{
+		LanguageCode: "en",
+		CaptionType:  models.CaptionTypeSRT,
+		StreamIndex:  sql.NullInt64{Int64: 2, Valid: true},
+	}
+	deStream := &models.SceneCaption{
+		FileID:       fileID,
+		LanguageCode: "de",
+		CaptionType:  models.CaptionTypeASS,
+		StreamIndex:  sql.NullInt64{Int64: 3, Valid: true},
+	}
+
+	// unchanged captions are not written
+	mockSceneReader := &mocks.SceneReaderWriter{}
+	mockSceneReader.On("GetFileCaptions", fileID).Return([]*models.SceneCaption{deStream}, nil).Once()
+	assert.Nil(t, UpdateCaptions(mockSceneReader, fileID, nil, nil))
+	mockSceneReader.AssertExpectations(t)
+
+	// streams with the same language and type as a caption file are omitted,
+	// and existing streams are kept if the file was not probed
+	mockSceneReader = &mocks.SceneReaderWriter{}
+	mockSceneReader.On("GetFileCaptions", fileID).Return([]*models.SceneCaption{deStream}, nil).Once()
+	mockSceneReader.On("UpdateFileCaptions", fileID, []*models.SceneCaption{enFile, deStream}).Return(nil).Once()
+	assert.Nil(t, UpdateCaptions(mockSceneReader, fileID, []*models.SceneCaption{enFile}, nil))
+	mockSceneReader.AssertExpectations(t)
+
+	// probed streams replace the existing streams
+	mockSceneReader = &mocks.SceneReaderWriter{}
+	mockSceneReader.On("GetFileCaptions", fileID).Return([]*models.SceneCaption{deStream}, nil).Once()
+	mockSceneReader.On("UpdateFileCaptions", fileID, mock.Anything).Return(nil).Once()
+	assert.Nil(t, UpdateCaptions(mockSceneReader, fileID, []*models.SceneCaption{enFile}, []*models.SceneCaption{enStream}))
+	mockSceneReader.AssertCalled(t, "UpdateFileCaptions", fileID, []*models.SceneCaption{enFile})
+}
@@ -43,12 +43,7 @@ func ToBasicJSON(reader models.SceneReader, scene *models.Scene) (*jsonschema.Sc
 		newSceneJSON.Date = utils.GetYMDFromDatabaseDate(scene.Date.String)
 	}
 
-	if scene.Rating.Valid {
-		newSceneJSON.Rating = int(scene.Rating.Int64)
-	}
-
 	newSceneJSON.Organized = scene.Organized
-	newSceneJSON.OCounter = scene.OCounter
 
 	if scene.Details.Valid {
 		newSceneJSON.Details = scene.Details.String
@@ -253,6 +248,7 @@ func GetSceneMarkersJSON(markerReader models.SceneMarkerReader, tagReader models
 		sceneMarkerJSON := jsonschema.SceneMarker{
 			Title:      sceneMarker.Title,
 			Seconds:    getDecimalString(sceneMarker.Seconds),
+			EndSeconds: getDecimalString(sceneMarker.EndSeconds.Float64),
 			PrimaryTag: primaryTag.Name,
 			Tags:       getTagNames(sceneMarkerTags),
 			CreatedAt:  models.JSONTime{Time: sceneMarker.CreatedAt.Timestamp},
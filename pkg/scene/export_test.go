@@ -112,10 +112,8 @@ func createFullScene(id int) models.Scene {
 			Valid:   true,
 		},
 		Height:     models.NullInt64(height),
-		OCounter:   ocounter,
 		OSHash:     models.NullString(oshash),
 		Phash:      models.NullInt64(phash),
-		Rating:     models.NullInt64(rating),
 		Organized:  organized,
 		Size:       models.NullString(size),
 		VideoCodec: models.NullString(videoCodec),
@@ -148,10 +146,8 @@ func createFullJSONScene(image string) *jsonschema.Scene {
 		Checksum:  checksum,
 		Date:      date,
 		Details:   details,
-		OCounter:  ocounter,
 		OSHash:    oshash,
 		Phash:     utils.PhashToString(phash),
-		Rating:    rating,
 		Organized: organized,
 		URL:       url,
 		File: &jsonschema.SceneFile{
@@ -466,8 +462,12 @@ const (
 	markerSeconds1 = 1.0
 	markerSeconds2 = 2.3
 
+	markerEndSeconds2 = 10.5
+
 	markerSeconds1Str = "1.0"
 	markerSeconds2Str = "2.3"
+
+	markerEndSeconds2Str = "10.5"
 )
 
 type sceneMarkersTestScenario struct {
@@ -499,6 +499,7 @@ var getSceneMarkersJSONScenarios = []sceneMarkersTestScenario{
 				Title:      markerTitle2,
 				PrimaryTag: validTagName2,
 				Seconds:    markerSeconds2Str,
+				EndSeconds: markerEndSeconds2Str,
 				Tags: []string{
 					validTagName2,
 				},
@@ -552,6 +553,7 @@ var validMarkers = []*models.SceneMarker{
 		Title:        markerTitle2,
 		PrimaryTagID: validTagID2,
 		Seconds:      markerSeconds2,
+		EndSeconds:   sql.NullFloat64{Float64: markerEndSeconds2, Valid: true},
 		CreatedAt: models.SQLiteTimestamp{
 			Timestamp: createTime,
 		},
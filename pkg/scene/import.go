@@ -8,6 +8,7 @@ import (
 
 	"github.com/stashapp/stash/pkg/manager/jsonschema"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/user"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -18,6 +19,7 @@ type Importer struct {
 	PerformerWriter     models.PerformerReaderWriter
 	MovieWriter         models.MovieReaderWriter
 	TagWriter           models.TagReaderWriter
+	UserReader          models.UserReader
 	Input               jsonschema.Scene
 	Path                string
 	MissingRefBehaviour models.ImportMissingRefEnum
@@ -29,6 +31,7 @@ type Importer struct {
 	performers     []*models.Performer
 	movies         []models.MoviesScenes
 	tags           []*models.Tag
+	userData       []*models.UserData
 	coverImageData []byte
 }
 
@@ -55,6 +58,10 @@ func (i *Importer) PreImport() error {
 		return err
 	}
 
+	if err := i.populateUserData(); err != nil {
+		return err
+	}
+
 	var err error
 	if len(i.Input.Cover) > 0 {
 		_, i.coverImageData, err = utils.ProcessBase64Image(i.Input.Cover)
@@ -90,12 +97,7 @@ func (i *Importer) sceneJSONToScene(sceneJSON jsonschema.Scene) models.Scene {
 	if sceneJSON.Date != "" {
 		newScene.Date = models.SQLiteDate{String: sceneJSON.Date, Valid: true}
 	}
-	if sceneJSON.Rating != 0 {
-		newScene.Rating = sql.NullInt64{Int64: int64(sceneJSON.Rating), Valid: true}
-	}
-
 	newScene.Organized = sceneJSON.Organized
-	newScene.OCounter = sceneJSON.OCounter
 	newScene.CreatedAt = models.SQLiteTimestamp{Timestamp: sceneJSON.CreatedAt.GetTime()}
 	newScene.UpdatedAt = models.SQLiteTimestamp{Timestamp: sceneJSON.UpdatedAt.GetTime()}
 
@@ -338,6 +340,21 @@ func (i *Importer) populateTags() error {
 	return nil
 }
 
+func (i *Importer) populateUserData() error {
+	input := i.Input.UserData
+	if len(input) == 0 && (i.Input.Rating != 0 || i.Input.OCounter != 0) {
+		// exported before values were stored per user
+		input = []jsonschema.UserData{{
+			Rating:   i.Input.Rating,
+			OCounter: i.Input.OCounter,
+		}}
+	}
+
+	var err error
+	i.userData, err = user.UserDataFromJSON(i.UserReader, input, i.MissingRefBehaviour)
+	return err
+}
+
 func (i *Importer) PostImport(id int) error {
 	if len(i.coverImageData) > 0 {
 		if err := i.ReaderWriter.UpdateCover(id, i.coverImageData); err != nil {
@@ -386,6 +403,12 @@ func (i *Importer) PostImport(id int) error {
 		}
 	}
 
+	if len(i.userData) > 0 {
+		if err := i.ReaderWriter.UpdateAllUserData(id, i.userData); err != nil {
+			return fmt.Errorf("failed to set user data: %s", err.Error())
+		}
+	}
+
 	return nil
 }
 
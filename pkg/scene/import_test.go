@@ -42,12 +42,17 @@ const (
 	existingMovieErr  = "existingMovieErr"
 	missingMovieName  = "missingMovieName"
 
+	existingUserID   = 106
+	existingUsername = "existingUsername"
+	missingUsername  = "missingUsername"
+
 	existingTagName = "existingTagName"
 	existingTagErr  = "existingTagErr"
 	missingTagName  = "missingTagName"
 
 	errPerformersID = 200
 	errGalleriesID  = 201
+	errUserDataID   = 202
 
 	missingChecksum = "missingChecksum"
 	missingOSHash   = "missingOSHash"
@@ -492,6 +497,103 @@ func TestImporterPreImportWithMissingTagCreateErr(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestImporterPreImportWithUserData(t *testing.T) {
+	userReader := &mocks.UserReaderWriter{}
+
+	i := Importer{
+		UserReader: userReader,
+		Path:       path,
+		Input: jsonschema.Scene{
+			UserData: []jsonschema.UserData{
+				{
+					User:    existingUsername,
+					Rating:  rating,
+					Watched: true,
+				},
+			},
+		},
+	}
+
+	userReader.On("All").Return([]*models.User{
+		{
+			ID:       existingUserID,
+			Username: existingUsername,
+			Role:     models.UserRoleAdmin,
+		},
+	}, nil).Once()
+
+	err := i.PreImport()
+	assert.Nil(t, err)
+	if assert.Len(t, i.userData, 1) {
+		assert.Equal(t, existingUserID, i.userData[0].UserID)
+		assert.Equal(t, models.NullInt64(rating), i.userData[0].Rating)
+		assert.True(t, i.userData[0].Watched)
+	}
+
+	userReader.AssertExpectations(t)
+}
+
+func TestImporterPreImportWithLegacyUserData(t *testing.T) {
+	userReader := &mocks.UserReaderWriter{}
+
+	i := Importer{
+		UserReader: userReader,
+		Path:       path,
+		Input: jsonschema.Scene{
+			Rating:   rating,
+			OCounter: ocounter,
+		},
+	}
+
+	userReader.On("All").Return([]*models.User{
+		{
+			ID:       existingUserID,
+			Username: existingUsername,
+			Role:     models.UserRoleAdmin,
+		},
+	}, nil).Once()
+
+	err := i.PreImport()
+	assert.Nil(t, err)
+	if assert.Len(t, i.userData, 1) {
+		assert.Equal(t, existingUserID, i.userData[0].UserID)
+		assert.Equal(t, models.NullInt64(rating), i.userData[0].Rating)
+		assert.Equal(t, ocounter, i.userData[0].OCounter)
+	}
+
+	userReader.AssertExpectations(t)
+}
+
+func TestImporterPreImportWithMissingUser(t *testing.T) {
+	userReader := &mocks.UserReaderWriter{}
+
+	i := Importer{
+		UserReader: userReader,
+		Path:       path,
+		Input: jsonschema.Scene{
+			UserData: []jsonschema.UserData{
+				{
+					User:   missingUsername,
+					Rating: rating,
+				},
+			},
+		},
+		MissingRefBehaviour: models.ImportMissingRefEnumFail,
+	}
+
+	userReader.On("All").Return(nil, nil).Times(2)
+
+	err := i.PreImport()
+	assert.NotNil(t, err)
+
+	i.MissingRefBehaviour = models.ImportMissingRefEnumIgnore
+	err = i.PreImport()
+	assert.Nil(t, err)
+	assert.Len(t, i.userData, 0)
+
+	userReader.AssertExpectations(t)
+}
+
 func TestImporterPostImport(t *testing.T) {
 	readerWriter := &mocks.SceneReaderWriter{}
 
@@ -540,6 +642,35 @@ func TestImporterPostImportUpdateGalleries(t *testing.T) {
 	sceneReaderWriter.AssertExpectations(t)
 }
 
+func TestImporterPostImportUpdateUserData(t *testing.T) {
+	sceneReaderWriter := &mocks.SceneReaderWriter{}
+
+	userData := []*models.UserData{
+		{
+			UserID: existingUserID,
+			Rating: models.NullInt64(rating),
+		},
+	}
+
+	i := Importer{
+		ReaderWriter: sceneReaderWriter,
+		userData:     userData,
+	}
+
+	updateErr := errors.New("UpdateAllUserData error")
+
+	sceneReaderWriter.On("UpdateAllUserData", sceneID, userData).Return(nil).Once()
+	sceneReaderWriter.On("UpdateAllUserData", errUserDataID, userData).Return(updateErr).Once()
+
+	err := i.PostImport(sceneID)
+	assert.Nil(t, err)
+
+	err = i.PostImport(errUserDataID)
+	assert.NotNil(t, err)
+
+	sceneReaderWriter.AssertExpectations(t)
+}
+
 func TestImporterPostImportUpdatePerformers(t *testing.T) {
 	sceneReaderWriter := &mocks.SceneReaderWriter{}
 
@@ -30,6 +30,19 @@ func (i *MarkerImporter) PreImport() error {
 		UpdatedAt: models.SQLiteTimestamp{Timestamp: i.Input.UpdatedAt.GetTime()},
 	}
 
+	if i.Input.EndSeconds != "" {
+		endSeconds, err := strconv.ParseFloat(i.Input.EndSeconds, 64)
+		if err != nil {
+			return fmt.Errorf("invalid end seconds: %s", err.Error())
+		}
+
+		if endSeconds <= seconds {
+			return fmt.Errorf("end seconds %s must be greater than seconds %s", i.Input.EndSeconds, i.Input.Seconds)
+		}
+
+		i.marker.EndSeconds = sql.NullFloat64{Float64: endSeconds, Valid: true}
+	}
+
 	if err := i.populateTags(); err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package scene
 
 import (
+	"database/sql"
 	"errors"
 	"testing"
 
@@ -70,6 +71,42 @@ func TestMarkerImporterPreImportWithTag(t *testing.T) {
 	tagReaderWriter.AssertExpectations(t)
 }
 
+func TestMarkerImporterPreImportEndSeconds(t *testing.T) {
+	tagReaderWriter := &mocks.TagReaderWriter{}
+
+	i := MarkerImporter{
+		TagWriter:           tagReaderWriter,
+		MissingRefBehaviour: models.ImportMissingRefEnumFail,
+		Input: jsonschema.SceneMarker{
+			Seconds:    seconds,
+			EndSeconds: "12.5",
+			PrimaryTag: existingTagName,
+		},
+	}
+
+	tagReaderWriter.On("FindByNames", []string{existingTagName}, false).Return([]*models.Tag{
+		{
+			ID:   existingTagID,
+			Name: existingTagName,
+		},
+	}, nil).Once()
+
+	err := i.PreImport()
+	assert.Nil(t, err)
+	assert.Equal(t, sql.NullFloat64{Float64: 12.5, Valid: true}, i.marker.EndSeconds)
+
+	// end time must be after the start time
+	i.Input.EndSeconds = seconds
+	err = i.PreImport()
+	assert.NotNil(t, err)
+
+	i.Input.EndSeconds = "invalid"
+	err = i.PreImport()
+	assert.NotNil(t, err)
+
+	tagReaderWriter.AssertExpectations(t)
+}
+
 func TestMarkerImporterPostImportUpdateTags(t *testing.T) {
 	sceneMarkerReaderWriter := &mocks.SceneMarkerReaderWriter{}
 
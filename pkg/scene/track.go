@@ -0,0 +1,44 @@
+package scene
+
+import (
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func newTrack(stream *ffmpeg.FFProbeStream, trackType string) *models.SceneTrack {
+	lang := stream.Tags.Language
+	if lang == "" {
+		lang = models.LanguageUnknown
+	}
+
+	return &models.SceneTrack{
+		Index:     stream.Index,
+		TrackType: trackType,
+		Codec:     stream.CodecName,
+		Language:  lang,
+		Title:     stream.Tags.Title,
+		Channels:  stream.Channels,
+		Default:   stream.Disposition.Default != 0,
+	}
+}
+
+// GetTracks returns the video and audio tracks of the video file, ordered
+// by their index in the file.
+func GetTracks(videoFile *ffmpeg.VideoFile) []*models.SceneTrack {
+	var ret []*models.SceneTrack
+	video := videoFile.GetVideoStreams()
+	audio := videoFile.GetAudioStreams()
+
+	// merge the streams in index order
+	for len(video) > 0 || len(audio) > 0 {
+		if len(audio) == 0 || (len(video) > 0 && video[0].Index < audio[0].Index) {
+			ret = append(ret, newTrack(video[0], models.TrackTypeVideo))
+			video = video[1:]
+		} else {
+			ret = append(ret, newTrack(audio[0], models.TrackTypeAudio))
+			audio = audio[1:]
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,47 @@
+package scene
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTracks(t *testing.T) {
+	videoFile := &ffmpeg.VideoFile{}
+	videoFile.JSON.Streams = []ffmpeg.FFProbeStream{
+		{Index: 0, CodecType: "audio", CodecName: "aac", Channels: 6},
+		{Index: 1, CodecType: "video", CodecName: "h264"},
+		{Index: 2, CodecType: "subtitle", CodecName: "subrip"},
+		{Index: 3, CodecType: "audio", CodecName: "opus", Channels: 2},
+	}
+	videoFile.JSON.Streams[0].Tags.Language = "jpn"
+	videoFile.JSON.Streams[0].Disposition.Default = 1
+	videoFile.JSON.Streams[3].Tags.Title = "Commentary"
+
+	assert.Equal(t, []*models.SceneTrack{
+		{
+			Index:     0,
+			TrackType: models.TrackTypeAudio,
+			Codec:     "aac",
+			Language:  "jpn",
+			Channels:  6,
+			Default:   true,
+		},
+		{
+			Index:     1,
+			TrackType: models.TrackTypeVideo,
+			Codec:     "h264",
+			Language:  models.LanguageUnknown,
+		},
+		{
+			Index:     3,
+			TrackType: models.TrackTypeAudio,
+			Codec:     "opus",
+			Language:  models.LanguageUnknown,
+			Title:     "Commentary",
+			Channels:  2,
+		},
+	}, GetTracks(videoFile))
+}
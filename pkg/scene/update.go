@@ -7,9 +7,9 @@ import (
 	"github.com/stashapp/stash/pkg/utils"
 )
 
-func UpdateFormat(qb models.SceneWriter, id int, format string) (*models.Scene, error) {
-	return qb.Update(models.ScenePartial{
-		ID: id,
+func UpdateFormat(qb models.SceneWriter, fileID int, format string) (*models.SceneFile, error) {
+	return qb.UpdateFile(models.SceneFilePartial{
+		ID: fileID,
 		Format: &sql.NullString{
 			String: format,
 			Valid:  true,
@@ -17,9 +17,9 @@ func UpdateFormat(qb models.SceneWriter, id int, format string) (*models.Scene,
 	})
 }
 
-func UpdateOSHash(qb models.SceneWriter, id int, oshash string) (*models.Scene, error) {
-	return qb.Update(models.ScenePartial{
-		ID: id,
+func UpdateOSHash(qb models.SceneWriter, fileID int, oshash string) (*models.SceneFile, error) {
+	return qb.UpdateFile(models.SceneFilePartial{
+		ID: fileID,
 		OSHash: &sql.NullString{
 			String: oshash,
 			Valid:  true,
@@ -27,9 +27,9 @@ func UpdateOSHash(qb models.SceneWriter, id int, oshash string) (*models.Scene,
 	})
 }
 
-func UpdateChecksum(qb models.SceneWriter, id int, checksum string) (*models.Scene, error) {
-	return qb.Update(models.ScenePartial{
-		ID: id,
+func UpdateChecksum(qb models.SceneWriter, fileID int, checksum string) (*models.SceneFile, error) {
+	return qb.UpdateFile(models.SceneFilePartial{
+		ID: fileID,
 		Checksum: &sql.NullString{
 			String: checksum,
 			Valid:  true,
@@ -37,13 +37,38 @@ func UpdateChecksum(qb models.SceneWriter, id int, checksum string) (*models.Sce
 	})
 }
 
-func UpdateFileModTime(qb models.SceneWriter, id int, modTime models.NullSQLiteTimestamp) (*models.Scene, error) {
-	return qb.Update(models.ScenePartial{
-		ID:          id,
+func UpdateFileModTime(qb models.SceneWriter, fileID int, modTime models.NullSQLiteTimestamp) (*models.SceneFile, error) {
+	return qb.UpdateFile(models.SceneFilePartial{
+		ID:          fileID,
 		FileModTime: &modTime,
 	})
 }
 
+// GetPrimaryFile returns the primary file of the scene with the provided id.
+// Returns nil if the scene has no files.
+func GetPrimaryFile(qb models.SceneReader, sceneID int) (*models.SceneFile, error) {
+	files, err := qb.GetFiles(sceneID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.Primary {
+			return f, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// AddFile adds the file to the scene. The file is made the primary file if
+// primary is true.
+func AddFile(qb models.SceneWriter, sceneID int, file models.SceneFile, primary bool) (*models.SceneFile, error) {
+	file.SceneID = sceneID
+	file.Primary = primary
+	return qb.CreateFile(file)
+}
+
 func AddPerformer(qb models.SceneReaderWriter, id int, performerID int) (bool, error) {
 	performerIDs, err := qb.GetPerformerIDs(id)
 	if err != nil {
@@ -33,6 +33,12 @@ type scraper interface {
 	scrapeGalleryByFragment(gallery models.ScrapedGalleryInput) (*models.ScrapedGallery, error)
 	scrapeGalleryByURL(url string) (*models.ScrapedGallery, error)
 
+	scrapeImageByImage(image *models.Image) (*models.ScrapedImage, error)
+	scrapeImageByFragment(image models.ScrapedImageInput) (*models.ScrapedImage, error)
+	scrapeImageByURL(url string) (*models.ScrapedImage, error)
+
+	scrapeMoviesByName(name string) ([]*models.ScrapedMovie, error)
+	scrapeMovieByFragment(movie models.ScrapedMovieInput) (*models.ScrapedMovie, error)
 	scrapeMovieByURL(url string) (*models.ScrapedMovie, error)
 }
 
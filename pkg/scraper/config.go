@@ -35,6 +35,9 @@ type config struct {
 	// Configuration for querying gallery by a Gallery fragment
 	GalleryByFragment *scraperTypeConfig `yaml:"galleryByFragment"`
 
+	// Configuration for querying image by an Image fragment
+	ImageByFragment *scraperTypeConfig `yaml:"imageByFragment"`
+
 	// Configuration for querying scenes by name
 	SceneByName *scraperTypeConfig `yaml:"sceneByName"`
 
@@ -47,6 +50,15 @@ type config struct {
 	// Configuration for querying a gallery by a URL
 	GalleryByURL []*scrapeByURLConfig `yaml:"galleryByURL"`
 
+	// Configuration for querying an image by a URL
+	ImageByURL []*scrapeByURLConfig `yaml:"imageByURL"`
+
+	// Configuration for querying movies by name
+	MovieByName *scraperTypeConfig `yaml:"movieByName"`
+
+	// Configuration for querying a movie by a Movie fragment
+	MovieByFragment *scraperTypeConfig `yaml:"movieByFragment"`
+
 	// Configuration for querying a movie by a URL
 	MovieByURL []*scrapeByURLConfig `yaml:"movieByURL"`
 
@@ -89,6 +101,24 @@ func (c config) validate() error {
 		}
 	}
 
+	if c.ImageByFragment != nil {
+		if err := c.ImageByFragment.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.MovieByName != nil {
+		if err := c.MovieByName.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.MovieByFragment != nil {
+		if err := c.MovieByFragment.validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, s := range c.PerformerByURL {
 		if err := s.validate(); err != nil {
 			return err
@@ -101,6 +131,12 @@ func (c config) validate() error {
 		}
 	}
 
+	for _, s := range c.ImageByURL {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, s := range c.MovieByURL {
 		if err := s.validate(); err != nil {
 			return err
@@ -291,7 +327,28 @@ func (c config) toScraper() *models.Scraper {
 		ret.Gallery = &gallery
 	}
 
+	image := models.ScraperSpec{}
+	if c.ImageByFragment != nil {
+		image.SupportedScrapes = append(image.SupportedScrapes, models.ScrapeTypeFragment)
+	}
+	if len(c.ImageByURL) > 0 {
+		image.SupportedScrapes = append(image.SupportedScrapes, models.ScrapeTypeURL)
+		for _, v := range c.ImageByURL {
+			image.Urls = append(image.Urls, v.URL...)
+		}
+	}
+
+	if len(image.SupportedScrapes) > 0 {
+		ret.Image = &image
+	}
+
 	movie := models.ScraperSpec{}
+	if c.MovieByName != nil {
+		movie.SupportedScrapes = append(movie.SupportedScrapes, models.ScrapeTypeName)
+	}
+	if c.MovieByFragment != nil {
+		movie.SupportedScrapes = append(movie.SupportedScrapes, models.ScrapeTypeFragment)
+	}
 	if len(c.MovieByURL) > 0 {
 		movie.SupportedScrapes = append(movie.SupportedScrapes, models.ScrapeTypeURL)
 		for _, v := range c.MovieByURL {
@@ -369,6 +426,10 @@ func (c config) supportsGalleries() bool {
 	return c.GalleryByFragment != nil || len(c.GalleryByURL) > 0
 }
 
+func (c config) supportsImages() bool {
+	return c.ImageByFragment != nil || len(c.ImageByURL) > 0
+}
+
 func (c config) matchesSceneURL(url string) bool {
 	for _, scraper := range c.SceneByURL {
 		if scraper.matchesURL(url) {
@@ -388,8 +449,17 @@ func (c config) matchesGalleryURL(url string) bool {
 	return false
 }
 
+func (c config) matchesImageURL(url string) bool {
+	for _, scraper := range c.ImageByURL {
+		if scraper.matchesURL(url) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c config) supportsMovies() bool {
-	return len(c.MovieByURL) > 0
+	return c.MovieByName != nil || c.MovieByFragment != nil || len(c.MovieByURL) > 0
 }
 
 func (c config) matchesMovieURL(url string) bool {
@@ -484,6 +554,65 @@ func (c config) ScrapeGalleryURL(url string, txnManager models.TransactionManage
 	return nil, nil
 }
 
+func (c config) ScrapeImageByImage(image *models.Image, txnManager models.TransactionManager, globalConfig GlobalConfig) (*models.ScrapedImage, error) {
+	if c.ImageByFragment != nil {
+		s := getScraper(*c.ImageByFragment, txnManager, c, globalConfig)
+		return s.scrapeImageByImage(image)
+	}
+
+	return nil, nil
+}
+
+func (c config) ScrapeImageByFragment(image models.ScrapedImageInput, txnManager models.TransactionManager, globalConfig GlobalConfig) (*models.ScrapedImage, error) {
+	if c.ImageByFragment != nil {
+		s := getScraper(*c.ImageByFragment, txnManager, c, globalConfig)
+		return s.scrapeImageByFragment(image)
+	}
+
+	return nil, nil
+}
+
+func (c config) ScrapeImageURL(url string, txnManager models.TransactionManager, globalConfig GlobalConfig) (*models.ScrapedImage, error) {
+	for _, scraper := range c.ImageByURL {
+		if scraper.matchesURL(url) {
+			s := getScraper(scraper.scraperTypeConfig, txnManager, c, globalConfig)
+			ret, err := s.scrapeImageByURL(url)
+			if err != nil {
+				return nil, err
+			}
+
+			if ret != nil {
+				return ret, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (c config) ScrapeMovieNames(name string, txnManager models.TransactionManager, globalConfig GlobalConfig) ([]*models.ScrapedMovie, error) {
+	if c.MovieByName != nil {
+		s := getScraper(*c.MovieByName, txnManager, c, globalConfig)
+		return s.scrapeMoviesByName(name)
+	}
+
+	return nil, nil
+}
+
+func (c config) ScrapeMovie(scrapedMovie models.ScrapedMovieInput, txnManager models.TransactionManager, globalConfig GlobalConfig) (*models.ScrapedMovie, error) {
+	if c.MovieByFragment != nil {
+		s := getScraper(*c.MovieByFragment, txnManager, c, globalConfig)
+		return s.scrapeMovieByFragment(scrapedMovie)
+	}
+
+	// try to match against URL if present
+	if scrapedMovie.URL != nil && *scrapedMovie.URL != "" {
+		return c.ScrapeMovieURL(*scrapedMovie.URL, txnManager, globalConfig)
+	}
+
+	return nil, nil
+}
+
 func (c config) ScrapeMovieURL(url string, txnManager models.TransactionManager, globalConfig GlobalConfig) (*models.ScrapedMovie, error) {
 	for _, scraper := range c.MovieByURL {
 		if scraper.matchesURL(url) {
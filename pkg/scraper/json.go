@@ -103,6 +103,17 @@ func (s *jsonScraper) scrapeGalleryByURL(url string) (*models.ScrapedGallery, er
 	return scraper.scrapeGallery(q)
 }
 
+func (s *jsonScraper) scrapeImageByURL(url string) (*models.ScrapedImage, error) {
+	u := replaceURL(url, s.scraper) // allow a URL Replace for image by URL queries
+	doc, scraper, err := s.scrapeURL(u)
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getJsonQuery(doc)
+	return scraper.scrapeImage(q)
+}
+
 func (s *jsonScraper) scrapeMovieByURL(url string) (*models.ScrapedMovie, error) {
 	u := replaceURL(url, s.scraper) // allow a URL Replace for movie by URL queries
 	doc, scraper, err := s.scrapeURL(u)
@@ -244,6 +255,94 @@ func (s *jsonScraper) scrapeGalleryByFragment(gallery models.ScrapedGalleryInput
 	return nil, errors.New("scrapeGalleryByFragment not supported for json scraper")
 }
 
+func (s *jsonScraper) scrapeImageByImage(image *models.Image) (*models.ScrapedImage, error) {
+	// construct the URL
+	queryURL := queryURLParametersFromImage(image)
+	if s.scraper.QueryURLReplacements != nil {
+		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
+	}
+	url := queryURL.constructURL(s.scraper.QueryURL)
+
+	return s.scrapeImageByQueryURL(url)
+}
+
+func (s *jsonScraper) scrapeImageByFragment(image models.ScrapedImageInput) (*models.ScrapedImage, error) {
+	// construct the URL
+	queryURL := queryURLParametersFromScrapedImage(image)
+	if s.scraper.QueryURLReplacements != nil {
+		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
+	}
+	url := queryURL.constructURL(s.scraper.QueryURL)
+
+	return s.scrapeImageByQueryURL(url)
+}
+
+func (s *jsonScraper) scrapeImageByQueryURL(url string) (*models.ScrapedImage, error) {
+	scraper := s.getJsonScraper()
+
+	if scraper == nil {
+		return nil, errors.New("json scraper with name " + s.scraper.Scraper + " not found in config")
+	}
+
+	doc, err := s.loadURL(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getJsonQuery(doc)
+	return scraper.scrapeImage(q)
+}
+
+func (s *jsonScraper) scrapeMoviesByName(name string) ([]*models.ScrapedMovie, error) {
+	scraper := s.getJsonScraper()
+
+	if scraper == nil {
+		return nil, errors.New("json scraper with name " + s.scraper.Scraper + " not found in config")
+	}
+
+	const placeholder = "{}"
+
+	// replace the placeholder string with the URL-escaped name
+	escapedName := url.QueryEscape(name)
+
+	url := s.scraper.QueryURL
+	url = strings.Replace(url, placeholder, escapedName, -1)
+
+	doc, err := s.loadURL(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getJsonQuery(doc)
+	return scraper.scrapeMovies(q)
+}
+
+func (s *jsonScraper) scrapeMovieByFragment(movie models.ScrapedMovieInput) (*models.ScrapedMovie, error) {
+	// construct the URL
+	queryURL := queryURLParametersFromScrapedMovie(movie)
+	if s.scraper.QueryURLReplacements != nil {
+		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
+	}
+	url := queryURL.constructURL(s.scraper.QueryURL)
+
+	scraper := s.getJsonScraper()
+
+	if scraper == nil {
+		return nil, errors.New("json scraper with name " + s.scraper.Scraper + " not found in config")
+	}
+
+	doc, err := s.loadURL(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getJsonQuery(doc)
+	return scraper.scrapeMovie(q)
+}
+
 func (s *jsonScraper) getJsonQuery(doc string) *jsonQuery {
 	return &jsonQuery{
 		doc:     doc,
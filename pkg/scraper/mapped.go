@@ -211,6 +211,60 @@ func (s *mappedGalleryScraperConfig) UnmarshalYAML(unmarshal func(interface{}) e
 	return nil
 }
 
+type mappedImageScraperConfig struct {
+	mappedConfig
+
+	Tags       mappedConfig `yaml:"Tags"`
+	Performers mappedConfig `yaml:"Performers"`
+	Studio     mappedConfig `yaml:"Studio"`
+}
+type _mappedImageScraperConfig mappedImageScraperConfig
+
+func (s *mappedImageScraperConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// HACK - unmarshal to map first, then remove known scene sub-fields, then
+	// remarshal to yaml and pass that down to the base map
+	parentMap := make(map[string]interface{})
+	if err := unmarshal(parentMap); err != nil {
+		return err
+	}
+
+	// move the known sub-fields to a separate map
+	thisMap := make(map[string]interface{})
+
+	thisMap[mappedScraperConfigSceneTags] = parentMap[mappedScraperConfigSceneTags]
+	thisMap[mappedScraperConfigScenePerformers] = parentMap[mappedScraperConfigScenePerformers]
+	thisMap[mappedScraperConfigSceneStudio] = parentMap[mappedScraperConfigSceneStudio]
+
+	delete(parentMap, mappedScraperConfigSceneTags)
+	delete(parentMap, mappedScraperConfigScenePerformers)
+	delete(parentMap, mappedScraperConfigSceneStudio)
+
+	// re-unmarshal the sub-fields
+	yml, err := yaml.Marshal(thisMap)
+	if err != nil {
+		return err
+	}
+
+	// needs to be a different type to prevent infinite recursion
+	c := _mappedImageScraperConfig{}
+	if err := yaml.Unmarshal(yml, &c); err != nil {
+		return err
+	}
+
+	*s = mappedImageScraperConfig(c)
+
+	yml, err = yaml.Marshal(parentMap)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(yml, &s.mappedConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 type mappedPerformerScraperConfig struct {
 	mappedConfig
 
@@ -697,6 +751,7 @@ type mappedScraper struct {
 	Common    commonMappedConfig            `yaml:"common"`
 	Scene     *mappedSceneScraperConfig     `yaml:"scene"`
 	Gallery   *mappedGalleryScraperConfig   `yaml:"gallery"`
+	Image     *mappedImageScraperConfig     `yaml:"image"`
 	Performer *mappedPerformerScraperConfig `yaml:"performer"`
 	Movie     *mappedMovieScraperConfig     `yaml:"movie"`
 }
@@ -958,24 +1013,50 @@ func (s mappedScraper) scrapeGallery(q mappedQuery) (*models.ScrapedGallery, err
 	return &ret, nil
 }
 
-func (s mappedScraper) scrapeMovie(q mappedQuery) (*models.ScrapedMovie, error) {
-	var ret models.ScrapedMovie
+func (s mappedScraper) scrapeImage(q mappedQuery) (*models.ScrapedImage, error) {
+	var ret models.ScrapedImage
 
-	movieScraperConfig := s.Movie
-	movieMap := movieScraperConfig.mappedConfig
-	if movieMap == nil {
+	imageScraperConfig := s.Image
+	if imageScraperConfig == nil || imageScraperConfig.mappedConfig == nil {
 		return nil, nil
 	}
 
-	movieStudioMap := movieScraperConfig.Studio
+	imageMap := imageScraperConfig.mappedConfig
+	imagePerformersMap := imageScraperConfig.Performers
+	imageTagsMap := imageScraperConfig.Tags
+	imageStudioMap := imageScraperConfig.Studio
 
-	results := movieMap.process(q, s.Common)
+	logger.Debug(`Processing image:`)
+	results := imageMap.process(q, s.Common)
 	if len(results) > 0 {
 		results[0].apply(&ret)
 
-		if movieStudioMap != nil {
-			logger.Debug(`Processing movie studio:`)
-			studioResults := movieStudioMap.process(q, s.Common)
+		// now apply the performers and tags
+		if imagePerformersMap != nil {
+			logger.Debug(`Processing image performers:`)
+			performerResults := imagePerformersMap.process(q, s.Common)
+
+			for _, p := range performerResults {
+				performer := &models.ScrapedPerformer{}
+				p.apply(performer)
+				ret.Performers = append(ret.Performers, performer)
+			}
+		}
+
+		if imageTagsMap != nil {
+			logger.Debug(`Processing image tags:`)
+			tagResults := imageTagsMap.process(q, s.Common)
+
+			for _, p := range tagResults {
+				tag := &models.ScrapedTag{}
+				p.apply(tag)
+				ret.Tags = append(ret.Tags, tag)
+			}
+		}
+
+		if imageStudioMap != nil {
+			logger.Debug(`Processing image studio:`)
+			studioResults := imageStudioMap.process(q, s.Common)
 
 			if len(studioResults) > 0 {
 				studio := &models.ScrapedStudio{}
@@ -987,3 +1068,60 @@ func (s mappedScraper) scrapeMovie(q mappedQuery) (*models.ScrapedMovie, error)
 
 	return &ret, nil
 }
+
+func (s mappedScraper) processMovie(q mappedQuery, r mappedResult) *models.ScrapedMovie {
+	var ret models.ScrapedMovie
+
+	movieStudioMap := s.Movie.Studio
+
+	r.apply(&ret)
+
+	if movieStudioMap != nil {
+		logger.Debug(`Processing movie studio:`)
+		studioResults := movieStudioMap.process(q, s.Common)
+
+		if len(studioResults) > 0 {
+			studio := &models.ScrapedStudio{}
+			studioResults[0].apply(studio)
+			ret.Studio = studio
+		}
+	}
+
+	return &ret
+}
+
+func (s mappedScraper) scrapeMovies(q mappedQuery) ([]*models.ScrapedMovie, error) {
+	var ret []*models.ScrapedMovie
+
+	movieScraperConfig := s.Movie
+	movieMap := movieScraperConfig.mappedConfig
+	if movieMap == nil {
+		return nil, nil
+	}
+
+	logger.Debug(`Processing movies:`)
+	results := movieMap.process(q, s.Common)
+	for _, r := range results {
+		logger.Debug(`Processing movie:`)
+		ret = append(ret, s.processMovie(q, r))
+	}
+
+	return ret, nil
+}
+
+func (s mappedScraper) scrapeMovie(q mappedQuery) (*models.ScrapedMovie, error) {
+	var ret models.ScrapedMovie
+
+	movieScraperConfig := s.Movie
+	movieMap := movieScraperConfig.mappedConfig
+	if movieMap == nil {
+		return nil, nil
+	}
+
+	results := movieMap.process(q, s.Common)
+	if len(results) > 0 {
+		ret = *s.processMovie(q, results[0])
+	}
+
+	return &ret, nil
+}
@@ -57,6 +57,46 @@ func queryURLParametersFromGallery(gallery *models.Gallery) queryURLParameters {
 	return ret
 }
 
+func queryURLParametersFromImage(image *models.Image) queryURLParameters {
+	ret := make(queryURLParameters)
+	ret["checksum"] = image.Checksum
+	ret["filename"] = filepath.Base(image.Path)
+	ret["title"] = image.Title.String
+
+	return ret
+}
+
+func queryURLParametersFromScrapedImage(image models.ScrapedImageInput) queryURLParameters {
+	ret := make(queryURLParameters)
+
+	setField := func(field string, value *string) {
+		if value != nil {
+			ret[field] = *value
+		}
+	}
+
+	setField("title", image.Title)
+	setField("date", image.Date)
+	return ret
+}
+
+func queryURLParametersFromScrapedMovie(movie models.ScrapedMovieInput) queryURLParameters {
+	ret := make(queryURLParameters)
+
+	setField := func(field string, value *string) {
+		if value != nil {
+			ret[field] = *value
+		}
+	}
+
+	setField("name", movie.Name)
+	setField("aliases", movie.Aliases)
+	setField("date", movie.Date)
+	setField("director", movie.Director)
+	setField("url", movie.URL)
+	return ret
+}
+
 func (p queryURLParameters) applyReplacements(r queryURLReplacements) {
 	for k, v := range p {
 		rpl, found := r[k]
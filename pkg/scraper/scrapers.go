@@ -150,6 +150,20 @@ func (c Cache) ListGalleryScrapers() []*models.Scraper {
 	return ret
 }
 
+// ListImageScrapers returns a list of scrapers that are capable of
+// scraping images.
+func (c Cache) ListImageScrapers() []*models.Scraper {
+	var ret []*models.Scraper
+	for _, s := range c.scrapers {
+		// filter on type
+		if s.supportsImages() {
+			ret = append(ret, s.toScraper())
+		}
+	}
+
+	return ret
+}
+
 // ListMovieScrapers returns a list of scrapers that are capable of
 // scraping scenes.
 func (c Cache) ListMovieScrapers() []*models.Scraper {
@@ -361,6 +375,40 @@ func (c Cache) postScrapeGallery(ret *models.ScrapedGallery) error {
 	return nil
 }
 
+func (c Cache) postScrapeImage(ret *models.ScrapedImage) error {
+	if err := c.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		pqb := r.Performer()
+		tqb := r.Tag()
+		sqb := r.Studio()
+
+		for _, p := range ret.Performers {
+			err := MatchScrapedPerformer(pqb, p)
+			if err != nil {
+				return err
+			}
+		}
+
+		tags, err := postProcessTags(tqb, ret.Tags)
+		if err != nil {
+			return err
+		}
+		ret.Tags = tags
+
+		if ret.Studio != nil {
+			err := MatchScrapedStudio(sqb, ret.Studio)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ScrapeScene uses the scraper with the provided ID to scrape a scene using existing data.
 func (c Cache) ScrapeScene(scraperID string, sceneID int) (*models.ScrapedScene, error) {
 	// find scraper with the provided id
@@ -528,6 +576,184 @@ func (c Cache) ScrapeGalleryURL(url string) (*models.ScrapedGallery, error) {
 	return nil, nil
 }
 
+// ScrapeImage uses the scraper with the provided ID to scrape an image using existing data.
+func (c Cache) ScrapeImage(scraperID string, imageID int) (*models.ScrapedImage, error) {
+	s := c.findScraper(scraperID)
+	if s != nil {
+		// get image from id
+		image, err := getImageByID(imageID, c.txnManager)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := s.ScrapeImageByImage(image, c.txnManager, c.globalConfig)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ret != nil {
+			err = c.postScrapeImage(ret)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ret, nil
+	}
+
+	return nil, errors.New("Scraper with ID " + scraperID + " not found")
+}
+
+// ScrapeImageFragment uses the scraper with the provided ID to scrape an image.
+func (c Cache) ScrapeImageFragment(scraperID string, image models.ScrapedImageInput) (*models.ScrapedImage, error) {
+	s := c.findScraper(scraperID)
+	if s != nil {
+		ret, err := s.ScrapeImageByFragment(image, c.txnManager, c.globalConfig)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ret != nil {
+			err = c.postScrapeImage(ret)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ret, nil
+	}
+
+	return nil, errors.New("Scraper with ID " + scraperID + " not found")
+}
+
+// ScrapeImageURL uses the first scraper it finds that matches the URL
+// provided to scrape an image. If no scrapers are found that matches
+// the URL, then nil is returned.
+func (c Cache) ScrapeImageURL(url string) (*models.ScrapedImage, error) {
+	for _, s := range c.scrapers {
+		if s.matchesImageURL(url) {
+			ret, err := s.ScrapeImageURL(url, c.txnManager, c.globalConfig)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if ret != nil {
+				err = c.postScrapeImage(ret)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return ret, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// matchScrapedMovies matches the provided movies and their studios with
+// the movies and studios in the database.
+func (c Cache) matchScrapedMovies(movies []*models.ScrapedMovie) error {
+	return c.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		mqb := r.Movie()
+		sqb := r.Studio()
+
+		for _, m := range movies {
+			if err := MatchScrapedMovie(mqb, m); err != nil {
+				return err
+			}
+
+			if m.Studio != nil {
+				if err := MatchScrapedStudio(sqb, m.Studio); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (c Cache) postScrapeMovie(ret *models.ScrapedMovie) error {
+	if err := c.matchScrapedMovies([]*models.ScrapedMovie{ret}); err != nil {
+		return err
+	}
+
+	// post-process - set the image if applicable
+	if err := setMovieFrontImage(ret, c.globalConfig); err != nil {
+		logger.Warnf("Could not set front image using URL %s: %s", *ret.FrontImage, err.Error())
+	}
+	if err := setMovieBackImage(ret, c.globalConfig); err != nil {
+		logger.Warnf("Could not set back image using URL %s: %s", *ret.BackImage, err.Error())
+	}
+
+	return nil
+}
+
+// ScrapeMovieList uses the scraper with the provided ID to query for
+// movies using the provided query string. It returns a list of
+// scraped movie data.
+func (c Cache) ScrapeMovieList(scraperID string, query string) ([]*models.ScrapedMovie, error) {
+	// find scraper with the provided id
+	s := c.findScraper(scraperID)
+	if s != nil {
+		ret, err := s.ScrapeMovieNames(query, c.txnManager, c.globalConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.matchScrapedMovies(ret); err != nil {
+			return nil, err
+		}
+
+		return ret, nil
+	}
+
+	return nil, errors.New("Scraper with ID " + scraperID + " not found")
+}
+
+// ScrapeMovie uses the scraper with the provided ID to scrape a
+// movie using the provided movie fragment.
+func (c Cache) ScrapeMovie(scraperID string, scrapedMovie models.ScrapedMovieInput) (*models.ScrapedMovie, error) {
+	// find scraper with the provided id
+	s := c.findScraper(scraperID)
+	if s != nil {
+		ret, err := s.ScrapeMovie(scrapedMovie, c.txnManager, c.globalConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		if ret != nil {
+			err = c.postScrapeMovie(ret)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return ret, nil
+	}
+
+	return nil, errors.New("Scraper with ID " + scraperID + " not found")
+}
+
+// ScrapeMovieByID scrapes the movie with the provided id, using its current
+// fields as the movie fragment.
+func (c Cache) ScrapeMovieByID(scraperID string, movieID int) (*models.ScrapedMovie, error) {
+	if c.findScraper(scraperID) == nil {
+		return nil, errors.New("Scraper with ID " + scraperID + " not found")
+	}
+
+	movie, err := getMovie(movieID, c.txnManager)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ScrapeMovie(scraperID, movieToScrapedInput(movie))
+}
+
 // ScrapeMovieURL uses the first scraper it finds that matches the URL
 // provided to scrape a movie. If no scrapers are found that matches
 // the URL, then nil is returned.
@@ -539,22 +765,13 @@ func (c Cache) ScrapeMovieURL(url string) (*models.ScrapedMovie, error) {
 				return nil, err
 			}
 
-			if ret.Studio != nil {
-				if err := c.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
-					return MatchScrapedStudio(r.Studio(), ret.Studio)
-				}); err != nil {
+			if ret != nil {
+				err = c.postScrapeMovie(ret)
+				if err != nil {
 					return nil, err
 				}
 			}
 
-			// post-process - set the image if applicable
-			if err := setMovieFrontImage(ret, c.globalConfig); err != nil {
-				logger.Warnf("Could not set front image using URL %s: %s", *ret.FrontImage, err.Error())
-			}
-			if err := setMovieBackImage(ret, c.globalConfig); err != nil {
-				logger.Warnf("Could not set back image using URL %s: %s", *ret.BackImage, err.Error())
-			}
-
 			return ret, nil
 		}
 	}
@@ -208,6 +208,34 @@ func (s *scriptScraper) scrapeGalleryByFragment(gallery models.ScrapedGalleryInp
 	return &ret, err
 }
 
+func (s *scriptScraper) scrapeImageByImage(image *models.Image) (*models.ScrapedImage, error) {
+	inString, err := json.Marshal(imageToUpdateInput(image))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var ret models.ScrapedImage
+
+	err = s.runScraperScript(string(inString), &ret)
+
+	return &ret, err
+}
+
+func (s *scriptScraper) scrapeImageByFragment(image models.ScrapedImageInput) (*models.ScrapedImage, error) {
+	inString, err := json.Marshal(image)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var ret models.ScrapedImage
+
+	err = s.runScraperScript(string(inString), &ret)
+
+	return &ret, err
+}
+
 func (s *scriptScraper) scrapeSceneByURL(url string) (*models.ScrapedScene, error) {
 	inString := `{"url": "` + url + `"}`
 
@@ -228,6 +256,48 @@ func (s *scriptScraper) scrapeGalleryByURL(url string) (*models.ScrapedGallery,
 	return &ret, err
 }
 
+func (s *scriptScraper) scrapeImageByURL(url string) (*models.ScrapedImage, error) {
+	inString := `{"url": "` + url + `"}`
+
+	var ret models.ScrapedImage
+
+	err := s.runScraperScript(string(inString), &ret)
+
+	return &ret, err
+}
+
+func (s *scriptScraper) scrapeMoviesByName(name string) ([]*models.ScrapedMovie, error) {
+	inString := `{"name": "` + name + `"}`
+
+	var movies []models.ScrapedMovie
+
+	err := s.runScraperScript(inString, &movies)
+
+	// convert to pointers
+	var ret []*models.ScrapedMovie
+	if err == nil {
+		for i := 0; i < len(movies); i++ {
+			ret = append(ret, &movies[i])
+		}
+	}
+
+	return ret, err
+}
+
+func (s *scriptScraper) scrapeMovieByFragment(movie models.ScrapedMovieInput) (*models.ScrapedMovie, error) {
+	inString, err := json.Marshal(movie)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var ret models.ScrapedMovie
+
+	err = s.runScraperScript(string(inString), &ret)
+
+	return &ret, err
+}
+
 func (s *scriptScraper) scrapeMovieByURL(url string) (*models.ScrapedMovie, error) {
 	inString := `{"url": "` + url + `"}`
 
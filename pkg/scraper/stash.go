@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/jinzhu/copier"
@@ -207,13 +208,26 @@ func (s *stashScraper) scrapeScenesByName(name string) ([]*models.ScrapedScene,
 	return ret, nil
 }
 
+// scrapedSceneFileStash is the file metadata queried from the stash
+// server. Tracks are omitted, as older servers do not support them.
+type scrapedSceneFileStash struct {
+	Size       *string  `graphql:"size" json:"size"`
+	Duration   *float64 `graphql:"duration" json:"duration"`
+	VideoCodec *string  `graphql:"video_codec" json:"video_codec"`
+	AudioCodec *string  `graphql:"audio_codec" json:"audio_codec"`
+	Width      *int     `graphql:"width" json:"width"`
+	Height     *int     `graphql:"height" json:"height"`
+	Framerate  *float64 `graphql:"framerate" json:"framerate"`
+	Bitrate    *int     `graphql:"bitrate" json:"bitrate"`
+}
+
 type scrapedSceneStash struct {
 	ID         string                   `graphql:"id" json:"id"`
 	Title      *string                  `graphql:"title" json:"title"`
 	Details    *string                  `graphql:"details" json:"details"`
 	URL        *string                  `graphql:"url" json:"url"`
 	Date       *string                  `graphql:"date" json:"date"`
-	File       *models.SceneFileType    `graphql:"file" json:"file"`
+	File       *scrapedSceneFileStash   `graphql:"file" json:"file"`
 	Studio     *scrapedStudioStash      `graphql:"studio" json:"studio"`
 	Tags       []*scrapedTagStash       `graphql:"tags" json:"tags"`
 	Performers []*scrapedPerformerStash `graphql:"performers" json:"performers"`
@@ -269,7 +283,7 @@ type scrapedGalleryStash struct {
 	Details    *string                  `graphql:"details" json:"details"`
 	URL        *string                  `graphql:"url" json:"url"`
 	Date       *string                  `graphql:"date" json:"date"`
-	File       *models.SceneFileType    `graphql:"file" json:"file"`
+	File       *scrapedSceneFileStash   `graphql:"file" json:"file"`
 	Studio     *scrapedStudioStash      `graphql:"studio" json:"studio"`
 	Tags       []*scrapedTagStash       `graphql:"tags" json:"tags"`
 	Performers []*scrapedPerformerStash `graphql:"performers" json:"performers"`
@@ -310,6 +324,145 @@ func (s *stashScraper) scrapeGalleryByFragment(scene models.ScrapedGalleryInput)
 	return nil, errors.New("scrapeGalleryByFragment not supported for stash scraper")
 }
 
+type scrapedImageStash struct {
+	ID         string                   `graphql:"id" json:"id"`
+	Title      *string                  `graphql:"title" json:"title"`
+	Date       *string                  `graphql:"date" json:"date"`
+	Studio     *scrapedStudioStash      `graphql:"studio" json:"studio"`
+	Tags       []*scrapedTagStash       `graphql:"tags" json:"tags"`
+	Performers []*scrapedPerformerStash `graphql:"performers" json:"performers"`
+}
+
+func (s *stashScraper) scrapeImageByImage(image *models.Image) (*models.ScrapedImage, error) {
+	var q struct {
+		FindImage *scrapedImageStash `graphql:"findImage(checksum: $c)"`
+	}
+
+	vars := map[string]interface{}{
+		"c": graphql.String(image.Checksum),
+	}
+
+	client := s.getStashClient()
+	if err := client.Query(context.Background(), &q, vars); err != nil {
+		return nil, err
+	}
+
+	if q.FindImage == nil {
+		return nil, nil
+	}
+
+	// need to copy back to a scraped image
+	ret := models.ScrapedImage{}
+	if err := copier.Copy(&ret, q.FindImage); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (s *stashScraper) scrapeImageByFragment(image models.ScrapedImageInput) (*models.ScrapedImage, error) {
+	return nil, errors.New("scrapeImageByFragment not supported for stash scraper")
+}
+
+type stashFindMovieNameMovie struct {
+	ID   string `json:"id" graphql:"id"`
+	Name string `json:"name" graphql:"name"`
+}
+
+func (m stashFindMovieNameMovie) toMovie() *models.ScrapedMovie {
+	return &models.ScrapedMovie{
+		Name: &m.Name,
+		// put id into the URL field
+		URL: &m.ID,
+	}
+}
+
+type stashFindMovieNamesResultType struct {
+	Count  int                        `graphql:"count"`
+	Movies []*stashFindMovieNameMovie `graphql:"movies"`
+}
+
+func (s *stashScraper) scrapeMoviesByName(name string) ([]*models.ScrapedMovie, error) {
+	client := s.getStashClient()
+
+	var q struct {
+		FindMovies stashFindMovieNamesResultType `graphql:"findMovies(filter: $f)"`
+	}
+
+	page := 1
+	perPage := 10
+
+	vars := map[string]interface{}{
+		"f": models.FindFilterType{
+			Q:       &name,
+			Page:    &page,
+			PerPage: &perPage,
+		},
+	}
+
+	err := client.Query(context.Background(), &q, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*models.ScrapedMovie
+	for _, m := range q.FindMovies.Movies {
+		ret = append(ret, m.toMovie())
+	}
+
+	return ret, nil
+}
+
+// need a separate for scraped stash movies - duration and rating are not strings
+type scrapedMovieStash struct {
+	Name       *string             `graphql:"name" json:"name"`
+	Aliases    *string             `graphql:"aliases" json:"aliases"`
+	Date       *string             `graphql:"date" json:"date"`
+	Director   *string             `graphql:"director" json:"director"`
+	URL        *string             `graphql:"url" json:"url"`
+	Synopsis   *string             `graphql:"synopsis" json:"synopsis"`
+	Studio     *scrapedStudioStash `graphql:"studio" json:"studio"`
+	FrontImage *string             `graphql:"front_image_path" json:"front_image_path"`
+	BackImage  *string             `graphql:"back_image_path" json:"back_image_path"`
+}
+
+func (s *stashScraper) scrapeMovieByFragment(movie models.ScrapedMovieInput) (*models.ScrapedMovie, error) {
+	if movie.URL == nil {
+		return nil, errors.New("movie id must be set in the url field")
+	}
+
+	client := s.getStashClient()
+
+	var q struct {
+		FindMovie *scrapedMovieStash `graphql:"findMovie(id: $f)"`
+	}
+
+	// get the id from the URL field
+	vars := map[string]interface{}{
+		"f": graphql.ID(*movie.URL),
+	}
+
+	err := client.Query(context.Background(), &q, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.FindMovie == nil {
+		return nil, nil
+	}
+
+	// need to copy back to a scraped movie
+	ret := models.ScrapedMovie{}
+	err = copier.Copy(&ret, q.FindMovie)
+	if err != nil {
+		return nil, err
+	}
+
+	// the image paths are URLs of the remote stash server and are
+	// downloaded during post-processing
+	return &ret, nil
+}
+
 func (s *stashScraper) scrapePerformerByURL(url string) (*models.ScrapedPerformer, error) {
 	return nil, errors.New("scrapePerformerByURL not supported for stash scraper")
 }
@@ -322,6 +475,10 @@ func (s *stashScraper) scrapeGalleryByURL(url string) (*models.ScrapedGallery, e
 	return nil, errors.New("scrapeGalleryByURL not supported for stash scraper")
 }
 
+func (s *stashScraper) scrapeImageByURL(url string) (*models.ScrapedImage, error) {
+	return nil, errors.New("scrapeImageByURL not supported for stash scraper")
+}
+
 func (s *stashScraper) scrapeMovieByURL(url string) (*models.ScrapedMovie, error) {
 	return nil, errors.New("scrapeMovieByURL not supported for stash scraper")
 }
@@ -401,3 +558,90 @@ func galleryToUpdateInput(gallery *models.Gallery) models.GalleryUpdateInput {
 		Date:    dateToStringPtr(gallery.Date),
 	}
 }
+
+func getImageByID(imageID int, txnManager models.TransactionManager) (*models.Image, error) {
+	var ret *models.Image
+	if err := txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		var err error
+		ret, err = r.Image().Find(imageID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func imageToUpdateInput(image *models.Image) models.ImageUpdateInput {
+	toStringPtr := func(s sql.NullString) *string {
+		if s.Valid {
+			return &s.String
+		}
+
+		return nil
+	}
+
+	dateToStringPtr := func(s models.SQLiteDate) *string {
+		if s.Valid {
+			return &s.String
+		}
+
+		return nil
+	}
+
+	return models.ImageUpdateInput{
+		ID:    strconv.Itoa(image.ID),
+		Title: toStringPtr(image.Title),
+		Date:  dateToStringPtr(image.Date),
+	}
+}
+
+func getMovie(movieID int, txnManager models.TransactionManager) (*models.Movie, error) {
+	var ret *models.Movie
+	if err := txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		var err error
+		ret, err = r.Movie().Find(movieID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if ret == nil {
+		return nil, fmt.Errorf("movie with id %d not found", movieID)
+	}
+
+	return ret, nil
+}
+
+func movieToScrapedInput(movie *models.Movie) models.ScrapedMovieInput {
+	toStringPtr := func(s sql.NullString) *string {
+		if s.Valid {
+			return &s.String
+		}
+
+		return nil
+	}
+
+	dateToStringPtr := func(s models.SQLiteDate) *string {
+		if s.Valid {
+			return &s.String
+		}
+
+		return nil
+	}
+
+	ret := models.ScrapedMovieInput{
+		Name:     toStringPtr(movie.Name),
+		Aliases:  toStringPtr(movie.Aliases),
+		Date:     dateToStringPtr(movie.Date),
+		Director: toStringPtr(movie.Director),
+		URL:      toStringPtr(movie.URL),
+		Synopsis: toStringPtr(movie.Synopsis),
+	}
+
+	if movie.Duration.Valid {
+		duration := strconv.FormatInt(movie.Duration.Int64, 10)
+		ret.Duration = &duration
+	}
+
+	return ret
+}
@@ -84,6 +84,17 @@ func (s *xpathScraper) scrapeGalleryByURL(url string) (*models.ScrapedGallery, e
 	return scraper.scrapeGallery(q)
 }
 
+func (s *xpathScraper) scrapeImageByURL(url string) (*models.ScrapedImage, error) {
+	u := replaceURL(url, s.scraper) // allow a URL Replace for image by URL queries
+	doc, scraper, err := s.scrapeURL(u)
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getXPathQuery(doc)
+	return scraper.scrapeImage(q)
+}
+
 func (s *xpathScraper) scrapeMovieByURL(url string) (*models.ScrapedMovie, error) {
 	u := replaceURL(url, s.scraper) // allow a URL Replace for movie by URL queries
 	doc, scraper, err := s.scrapeURL(u)
@@ -225,6 +236,45 @@ func (s *xpathScraper) scrapeGalleryByFragment(gallery models.ScrapedGalleryInpu
 	return nil, errors.New("scrapeGalleryByFragment not supported for xpath scraper")
 }
 
+func (s *xpathScraper) scrapeImageByImage(image *models.Image) (*models.ScrapedImage, error) {
+	// construct the URL
+	queryURL := queryURLParametersFromImage(image)
+	if s.scraper.QueryURLReplacements != nil {
+		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
+	}
+	url := queryURL.constructURL(s.scraper.QueryURL)
+
+	return s.scrapeImageByQueryURL(url)
+}
+
+func (s *xpathScraper) scrapeImageByFragment(image models.ScrapedImageInput) (*models.ScrapedImage, error) {
+	// construct the URL
+	queryURL := queryURLParametersFromScrapedImage(image)
+	if s.scraper.QueryURLReplacements != nil {
+		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
+	}
+	url := queryURL.constructURL(s.scraper.QueryURL)
+
+	return s.scrapeImageByQueryURL(url)
+}
+
+func (s *xpathScraper) scrapeImageByQueryURL(url string) (*models.ScrapedImage, error) {
+	scraper := s.getXpathScraper()
+
+	if scraper == nil {
+		return nil, errors.New("xpath scraper with name " + s.scraper.Scraper + " not found in config")
+	}
+
+	doc, err := s.loadURL(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getXPathQuery(doc)
+	return scraper.scrapeImage(q)
+}
+
 func (s *xpathScraper) loadURL(url string) (*html.Node, error) {
 	r, err := loadURL(url, s.config, s.globalConfig)
 	if err != nil {
@@ -242,6 +292,55 @@ func (s *xpathScraper) loadURL(url string) (*html.Node, error) {
 	return ret, err
 }
 
+func (s *xpathScraper) scrapeMoviesByName(name string) ([]*models.ScrapedMovie, error) {
+	scraper := s.getXpathScraper()
+
+	if scraper == nil {
+		return nil, errors.New("xpath scraper with name " + s.scraper.Scraper + " not found in config")
+	}
+
+	const placeholder = "{}"
+
+	// replace the placeholder string with the URL-escaped name
+	escapedName := url.QueryEscape(name)
+
+	url := s.scraper.QueryURL
+	url = strings.Replace(url, placeholder, escapedName, -1)
+
+	doc, err := s.loadURL(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getXPathQuery(doc)
+	return scraper.scrapeMovies(q)
+}
+
+func (s *xpathScraper) scrapeMovieByFragment(movie models.ScrapedMovieInput) (*models.ScrapedMovie, error) {
+	// construct the URL
+	queryURL := queryURLParametersFromScrapedMovie(movie)
+	if s.scraper.QueryURLReplacements != nil {
+		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
+	}
+	url := queryURL.constructURL(s.scraper.QueryURL)
+
+	scraper := s.getXpathScraper()
+
+	if scraper == nil {
+		return nil, errors.New("xpath scraper with name " + s.scraper.Scraper + " not found in config")
+	}
+
+	doc, err := s.loadURL(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getXPathQuery(doc)
+	return scraper.scrapeMovie(q)
+}
+
 func (s *xpathScraper) getXPathQuery(doc *html.Node) *xpathQuery {
 	return &xpathQuery{
 		doc:     doc,
@@ -883,3 +883,182 @@ xPathScrapers:
 
 	verifyField(t, "The name", performer.Name, "Name")
 }
+
+func TestScrapeImageXPath(t *testing.T) {
+	const imageHTML = `
+	<div>
+		<h1>Image title</h1>
+		<span class="date">2021-01-02</span>
+		<a class="performer">Performer 1</a>
+		<a class="performer">Performer 2</a>
+		<a class="tag">Tag</a>
+		<a class="studio">Studio</a>
+	</div>
+	`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, imageHTML)
+	}))
+	defer ts.Close()
+
+	yamlStr := `name: Test
+imageByURL:
+  - action: scrapeXPath
+    url: 
+      - ` + ts.URL + `
+    scraper: imageScraper
+imageByFragment:
+  action: scrapeXPath
+  queryURL: ` + ts.URL + `/search?q={title}
+  scraper: imageScraper
+xPathScrapers:
+  imageScraper:
+    image:
+      Title: //h1
+      Date: //span[@class="date"]
+      Performers:
+        Name: //a[@class="performer"]
+      Tags:
+        Name: //a[@class="tag"]
+      Studio:
+        Name: //a[@class="studio"]
+`
+
+	c := &config{}
+	err := yaml.Unmarshal([]byte(yamlStr), &c)
+
+	if err != nil {
+		t.Errorf("Error loading yaml: %s", err.Error())
+		return
+	}
+
+	assert.True(t, c.supportsImages())
+	assert.True(t, c.matchesImageURL(ts.URL))
+	assert.Equal(t, []models.ScrapeType{models.ScrapeTypeFragment, models.ScrapeTypeURL}, c.toScraper().Image.SupportedScrapes)
+
+	globalConfig := mockGlobalConfig{}
+
+	verifyImage := func(image *models.ScrapedImage) {
+		verifyField(t, "Image title", image.Title, "Title")
+		verifyField(t, "2021-01-02", image.Date, "Date")
+
+		if assert.Len(t, image.Performers, 2) {
+			verifyField(t, "Performer 1", image.Performers[0].Name, "Performers[0].Name")
+			verifyField(t, "Performer 2", image.Performers[1].Name, "Performers[1].Name")
+		}
+		if assert.Len(t, image.Tags, 1) {
+			assert.Equal(t, "Tag", image.Tags[0].Name)
+		}
+		if assert.NotNil(t, image.Studio) {
+			assert.Equal(t, "Studio", image.Studio.Name)
+		}
+	}
+
+	image, err := c.ScrapeImageURL(ts.URL, nil, globalConfig)
+
+	if err != nil {
+		t.Errorf("Error scraping image: %s", err.Error())
+		return
+	}
+
+	verifyImage(image)
+
+	title := "title"
+	image, err = c.ScrapeImageByFragment(models.ScrapedImageInput{
+		Title: &title,
+	}, nil, globalConfig)
+
+	if err != nil {
+		t.Errorf("Error scraping image: %s", err.Error())
+		return
+	}
+
+	verifyImage(image)
+}
+
+func TestScrapeMovieXPath(t *testing.T) {
+	const searchHTML = `
+	<div>
+		<a class="movie">Movie 1</a>
+		<a class="movie">Movie 2</a>
+		<a class="studio">Studio</a>
+	</div>
+	`
+
+	const movieHTML = `
+	<div>
+		<h1>Movie 1</h1>
+		<span class="director">Director</span>
+		<a class="studio">Studio</a>
+	</div>
+	`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search" {
+			fmt.Fprint(w, searchHTML)
+			return
+		}
+
+		fmt.Fprint(w, movieHTML)
+	}))
+	defer ts.Close()
+
+	yamlStr := `name: Test
+movieByName:
+  action: scrapeXPath
+  queryURL: ` + ts.URL + `/search?q={}
+  scraper: movieSearch
+movieByFragment:
+  action: scrapeXPath
+  queryURL: ` + ts.URL + `/movie?name={name}
+  scraper: movieScraper
+xPathScrapers:
+  movieSearch:
+    movie:
+      Name: //a[@class="movie"]
+  movieScraper:
+    movie:
+      Name: //h1
+      Director: //span[@class="director"]
+      Studio:
+        Name: //a[@class="studio"]
+`
+
+	c := &config{}
+	err := yaml.Unmarshal([]byte(yamlStr), &c)
+
+	if err != nil {
+		t.Errorf("Error loading yaml: %s", err.Error())
+		return
+	}
+
+	assert.True(t, c.supportsMovies())
+	assert.Equal(t, []models.ScrapeType{models.ScrapeTypeName, models.ScrapeTypeFragment}, c.toScraper().Movie.SupportedScrapes)
+
+	globalConfig := mockGlobalConfig{}
+
+	movies, err := c.ScrapeMovieNames("movie", nil, globalConfig)
+
+	if err != nil {
+		t.Errorf("Error scraping movies: %s", err.Error())
+		return
+	}
+
+	verifyMovies(t, []string{"Movie 1", "Movie 2"}, movies)
+
+	name := "movie"
+	movie, err := c.ScrapeMovie(models.ScrapedMovieInput{
+		Name: &name,
+	}, nil, globalConfig)
+
+	if err != nil {
+		t.Errorf("Error scraping movie: %s", err.Error())
+		return
+	}
+
+	verifyField(t, "Movie 1", movie.Name, "Name")
+	verifyField(t, "Director", movie.Director, "Director")
+	if assert.NotNil(t, movie.Studio) {
+		assert.Equal(t, "Studio", movie.Studio.Name)
+	}
+}
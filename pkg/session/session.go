@@ -7,8 +7,11 @@ import (
 
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
+	"github.com/stashapp/stash/pkg/database"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/user"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -38,15 +41,22 @@ const (
 var ErrInvalidCredentials = errors.New("invalid username or password")
 var ErrUnauthorized = errors.New("unauthorized")
 
+// legacyUserID is the user id stored in the session when logging in with the
+// credentials in the configuration file, which is only possible while the
+// database is not available.
+const legacyUserID = 0
+
 type Store struct {
 	sessionStore *sessions.CookieStore
 	config       *config.Instance
+	txnManager   models.TransactionManager
 }
 
-func NewStore(c *config.Instance) *Store {
+func NewStore(c *config.Instance, txnManager models.TransactionManager) *Store {
 	ret := &Store{
 		sessionStore: sessions.NewCookieStore(config.GetInstance().GetSessionStoreKey()),
 		config:       c,
+		txnManager:   txnManager,
 	}
 
 	ret.sessionStore.MaxAge(config.GetInstance().GetMaxSessionAge())
@@ -62,11 +72,31 @@ func (s *Store) Login(w http.ResponseWriter, r *http.Request) error {
 	password := r.FormValue(passwordFormKey)
 
 	// authenticate the user
-	if !config.GetInstance().ValidateCredentials(username, password) {
-		return ErrInvalidCredentials
+	userID := legacyUserID
+	if database.Ready() != nil {
+		// database is not available, so fall back to the credentials in
+		// the configuration file
+		if !s.config.HasCredentials() || !s.config.ValidateCredentials(username, password) {
+			return ErrInvalidCredentials
+		}
+	} else {
+		var u *models.User
+		if err := s.txnManager.WithReadTxn(r.Context(), func(repo models.ReaderRepository) error {
+			var err error
+			u, err = repo.User().FindByUsername(username)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		if u == nil || !user.CheckPassword(u, password) {
+			return ErrInvalidCredentials
+		}
+
+		userID = u.ID
 	}
 
-	newSession.Values[userIDKey] = username
+	newSession.Values[userIDKey] = userID
 
 	err := newSession.Save(r, w)
 	if err != nil {
@@ -93,12 +123,14 @@ func (s *Store) Logout(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
-func (s *Store) GetSessionUserID(w http.ResponseWriter, r *http.Request) (string, error) {
+// GetSessionUserID returns the user id stored in the session cookie. Returns
+// false if the session does not have a user id.
+func (s *Store) GetSessionUserID(w http.ResponseWriter, r *http.Request) (int, bool, error) {
 	session, err := s.sessionStore.Get(r, cookieName)
 	// ignore errors and treat as an empty user id, so that we handle expired
 	// cookie
 	if err != nil {
-		return "", nil
+		return 0, false, nil
 	}
 
 	if !session.IsNew {
@@ -107,32 +139,80 @@ func (s *Store) GetSessionUserID(w http.ResponseWriter, r *http.Request) (string
 		// refresh the cookie
 		err = session.Save(r, w)
 		if err != nil {
-			return "", err
+			return 0, false, err
 		}
 
-		ret, _ := val.(string)
+		ret, ok := val.(int)
+
+		return ret, ok, nil
+	}
+
+	return 0, false, nil
+}
+
+// AuthenticationRequired returns true if users must log in to access the
+// server. This is the case if any users exist, or, if the database is not
+// available, if credentials are set in the configuration file.
+func (s *Store) AuthenticationRequired(ctx context.Context) (bool, error) {
+	if database.Ready() != nil {
+		return s.config.HasCredentials(), nil
+	}
 
-		return ret, nil
+	var count int
+	if err := s.txnManager.WithReadTxn(ctx, func(repo models.ReaderRepository) error {
+		var err error
+		count, err = repo.User().Count()
+		return err
+	}); err != nil {
+		return false, err
 	}
 
-	return "", nil
+	return count > 0, nil
 }
 
-func SetCurrentUserID(ctx context.Context, userID string) context.Context {
-	return context.WithValue(ctx, contextUser, userID)
+// SetCurrentUser sets the current user in the provided context.
+func SetCurrentUser(ctx context.Context, u *models.User) context.Context {
+	return context.WithValue(ctx, contextUser, u)
 }
 
-// GetCurrentUserID gets the current user id from the provided context
-func GetCurrentUserID(ctx context.Context) *string {
+// GetCurrentUser gets the current user from the provided context. Returns
+// nil if there is no current user.
+func GetCurrentUser(ctx context.Context) *models.User {
 	userCtxVal := ctx.Value(contextUser)
 	if userCtxVal != nil {
-		currentUser := userCtxVal.(string)
-		return &currentUser
+		return userCtxVal.(*models.User)
 	}
 
 	return nil
 }
 
+// GetCurrentUserID gets the current user id from the provided context.
+// Returns nil if there is no current user, or if the current user is not
+// stored in the database.
+func GetCurrentUserID(ctx context.Context) *int {
+	u := GetCurrentUser(ctx)
+	if u == nil || u.ID == 0 {
+		return nil
+	}
+
+	return &u.ID
+}
+
+// GetUserDataID returns the id of the user that per-user values such as
+// ratings are stored for. Returns 0 if there is no current user, which is
+// the case when authentication is not enabled.
+func GetUserDataID(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+
+	if id := GetCurrentUserID(ctx); id != nil {
+		return *id
+	}
+
+	return 0
+}
+
 func (s *Store) VisitedPluginHandler() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -174,12 +254,12 @@ func setVisitedPlugins(ctx context.Context, visitedPlugins []string) context.Con
 }
 
 func (s *Store) MakePluginCookie(ctx context.Context) *http.Cookie {
-	currentUser := GetCurrentUserID(ctx)
+	currentUser := GetCurrentUser(ctx)
 	visitedPlugins := GetVisitedPlugins(ctx)
 
 	session := sessions.NewSession(s.sessionStore, cookieName)
 	if currentUser != nil {
-		session.Values[userIDKey] = *currentUser
+		session.Values[userIDKey] = currentUser.ID
 	}
 
 	session.Values[visitedPluginsKey] = visitedPlugins
@@ -194,9 +274,10 @@ func (s *Store) MakePluginCookie(ctx context.Context) *http.Cookie {
 	return sessions.NewCookie(session.Name(), encoded, session.Options)
 }
 
-func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID string, err error) {
-	c := s.config
-
+// Authenticate returns the user that made the request, using the API key if
+// present, or the session cookie otherwise. Returns nil if the request is
+// not authenticated. Returns ErrUnauthorized if the API key is invalid.
+func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (*models.User, error) {
 	// translate api key into current user, if present
 	apiKey := r.Header.Get(ApiKeyHeader)
 
@@ -206,22 +287,66 @@ func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID str
 	}
 
 	if apiKey != "" {
-		// match against configured API and set userID to the
-		// configured username. In future, we'll want to
-		// get the username from the key.
-		if c.GetAPIKey() != apiKey {
-			return "", ErrUnauthorized
+		return s.authenticateAPIKey(r.Context(), apiKey)
+	}
+
+	// handle session
+	userID, ok, err := s.GetSessionUserID(w, r)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	if database.Ready() != nil {
+		if userID != legacyUserID {
+			return nil, nil
 		}
 
-		userID = c.GetUsername()
-	} else {
-		// handle session
-		userID, err = s.GetSessionUserID(w, r)
+		return s.legacyUser(), nil
 	}
 
-	if err != nil {
-		return "", err
+	var u *models.User
+	if err := s.txnManager.WithReadTxn(r.Context(), func(repo models.ReaderRepository) error {
+		u, err = repo.User().Find(userID)
+		return err
+	}); err != nil {
+		return nil, err
 	}
 
-	return
+	return u, nil
+}
+
+func (s *Store) authenticateAPIKey(ctx context.Context, apiKey string) (*models.User, error) {
+	if database.Ready() != nil {
+		if s.config.GetAPIKey() != apiKey {
+			return nil, ErrUnauthorized
+		}
+
+		return s.legacyUser(), nil
+	}
+
+	var u *models.User
+	if err := s.txnManager.WithReadTxn(ctx, func(repo models.ReaderRepository) error {
+		var err error
+		u, err = repo.User().FindByAPIKey(apiKey)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, ErrUnauthorized
+	}
+
+	return u, nil
+}
+
+// legacyUser returns the user for the credentials in the configuration file.
+// These credentials are migrated to an admin user once the database is
+// available.
+func (s *Store) legacyUser() *models.User {
+	return &models.User{
+		ID:       legacyUserID,
+		Username: s.config.GetUsername(),
+		Role:     models.UserRoleAdmin,
+	}
 }
@@ -387,6 +387,23 @@ func intCriterionHandler(c *models.IntCriterionInput, column string) criterionHa
 	}
 }
 
+// userDataCriterionHandler filters on the rating, o-counter and watched state
+// of the user. Objects without values for the user have no rating, an
+// o-counter of zero and are not watched.
+func userDataCriterionHandler(r *userDataRepository, userID int, parentIDCol string, rating *models.IntCriterionInput, oCounter *models.IntCriterionInput, watched *bool) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if rating == nil && oCounter == nil && watched == nil {
+			return
+		}
+
+		f.addJoin(r.tableName, "", r.joinOnClause(userID, parentIDCol))
+
+		intCriterionHandler(rating, r.tableName+".rating")(f)
+		intCriterionHandler(oCounter, "IFNULL("+r.tableName+".o_counter, 0)")(f)
+		boolCriterionHandler(watched, "IFNULL("+r.tableName+".watched, 0)")(f)
+	}
+}
+
 func boolCriterionHandler(c *bool, column string) criterionHandlerFunc {
 	return func(f *filterBuilder) {
 		if c != nil {
@@ -526,8 +526,30 @@ func (qb *galleryQueryBuilder) GetImageIDs(galleryID int) ([]int, error) {
 }
 
 func (qb *galleryQueryBuilder) UpdateImages(galleryID int, imageIDs []int) error {
-	// Delete the existing joins and then create new ones
-	return qb.imagesRepository().replace(galleryID, imageIDs)
+	// Keep the existing joins, so that the image positions are kept
+	return qb.imagesRepository().replaceKeepExisting(galleryID, imageIDs)
+}
+
+func (qb *galleryQueryBuilder) GetOrderedImageIDs(galleryID int) ([]int, error) {
+	query := `SELECT image_id as id FROM galleries_images
+	WHERE gallery_id = ? AND position IS NOT NULL
+	ORDER BY position ASC`
+	return qb.runIdsQuery(query, []interface{}{galleryID})
+}
+
+func (qb *galleryQueryBuilder) UpdateImageOrder(galleryID int, imageIDs []int) error {
+	// Clear the existing positions and then set the new ones
+	if _, err := qb.tx.Exec("UPDATE galleries_images SET position = NULL WHERE gallery_id = ?", galleryID); err != nil {
+		return err
+	}
+
+	for i, imageID := range imageIDs {
+		if _, err := qb.tx.Exec("UPDATE galleries_images SET position = ? WHERE gallery_id = ? AND image_id = ?", i, galleryID, imageID); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (qb *galleryQueryBuilder) scenesRepository() *joinRepository {
@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const galleryChapterTable = "galleries_chapters"
+
+type galleryChapterQueryBuilder struct {
+	repository
+}
+
+func NewGalleryChapterReaderWriter(tx dbi) *galleryChapterQueryBuilder {
+	return &galleryChapterQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: galleryChapterTable,
+			idColumn:  idColumn,
+		},
+	}
+}
+
+func (qb *galleryChapterQueryBuilder) Create(newObject models.GalleryChapter) (*models.GalleryChapter, error) {
+	var ret models.GalleryChapter
+	if err := qb.insertObject(newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *galleryChapterQueryBuilder) Update(updatedObject models.GalleryChapter) (*models.GalleryChapter, error) {
+	const partial = false
+	if err := qb.update(updatedObject.ID, updatedObject, partial); err != nil {
+		return nil, err
+	}
+
+	var ret models.GalleryChapter
+	if err := qb.get(updatedObject.ID, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *galleryChapterQueryBuilder) Destroy(id int) error {
+	return qb.destroyExisting([]int{id})
+}
+
+func (qb *galleryChapterQueryBuilder) Find(id int) (*models.GalleryChapter, error) {
+	var ret models.GalleryChapter
+	if err := qb.get(id, &ret); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *galleryChapterQueryBuilder) FindMany(ids []int) ([]*models.GalleryChapter, error) {
+	var chapters []*models.GalleryChapter
+	for _, id := range ids {
+		chapter, err := qb.Find(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if chapter == nil {
+			return nil, fmt.Errorf("gallery chapter with id %d not found", id)
+		}
+
+		chapters = append(chapters, chapter)
+	}
+
+	return chapters, nil
+}
+
+func (qb *galleryChapterQueryBuilder) FindByGalleryID(galleryID int) ([]*models.GalleryChapter, error) {
+	query := `
+		SELECT galleries_chapters.* FROM galleries_chapters
+		WHERE galleries_chapters.gallery_id = ?
+		ORDER BY galleries_chapters.image_index ASC, galleries_chapters.id ASC
+	`
+	args := []interface{}{galleryID}
+	return qb.queryGalleryChapters(query, args)
+}
+
+func (qb *galleryChapterQueryBuilder) queryGalleryChapters(query string, args []interface{}) ([]*models.GalleryChapter, error) {
+	var ret models.GalleryChapters
+	if err := qb.query(query, args, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.GalleryChapter(ret), nil
+}
@@ -0,0 +1,162 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func createGalleryChapter(qb models.GalleryChapterReaderWriter, galleryID int, title string, imageIndex int) (*models.GalleryChapter, error) {
+	return qb.Create(models.GalleryChapter{
+		Title:      title,
+		ImageIndex: imageIndex,
+		GalleryID:  sql.NullInt64{Int64: int64(galleryID), Valid: true},
+	})
+}
+
+func TestGalleryChapterFindByGalleryID(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.GalleryChapter()
+
+		galleryID := galleryIDs[galleryIdxWithTwoImages]
+		second, err := createGalleryChapter(qb, galleryID, "second", 2)
+		if err != nil {
+			t.Errorf("Error creating gallery chapter: %s", err.Error())
+			return nil
+		}
+		first, err := createGalleryChapter(qb, galleryID, "first", 1)
+		if err != nil {
+			t.Errorf("Error creating gallery chapter: %s", err.Error())
+			return nil
+		}
+
+		chapters, err := qb.FindByGalleryID(galleryID)
+		if err != nil {
+			t.Errorf("Error finding gallery chapters: %s", err.Error())
+		}
+
+		// chapters are ordered by image index
+		assert.Len(t, chapters, 2)
+		assert.Equal(t, first.ID, chapters[0].ID)
+		assert.Equal(t, second.ID, chapters[1].ID)
+
+		chapters, err = qb.FindByGalleryID(0)
+		if err != nil {
+			t.Errorf("Error finding gallery chapters: %s", err.Error())
+		}
+		assert.Len(t, chapters, 0)
+
+		return nil
+	})
+}
+
+func TestGalleryChapterUpdateDestroy(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.GalleryChapter()
+
+		galleryID := galleryIDs[galleryIdxWithTwoImages]
+		chapter, err := createGalleryChapter(qb, galleryID, "chapter", 1)
+		if err != nil {
+			t.Errorf("Error creating gallery chapter: %s", err.Error())
+			return nil
+		}
+
+		chapter.Title = "updated"
+		chapter.ImageIndex = 2
+		updated, err := qb.Update(*chapter)
+		if err != nil {
+			t.Errorf("Error updating gallery chapter: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, "updated", updated.Title)
+		assert.Equal(t, 2, updated.ImageIndex)
+
+		if err := qb.Destroy(chapter.ID); err != nil {
+			t.Errorf("Error destroying gallery chapter: %s", err.Error())
+			return nil
+		}
+
+		found, err := qb.Find(chapter.ID)
+		if err != nil {
+			t.Errorf("Error finding gallery chapter: %s", err.Error())
+		}
+		assert.Nil(t, found)
+
+		return nil
+	})
+}
+
+func TestImageQueryGalleryChapters(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.GalleryChapter()
+		iqb := r.Image()
+
+		galleryID := galleryIDs[galleryIdxWithTwoImages]
+		first := imageIDs[imageIdx1WithGallery]
+		second := imageIDs[imageIdx2WithGallery]
+
+		firstChapter, err := createGalleryChapter(qb, galleryID, "first", 1)
+		if err != nil {
+			t.Errorf("Error creating gallery chapter: %s", err.Error())
+			return nil
+		}
+		secondChapter, err := createGalleryChapter(qb, galleryID, "second", 2)
+		if err != nil {
+			t.Errorf("Error creating gallery chapter: %s", err.Error())
+			return nil
+		}
+
+		queryIDs := func(modifier models.CriterionModifier, chapters ...*models.GalleryChapter) []int {
+			var values []string
+			for _, c := range chapters {
+				values = append(values, strconv.Itoa(c.ID))
+			}
+
+			images := queryImages(t, iqb, &models.ImageFilterType{
+				Galleries: &models.MultiCriterionInput{
+					Value:    []string{strconv.Itoa(galleryID)},
+					Modifier: models.CriterionModifierIncludes,
+				},
+				GalleryChapters: &models.MultiCriterionInput{
+					Value:    values,
+					Modifier: modifier,
+				},
+			}, nil)
+
+			var ret []int
+			for _, i := range images {
+				ret = append(ret, i.ID)
+			}
+			return ret
+		}
+
+		// images are ordered by path without positions
+		assert.Equal(t, []int{first}, queryIDs(models.CriterionModifierIncludes, firstChapter))
+		assert.Equal(t, []int{second}, queryIDs(models.CriterionModifierIncludes, secondChapter))
+		assert.Equal(t, []int{first, second}, queryIDs(models.CriterionModifierIncludes, firstChapter, secondChapter))
+		assert.Len(t, queryIDs(models.CriterionModifierIncludesAll, firstChapter, secondChapter), 0)
+		assert.Equal(t, []int{second}, queryIDs(models.CriterionModifierExcludes, firstChapter))
+
+		// chapters follow the gallery order
+		if err := r.Gallery().UpdateImageOrder(galleryID, []int{second, first}); err != nil {
+			t.Errorf("Error updating image order: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []int{second}, queryIDs(models.CriterionModifierIncludes, firstChapter))
+
+		// the last chapter runs to the end of the gallery
+		if err := qb.Destroy(secondChapter.ID); err != nil {
+			t.Errorf("Error destroying gallery chapter: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []int{second, first}, queryIDs(models.CriterionModifierIncludes, firstChapter))
+
+		return nil
+	})
+}
@@ -985,6 +985,91 @@ func verifyGalleriesImageCount(t *testing.T, imageCountCriterion models.IntCrite
 	})
 }
 
+func TestGalleryImageOrder(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.Gallery()
+		iqb := r.Image()
+
+		galleryID := galleryIDs[galleryIdxWithTwoImages]
+		first := imageIDs[imageIdx1WithGallery]
+		second := imageIDs[imageIdx2WithGallery]
+
+		findIDs := func() []int {
+			images, err := iqb.FindByGalleryID(galleryID)
+			if err != nil {
+				t.Errorf("Error finding images: %s", err.Error())
+			}
+
+			var ret []int
+			for _, i := range images {
+				ret = append(ret, i.ID)
+			}
+			return ret
+		}
+
+		queryIDs := func(findFilter *models.FindFilterType) []int {
+			images := queryImages(t, iqb, &models.ImageFilterType{
+				Galleries: &models.MultiCriterionInput{
+					Value:    []string{strconv.Itoa(galleryID)},
+					Modifier: models.CriterionModifierIncludes,
+				},
+			}, findFilter)
+
+			var ret []int
+			for _, i := range images {
+				ret = append(ret, i.ID)
+			}
+			return ret
+		}
+
+		// images are ordered by path without positions
+		assert.Equal(t, []int{first, second}, findIDs())
+
+		if err := qb.UpdateImageOrder(galleryID, []int{second, first}); err != nil {
+			t.Errorf("Error updating image order: %s", err.Error())
+			return nil
+		}
+
+		ordered, err := qb.GetOrderedImageIDs(galleryID)
+		if err != nil {
+			t.Errorf("Error getting ordered image ids: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []int{second, first}, ordered)
+		assert.Equal(t, []int{second, first}, findIDs())
+
+		// images in a gallery are sorted by position by default
+		assert.Equal(t, []int{second, first}, queryIDs(&models.FindFilterType{}))
+		sort := "gallery_image_position"
+		direction := models.SortDirectionEnumDesc
+		assert.Equal(t, []int{first, second}, queryIDs(&models.FindFilterType{
+			Sort:      &sort,
+			Direction: &direction,
+		}))
+		sort = "path"
+		assert.Equal(t, []int{second, first}, queryIDs(&models.FindFilterType{
+			Sort:      &sort,
+			Direction: &direction,
+		}))
+
+		// updating the galleries of an image keeps its position
+		if err := iqb.UpdateGalleries(second, []int{galleryID, galleryIDs[galleryIdxWithImage]}); err != nil {
+			t.Errorf("Error updating image galleries: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []int{second, first}, findIDs())
+
+		// images without a position are ordered last
+		if err := qb.UpdateImageOrder(galleryID, []int{first}); err != nil {
+			t.Errorf("Error updating image order: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []int{first, second}, findIDs())
+
+		return nil
+	})
+}
+
 // TODO Count
 // TODO All
 // TODO Query
@@ -3,6 +3,8 @@ package sqlite
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/stashapp/stash/pkg/models"
 )
@@ -11,11 +13,13 @@ const imageTable = "images"
 const imageIDColumn = "image_id"
 const performersImagesTable = "performers_images"
 const imagesTagsTable = "images_tags"
+const imagesUsersTable = "images_users"
 
 var imagesForGalleryQuery = selectAll(imageTable) + `
 LEFT JOIN galleries_images as galleries_join on galleries_join.image_id = images.id
 WHERE galleries_join.gallery_id = ?
 GROUP BY images.id
+ORDER BY galleries_join.position IS NULL, galleries_join.position ASC, images.path ASC
 `
 
 var countImagesForGalleryQuery = `
@@ -26,15 +30,22 @@ GROUP BY image_id
 
 type imageQueryBuilder struct {
 	repository
+
+	// userID is the user that user-specific values are filtered and sorted
+	// for.
+	userID int
 }
 
-func NewImageReaderWriter(tx dbi) *imageQueryBuilder {
+// NewImageReaderWriter returns an image query builder that filters and sorts
+// user-specific values for the user with the provided id.
+func NewImageReaderWriter(tx dbi, userID int) *imageQueryBuilder {
 	return &imageQueryBuilder{
-		repository{
+		repository: repository{
 			tx:        tx,
 			tableName: imageTable,
 			idColumn:  idColumn,
 		},
+		userID: userID,
 	}
 }
 
@@ -65,55 +76,32 @@ func (qb *imageQueryBuilder) UpdateFull(updatedObject models.Image) (*models.Ima
 	return qb.find(updatedObject.ID)
 }
 
-func (qb *imageQueryBuilder) IncrementOCounter(id int) (int, error) {
-	_, err := qb.tx.Exec(
-		`UPDATE `+imageTable+` SET o_counter = o_counter + 1 WHERE `+imageTable+`.id = ?`,
-		id,
-	)
-	if err != nil {
-		return 0, err
-	}
-
-	image, err := qb.find(id)
-	if err != nil {
-		return 0, err
-	}
-
-	return image.OCounter, nil
+func (qb *imageQueryBuilder) IncrementOCounter(id int, userID int) (int, error) {
+	return qb.userDataRepository().addOCounter(id, userID, 1)
 }
 
-func (qb *imageQueryBuilder) DecrementOCounter(id int) (int, error) {
-	_, err := qb.tx.Exec(
-		`UPDATE `+imageTable+` SET o_counter = o_counter - 1 WHERE `+imageTable+`.id = ? and `+imageTable+`.o_counter > 0`,
-		id,
-	)
-	if err != nil {
-		return 0, err
-	}
+func (qb *imageQueryBuilder) DecrementOCounter(id int, userID int) (int, error) {
+	return qb.userDataRepository().addOCounter(id, userID, -1)
+}
 
-	image, err := qb.find(id)
-	if err != nil {
-		return 0, err
-	}
+func (qb *imageQueryBuilder) ResetOCounter(id int, userID int) (int, error) {
+	return qb.userDataRepository().resetOCounter(id, userID)
+}
 
-	return image.OCounter, nil
+func (qb *imageQueryBuilder) GetUserData(imageID int, userID int) (*models.UserData, error) {
+	return qb.userDataRepository().get(imageID, userID)
 }
 
-func (qb *imageQueryBuilder) ResetOCounter(id int) (int, error) {
-	_, err := qb.tx.Exec(
-		`UPDATE `+imageTable+` SET o_counter = 0 WHERE `+imageTable+`.id = ?`,
-		id,
-	)
-	if err != nil {
-		return 0, err
-	}
+func (qb *imageQueryBuilder) GetAllUserData(imageID int) ([]*models.UserData, error) {
+	return qb.userDataRepository().getAll(imageID)
+}
 
-	image, err := qb.find(id)
-	if err != nil {
-		return 0, err
-	}
+func (qb *imageQueryBuilder) UpdateUserData(imageID int, userID int, updatedData models.UserDataPartial) error {
+	return qb.userDataRepository().update(imageID, userID, updatedData)
+}
 
-	return image.OCounter, nil
+func (qb *imageQueryBuilder) UpdateAllUserData(imageID int, data []*models.UserData) error {
+	return qb.userDataRepository().replace(imageID, data)
 }
 
 func (qb *imageQueryBuilder) Destroy(id int) error {
@@ -167,7 +155,7 @@ func (qb *imageQueryBuilder) FindByPath(path string) (*models.Image, error) {
 
 func (qb *imageQueryBuilder) FindByGalleryID(galleryID int) ([]*models.Image, error) {
 	args := []interface{}{galleryID}
-	return qb.queryImages(imagesForGalleryQuery+qb.getImageSort(nil), args)
+	return qb.queryImages(imagesForGalleryQuery, args)
 }
 
 func (qb *imageQueryBuilder) CountByGalleryID(galleryID int) (int, error) {
@@ -234,8 +222,7 @@ func (qb *imageQueryBuilder) makeFilter(imageFilter *models.ImageFilterType) *fi
 	query.handleCriterion(stringCriterionHandler(imageFilter.Checksum, "images.checksum"))
 	query.handleCriterion(stringCriterionHandler(imageFilter.Title, "images.title"))
 	query.handleCriterion(stringCriterionHandler(imageFilter.Path, "images.path"))
-	query.handleCriterion(intCriterionHandler(imageFilter.Rating, "images.rating"))
-	query.handleCriterion(intCriterionHandler(imageFilter.OCounter, "images.o_counter"))
+	query.handleCriterion(userDataCriterionHandler(qb.userDataRepository(), qb.userID, "images.id", imageFilter.Rating, imageFilter.OCounter, imageFilter.Watched))
 	query.handleCriterion(boolCriterionHandler(imageFilter.Organized, "images.organized"))
 	query.handleCriterion(resolutionCriterionHandler(imageFilter.Resolution, "images.height", "images.width"))
 	query.handleCriterion(imageIsMissingCriterionHandler(qb, imageFilter.IsMissing))
@@ -243,6 +230,7 @@ func (qb *imageQueryBuilder) makeFilter(imageFilter *models.ImageFilterType) *fi
 	query.handleCriterion(imageTagsCriterionHandler(qb, imageFilter.Tags))
 	query.handleCriterion(imageTagCountCriterionHandler(qb, imageFilter.TagCount))
 	query.handleCriterion(imageGalleriesCriterionHandler(qb, imageFilter.Galleries))
+	query.handleCriterion(imageGalleryChaptersCriterionHandler(qb, imageFilter.GalleryChapters))
 	query.handleCriterion(imagePerformersCriterionHandler(qb, imageFilter.Performers))
 	query.handleCriterion(imagePerformerCountCriterionHandler(qb, imageFilter.PerformerCount))
 	query.handleCriterion(imageStudioCriterionHandler(qb, imageFilter.Studios))
@@ -277,7 +265,8 @@ func (qb *imageQueryBuilder) makeQuery(imageFilter *models.ImageFilterType, find
 
 	query.addFilter(filter)
 
-	query.sortAndPagination = qb.getImageSort(findFilter) + getPagination(findFilter)
+	qb.setImageSort(&query, imageFilter, findFilter)
+	query.sortAndPagination += getPagination(findFilter)
 
 	return &query, nil
 }
@@ -330,6 +319,9 @@ func imageIsMissingCriterionHandler(qb *imageQueryBuilder, isMissing *string) cr
 			case "tags":
 				qb.tagsRepository().join(f, "tags_join", "images.id")
 				f.addWhere("tags_join.image_id IS NULL")
+			case "rating":
+				f.addJoin(imagesUsersTable, "", qb.userDataRepository().joinOnClause(qb.userID, "images.id"))
+				f.addWhere(imagesUsersTable + ".rating IS NULL")
 			default:
 				f.addWhere("(images." + *isMissing + " IS NULL OR TRIM(images." + *isMissing + ") = '')")
 			}
@@ -385,6 +377,58 @@ func imageGalleriesCriterionHandler(qb *imageQueryBuilder, galleries *models.Mul
 	return h.handler(galleries)
 }
 
+// galleryChapterImagesQuery selects the ids of the images of a gallery
+// chapter. A chapter has the images of its gallery from its image index up to
+// the image index of the next chapter, where image indexes start at 1 and
+// follow the gallery order. It takes the chapter id twice as arguments.
+const galleryChapterImagesQuery = `SELECT gallery_images.image_id FROM (
+	SELECT galleries_images.image_id, ROW_NUMBER() OVER (
+		ORDER BY galleries_images.position IS NULL, galleries_images.position ASC, images.path ASC
+	) AS image_index
+	FROM galleries_images
+	INNER JOIN images ON images.id = galleries_images.image_id
+	WHERE galleries_images.gallery_id = (SELECT gallery_id FROM galleries_chapters WHERE id = ?)
+) AS gallery_images
+INNER JOIN galleries_chapters AS chapter ON chapter.id = ?
+WHERE gallery_images.image_index >= chapter.image_index AND NOT EXISTS (
+	SELECT 1 FROM galleries_chapters AS next_chapter
+	WHERE next_chapter.gallery_id = chapter.gallery_id
+	AND next_chapter.image_index > chapter.image_index
+	AND next_chapter.image_index <= gallery_images.image_index
+)`
+
+func imageGalleryChaptersCriterionHandler(qb *imageQueryBuilder, chapters *models.MultiCriterionInput) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if chapters == nil || len(chapters.Value) == 0 {
+			return
+		}
+
+		var clauses []string
+		var args []interface{}
+		for _, v := range chapters.Value {
+			chapterID, err := strconv.Atoi(v)
+			if err != nil {
+				f.setError(fmt.Errorf("invalid gallery chapter id %s: %w", v, err))
+				return
+			}
+
+			clauses = append(clauses, "images.id IN ("+galleryChapterImagesQuery+")")
+			args = append(args, chapterID, chapterID)
+		}
+
+		switch chapters.Modifier {
+		case models.CriterionModifierIncludes:
+			f.addWhere("("+strings.Join(clauses, " OR ")+")", args...)
+		case models.CriterionModifierIncludesAll:
+			f.addWhere("("+strings.Join(clauses, " AND ")+")", args...)
+		case models.CriterionModifierExcludes:
+			f.addWhere("NOT ("+strings.Join(clauses, " OR ")+")", args...)
+		default:
+			f.setError(fmt.Errorf("unsupported gallery chapters modifier %s", chapters.Modifier))
+		}
+	}
+}
+
 func imagePerformersCriterionHandler(qb *imageQueryBuilder, performers *models.MultiCriterionInput) criterionHandlerFunc {
 	h := joinedMultiCriterionHandlerBuilder{
 		primaryTable: imageTable,
@@ -443,6 +487,36 @@ INNER JOIN (` + valuesClause + `) t ON t.column2 = pt.tag_id
 	}
 }
 
+// imageFilterGalleryID returns the id of the gallery that the filter limits
+// the images to, or 0 if the images are not limited to a single gallery.
+func imageFilterGalleryID(imageFilter *models.ImageFilterType) int {
+	galleries := imageFilter.Galleries
+	if galleries == nil || len(galleries.Value) != 1 {
+		return 0
+	}
+
+	if galleries.Modifier != models.CriterionModifierIncludes && galleries.Modifier != models.CriterionModifierIncludesAll {
+		return 0
+	}
+
+	galleryID, _ := strconv.Atoi(galleries.Value[0])
+	return galleryID
+}
+
+// setImageSort sets the sort of the query. Images in a single gallery are
+// sorted by their position in the gallery by default.
+func (qb *imageQueryBuilder) setImageSort(query *queryBuilder, imageFilter *models.ImageFilterType, findFilter *models.FindFilterType) {
+	galleryID := imageFilterGalleryID(imageFilter)
+	if galleryID != 0 && (findFilter.Sort == nil || findFilter.GetSort("") == "gallery_image_position") {
+		direction := getSortDirection(findFilter.GetDirection())
+		query.join(galleriesImagesTable, "gallery_positions", fmt.Sprintf("gallery_positions.image_id = images.id AND gallery_positions.gallery_id = %d", galleryID))
+		query.sortAndPagination += fmt.Sprintf(" ORDER BY gallery_positions.position IS NULL %[1]s, gallery_positions.position %[1]s, images.path %[1]s", direction)
+		return
+	}
+
+	query.sortAndPagination += qb.getImageSort(findFilter)
+}
+
 func (qb *imageQueryBuilder) getImageSort(findFilter *models.FindFilterType) string {
 	if findFilter == nil {
 		return " ORDER BY images.path ASC "
@@ -451,10 +525,15 @@ func (qb *imageQueryBuilder) getImageSort(findFilter *models.FindFilterType) str
 	direction := findFilter.GetDirection()
 
 	switch sort {
+	case "gallery_image_position":
+		// images are only sorted by position within a single gallery
+		return getSort("path", direction, "images")
 	case "tag_count":
 		return getCountSort(imageTable, imagesTagsTable, imageIDColumn, direction)
 	case "performer_count":
 		return getCountSort(imageTable, performersImagesTable, imageIDColumn, direction)
+	case "rating", "o_counter":
+		return qb.userDataRepository().getSort(sort, qb.userID, "images.id", direction)
 	default:
 		return getSort(sort, direction, "images")
 	}
@@ -493,8 +572,8 @@ func (qb *imageQueryBuilder) GetGalleryIDs(imageID int) ([]int, error) {
 }
 
 func (qb *imageQueryBuilder) UpdateGalleries(imageID int, galleryIDs []int) error {
-	// Delete the existing joins and then create new ones
-	return qb.galleriesRepository().replace(imageID, galleryIDs)
+	// Keep the existing joins, so that the image positions are kept
+	return qb.galleriesRepository().replaceKeepExisting(imageID, galleryIDs)
 }
 
 func (qb *imageQueryBuilder) performersRepository() *joinRepository {
@@ -517,6 +596,16 @@ func (qb *imageQueryBuilder) UpdatePerformers(imageID int, performerIDs []int) e
 	return qb.performersRepository().replace(imageID, performerIDs)
 }
 
+func (qb *imageQueryBuilder) userDataRepository() *userDataRepository {
+	return &userDataRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: imagesUsersTable,
+			idColumn:  imageIDColumn,
+		},
+	}
+}
+
 func (qb *imageQueryBuilder) tagsRepository() *joinRepository {
 	return &joinRepository{
 		repository: repository{
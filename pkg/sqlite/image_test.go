@@ -214,7 +214,7 @@ func TestImageQueryPathAndRating(t *testing.T) {
 
 		assert.Len(t, images, 1)
 		assert.Equal(t, imagePath, images[0].Path)
-		assert.Equal(t, imageRating.Int64, images[0].Rating.Int64)
+		assert.Equal(t, imageRating.Int64, getImageUserData(t, sqb, images[0].ID).Rating.Int64)
 
 		return nil
 	})
@@ -250,7 +250,7 @@ func TestImageQueryPathNotRating(t *testing.T) {
 		for _, image := range images {
 			verifyString(t, image.Path, pathCriterion)
 			ratingCriterion.Modifier = models.CriterionModifierNotEquals
-			verifyInt64(t, image.Rating, ratingCriterion)
+			verifyInt64(t, getImageUserData(t, sqb, image.ID).Rating, ratingCriterion)
 		}
 
 		return nil
@@ -331,7 +331,7 @@ func verifyImagesRating(t *testing.T, ratingCriterion models.IntCriterionInput)
 		}
 
 		for _, image := range images {
-			verifyInt64(t, image.Rating, ratingCriterion)
+			verifyInt64(t, getImageUserData(t, sqb, image.ID).Rating, ratingCriterion)
 		}
 
 		return nil
@@ -370,13 +370,46 @@ func verifyImagesOCounter(t *testing.T, oCounterCriterion models.IntCriterionInp
 		}
 
 		for _, image := range images {
-			verifyInt(t, image.OCounter, oCounterCriterion)
+			verifyInt(t, getImageUserData(t, sqb, image.ID).OCounter, oCounterCriterion)
 		}
 
 		return nil
 	})
 }
 
+func TestImageQueryWatched(t *testing.T) {
+	verifyImagesWatched(t, true)
+	verifyImagesWatched(t, false)
+}
+
+func verifyImagesWatched(t *testing.T, watched bool) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Image()
+		imageFilter := models.ImageFilterType{
+			Watched: &watched,
+		}
+
+		images := queryImages(t, sqb, &imageFilter, nil)
+		assert.Greater(t, len(images), 0)
+
+		for _, image := range images {
+			assert.Equal(t, watched, getImageUserData(t, sqb, image.ID).Watched)
+		}
+
+		return nil
+	})
+}
+
+func getImageUserData(t *testing.T, qb models.ImageReader, imageID int) *models.UserData {
+	ret, err := qb.GetUserData(imageID, 0)
+	if err != nil {
+		t.Errorf("Error getting image user data: %s", err.Error())
+		return &models.UserData{}
+	}
+
+	return ret
+}
+
 func TestImageQueryResolution(t *testing.T) {
 	verifyImagesResolution(t, models.ResolutionEnumLow)
 	verifyImagesResolution(t, models.ResolutionEnumStandard)
@@ -583,7 +616,7 @@ func TestImageQueryIsMissingRating(t *testing.T) {
 
 		// ensure date is null, empty or "0001-01-01"
 		for _, image := range images {
-			assert.True(t, !image.Rating.Valid)
+			assert.True(t, !getImageUserData(t, sqb, image.ID).Rating.Valid)
 		}
 
 		return nil
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const jobCheckpointTable = "job_checkpoints"
+
+type jobCheckpointQueryBuilder struct {
+	repository
+}
+
+func NewJobCheckpointReaderWriter(tx dbi) *jobCheckpointQueryBuilder {
+	return &jobCheckpointQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: jobCheckpointTable,
+			idColumn:  "key",
+		},
+	}
+}
+
+func (qb *jobCheckpointQueryBuilder) GetItems(key string) ([]string, error) {
+	query := fmt.Sprintf("SELECT item FROM %s WHERE key = ?", jobCheckpointTable)
+
+	var ret []string
+	if err := qb.queryFunc(query, []interface{}{key}, func(rows *sqlx.Rows) error {
+		var item string
+		if err := rows.Scan(&item); err != nil {
+			return err
+		}
+
+		ret = append(ret, item)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *jobCheckpointQueryBuilder) AddItems(key string, items []string) error {
+	stmt := fmt.Sprintf("INSERT OR IGNORE INTO %s (key, item, time) VALUES (?, ?, ?)", jobCheckpointTable)
+	now := models.SQLiteTimestamp{Timestamp: time.Now()}
+	for _, item := range items {
+		if _, err := qb.tx.Exec(stmt, key, item, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *jobCheckpointQueryBuilder) Destroy(key string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE key = ?", jobCheckpointTable)
+	_, err := qb.tx.Exec(query, key)
+	return err
+}
+
+func (qb *jobCheckpointQueryBuilder) DestroyOlderThan(t time.Time) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE time < ?", jobCheckpointTable)
+	_, err := qb.tx.Exec(query, models.SQLiteTimestamp{Timestamp: t})
+	return err
+}
@@ -0,0 +1,64 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobCheckpoint(t *testing.T) {
+	const key = "scan:test"
+	const otherKey = "generate:test"
+
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.JobCheckpoint()
+
+		// adding an existing item should be ignored
+		if err := qb.AddItems(key, []string{"a", "b", "a"}); err != nil {
+			t.Errorf("Error adding checkpoint items: %s", err.Error())
+			return nil
+		}
+		if err := qb.AddItems(otherKey, []string{"c"}); err != nil {
+			t.Errorf("Error adding checkpoint items: %s", err.Error())
+			return nil
+		}
+
+		items, err := qb.GetItems(key)
+		if err != nil {
+			t.Errorf("Error getting checkpoint items: %s", err.Error())
+			return nil
+		}
+		assert.ElementsMatch(t, []string{"a", "b"}, items)
+
+		if err := qb.Destroy(key); err != nil {
+			t.Errorf("Error destroying checkpoint: %s", err.Error())
+			return nil
+		}
+
+		items, err = qb.GetItems(key)
+		if err != nil {
+			t.Errorf("Error getting checkpoint items: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, items, 0)
+
+		if err := qb.DestroyOlderThan(time.Now().Add(time.Minute)); err != nil {
+			t.Errorf("Error destroying checkpoints: %s", err.Error())
+			return nil
+		}
+
+		items, err = qb.GetItems(otherKey)
+		if err != nil {
+			t.Errorf("Error getting checkpoint items: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, items, 0)
+
+		return nil
+	})
+}
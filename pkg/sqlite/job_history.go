@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const jobHistoryTable = "job_history"
+const jobHistoryLogsTable = "job_history_logs"
+
+type jobHistoryQueryBuilder struct {
+	repository
+}
+
+func NewJobHistoryReaderWriter(tx dbi) *jobHistoryQueryBuilder {
+	return &jobHistoryQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: jobHistoryTable,
+			idColumn:  idColumn,
+		},
+	}
+}
+
+func (qb *jobHistoryQueryBuilder) Create(newObject models.JobHistory) (*models.JobHistory, error) {
+	var ret models.JobHistory
+	if err := qb.insertObject(newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *jobHistoryQueryBuilder) CreateLogs(jobID int, logs []*models.JobHistoryLog) error {
+	stmt := fmt.Sprintf("INSERT INTO %s (job_id, time, level, message) VALUES (:job_id, :time, :level, :message)", jobHistoryLogsTable)
+	for _, l := range logs {
+		l.JobID = jobID
+		if _, err := qb.tx.NamedExec(stmt, l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *jobHistoryQueryBuilder) DestroyOlderThan(t time.Time) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE end_time < ?", jobHistoryTable)
+	_, err := qb.tx.Exec(query, models.SQLiteTimestamp{Timestamp: t})
+	return err
+}
+
+func (qb *jobHistoryQueryBuilder) DestroyExcess(keep int) error {
+	query := fmt.Sprintf("DELETE FROM %[1]s WHERE id NOT IN (SELECT id FROM %[1]s ORDER BY end_time DESC, id DESC LIMIT ?)", jobHistoryTable)
+	_, err := qb.tx.Exec(query, keep)
+	return err
+}
+
+func (qb *jobHistoryQueryBuilder) Find(id int) (*models.JobHistory, error) {
+	var ret models.JobHistory
+	if err := qb.get(id, &ret); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *jobHistoryQueryBuilder) GetLogs(jobID int) ([]*models.JobHistoryLog, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE job_id = ? ORDER BY time ASC, rowid ASC", jobHistoryLogsTable)
+
+	var ret models.JobHistoryLogs
+	if err := qb.query(query, []interface{}{jobID}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.JobHistoryLog(ret), nil
+}
+
+func (qb *jobHistoryQueryBuilder) makeFilter(jobFilter *models.JobHistoryFilterType) *filterBuilder {
+	query := &filterBuilder{}
+
+	query.handleCriterion(stringCriterionHandler(jobFilter.Description, jobHistoryTable+".description"))
+	query.handleCriterion(stringCriterionHandler(jobFilter.Error, jobHistoryTable+".error"))
+	query.handleCriterion(jobHistoryStatusCriterionHandler(jobFilter.Status))
+
+	query.handleCriterion(criterionHandlerFunc(func(f *filterBuilder) {
+		if jobFilter.EndedAfter != nil {
+			f.addWhere(jobHistoryTable+".end_time >= ?", models.SQLiteTimestamp{Timestamp: *jobFilter.EndedAfter})
+		}
+		if jobFilter.EndedBefore != nil {
+			f.addWhere(jobHistoryTable+".end_time < ?", models.SQLiteTimestamp{Timestamp: *jobFilter.EndedBefore})
+		}
+	}))
+
+	return query
+}
+
+func jobHistoryStatusCriterionHandler(statuses []models.JobStatus) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if len(statuses) == 0 {
+			return
+		}
+
+		var args []interface{}
+		for _, s := range statuses {
+			args = append(args, s.String())
+		}
+
+		f.addWhere(jobHistoryTable+".status IN "+getInBinding(len(args)), args...)
+	}
+}
+
+func (qb *jobHistoryQueryBuilder) Query(jobFilter *models.JobHistoryFilterType, findFilter *models.FindFilterType) ([]*models.JobHistory, int, error) {
+	if jobFilter == nil {
+		jobFilter = &models.JobHistoryFilterType{}
+	}
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	query := qb.newQuery()
+
+	query.body = selectDistinctIDs(jobHistoryTable)
+
+	if q := findFilter.Q; q != nil && *q != "" {
+		searchColumns := []string{"job_history.description"}
+		clause, thisArgs := getSearchBinding(searchColumns, *q, false)
+		query.addWhere(clause)
+		query.addArg(thisArgs...)
+	}
+
+	filter := qb.makeFilter(jobFilter)
+
+	query.addFilter(filter)
+
+	query.sortAndPagination = qb.getJobHistorySort(findFilter) + getPagination(findFilter)
+	idsResult, countResult, err := query.executeFind()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []*models.JobHistory
+	for _, id := range idsResult {
+		j, err := qb.Find(id)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		jobs = append(jobs, j)
+	}
+
+	return jobs, countResult, nil
+}
+
+func (qb *jobHistoryQueryBuilder) getJobHistorySort(findFilter *models.FindFilterType) string {
+	// most recent first by default
+	sort := findFilter.GetSort("end_time")
+	direction := "DESC"
+	if findFilter.Direction != nil {
+		direction = findFilter.GetDirection()
+	}
+
+	return getSort(sort, direction, jobHistoryTable) + ", " + getColumn(jobHistoryTable, "id") + " " + getSortDirection(direction)
+}
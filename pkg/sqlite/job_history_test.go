@@ -0,0 +1,173 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func createJobHistory(qb models.JobHistoryReaderWriter, description string, status models.JobStatus, endTime time.Time) (*models.JobHistory, error) {
+	newJob := models.JobHistory{
+		Description: description,
+		Status:      status.String(),
+		AddTime:     models.SQLiteTimestamp{Timestamp: endTime},
+		EndTime:     models.SQLiteTimestamp{Timestamp: endTime},
+	}
+
+	if status == models.JobStatusFailed {
+		newJob.Error = sql.NullString{String: "failed", Valid: true}
+	}
+
+	return qb.Create(newJob)
+}
+
+func TestJobHistoryCreateLogs(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.JobHistory()
+
+		created, err := createJobHistory(qb, "Scanning...", models.JobStatusFinished, time.Now())
+		if err != nil {
+			t.Errorf("Error creating job history: %s", err.Error())
+			return nil
+		}
+
+		now := time.Now()
+		logs := []*models.JobHistoryLog{
+			{Time: models.SQLiteTimestamp{Timestamp: now}, Level: "info", Message: "first"},
+			{Time: models.SQLiteTimestamp{Timestamp: now}, Level: "error", Message: "second"},
+		}
+
+		if err := qb.CreateLogs(created.ID, logs); err != nil {
+			t.Errorf("Error creating job history logs: %s", err.Error())
+			return nil
+		}
+
+		found, err := qb.GetLogs(created.ID)
+		if err != nil {
+			t.Errorf("Error getting job history logs: %s", err.Error())
+			return nil
+		}
+
+		if assert.Len(t, found, 2) {
+			assert.Equal(t, "first", found[0].Message)
+			assert.Equal(t, "error", found[1].Level)
+		}
+
+		return nil
+	})
+}
+
+func TestJobHistoryQuery(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.JobHistory()
+
+		now := time.Now()
+		finished, err := createJobHistory(qb, "Generating content", models.JobStatusFinished, now.Add(-time.Hour))
+		if err != nil {
+			t.Errorf("Error creating job history: %s", err.Error())
+			return nil
+		}
+		failed, err := createJobHistory(qb, "Scanning...", models.JobStatusFailed, now)
+		if err != nil {
+			t.Errorf("Error creating job history: %s", err.Error())
+			return nil
+		}
+
+		// most recent first
+		jobs, count, err := qb.Query(nil, nil)
+		if err != nil {
+			t.Errorf("Error querying job history: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 2, count)
+		if assert.Len(t, jobs, 2) {
+			assert.Equal(t, failed.ID, jobs[0].ID)
+			assert.Equal(t, finished.ID, jobs[1].ID)
+		}
+
+		jobs, _, err = qb.Query(&models.JobHistoryFilterType{
+			Status: []models.JobStatus{models.JobStatusFailed},
+		}, nil)
+		if err != nil {
+			t.Errorf("Error querying job history: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, jobs, 1) {
+			assert.Equal(t, failed.ID, jobs[0].ID)
+			assert.Equal(t, "failed", jobs[0].Error.String)
+		}
+
+		endedBefore := now.Add(-time.Minute)
+		jobs, _, err = qb.Query(&models.JobHistoryFilterType{
+			EndedBefore: &endedBefore,
+		}, nil)
+		if err != nil {
+			t.Errorf("Error querying job history: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, jobs, 1) {
+			assert.Equal(t, finished.ID, jobs[0].ID)
+		}
+
+		q := "generating"
+		jobs, _, err = qb.Query(nil, &models.FindFilterType{Q: &q})
+		if err != nil {
+			t.Errorf("Error querying job history: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, jobs, 1) {
+			assert.Equal(t, finished.ID, jobs[0].ID)
+		}
+
+		return nil
+	})
+}
+
+func TestJobHistoryDestroy(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.JobHistory()
+
+		now := time.Now()
+		for i := 0; i < 4; i++ {
+			if _, err := createJobHistory(qb, "Scanning...", models.JobStatusFinished, now.AddDate(0, 0, -i)); err != nil {
+				t.Errorf("Error creating job history: %s", err.Error())
+				return nil
+			}
+		}
+
+		if err := qb.DestroyOlderThan(now.AddDate(0, 0, -2).Add(-time.Minute)); err != nil {
+			t.Errorf("Error destroying job history: %s", err.Error())
+			return nil
+		}
+
+		_, count, err := qb.Query(nil, nil)
+		if err != nil {
+			t.Errorf("Error querying job history: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 3, count)
+
+		if err := qb.DestroyExcess(1); err != nil {
+			t.Errorf("Error destroying job history: %s", err.Error())
+			return nil
+		}
+
+		jobs, count, err := qb.Query(nil, nil)
+		if err != nil {
+			t.Errorf("Error querying job history: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 1, count)
+		if assert.Len(t, jobs, 1) {
+			assert.Equal(t, now.Unix(), jobs[0].EndTime.Timestamp.Unix())
+		}
+
+		return nil
+	})
+}
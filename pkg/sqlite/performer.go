@@ -40,6 +40,10 @@ func (qb *performerQueryBuilder) Create(newObject models.Performer) (*models.Per
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(ret.ID); err != nil {
+		return nil, err
+	}
+
 	return &ret, nil
 }
 
@@ -49,6 +53,10 @@ func (qb *performerQueryBuilder) Update(updatedObject models.PerformerPartial) (
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(updatedObject.ID); err != nil {
+		return nil, err
+	}
+
 	var ret models.Performer
 	if err := qb.get(updatedObject.ID, &ret); err != nil {
 		return nil, err
@@ -63,6 +71,10 @@ func (qb *performerQueryBuilder) UpdateFull(updatedObject models.Performer) (*mo
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(updatedObject.ID); err != nil {
+		return nil, err
+	}
+
 	var ret models.Performer
 	if err := qb.get(updatedObject.ID, &ret); err != nil {
 		return nil, err
@@ -78,7 +90,11 @@ func (qb *performerQueryBuilder) Destroy(id int) error {
 		return err
 	}
 
-	return qb.destroyExisting([]int{id})
+	if err := qb.destroyExisting([]int{id}); err != nil {
+		return err
+	}
+
+	return qb.updateSearchIndex(id)
 }
 
 func (qb *performerQueryBuilder) Find(id int) (*models.Performer, error) {
@@ -603,3 +619,7 @@ func (qb *performerQueryBuilder) FindByStashIDStatus(hasStashID bool, stashboxEn
 	args := []interface{}{stashboxEndpoint}
 	return qb.queryPerformers(query, args)
 }
+
+func (qb *performerQueryBuilder) updateSearchIndex(id int) error {
+	return updateSearchIndex(qb.tx, models.SearchResultTypePerformer, id)
+}
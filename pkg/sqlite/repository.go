@@ -10,6 +10,7 @@ import (
 
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 const idColumn = "id"
@@ -78,8 +79,14 @@ func (r *repository) updateMap(id int, m map[string]interface{}) error {
 		return fmt.Errorf("%s %d does not exist in %s", r.idColumn, id, r.tableName)
 	}
 
+	// the id is bound separately from the updated columns
+	args := map[string]interface{}{"id": id}
+	for k, v := range m {
+		args[k] = v
+	}
+
 	stmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s.%s = :id", r.tableName, updateSetMap(m), r.tableName, r.idColumn)
-	_, err = r.tx.NamedExec(stmt, m)
+	_, err = r.tx.NamedExec(stmt, args)
 
 	return err
 }
@@ -329,6 +336,38 @@ func (r *joinRepository) replace(id int, foreignIDs []int) error {
 	return nil
 }
 
+// replaceKeepExisting replaces the joins of id with foreignIDs, without
+// deleting the joins that are kept, so that their other columns are kept.
+func (r *joinRepository) replaceKeepExisting(id int, foreignIDs []int) error {
+	existing, err := r.getIDs(id)
+	if err != nil {
+		return err
+	}
+
+	for _, fk := range existing {
+		if utils.IntInclude(foreignIDs, fk) {
+			continue
+		}
+
+		stmt := fmt.Sprintf("DELETE FROM %s WHERE %s = ? AND %s = ?", r.tableName, r.idColumn, r.fkColumn)
+		if _, err := r.tx.Exec(stmt, id, fk); err != nil {
+			return err
+		}
+	}
+
+	for _, fk := range utils.IntAppendUniques(nil, foreignIDs) {
+		if utils.IntInclude(existing, fk) {
+			continue
+		}
+
+		if _, err := r.insert(id, fk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 type imageRepository struct {
 	repository
 	imageColumn string
@@ -427,6 +466,135 @@ func (r *stashIDRepository) replace(id int, newIDs []models.StashID) error {
 	return nil
 }
 
+// userDataRepository stores the values of objects that are specific to each
+// user. Objects without a row for a user have default values.
+type userDataRepository struct {
+	repository
+}
+
+type userDatas []*models.UserData
+
+func (s *userDatas) Append(o interface{}) {
+	*s = append(*s, o.(*models.UserData))
+}
+
+func (s *userDatas) New() interface{} {
+	return &models.UserData{}
+}
+
+func (r *userDataRepository) get(id int, userID int) (*models.UserData, error) {
+	query := fmt.Sprintf("SELECT user_id, rating, o_counter, watched FROM %s WHERE %s = ? AND user_id = ?", r.tableName, r.idColumn)
+	var ret userDatas
+	if err := r.query(query, []interface{}{id, userID}, &ret); err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return &models.UserData{UserID: userID}, nil
+	}
+
+	return ret[0], nil
+}
+
+func (r *userDataRepository) getAll(id int) ([]*models.UserData, error) {
+	query := fmt.Sprintf("SELECT user_id, rating, o_counter, watched FROM %s WHERE %s = ? ORDER BY user_id", r.tableName, r.idColumn)
+	var ret userDatas
+	err := r.query(query, []interface{}{id}, &ret)
+	return []*models.UserData(ret), err
+}
+
+// ensureExists creates the row for the object and user if it does not exist.
+func (r *userDataRepository) ensureExists(id int, userID int) error {
+	stmt := fmt.Sprintf("INSERT OR IGNORE INTO %s (%s, user_id) VALUES (?, ?)", r.tableName, r.idColumn)
+	_, err := r.tx.Exec(stmt, id, userID)
+	return err
+}
+
+func (r *userDataRepository) setColumn(id int, userID int, column string, value interface{}) error {
+	if err := r.ensureExists(id, userID); err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ? AND user_id = ?", r.tableName, column, r.idColumn)
+	_, err := r.tx.Exec(stmt, value, id, userID)
+	return err
+}
+
+func (r *userDataRepository) update(id int, userID int, updatedData models.UserDataPartial) error {
+	if updatedData.Rating != nil {
+		if err := r.setColumn(id, userID, "rating", *updatedData.Rating); err != nil {
+			return err
+		}
+	}
+
+	if updatedData.Watched != nil {
+		if err := r.setColumn(id, userID, "watched", *updatedData.Watched); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addOCounter adds delta to the o-counter of the object for the user, to a
+// minimum of zero. Returns the new o-counter.
+func (r *userDataRepository) addOCounter(id int, userID int, delta int) (int, error) {
+	if err := r.ensureExists(id, userID); err != nil {
+		return 0, err
+	}
+
+	stmt := fmt.Sprintf("UPDATE %s SET o_counter = MAX(o_counter + ?, 0) WHERE %s = ? AND user_id = ?", r.tableName, r.idColumn)
+	if _, err := r.tx.Exec(stmt, delta, id, userID); err != nil {
+		return 0, err
+	}
+
+	data, err := r.get(id, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return data.OCounter, nil
+}
+
+func (r *userDataRepository) resetOCounter(id int, userID int) (int, error) {
+	if err := r.setColumn(id, userID, "o_counter", 0); err != nil {
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+// replace replaces the values of the object for all users. Other columns of
+// the existing rows, such as the play history of scenes, are kept.
+func (r *userDataRepository) replace(id int, data []*models.UserData) error {
+	resetStmt := fmt.Sprintf("UPDATE %s SET rating = NULL, o_counter = 0, watched = 0 WHERE %s = ?", r.tableName, r.idColumn)
+	if _, err := r.tx.Exec(resetStmt, id); err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf(`INSERT INTO %[1]s (%[2]s, user_id, rating, o_counter, watched) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(%[2]s, user_id) DO UPDATE SET rating = excluded.rating, o_counter = excluded.o_counter, watched = excluded.watched`, r.tableName, r.idColumn)
+	for _, d := range data {
+		if _, err := r.tx.Exec(stmt, id, d.UserID, d.Rating, d.OCounter, d.Watched); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getSort returns the sort clause to sort by a column of the rows of the
+// user.
+func (r *userDataRepository) getSort(column string, userID int, parentIDCol string, direction string) string {
+	return fmt.Sprintf(" ORDER BY (SELECT %s FROM %s WHERE %s) %s", column, r.tableName, r.joinOnClause(userID, parentIDCol), getSortDirection(direction))
+}
+
+// joinOnClause returns the join clause to join the rows of the user, using
+// the table name as the alias.
+func (r *userDataRepository) joinOnClause(userID int, parentIDCol string) string {
+	return fmt.Sprintf("%s.%s = %s AND %s.user_id = %d", r.tableName, r.idColumn, parentIDCol, r.tableName, userID)
+}
+
 func listKeys(i interface{}, addPrefix bool) string {
 	var query []string
 	v := reflect.ValueOf(i)
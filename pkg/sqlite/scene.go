@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash/pkg/models"
@@ -17,6 +18,10 @@ const performersScenesTable = "performers_scenes"
 const scenesTagsTable = "scenes_tags"
 const scenesGalleriesTable = "scenes_galleries"
 const moviesScenesTable = "movies_scenes"
+const sceneFilesTable = "scene_files"
+const scenesUsersTable = "scenes_users"
+const sceneCaptionsTable = "scene_captions"
+const sceneTracksTable = "scene_tracks"
 
 var scenesForPerformerQuery = selectAll(sceneTable) + `
 LEFT JOIN performers_scenes as performers_join on performers_join.scene_id = scenes.id
@@ -53,6 +58,10 @@ WHERE galleries_join.gallery_id = ?
 GROUP BY scenes.id
 `
 
+var sceneFilesJoin = `
+INNER JOIN scene_files ON scene_files.scene_id = scenes.id
+`
+
 var countScenesForMissingChecksumQuery = `
 SELECT id FROM scenes
 WHERE scenes.checksum is null
@@ -81,24 +90,44 @@ ORDER BY size DESC
 
 type sceneQueryBuilder struct {
 	repository
+
+	// userID is the user that user-specific values are filtered and sorted
+	// for.
+	userID int
 }
 
-func NewSceneReaderWriter(tx dbi) *sceneQueryBuilder {
+// NewSceneReaderWriter returns a scene query builder that filters and sorts
+// user-specific values for the user with the provided id.
+func NewSceneReaderWriter(tx dbi, userID int) *sceneQueryBuilder {
 	return &sceneQueryBuilder{
-		repository{
+		repository: repository{
 			tx:        tx,
 			tableName: sceneTable,
 			idColumn:  idColumn,
 		},
+		userID: userID,
 	}
 }
 
+// Create creates a new scene. The file fields of newObject are used to create
+// the primary file of the scene.
 func (qb *sceneQueryBuilder) Create(newObject models.Scene) (*models.Scene, error) {
 	var ret models.Scene
 	if err := qb.insertObject(newObject, &ret); err != nil {
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(ret.ID); err != nil {
+		return nil, err
+	}
+
+	primaryFile := models.NewSceneFile(newObject)
+	primaryFile.SceneID = ret.ID
+	primaryFile.Primary = true
+	if _, err := qb.filesRepository().insert(primaryFile); err != nil {
+		return nil, err
+	}
+
 	return &ret, nil
 }
 
@@ -108,6 +137,30 @@ func (qb *sceneQueryBuilder) Update(updatedObject models.ScenePartial) (*models.
 		return nil, err
 	}
 
+	if err := qb.updatePrimaryFile(updatedObject.ID, models.SceneFilePartial{
+		Path:        updatedObject.Path,
+		Checksum:    updatedObject.Checksum,
+		OSHash:      updatedObject.OSHash,
+		Phash:       updatedObject.Phash,
+		Size:        updatedObject.Size,
+		Duration:    updatedObject.Duration,
+		VideoCodec:  updatedObject.VideoCodec,
+		AudioCodec:  updatedObject.AudioCodec,
+		Format:      updatedObject.Format,
+		Width:       updatedObject.Width,
+		Height:      updatedObject.Height,
+		Framerate:   updatedObject.Framerate,
+		Bitrate:     updatedObject.Bitrate,
+		Interactive: updatedObject.Interactive,
+		FileModTime: updatedObject.FileModTime,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := qb.updateSearchIndex(updatedObject.ID); err != nil {
+		return nil, err
+	}
+
 	return qb.find(updatedObject.ID)
 }
 
@@ -117,64 +170,161 @@ func (qb *sceneQueryBuilder) UpdateFull(updatedObject models.Scene) (*models.Sce
 		return nil, err
 	}
 
+	f := models.NewSceneFile(updatedObject)
+	if err := qb.updatePrimaryFile(updatedObject.ID, models.SceneFilePartial{
+		Path:        &f.Path,
+		Checksum:    &f.Checksum,
+		OSHash:      &f.OSHash,
+		Phash:       &f.Phash,
+		Size:        &f.Size,
+		Duration:    &f.Duration,
+		VideoCodec:  &f.VideoCodec,
+		AudioCodec:  &f.AudioCodec,
+		Format:      &f.Format,
+		Width:       &f.Width,
+		Height:      &f.Height,
+		Framerate:   &f.Framerate,
+		Bitrate:     &f.Bitrate,
+		Interactive: &f.Interactive,
+		FileModTime: &f.FileModTime,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := qb.updateSearchIndex(updatedObject.ID); err != nil {
+		return nil, err
+	}
+
 	return qb.find(updatedObject.ID)
 }
 
 func (qb *sceneQueryBuilder) UpdateFileModTime(id int, modTime models.NullSQLiteTimestamp) error {
-	return qb.updateMap(id, map[string]interface{}{
+	if err := qb.updateMap(id, map[string]interface{}{
 		"file_mod_time": modTime,
+	}); err != nil {
+		return err
+	}
+
+	return qb.updatePrimaryFile(id, models.SceneFilePartial{
+		FileModTime: &modTime,
 	})
 }
 
-func (qb *sceneQueryBuilder) IncrementOCounter(id int) (int, error) {
-	_, err := qb.tx.Exec(
-		`UPDATE scenes SET o_counter = o_counter + 1 WHERE scenes.id = ?`,
-		id,
-	)
-	if err != nil {
-		return 0, err
+// updatePrimaryFile writes the file fields that were written to the scene to
+// its primary file as well, so that the file columns of the scene do not
+// drift from the scene_files table.
+func (qb *sceneQueryBuilder) updatePrimaryFile(sceneID int, updatedFile models.SceneFilePartial) error {
+	if updatedFile == (models.SceneFilePartial{}) {
+		return nil
 	}
 
-	scene, err := qb.find(id)
+	files, err := qb.GetFiles(sceneID)
 	if err != nil {
-		return 0, err
+		return err
+	}
+
+	// GetFiles returns the primary file first
+	if len(files) == 0 || !files[0].Primary {
+		return nil
 	}
 
-	return scene.OCounter, nil
+	updatedFile.ID = files[0].ID
+
+	const partial = true
+	return qb.filesRepository().update(updatedFile.ID, updatedFile, partial)
 }
 
-func (qb *sceneQueryBuilder) DecrementOCounter(id int) (int, error) {
-	_, err := qb.tx.Exec(
-		`UPDATE scenes SET o_counter = o_counter - 1 WHERE scenes.id = ? and scenes.o_counter > 0`,
-		id,
-	)
-	if err != nil {
-		return 0, err
+func (qb *sceneQueryBuilder) IncrementOCounter(id int, userID int) (int, error) {
+	return qb.userDataRepository().addOCounter(id, userID, 1)
+}
+
+func (qb *sceneQueryBuilder) DecrementOCounter(id int, userID int) (int, error) {
+	return qb.userDataRepository().addOCounter(id, userID, -1)
+}
+
+func (qb *sceneQueryBuilder) ResetOCounter(id int, userID int) (int, error) {
+	return qb.userDataRepository().resetOCounter(id, userID)
+}
+
+func (qb *sceneQueryBuilder) GetUserData(sceneID int, userID int) (*models.UserData, error) {
+	return qb.userDataRepository().get(sceneID, userID)
+}
+
+func (qb *sceneQueryBuilder) GetAllUserData(sceneID int) ([]*models.UserData, error) {
+	return qb.userDataRepository().getAll(sceneID)
+}
+
+func (qb *sceneQueryBuilder) UpdateUserData(sceneID int, userID int, updatedData models.UserDataPartial) error {
+	return qb.userDataRepository().update(sceneID, userID, updatedData)
+}
+
+func (qb *sceneQueryBuilder) UpdateAllUserData(sceneID int, data []*models.UserData) error {
+	return qb.userDataRepository().replace(sceneID, data)
+}
+
+type scenePlayHistories []*models.ScenePlayHistory
+
+func (s *scenePlayHistories) Append(o interface{}) {
+	*s = append(*s, o.(*models.ScenePlayHistory))
+}
+
+func (s *scenePlayHistories) New() interface{} {
+	return &models.ScenePlayHistory{}
+}
+
+func (qb *sceneQueryBuilder) GetPlayHistory(sceneID int, userID int) (*models.ScenePlayHistory, error) {
+	query := "SELECT user_id, play_count, play_duration, last_played_at, resume_time FROM " + scenesUsersTable + " WHERE scene_id = ? AND user_id = ?"
+	var ret scenePlayHistories
+	if err := qb.query(query, []interface{}{sceneID, userID}, &ret); err != nil {
+		return nil, err
 	}
 
-	scene, err := qb.find(id)
-	if err != nil {
-		return 0, err
+	if len(ret) == 0 {
+		return &models.ScenePlayHistory{UserID: userID}, nil
 	}
 
-	return scene.OCounter, nil
+	return ret[0], nil
 }
 
-func (qb *sceneQueryBuilder) ResetOCounter(id int) (int, error) {
-	_, err := qb.tx.Exec(
-		`UPDATE scenes SET o_counter = 0 WHERE scenes.id = ?`,
-		id,
-	)
-	if err != nil {
+func (qb *sceneQueryBuilder) AddPlay(sceneID int, userID int, playedAt time.Time) (int, error) {
+	if err := qb.userDataRepository().ensureExists(sceneID, userID); err != nil {
+		return 0, err
+	}
+
+	stmt := "UPDATE " + scenesUsersTable + " SET play_count = play_count + 1, last_played_at = ? WHERE scene_id = ? AND user_id = ?"
+	if _, err := qb.tx.Exec(stmt, models.SQLiteTimestamp{Timestamp: playedAt}, sceneID, userID); err != nil {
 		return 0, err
 	}
 
-	scene, err := qb.find(id)
+	history, err := qb.GetPlayHistory(sceneID, userID)
 	if err != nil {
 		return 0, err
 	}
 
-	return scene.OCounter, nil
+	return history.PlayCount, nil
+}
+
+func (qb *sceneQueryBuilder) SaveActivity(sceneID int, userID int, resumeTime *float64, playDuration *float64) error {
+	r := qb.userDataRepository()
+
+	if resumeTime != nil {
+		if err := r.setColumn(sceneID, userID, "resume_time", *resumeTime); err != nil {
+			return err
+		}
+	}
+
+	if playDuration != nil {
+		if err := r.ensureExists(sceneID, userID); err != nil {
+			return err
+		}
+
+		stmt := "UPDATE " + scenesUsersTable + " SET play_duration = play_duration + ? WHERE scene_id = ? AND user_id = ?"
+		if _, err := qb.tx.Exec(stmt, *playDuration, sceneID, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (qb *sceneQueryBuilder) Destroy(id int) error {
@@ -187,7 +337,11 @@ func (qb *sceneQueryBuilder) Destroy(id int) error {
 	// scene markers should be handled prior to calling destroy
 	// galleries should be handled prior to calling destroy
 
-	return qb.destroyExisting([]int{id})
+	if err := qb.destroyExisting([]int{id}); err != nil {
+		return err
+	}
+
+	return qb.updateSearchIndex(id)
 }
 
 func (qb *sceneQueryBuilder) Find(id int) (*models.Scene, error) {
@@ -223,20 +377,23 @@ func (qb *sceneQueryBuilder) find(id int) (*models.Scene, error) {
 	return &ret, nil
 }
 
+// FindByChecksum returns the scene owning a file with the provided checksum.
 func (qb *sceneQueryBuilder) FindByChecksum(checksum string) (*models.Scene, error) {
-	query := "SELECT * FROM scenes WHERE checksum = ? LIMIT 1"
+	query := selectAll(sceneTable) + sceneFilesJoin + "WHERE scene_files.checksum = ? LIMIT 1"
 	args := []interface{}{checksum}
 	return qb.queryScene(query, args)
 }
 
+// FindByOSHash returns the scene owning a file with the provided oshash.
 func (qb *sceneQueryBuilder) FindByOSHash(oshash string) (*models.Scene, error) {
-	query := "SELECT * FROM scenes WHERE oshash = ? LIMIT 1"
+	query := selectAll(sceneTable) + sceneFilesJoin + "WHERE scene_files.oshash = ? LIMIT 1"
 	args := []interface{}{oshash}
 	return qb.queryScene(query, args)
 }
 
+// FindByPath returns the scene owning the file with the provided path.
 func (qb *sceneQueryBuilder) FindByPath(path string) (*models.Scene, error) {
-	query := selectAll(sceneTable) + "WHERE path = ? LIMIT 1"
+	query := selectAll(sceneTable) + sceneFilesJoin + "WHERE scene_files.path = ? LIMIT 1"
 	args := []interface{}{path}
 	return qb.queryScene(query, args)
 }
@@ -365,8 +522,8 @@ func (qb *sceneQueryBuilder) makeFilter(sceneFilter *models.SceneFilterType) *fi
 	query.handleCriterion(stringCriterionHandler(sceneFilter.Oshash, "scenes.oshash"))
 	query.handleCriterion(stringCriterionHandler(sceneFilter.Checksum, "scenes.checksum"))
 	query.handleCriterion(phashCriterionHandler(sceneFilter.Phash))
-	query.handleCriterion(intCriterionHandler(sceneFilter.Rating, "scenes.rating"))
-	query.handleCriterion(intCriterionHandler(sceneFilter.OCounter, "scenes.o_counter"))
+	query.handleCriterion(userDataCriterionHandler(qb.userDataRepository(), qb.userID, "scenes.id", sceneFilter.Rating, sceneFilter.OCounter, sceneFilter.Watched))
+	query.handleCriterion(scenePlayHistoryCriterionHandler(qb, sceneFilter.PlayCount, sceneFilter.PlayDuration))
 	query.handleCriterion(boolCriterionHandler(sceneFilter.Organized, "scenes.organized"))
 	query.handleCriterion(durationCriterionHandler(sceneFilter.Duration, "scenes.duration"))
 	query.handleCriterion(resolutionCriterionHandler(sceneFilter.Resolution, "scenes.height", "scenes.width"))
@@ -507,6 +664,19 @@ func hasMarkersCriterionHandler(hasMarkers *string) criterionHandlerFunc {
 	}
 }
 
+func scenePlayHistoryCriterionHandler(qb *sceneQueryBuilder, playCount *models.IntCriterionInput, playDuration *models.IntCriterionInput) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if playCount == nil && playDuration == nil {
+			return
+		}
+
+		f.addJoin(scenesUsersTable, "", qb.userDataRepository().joinOnClause(qb.userID, "scenes.id"))
+
+		intCriterionHandler(playCount, "IFNULL("+scenesUsersTable+".play_count, 0)")(f)
+		intCriterionHandler(playDuration, "IFNULL("+scenesUsersTable+".play_duration, 0)")(f)
+	}
+}
+
 func sceneIsMissingCriterionHandler(qb *sceneQueryBuilder, isMissing *string) criterionHandlerFunc {
 	return func(f *filterBuilder) {
 		if isMissing != nil && *isMissing != "" {
@@ -530,6 +700,9 @@ func sceneIsMissingCriterionHandler(qb *sceneQueryBuilder, isMissing *string) cr
 			case "stash_id":
 				qb.stashIDRepository().join(f, "scene_stash_ids", "scenes.id")
 				f.addWhere("scene_stash_ids.scene_id IS NULL")
+			case "rating":
+				f.addJoin(scenesUsersTable, "", qb.userDataRepository().joinOnClause(qb.userID, "scenes.id"))
+				f.addWhere(scenesUsersTable + ".rating IS NULL")
 			default:
 				f.addWhere("(scenes." + *isMissing + " IS NULL OR TRIM(scenes." + *isMissing + ") = '')")
 			}
@@ -661,6 +834,8 @@ func (qb *sceneQueryBuilder) setSceneSort(query *queryBuilder, findFilter *model
 		query.sortAndPagination += getCountSort(sceneTable, scenesTagsTable, sceneIDColumn, direction)
 	case "performer_count":
 		query.sortAndPagination += getCountSort(sceneTable, performersScenesTable, sceneIDColumn, direction)
+	case "rating", "o_counter", "play_count", "play_duration", "last_played_at", "resume_time":
+		query.sortAndPagination += qb.userDataRepository().getSort(sort, qb.userID, "scenes.id", direction)
 	default:
 		query.sortAndPagination += getSort(sort, direction, "scenes")
 	}
@@ -767,6 +942,16 @@ func (qb *sceneQueryBuilder) UpdatePerformers(id int, performerIDs []int) error
 	return qb.performersRepository().replace(id, performerIDs)
 }
 
+func (qb *sceneQueryBuilder) userDataRepository() *userDataRepository {
+	return &userDataRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: scenesUsersTable,
+			idColumn:  sceneIDColumn,
+		},
+	}
+}
+
 func (qb *sceneQueryBuilder) tagsRepository() *joinRepository {
 	return &joinRepository{
 		repository: repository{
@@ -825,6 +1010,274 @@ func (qb *sceneQueryBuilder) UpdateStashIDs(sceneID int, stashIDs []models.Stash
 	return qb.stashIDRepository().replace(sceneID, stashIDs)
 }
 
+func (qb *sceneQueryBuilder) filesRepository() *repository {
+	return &repository{
+		tx:        qb.tx,
+		tableName: sceneFilesTable,
+		idColumn:  idColumn,
+	}
+}
+
+func (qb *sceneQueryBuilder) GetFiles(sceneID int) ([]*models.SceneFile, error) {
+	query := selectAll(sceneFilesTable) + "WHERE scene_id = ? ORDER BY is_primary DESC, path ASC"
+	return qb.querySceneFiles(query, []interface{}{sceneID})
+}
+
+func (qb *sceneQueryBuilder) FindFile(fileID int) (*models.SceneFile, error) {
+	var ret models.SceneFile
+	if err := qb.filesRepository().get(fileID, &ret); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *sceneQueryBuilder) FindFileByPath(path string) (*models.SceneFile, error) {
+	query := selectAll(sceneFilesTable) + "WHERE path = ? LIMIT 1"
+	ret, err := qb.querySceneFiles(query, []interface{}{path})
+	if err != nil || len(ret) < 1 {
+		return nil, err
+	}
+	return ret[0], nil
+}
+
+func (qb *sceneQueryBuilder) FindFilesByChecksum(checksum string) ([]*models.SceneFile, error) {
+	query := selectAll(sceneFilesTable) + "WHERE checksum = ?"
+	return qb.querySceneFiles(query, []interface{}{checksum})
+}
+
+func (qb *sceneQueryBuilder) FindFilesByOSHash(oshash string) ([]*models.SceneFile, error) {
+	query := selectAll(sceneFilesTable) + "WHERE oshash = ?"
+	return qb.querySceneFiles(query, []interface{}{oshash})
+}
+
+func (qb *sceneQueryBuilder) querySceneFiles(query string, args []interface{}) ([]*models.SceneFile, error) {
+	var ret models.SceneFiles
+	if err := qb.query(query, args, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.SceneFile(ret), nil
+}
+
+func (qb *sceneQueryBuilder) captionsRepository() *repository {
+	return &repository{
+		tx:        qb.tx,
+		tableName: sceneCaptionsTable,
+		idColumn:  "file_id",
+	}
+}
+
+// GetCaptions returns the captions of the primary file of the scene. Caption
+// files are returned before subtitle streams.
+func (qb *sceneQueryBuilder) GetCaptions(sceneID int) ([]*models.SceneCaption, error) {
+	query := selectAll(sceneCaptionsTable) + `
+INNER JOIN scene_files ON scene_files.id = scene_captions.file_id
+WHERE scene_files.scene_id = ? AND scene_files.is_primary = 1
+ORDER BY scene_captions.stream_index IS NOT NULL, scene_captions.path, scene_captions.stream_index
+`
+	return qb.querySceneCaptions(query, []interface{}{sceneID})
+}
+
+// GetFileCaptions returns the captions of the file. Caption files are
+// returned before subtitle streams.
+func (qb *sceneQueryBuilder) GetFileCaptions(fileID int) ([]*models.SceneCaption, error) {
+	query := selectAll(sceneCaptionsTable) + "WHERE file_id = ? ORDER BY stream_index IS NOT NULL, path, stream_index"
+	return qb.querySceneCaptions(query, []interface{}{fileID})
+}
+
+func (qb *sceneQueryBuilder) querySceneCaptions(query string, args []interface{}) ([]*models.SceneCaption, error) {
+	var ret models.SceneCaptions
+	if err := qb.captionsRepository().query(query, args, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.SceneCaption(ret), nil
+}
+
+func (qb *sceneQueryBuilder) UpdateFileCaptions(fileID int, captions []*models.SceneCaption) error {
+	r := qb.captionsRepository()
+	if err := r.destroy([]int{fileID}); err != nil {
+		return err
+	}
+
+	for _, c := range captions {
+		caption := *c
+		caption.FileID = fileID
+		if _, err := r.insert(caption); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *sceneQueryBuilder) tracksRepository() *repository {
+	return &repository{
+		tx:        qb.tx,
+		tableName: sceneTracksTable,
+		idColumn:  "file_id",
+	}
+}
+
+// GetTracks returns the tracks of the primary file of the scene, ordered by
+// their index in the file.
+func (qb *sceneQueryBuilder) GetTracks(sceneID int) ([]*models.SceneTrack, error) {
+	query := selectAll(sceneTracksTable) + `
+INNER JOIN scene_files ON scene_files.id = scene_tracks.file_id
+WHERE scene_files.scene_id = ? AND scene_files.is_primary = 1
+ORDER BY scene_tracks.stream_index
+`
+	var ret models.SceneTracks
+	if err := qb.tracksRepository().query(query, []interface{}{sceneID}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.SceneTrack(ret), nil
+}
+
+func (qb *sceneQueryBuilder) UpdateFileTracks(fileID int, tracks []*models.SceneTrack) error {
+	r := qb.tracksRepository()
+	if err := r.destroy([]int{fileID}); err != nil {
+		return err
+	}
+
+	for _, t := range tracks {
+		track := *t
+		track.FileID = fileID
+		if _, err := r.insert(track); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateFile adds a new file to a scene. If the new file is the primary file,
+// then any existing primary file of the scene is demoted.
+func (qb *sceneQueryBuilder) CreateFile(newFile models.SceneFile) (*models.SceneFile, error) {
+	var ret models.SceneFile
+	if err := qb.filesRepository().insertObject(newFile, &ret); err != nil {
+		return nil, err
+	}
+
+	if ret.Primary {
+		if err := qb.SetPrimaryFile(ret.SceneID, ret.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ret, nil
+}
+
+// UpdateFile updates the provided file. If the file is the primary file of
+// its scene, then the scene is updated to reflect the new file details.
+func (qb *sceneQueryBuilder) UpdateFile(updatedFile models.SceneFilePartial) (*models.SceneFile, error) {
+	const partial = true
+	if err := qb.filesRepository().update(updatedFile.ID, updatedFile, partial); err != nil {
+		return nil, err
+	}
+
+	ret, err := qb.FindFile(updatedFile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ret.Primary {
+		if err := qb.SetPrimaryFile(ret.SceneID, ret.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+// DestroyFile removes a file from its scene. If the file was the primary
+// file, then the remaining file with the lowest id becomes the primary file.
+// The scene itself is not removed, even if it has no files remaining.
+func (qb *sceneQueryBuilder) DestroyFile(fileID int) error {
+	f, err := qb.FindFile(fileID)
+	if err != nil {
+		return err
+	}
+
+	if f == nil {
+		return fmt.Errorf("scene file with id %d not found", fileID)
+	}
+
+	if err := qb.filesRepository().destroy([]int{fileID}); err != nil {
+		return err
+	}
+
+	if !f.Primary {
+		return nil
+	}
+
+	remaining, err := qb.GetFiles(f.SceneID)
+	if err != nil {
+		return err
+	}
+
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	// GetFiles sorts by path, so pick the oldest remaining file
+	newPrimary := remaining[0]
+	for _, rf := range remaining {
+		if rf.ID < newPrimary.ID {
+			newPrimary = rf
+		}
+	}
+
+	return qb.SetPrimaryFile(f.SceneID, newPrimary.ID)
+}
+
+// SetPrimaryFile makes the provided file the primary file of the scene and
+// updates the scene to reflect the details of the file.
+func (qb *sceneQueryBuilder) SetPrimaryFile(sceneID int, fileID int) error {
+	f, err := qb.FindFile(fileID)
+	if err != nil {
+		return err
+	}
+
+	if f == nil || f.SceneID != sceneID {
+		return fmt.Errorf("scene file with id %d not found for scene %d", fileID, sceneID)
+	}
+
+	if _, err := qb.tx.Exec(`UPDATE scene_files SET is_primary = (id = ?) WHERE scene_id = ?`, fileID, sceneID); err != nil {
+		return err
+	}
+
+	scenePartial := models.ScenePartial{
+		ID:          sceneID,
+		Path:        &f.Path,
+		Checksum:    &f.Checksum,
+		OSHash:      &f.OSHash,
+		Phash:       &f.Phash,
+		Size:        &f.Size,
+		Duration:    &f.Duration,
+		VideoCodec:  &f.VideoCodec,
+		AudioCodec:  &f.AudioCodec,
+		Format:      &f.Format,
+		Width:       &f.Width,
+		Height:      &f.Height,
+		Framerate:   &f.Framerate,
+		Bitrate:     &f.Bitrate,
+		Interactive: &f.Interactive,
+		FileModTime: &f.FileModTime,
+	}
+
+	const partial = true
+	if err := qb.update(sceneID, scenePartial, partial); err != nil {
+		return err
+	}
+
+	return qb.updateSearchIndex(sceneID)
+}
+
 func (qb *sceneQueryBuilder) FindDuplicates(distance int) ([][]*models.Scene, error) {
 	var dupeIds [][]int
 	if distance == 0 {
@@ -872,3 +1325,7 @@ func (qb *sceneQueryBuilder) FindDuplicates(distance int) ([][]*models.Scene, er
 
 	return duplicates, nil
 }
+
+func (qb *sceneQueryBuilder) updateSearchIndex(id int) error {
+	return updateSearchIndex(qb.tx, models.SearchResultTypeScene, id)
+}
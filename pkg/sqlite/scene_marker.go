@@ -36,10 +36,19 @@ func (qb *sceneMarkerQueryBuilder) Create(newObject models.SceneMarker) (*models
 		return nil, err
 	}
 
+	if err := qb.updateSceneSearchIndex(ret.SceneID); err != nil {
+		return nil, err
+	}
+
 	return &ret, nil
 }
 
 func (qb *sceneMarkerQueryBuilder) Update(updatedObject models.SceneMarker) (*models.SceneMarker, error) {
+	existing, err := qb.Find(updatedObject.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	const partial = false
 	if err := qb.update(updatedObject.ID, updatedObject, partial); err != nil {
 		return nil, err
@@ -50,11 +59,45 @@ func (qb *sceneMarkerQueryBuilder) Update(updatedObject models.SceneMarker) (*mo
 		return nil, err
 	}
 
+	// the marker may have been moved to another scene
+	if existing != nil && existing.SceneID != ret.SceneID {
+		if err := qb.updateSceneSearchIndex(existing.SceneID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := qb.updateSceneSearchIndex(ret.SceneID); err != nil {
+		return nil, err
+	}
+
 	return &ret, nil
 }
 
 func (qb *sceneMarkerQueryBuilder) Destroy(id int) error {
-	return qb.destroyExisting([]int{id})
+	existing, err := qb.Find(id)
+	if err != nil {
+		return err
+	}
+
+	if err := qb.destroyExisting([]int{id}); err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return qb.updateSceneSearchIndex(existing.SceneID)
+	}
+
+	return nil
+}
+
+// updateSceneSearchIndex updates the search index document of the scene of
+// a marker, which includes the marker titles.
+func (qb *sceneMarkerQueryBuilder) updateSceneSearchIndex(sceneID sql.NullInt64) error {
+	if !sceneID.Valid {
+		return nil
+	}
+
+	return updateSearchIndex(qb.tx, models.SearchResultTypeScene, int(sceneID.Int64))
 }
 
 func (qb *sceneMarkerQueryBuilder) Find(id int) (*models.SceneMarker, error) {
@@ -4,6 +4,7 @@
 package sqlite_test
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/stashapp/stash/pkg/models"
@@ -68,6 +69,33 @@ func TestMarkerCountByTagID(t *testing.T) {
 	})
 }
 
+func TestMarkerUpdateEndSeconds(t *testing.T) {
+	if err := withRollbackTxn(func(r models.Repository) error {
+		mqb := r.SceneMarker()
+
+		marker, err := mqb.Find(markerIDs[markerIdxWithScene])
+		if err != nil {
+			return err
+		}
+		assert.False(t, marker.EndSeconds.Valid)
+
+		marker.EndSeconds = sql.NullFloat64{Float64: marker.Seconds + 30, Valid: true}
+		if _, err := mqb.Update(*marker); err != nil {
+			return err
+		}
+
+		updated, err := mqb.Find(marker.ID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, marker.EndSeconds, updated.EndSeconds)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 // TODO Update
 // TODO Destroy
 // TODO Find
@@ -4,15 +4,21 @@
 package sqlite_test
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/sqlite"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -284,7 +290,7 @@ func TestSceneQueryPathAndRating(t *testing.T) {
 
 		assert.Len(t, scenes, 1)
 		assert.Equal(t, scenePath, scenes[0].Path)
-		assert.Equal(t, sceneRating.Int64, scenes[0].Rating.Int64)
+		assert.Equal(t, sceneRating.Int64, getSceneUserData(t, sqb, scenes[0].ID).Rating.Int64)
 
 		return nil
 	})
@@ -320,7 +326,7 @@ func TestSceneQueryPathNotRating(t *testing.T) {
 		for _, scene := range scenes {
 			verifyString(t, scene.Path, pathCriterion)
 			ratingCriterion.Modifier = models.CriterionModifierNotEquals
-			verifyInt64(t, scene.Rating, ratingCriterion)
+			verifyInt64(t, getSceneUserData(t, sqb, scene.ID).Rating, ratingCriterion)
 		}
 
 		return nil
@@ -483,7 +489,7 @@ func verifyScenesRating(t *testing.T, ratingCriterion models.IntCriterionInput)
 		scenes := queryScene(t, sqb, &sceneFilter, nil)
 
 		for _, scene := range scenes {
-			verifyInt64(t, scene.Rating, ratingCriterion)
+			verifyInt64(t, getSceneUserData(t, sqb, scene.ID).Rating, ratingCriterion)
 		}
 
 		return nil
@@ -542,7 +548,7 @@ func verifyScenesOCounter(t *testing.T, oCounterCriterion models.IntCriterionInp
 		scenes := queryScene(t, sqb, &sceneFilter, nil)
 
 		for _, scene := range scenes {
-			verifyInt(t, scene.OCounter, oCounterCriterion)
+			verifyInt(t, getSceneUserData(t, sqb, scene.ID).OCounter, oCounterCriterion)
 		}
 
 		return nil
@@ -954,6 +960,39 @@ func TestSceneQueryIsMissingTags(t *testing.T) {
 	})
 }
 
+func TestSceneQueryWatched(t *testing.T) {
+	verifyScenesWatched(t, true)
+	verifyScenesWatched(t, false)
+}
+
+func verifyScenesWatched(t *testing.T, watched bool) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+		sceneFilter := models.SceneFilterType{
+			Watched: &watched,
+		}
+
+		scenes := queryScene(t, sqb, &sceneFilter, nil)
+		assert.Greater(t, len(scenes), 0)
+
+		for _, scene := range scenes {
+			assert.Equal(t, watched, getSceneUserData(t, sqb, scene.ID).Watched)
+		}
+
+		return nil
+	})
+}
+
+func getSceneUserData(t *testing.T, qb models.SceneReader, sceneID int) *models.UserData {
+	ret, err := qb.GetUserData(sceneID, 0)
+	if err != nil {
+		t.Errorf("Error getting scene user data: %s", err.Error())
+		return &models.UserData{}
+	}
+
+	return ret
+}
+
 func TestSceneQueryIsMissingRating(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		sqb := r.Scene()
@@ -968,7 +1007,7 @@ func TestSceneQueryIsMissingRating(t *testing.T) {
 
 		// ensure date is null, empty or "0001-01-01"
 		for _, scene := range scenes {
-			assert.True(t, !scene.Rating.Valid)
+			assert.True(t, !getSceneUserData(t, sqb, scene.ID).Rating.Valid)
 		}
 
 		return nil
@@ -1320,6 +1359,30 @@ func TestSceneQuerySorting(t *testing.T) {
 	})
 }
 
+func TestSceneQuerySortingRating(t *testing.T) {
+	sort := "rating"
+	direction := models.SortDirectionEnumDesc
+	findFilter := models.FindFilterType{
+		Sort:      &sort,
+		Direction: &direction,
+	}
+
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+		scenes := queryScene(t, sqb, nil, &findFilter)
+
+		// ratings should be in descending order
+		last := int64(math.MaxInt64)
+		for _, scene := range scenes {
+			rating := getSceneUserData(t, sqb, scene.ID).Rating.Int64
+			assert.LessOrEqual(t, rating, last)
+			last = rating
+		}
+
+		return nil
+	})
+}
+
 func TestSceneQueryPagination(t *testing.T) {
 	perPage := 1
 	findFilter := models.FindFilterType{
@@ -1637,6 +1700,322 @@ func TestSceneDestroySceneCover(t *testing.T) {
 	}
 }
 
+func TestSceneFindFileByPath(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+
+		const sceneIdx = 1
+		scenePath := getSceneStringValue(sceneIdx, "Path")
+		file, err := sqb.FindFileByPath(scenePath)
+
+		if err != nil {
+			t.Errorf("Error finding scene file: %s", err.Error())
+		}
+
+		assert.Equal(t, sceneIDs[sceneIdx], file.SceneID)
+		assert.Equal(t, scenePath, file.Path)
+		assert.True(t, file.Primary)
+
+		file, err = sqb.FindFileByPath("not exist")
+
+		if err != nil {
+			t.Errorf("Error finding scene file: %s", err.Error())
+		}
+
+		assert.Nil(t, file)
+
+		return nil
+	})
+}
+
+func TestSceneFiles(t *testing.T) {
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Scene()
+
+		const name = "TestSceneFiles"
+		created, err := qb.Create(models.Scene{
+			Path:     name,
+			Checksum: sql.NullString{String: utils.MD5FromString(name), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("Error creating scene: %s", err.Error())
+		}
+
+		const secondName = name + "_2"
+		secondChecksum := utils.MD5FromString(secondName)
+		second, err := qb.CreateFile(models.SceneFile{
+			SceneID:  created.ID,
+			Path:     secondName,
+			Checksum: sql.NullString{String: secondChecksum, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("Error creating scene file: %s", err.Error())
+		}
+
+		files, err := qb.GetFiles(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting scene files: %s", err.Error())
+		}
+		assert.Len(t, files, 2)
+		assert.Equal(t, name, files[0].Path)
+		assert.True(t, files[0].Primary)
+		assert.False(t, files[1].Primary)
+
+		// scene should be found by the checksum of the non-primary file
+		found, err := qb.FindByChecksum(secondChecksum)
+		if err != nil {
+			return fmt.Errorf("Error finding scene: %s", err.Error())
+		}
+		assert.Equal(t, created.ID, found.ID)
+
+		// setting the primary file should update the scene's file fields
+		if err := qb.SetPrimaryFile(created.ID, second.ID); err != nil {
+			return fmt.Errorf("Error setting primary file: %s", err.Error())
+		}
+
+		found, err = qb.Find(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error finding scene: %s", err.Error())
+		}
+		assert.Equal(t, secondName, found.Path)
+		assert.Equal(t, secondChecksum, found.Checksum.String)
+
+		// destroying the primary file should promote the remaining file
+		if err := qb.DestroyFile(second.ID); err != nil {
+			return fmt.Errorf("Error destroying scene file: %s", err.Error())
+		}
+
+		files, err = qb.GetFiles(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting scene files: %s", err.Error())
+		}
+		assert.Len(t, files, 1)
+		assert.True(t, files[0].Primary)
+
+		found, err = qb.Find(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error finding scene: %s", err.Error())
+		}
+		assert.Equal(t, name, found.Path)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneUpdatePrimaryFile(t *testing.T) {
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Scene()
+
+		const name = "TestSceneUpdatePrimaryFile"
+		created, err := qb.Create(models.Scene{
+			Path:     name,
+			Checksum: sql.NullString{String: utils.MD5FromString(name), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("Error creating scene: %s", err.Error())
+		}
+
+		// updating the file fields of the scene should update its primary file
+		const newName = name + "_new"
+		updated := *created
+		updated.Path = newName
+		updated.Size = sql.NullString{String: "123", Valid: true}
+		if _, err := qb.UpdateFull(updated); err != nil {
+			return fmt.Errorf("Error updating scene: %s", err.Error())
+		}
+
+		modTime := models.NullSQLiteTimestamp{
+			Timestamp: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+			Valid:     true,
+		}
+		if err := qb.UpdateFileModTime(created.ID, modTime); err != nil {
+			return fmt.Errorf("Error updating scene file mod time: %s", err.Error())
+		}
+
+		files, err := qb.GetFiles(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting scene files: %s", err.Error())
+		}
+		assert.Len(t, files, 1)
+		assert.Equal(t, newName, files[0].Path)
+		assert.Equal(t, "123", files[0].Size.String)
+		assert.True(t, modTime.Timestamp.Equal(files[0].FileModTime.Timestamp))
+
+		// updating other fields should not change the file
+		title := sql.NullString{String: name, Valid: true}
+		if _, err := qb.Update(models.ScenePartial{
+			ID:    created.ID,
+			Title: &title,
+		}); err != nil {
+			return fmt.Errorf("Error updating scene: %s", err.Error())
+		}
+
+		file, err := qb.FindFileByPath(newName)
+		if err != nil {
+			return fmt.Errorf("Error finding scene file: %s", err.Error())
+		}
+		assert.Equal(t, "123", file.Size.String)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneCaptions(t *testing.T) {
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Scene()
+
+		const name = "TestSceneCaptions"
+		created, err := qb.Create(models.Scene{
+			Path:     name,
+			Checksum: sql.NullString{String: utils.MD5FromString(name), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("Error creating scene: %s", err.Error())
+		}
+
+		const secondName = name + "_2"
+		second, err := qb.CreateFile(models.SceneFile{
+			SceneID:  created.ID,
+			Path:     secondName,
+			Checksum: sql.NullString{String: utils.MD5FromString(secondName), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("Error creating scene file: %s", err.Error())
+		}
+
+		files, err := qb.GetFiles(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting scene files: %s", err.Error())
+		}
+		primaryID := files[0].ID
+
+		stream := &models.SceneCaption{
+			LanguageCode: "en",
+			CaptionType:  models.CaptionTypeSRT,
+			StreamIndex:  sql.NullInt64{Int64: 2, Valid: true},
+		}
+		captionFile := &models.SceneCaption{
+			LanguageCode: "de",
+			CaptionType:  models.CaptionTypeVTT,
+			Path:         sql.NullString{String: name + ".de.vtt", Valid: true},
+		}
+
+		if err := qb.UpdateFileCaptions(primaryID, []*models.SceneCaption{stream, captionFile}); err != nil {
+			return fmt.Errorf("Error updating captions: %s", err.Error())
+		}
+		if err := qb.UpdateFileCaptions(second.ID, []*models.SceneCaption{captionFile}); err != nil {
+			return fmt.Errorf("Error updating captions: %s", err.Error())
+		}
+
+		// the captions of the primary file are returned, caption files first
+		captions, err := qb.GetCaptions(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting captions: %s", err.Error())
+		}
+		if assert.Len(t, captions, 2) {
+			assert.Equal(t, "de", captions[0].LanguageCode)
+			assert.Equal(t, primaryID, captions[0].FileID)
+			assert.Equal(t, "en", captions[1].LanguageCode)
+			assert.True(t, captions[1].IsEmbedded())
+		}
+
+		// updating replaces the existing captions
+		if err := qb.UpdateFileCaptions(primaryID, nil); err != nil {
+			return fmt.Errorf("Error updating captions: %s", err.Error())
+		}
+
+		captions, err = qb.GetCaptions(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting captions: %s", err.Error())
+		}
+		assert.Len(t, captions, 0)
+
+		captions, err = qb.GetFileCaptions(second.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting captions: %s", err.Error())
+		}
+		assert.Len(t, captions, 1)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneTracks(t *testing.T) {
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Scene()
+
+		const name = "TestSceneTracks"
+		created, err := qb.Create(models.Scene{
+			Path:     name,
+			Checksum: sql.NullString{String: utils.MD5FromString(name), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("Error creating scene: %s", err.Error())
+		}
+
+		files, err := qb.GetFiles(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting scene files: %s", err.Error())
+		}
+		primaryID := files[0].ID
+
+		tracks := []*models.SceneTrack{
+			{
+				Index:     2,
+				TrackType: models.TrackTypeAudio,
+				Codec:     "ac3",
+				Language:  "jpn",
+				Title:     "Commentary",
+				Channels:  6,
+			},
+			{
+				Index:     0,
+				TrackType: models.TrackTypeVideo,
+				Codec:     "h264",
+				Language:  models.LanguageUnknown,
+				Default:   true,
+			},
+		}
+
+		if err := qb.UpdateFileTracks(primaryID, tracks); err != nil {
+			return fmt.Errorf("Error updating tracks: %s", err.Error())
+		}
+
+		// tracks are ordered by their index
+		got, err := qb.GetTracks(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting tracks: %s", err.Error())
+		}
+		if assert.Len(t, got, 2) {
+			tracks[0].FileID = primaryID
+			tracks[1].FileID = primaryID
+			assert.Equal(t, tracks[1], got[0])
+			assert.Equal(t, tracks[0], got[1])
+		}
+
+		// updating replaces the existing tracks
+		if err := qb.UpdateFileTracks(primaryID, tracks[1:]); err != nil {
+			return fmt.Errorf("Error updating tracks: %s", err.Error())
+		}
+
+		got, err = qb.GetTracks(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting tracks: %s", err.Error())
+		}
+		assert.Len(t, got, 1)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestSceneStashIDs(t *testing.T) {
 	if err := withTxn(func(r models.Repository) error {
 		qb := r.Scene()
@@ -1702,10 +2081,250 @@ func TestSceneQueryQTrim(t *testing.T) {
 	}
 }
 
+func TestSceneOCounter(t *testing.T) {
+	const userID = 1
+
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Scene()
+		sceneID := sceneIDs[0]
+
+		count, err := qb.IncrementOCounter(sceneID, userID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 1, count)
+
+		count, err = qb.IncrementOCounter(sceneID, userID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 2, count)
+
+		count, err = qb.DecrementOCounter(sceneID, userID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 1, count)
+
+		count, err = qb.ResetOCounter(sceneID, userID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 0, count)
+
+		// decrementing does not go below zero
+		count, err = qb.DecrementOCounter(sceneID, userID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 0, count)
+
+		// the values of other users are unchanged
+		assert.Equal(t, getOCounter(0), getSceneUserData(t, qb, sceneID).OCounter)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneUpdateUserData(t *testing.T) {
+	const (
+		userID   = 1
+		sceneIdx = 1
+		rating   = 2
+	)
+
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Scene()
+		sceneID := sceneIDs[sceneIdx]
+
+		watched := true
+		if err := qb.UpdateUserData(sceneID, userID, models.UserDataPartial{
+			Rating:  &sql.NullInt64{Int64: rating, Valid: true},
+			Watched: &watched,
+		}); err != nil {
+			return err
+		}
+
+		userData, err := qb.GetUserData(sceneID, userID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, int64(rating), userData.Rating.Int64)
+		assert.True(t, userData.Watched)
+		assert.Equal(t, 0, userData.OCounter)
+
+		// the values of other users are unchanged
+		assert.Equal(t, getRating(sceneIdx), getSceneUserData(t, qb, sceneID).Rating)
+
+		all, err := qb.GetAllUserData(sceneID)
+		if err != nil {
+			return err
+		}
+		assert.Len(t, all, 2)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneQueryRatingOtherUser(t *testing.T) {
+	const (
+		userID   = 1
+		sceneIdx = 1
+		rating   = 5
+	)
+
+	ctx := session.SetCurrentUser(context.TODO(), &models.User{ID: userID})
+	txnManager := sqlite.NewTransactionManager()
+
+	// always roll back the changes
+	txnManager.WithTxn(ctx, func(r models.Repository) error {
+		qb := r.Scene()
+		sceneID := sceneIDs[sceneIdx]
+
+		if err := qb.UpdateUserData(sceneID, userID, models.UserDataPartial{
+			Rating: &sql.NullInt64{Int64: rating, Valid: true},
+		}); err != nil {
+			t.Errorf("Error updating scene user data: %s", err.Error())
+		}
+
+		sceneFilter := models.SceneFilterType{
+			Rating: &models.IntCriterionInput{
+				Value:    rating,
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+
+		scenes := queryScene(t, qb, &sceneFilter, nil)
+		assert.Len(t, scenes, 1)
+		assert.Equal(t, sceneID, scenes[0].ID)
+
+		return errors.New("fake error for rollback")
+	})
+}
+
+func TestScenePlayHistory(t *testing.T) {
+	const (
+		sceneIdx     = 2
+		resumeTime   = 12.5
+		playDuration = 30.0
+	)
+
+	playedAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Scene()
+		sceneID := sceneIDs[sceneIdx]
+
+		history, err := qb.GetPlayHistory(sceneID, 0)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 0, history.PlayCount)
+		assert.False(t, history.LastPlayedAt.Valid)
+
+		if _, err := qb.AddPlay(sceneID, 0, playedAt.Add(-time.Hour)); err != nil {
+			return err
+		}
+		playCount, err := qb.AddPlay(sceneID, 0, playedAt)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 2, playCount)
+
+		rt := resumeTime
+		pd := playDuration
+		if err := qb.SaveActivity(sceneID, 0, &rt, &pd); err != nil {
+			return err
+		}
+		if err := qb.SaveActivity(sceneID, 0, nil, &pd); err != nil {
+			return err
+		}
+
+		// updating the other values must not reset the play history
+		watched := true
+		if err := qb.UpdateUserData(sceneID, 0, models.UserDataPartial{
+			Watched: &watched,
+		}); err != nil {
+			return err
+		}
+		if err := qb.UpdateAllUserData(sceneID, []*models.UserData{getUserData(sceneIdx)}); err != nil {
+			return err
+		}
+
+		history, err = qb.GetPlayHistory(sceneID, 0)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 2, history.PlayCount)
+		assert.Equal(t, resumeTime, history.ResumeTime)
+		assert.Equal(t, playDuration*2, history.PlayDuration)
+		assert.True(t, playedAt.Equal(history.LastPlayedAt.Timestamp))
+
+		// the history of other users is unaffected
+		history, err = qb.GetPlayHistory(sceneID, 1)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 0, history.PlayCount)
+
+		sceneFilter := models.SceneFilterType{
+			PlayCount: &models.IntCriterionInput{
+				Value:    2,
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+
+		scenes := queryScene(t, qb, &sceneFilter, nil)
+		assert.Len(t, scenes, 1)
+		assert.Equal(t, sceneID, scenes[0].ID)
+
+		sceneFilter = models.SceneFilterType{
+			PlayDuration: &models.IntCriterionInput{
+				Value:    int(playDuration),
+				Modifier: models.CriterionModifierGreaterThan,
+			},
+		}
+
+		scenes = queryScene(t, qb, &sceneFilter, nil)
+		assert.Len(t, scenes, 1)
+
+		// scenes that have never been played have a play count of 0
+		sceneFilter = models.SceneFilterType{
+			PlayCount: &models.IntCriterionInput{
+				Value:    0,
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+
+		_, count, err := qb.Query(&sceneFilter, nil)
+		if err != nil {
+			return err
+		}
+		_, total, err := qb.Query(nil, nil)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, total-1, count)
+
+		sort := "last_played_at"
+		direction := models.SortDirectionEnumDesc
+		scenes = queryScene(t, qb, nil, &models.FindFilterType{
+			Sort:      &sort,
+			Direction: &direction,
+		})
+		assert.Equal(t, sceneID, scenes[0].ID)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 // TODO Update
-// TODO IncrementOCounter
-// TODO DecrementOCounter
-// TODO ResetOCounter
 // TODO Destroy
 // TODO FindByChecksum
 // TODO Count
@@ -0,0 +1,263 @@
+package sqlite
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const searchIndexTable = "search_index"
+
+// searchTypeBits is the number of low bits of the rowid of a search index
+// document that hold the type of the object. The remaining bits hold the id
+// of the object.
+const searchTypeBits = 3
+
+const (
+	searchHighlightStart = "\x02"
+	searchHighlightEnd   = "\x03"
+)
+
+// searchIndexSource is the source of the search index documents of an
+// object type. query selects the name, aliases, details, path and markers
+// columns of the object with the provided id.
+type searchIndexSource struct {
+	code  int
+	query string
+}
+
+var searchIndexSources = map[models.SearchResultType]searchIndexSource{
+	models.SearchResultTypeScene: {
+		code: 1,
+		query: `SELECT title, NULL, details, path,
+(SELECT group_concat(title, ' ') FROM scene_markers WHERE scene_markers.scene_id = scenes.id)
+FROM scenes WHERE id = ?`,
+	},
+	models.SearchResultTypePerformer: {
+		code:  2,
+		query: "SELECT name, aliases, NULL, NULL, NULL FROM performers WHERE id = ?",
+	},
+	models.SearchResultTypeStudio: {
+		code: 3,
+		query: `SELECT name,
+(SELECT group_concat(alias, ' ') FROM studio_aliases WHERE studio_aliases.studio_id = studios.id),
+NULL, NULL, NULL
+FROM studios WHERE id = ?`,
+	},
+	models.SearchResultTypeTag: {
+		code: 4,
+		query: `SELECT name,
+(SELECT group_concat(alias, ' ') FROM tag_aliases WHERE tag_aliases.tag_id = tags.id),
+NULL, NULL, NULL
+FROM tags WHERE id = ?`,
+	},
+}
+
+// searchColumnWeights are the bm25 weights of the name, aliases, details,
+// path and markers columns.
+const searchColumnWeights = "10.0, 5.0, 1.0, 1.0, 2.0"
+
+func searchRowID(code int, id int) int {
+	return id<<searchTypeBits | code
+}
+
+// updateSearchIndex replaces the search index documents of the objects
+// with the current values of the objects. The documents of objects that no
+// longer exist are removed.
+func updateSearchIndex(tx dbi, objectType models.SearchResultType, ids ...int) error {
+	source, ok := searchIndexSources[objectType]
+	if !ok {
+		return fmt.Errorf("invalid search result type: %s", objectType)
+	}
+
+	deleteStmt := "DELETE FROM " + searchIndexTable + " WHERE rowid = ?"
+	insertStmt := "INSERT INTO " + searchIndexTable + " (rowid, name, aliases, details, path, markers) SELECT ?, * FROM (" + source.query + ")"
+
+	for _, id := range ids {
+		rowID := searchRowID(source.code, id)
+		if _, err := tx.Exec(deleteStmt, rowID); err != nil {
+			return fmt.Errorf("error removing %s %d from search index: %s", objectType, id, err.Error())
+		}
+
+		if _, err := tx.Exec(insertStmt, rowID, id); err != nil {
+			return fmt.Errorf("error adding %s %d to search index: %s", objectType, id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+type searchQueryBuilder struct {
+	repository
+}
+
+func NewSearchReader(tx dbi) *searchQueryBuilder {
+	return &searchQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: searchIndexTable,
+			idColumn:  "rowid",
+		},
+	}
+}
+
+func (qb *searchQueryBuilder) Search(query string, types []models.SearchResultType, limit int) ([]*models.SearchResult, int, error) {
+	match := buildSearchQuery(query)
+	if match == "" {
+		return nil, 0, nil
+	}
+
+	where := searchIndexTable + " MATCH ?"
+	args := []interface{}{match}
+
+	if len(types) > 0 {
+		var codes []string
+		for _, t := range types {
+			source, ok := searchIndexSources[t]
+			if !ok {
+				return nil, 0, fmt.Errorf("invalid search result type: %s", t)
+			}
+			codes = append(codes, fmt.Sprint(source.code))
+		}
+
+		where += fmt.Sprintf(" AND (rowid & %d) IN (%s)", 1<<searchTypeBits-1, strings.Join(codes, ", "))
+	}
+
+	count, err := qb.runCountQuery("SELECT COUNT(*) as count FROM "+searchIndexTable+" WHERE "+where, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stmt := fmt.Sprintf(`SELECT rowid, -bm25(%[1]s, %[2]s) AS score, snippet(%[1]s, -1, ?, ?, '…', 12)
+FROM %[1]s WHERE %[3]s ORDER BY score DESC LIMIT ?`, searchIndexTable, searchColumnWeights, where)
+	args = append([]interface{}{searchHighlightStart, searchHighlightEnd}, args...)
+	args = append(args, limit)
+
+	var ret []*models.SearchResult
+	if err := qb.queryFunc(stmt, args, func(rows *sqlx.Rows) error {
+		var (
+			rowID   int
+			score   float64
+			snippet string
+		)
+		if err := rows.Scan(&rowID, &score, &snippet); err != nil {
+			return err
+		}
+
+		ret = append(ret, &models.SearchResult{
+			Type:    searchResultType(rowID & (1<<searchTypeBits - 1)),
+			ID:      rowID >> searchTypeBits,
+			Score:   score,
+			Snippet: formatSearchSnippet(snippet),
+		})
+		return nil
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	return ret, count, nil
+}
+
+func searchResultType(code int) models.SearchResultType {
+	for t, source := range searchIndexSources {
+		if source.code == code {
+			return t
+		}
+	}
+
+	return ""
+}
+
+// formatSearchSnippet escapes a snippet returned by the search index and
+// wraps the highlighted terms in mark elements.
+func formatSearchSnippet(snippet string) string {
+	ret := html.EscapeString(snippet)
+	ret = strings.ReplaceAll(ret, searchHighlightStart, "<mark>")
+	return strings.ReplaceAll(ret, searchHighlightEnd, "</mark>")
+}
+
+// buildSearchQuery converts a user search query into an FTS5 query. Terms
+// are matched literally, so that punctuation in the query cannot cause
+// syntax errors. Quoted strings are matched as phrases, terms ending in *
+// match any word starting with the term, and terms starting with - exclude
+// the objects that match them. Returns an empty string if the query has no
+// terms to match.
+func buildSearchQuery(q string) string {
+	var include, exclude []string
+
+	runes := []rune(q)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		negate := false
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			negate = true
+			i++
+		}
+
+		var term string
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+
+			phrase := strings.TrimSpace(string(runes[i+1 : end]))
+			i = end + 1
+			if phrase == "" {
+				continue
+			}
+			term = quoteSearchTerm(phrase)
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+
+			word := string(runes[i:end])
+			i = end
+
+			prefix := strings.HasSuffix(word, "*")
+			word = strings.TrimRight(word, "*")
+			if word == "" {
+				continue
+			}
+
+			term = quoteSearchTerm(word)
+			if prefix {
+				term += "*"
+			}
+		}
+
+		if negate {
+			exclude = append(exclude, term)
+		} else {
+			include = append(include, term)
+		}
+	}
+
+	if len(include) == 0 {
+		return ""
+	}
+
+	ret := strings.Join(include, " ")
+	if len(exclude) > 0 {
+		ret = "(" + ret + ")"
+		for _, e := range exclude {
+			ret += " NOT " + e
+		}
+	}
+
+	return ret
+}
+
+func quoteSearchTerm(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"whitespace", "  \t ", ""},
+		{"single term", "foo", `"foo"`},
+		{"multiple terms", " foo  bar ", `"foo" "bar"`},
+		{"phrase", `"foo bar" baz`, `"foo bar" "baz"`},
+		{"unterminated phrase", `baz "foo bar`, `"baz" "foo bar"`},
+		{"empty phrase", `"" foo`, `"foo"`},
+		{"prefix", "foo*", `"foo"*`},
+		{"only wildcard", "*", ""},
+		{"exclude", "foo -bar -\"baz qux\"", `("foo") NOT "bar" NOT "baz qux"`},
+		{"only exclude", "-foo", ""},
+		{"lone dash", "foo - bar", `"foo" "-" "bar"`},
+		{"syntax characters", `OR (bar:* NEAR`, `"OR" "(bar:"* "NEAR"`},
+		{"quotes in term", `fo"o`, `"fo""o"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildSearchQuery(tt.query))
+		})
+	}
+}
+
+func TestFormatSearchSnippet(t *testing.T) {
+	snippet := "<b>" + searchHighlightStart + "foo" + searchHighlightEnd + " & bar"
+	assert.Equal(t, "&lt;b&gt;<mark>foo</mark> &amp; bar", formatSearchSnippet(snippet))
+}
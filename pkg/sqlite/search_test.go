@@ -0,0 +1,159 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func search(t *testing.T, r models.Repository, query string, types ...models.SearchResultType) []*models.SearchResult {
+	t.Helper()
+	results, count, err := r.Search().Search(query, types, 25)
+	if err != nil {
+		t.Errorf("Error searching for %q: %s", query, err.Error())
+	}
+
+	assert.Len(t, results, count)
+	return results
+}
+
+func assertSearchResult(t *testing.T, results []*models.SearchResult, objectType models.SearchResultType, id int) {
+	t.Helper()
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, objectType, results[0].Type)
+		assert.Equal(t, id, results[0].ID)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	if err := withRollbackTxn(func(r models.Repository) error {
+		performer, err := r.Performer().Create(models.Performer{
+			Name:     sql.NullString{String: "Zebediah Quartermaine", Valid: true},
+			Aliases:  sql.NullString{String: "Zeb", Valid: true},
+			Checksum: "search_performer",
+			Favorite: sql.NullBool{Bool: false, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		studio, err := r.Studio().Create(models.Studio{
+			Name:     sql.NullString{String: "Xylophone Pictures", Valid: true},
+			Checksum: "search_studio",
+		})
+		if err != nil {
+			return err
+		}
+		if err := r.Studio().UpdateAliases(studio.ID, []string{"Xylo"}); err != nil {
+			return err
+		}
+
+		tag, err := r.Tag().Create(models.Tag{
+			Name: "Quokka",
+		})
+		if err != nil {
+			return err
+		}
+		if err := r.Tag().UpdateAliases(tag.ID, []string{"Wallaby"}); err != nil {
+			return err
+		}
+
+		scene, err := r.Scene().Create(models.Scene{
+			Title:    sql.NullString{String: "Running Quokkas", Valid: true},
+			Details:  sql.NullString{String: "A documentary", Valid: true},
+			Path:     "/media/nature/marsupials.mp4",
+			Checksum: sql.NullString{String: "search_scene", Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		marker, err := r.SceneMarker().Create(models.SceneMarker{
+			Title:        "Jumping",
+			SceneID:      sql.NullInt64{Int64: int64(scene.ID), Valid: true},
+			PrimaryTagID: tag.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// names, aliases, details, paths and marker titles are searched
+		assertSearchResult(t, search(t, r, "quartermaine"), models.SearchResultTypePerformer, performer.ID)
+		assertSearchResult(t, search(t, r, "zeb"), models.SearchResultTypePerformer, performer.ID)
+		assertSearchResult(t, search(t, r, "xylo"), models.SearchResultTypeStudio, studio.ID)
+		assertSearchResult(t, search(t, r, "wallaby"), models.SearchResultTypeTag, tag.ID)
+		assertSearchResult(t, search(t, r, "documentary"), models.SearchResultTypeScene, scene.ID)
+		assertSearchResult(t, search(t, r, "marsupials"), models.SearchResultTypeScene, scene.ID)
+		assertSearchResult(t, search(t, r, "jumping"), models.SearchResultTypeScene, scene.ID)
+
+		// terms are stemmed
+		assertSearchResult(t, search(t, r, "run"), models.SearchResultTypeScene, scene.ID)
+
+		// prefixes
+		assertSearchResult(t, search(t, r, "quarter*"), models.SearchResultTypePerformer, performer.ID)
+
+		// phrases
+		assertSearchResult(t, search(t, r, `"running quokkas"`), models.SearchResultTypeScene, scene.ID)
+		assert.Len(t, search(t, r, `"quokkas running"`), 0)
+
+		// mixed results and type filter
+		assert.Len(t, search(t, r, "quokka"), 2)
+		assertSearchResult(t, search(t, r, "quokka", models.SearchResultTypeTag), models.SearchResultTypeTag, tag.ID)
+		assertSearchResult(t, search(t, r, "quokka -running"), models.SearchResultTypeTag, tag.ID)
+
+		// snippets highlight the matching terms
+		results := search(t, r, "xylophone")
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "<mark>Xylophone</mark> Pictures", results[0].Snippet)
+			assert.Greater(t, results[0].Score, 0.0)
+		}
+
+		// the index follows changes to the objects
+		name := sql.NullString{String: "Ambrose Pennywhistle", Valid: true}
+		if _, err := r.Performer().Update(models.PerformerPartial{
+			ID:   performer.ID,
+			Name: &name,
+		}); err != nil {
+			return err
+		}
+		assert.Len(t, search(t, r, "quartermaine"), 0)
+		assertSearchResult(t, search(t, r, "pennywhistle"), models.SearchResultTypePerformer, performer.ID)
+
+		if err := r.SceneMarker().Destroy(marker.ID); err != nil {
+			return err
+		}
+		assert.Len(t, search(t, r, "jumping"), 0)
+
+		if err := r.Tag().Destroy(tag.ID); err != nil {
+			return err
+		}
+		assertSearchResult(t, search(t, r, "quokka"), models.SearchResultTypeScene, scene.ID)
+
+		if err := r.Scene().Destroy(scene.ID); err != nil {
+			return err
+		}
+		assert.Len(t, search(t, r, "quokka"), 0)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSearchInvalidQuery(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		// syntax characters are matched literally
+		for _, q := range []string{`"`, "AND", "(", "NEAR(", "-", "*", "col:foo"} {
+			_, _, err := r.Search().Search(q, nil, 25)
+			assert.Nil(t, err, q)
+		}
+
+		return nil
+	})
+}
@@ -388,7 +388,9 @@ func runTests(m *testing.M) int {
 
 	f.Close()
 	databaseFile := f.Name()
-	database.Initialize(databaseFile)
+	if err := database.Initialize(databaseFile); err != nil {
+		panic(fmt.Sprintf("Could not initialize database: %s", err.Error()))
+	}
 
 	// defer close and delete the database
 	defer testTeardown(databaseFile)
@@ -558,6 +560,20 @@ func getOCounter(index int) int {
 	return index % 3
 }
 
+func getWatched(index int) bool {
+	return index%2 == 0
+}
+
+// getUserData returns the values of the user used by the tests, which is
+// no user.
+func getUserData(index int) *models.UserData {
+	return &models.UserData{
+		Rating:   getRating(index),
+		OCounter: getOCounter(index),
+		Watched:  getWatched(index),
+	}
+}
+
 func getSceneDuration(index int) sql.NullFloat64 {
 	duration := index % 4
 	duration = duration * 100
@@ -602,8 +618,6 @@ func createScenes(sqb models.SceneReaderWriter, n int) error {
 			Checksum: sql.NullString{String: getSceneStringValue(i, checksumField), Valid: true},
 			Details:  sql.NullString{String: getSceneStringValue(i, "Details"), Valid: true},
 			URL:      getSceneNullStringValue(i, urlField),
-			Rating:   getRating(i),
-			OCounter: getOCounter(i),
 			Duration: getSceneDuration(i),
 			Height:   getHeight(i),
 			Width:    getWidth(i),
@@ -616,6 +630,10 @@ func createScenes(sqb models.SceneReaderWriter, n int) error {
 			return fmt.Errorf("Error creating scene %v+: %s", scene, err.Error())
 		}
 
+		if err := sqb.UpdateAllUserData(created.ID, []*models.UserData{getUserData(i)}); err != nil {
+			return fmt.Errorf("Error setting scene user data: %s", err.Error())
+		}
+
 		sceneIDs = append(sceneIDs, created.ID)
 	}
 
@@ -641,8 +659,6 @@ func createImages(qb models.ImageReaderWriter, n int) error {
 			Path:     getImagePath(i),
 			Title:    sql.NullString{String: getImageStringValue(i, titleField), Valid: true},
 			Checksum: getImageStringValue(i, checksumField),
-			Rating:   getRating(i),
-			OCounter: getOCounter(i),
 			Height:   getHeight(i),
 			Width:    getWidth(i),
 		}
@@ -653,6 +669,10 @@ func createImages(qb models.ImageReaderWriter, n int) error {
 			return fmt.Errorf("Error creating image %v+: %s", image, err.Error())
 		}
 
+		if err := qb.UpdateAllUserData(created.ID, []*models.UserData{getUserData(i)}); err != nil {
+			return fmt.Errorf("Error setting image user data: %s", err.Error())
+		}
+
 		imageIDs = append(imageIDs, created.ID)
 	}
 
@@ -866,7 +886,7 @@ func getTagPerformerCount(id int) int {
 	return 0
 }
 
-//createTags creates n tags with plain Name and o tags with camel cased NaMe included
+// createTags creates n tags with plain Name and o tags with camel cased NaMe included
 func createTags(tqb models.TagReaderWriter, n int, o int) error {
 	const namePlain = "Name"
 	const nameNoCase = "NaMe"
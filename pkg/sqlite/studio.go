@@ -33,6 +33,10 @@ func (qb *studioQueryBuilder) Create(newObject models.Studio) (*models.Studio, e
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(ret.ID); err != nil {
+		return nil, err
+	}
+
 	return &ret, nil
 }
 
@@ -42,6 +46,10 @@ func (qb *studioQueryBuilder) Update(updatedObject models.StudioPartial) (*model
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(updatedObject.ID); err != nil {
+		return nil, err
+	}
+
 	return qb.Find(updatedObject.ID)
 }
 
@@ -51,6 +59,10 @@ func (qb *studioQueryBuilder) UpdateFull(updatedObject models.Studio) (*models.S
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(updatedObject.ID); err != nil {
+		return nil, err
+	}
+
 	return qb.Find(updatedObject.ID)
 }
 
@@ -62,7 +74,11 @@ func (qb *studioQueryBuilder) Destroy(id int) error {
 		return err
 	}
 
-	return qb.destroyExisting([]int{id})
+	if err := qb.destroyExisting([]int{id}); err != nil {
+		return err
+	}
+
+	return qb.updateSearchIndex(id)
 }
 
 func (qb *studioQueryBuilder) Find(id int) (*models.Studio, error) {
@@ -394,5 +410,13 @@ func (qb *studioQueryBuilder) GetAliases(studioID int) ([]string, error) {
 }
 
 func (qb *studioQueryBuilder) UpdateAliases(studioID int, aliases []string) error {
-	return qb.aliasRepository().replace(studioID, aliases)
+	if err := qb.aliasRepository().replace(studioID, aliases); err != nil {
+		return err
+	}
+
+	return qb.updateSearchIndex(studioID)
+}
+
+func (qb *studioQueryBuilder) updateSearchIndex(id int) error {
+	return updateSearchIndex(qb.tx, models.SearchResultTypeStudio, id)
 }
@@ -34,6 +34,10 @@ func (qb *tagQueryBuilder) Create(newObject models.Tag) (*models.Tag, error) {
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(ret.ID); err != nil {
+		return nil, err
+	}
+
 	return &ret, nil
 }
 
@@ -43,6 +47,10 @@ func (qb *tagQueryBuilder) Update(updatedObject models.TagPartial) (*models.Tag,
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(updatedObject.ID); err != nil {
+		return nil, err
+	}
+
 	return qb.Find(updatedObject.ID)
 }
 
@@ -52,6 +60,10 @@ func (qb *tagQueryBuilder) UpdateFull(updatedObject models.Tag) (*models.Tag, er
 		return nil, err
 	}
 
+	if err := qb.updateSearchIndex(updatedObject.ID); err != nil {
+		return nil, err
+	}
+
 	return qb.Find(updatedObject.ID)
 }
 
@@ -81,7 +93,11 @@ func (qb *tagQueryBuilder) Destroy(id int) error {
 		return errors.New("cannot delete tag used as a primary tag in scene markers")
 	}
 
-	return qb.destroyExisting([]int{id})
+	if err := qb.destroyExisting([]int{id}); err != nil {
+		return err
+	}
+
+	return qb.updateSearchIndex(id)
 }
 
 func (qb *tagQueryBuilder) Find(id int) (*models.Tag, error) {
@@ -531,7 +547,11 @@ func (qb *tagQueryBuilder) GetAliases(tagID int) ([]string, error) {
 }
 
 func (qb *tagQueryBuilder) UpdateAliases(tagID int, aliases []string) error {
-	return qb.aliasRepository().replace(tagID, aliases)
+	if err := qb.aliasRepository().replace(tagID, aliases); err != nil {
+		return err
+	}
+
+	return qb.updateSearchIndex(tagID)
 }
 
 func (qb *tagQueryBuilder) Merge(source []int, destination int) error {
@@ -592,7 +612,8 @@ AND NOT EXISTS(SELECT 1 FROM `+table+` o WHERE o.`+idColumn+` = `+table+`.`+idCo
 		}
 	}
 
-	return nil
+	// the names of the source tags are now aliases of the destination
+	return qb.updateSearchIndex(destination)
 }
 
 func (qb *tagQueryBuilder) UpdateParentTags(tagID int, parentIDs []int) error {
@@ -706,3 +727,7 @@ SELECT t.* FROM tags t INNER JOIN parents p ON t.id = p.parent_id
 
 	return ret, nil
 }
+
+func (qb *tagQueryBuilder) updateSearchIndex(id int) error {
+	return updateSearchIndex(qb.tx, models.SearchResultTypeTag, id)
+}
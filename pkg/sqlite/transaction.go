@@ -9,6 +9,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash/pkg/database"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
 )
 
 type dbi interface {
@@ -85,9 +86,14 @@ func (t *transaction) Gallery() models.GalleryReaderWriter {
 	return NewGalleryReaderWriter(t.tx)
 }
 
+func (t *transaction) GalleryChapter() models.GalleryChapterReaderWriter {
+	t.ensureTx()
+	return NewGalleryChapterReaderWriter(t.tx)
+}
+
 func (t *transaction) Image() models.ImageReaderWriter {
 	t.ensureTx()
-	return NewImageReaderWriter(t.tx)
+	return NewImageReaderWriter(t.tx, session.GetUserDataID(t.Ctx))
 }
 
 func (t *transaction) Movie() models.MovieReaderWriter {
@@ -107,7 +113,7 @@ func (t *transaction) SceneMarker() models.SceneMarkerReaderWriter {
 
 func (t *transaction) Scene() models.SceneReaderWriter {
 	t.ensureTx()
-	return NewSceneReaderWriter(t.tx)
+	return NewSceneReaderWriter(t.tx, session.GetUserDataID(t.Ctx))
 }
 
 func (t *transaction) ScrapedItem() models.ScrapedItemReaderWriter {
@@ -130,7 +136,29 @@ func (t *transaction) SavedFilter() models.SavedFilterReaderWriter {
 	return NewSavedFilterReaderWriter(t.tx)
 }
 
-type ReadTransaction struct{}
+func (t *transaction) JobHistory() models.JobHistoryReaderWriter {
+	t.ensureTx()
+	return NewJobHistoryReaderWriter(t.tx)
+}
+
+func (t *transaction) JobCheckpoint() models.JobCheckpointReaderWriter {
+	t.ensureTx()
+	return NewJobCheckpointReaderWriter(t.tx)
+}
+
+func (t *transaction) User() models.UserReaderWriter {
+	t.ensureTx()
+	return NewUserReaderWriter(t.tx)
+}
+
+func (t *transaction) Search() models.SearchReader {
+	t.ensureTx()
+	return NewSearchReader(t.tx)
+}
+
+type ReadTransaction struct {
+	Ctx context.Context
+}
 
 func (t *ReadTransaction) Begin() error {
 	if err := database.Ready(); err != nil {
@@ -156,8 +184,12 @@ func (t *ReadTransaction) Gallery() models.GalleryReader {
 	return NewGalleryReaderWriter(database.DB)
 }
 
+func (t *ReadTransaction) GalleryChapter() models.GalleryChapterReader {
+	return NewGalleryChapterReaderWriter(database.DB)
+}
+
 func (t *ReadTransaction) Image() models.ImageReader {
-	return NewImageReaderWriter(database.DB)
+	return NewImageReaderWriter(database.DB, session.GetUserDataID(t.Ctx))
 }
 
 func (t *ReadTransaction) Movie() models.MovieReader {
@@ -173,7 +205,7 @@ func (t *ReadTransaction) SceneMarker() models.SceneMarkerReader {
 }
 
 func (t *ReadTransaction) Scene() models.SceneReader {
-	return NewSceneReaderWriter(database.DB)
+	return NewSceneReaderWriter(database.DB, session.GetUserDataID(t.Ctx))
 }
 
 func (t *ReadTransaction) ScrapedItem() models.ScrapedItemReader {
@@ -192,6 +224,22 @@ func (t *ReadTransaction) SavedFilter() models.SavedFilterReader {
 	return NewSavedFilterReaderWriter(database.DB)
 }
 
+func (t *ReadTransaction) JobHistory() models.JobHistoryReader {
+	return NewJobHistoryReaderWriter(database.DB)
+}
+
+func (t *ReadTransaction) JobCheckpoint() models.JobCheckpointReader {
+	return NewJobCheckpointReaderWriter(database.DB)
+}
+
+func (t *ReadTransaction) User() models.UserReader {
+	return NewUserReaderWriter(database.DB)
+}
+
+func (t *ReadTransaction) Search() models.SearchReader {
+	return NewSearchReader(database.DB)
+}
+
 type TransactionManager struct {
 }
 
@@ -206,5 +254,5 @@ func (t *TransactionManager) WithTxn(ctx context.Context, fn func(r models.Repos
 }
 
 func (t *TransactionManager) WithReadTxn(ctx context.Context, fn func(r models.ReaderRepository) error) error {
-	return models.WithROTxn(&ReadTransaction{}, fn)
+	return models.WithROTxn(&ReadTransaction{Ctx: ctx}, fn)
 }
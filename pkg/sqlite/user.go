@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const userTable = "users"
+
+type userQueryBuilder struct {
+	repository
+}
+
+func NewUserReaderWriter(tx dbi) *userQueryBuilder {
+	return &userQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: userTable,
+			idColumn:  idColumn,
+		},
+	}
+}
+
+func (qb *userQueryBuilder) Create(newObject models.User) (*models.User, error) {
+	var ret models.User
+	if err := qb.insertObject(newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *userQueryBuilder) Update(updatedObject models.User) (*models.User, error) {
+	const partial = false
+	if err := qb.update(updatedObject.ID, updatedObject, partial); err != nil {
+		return nil, err
+	}
+
+	return qb.Find(updatedObject.ID)
+}
+
+// userDataTables are the tables holding values of objects per user.
+var userDataTables = []string{scenesUsersTable, imagesUsersTable}
+
+func (qb *userQueryBuilder) Destroy(id int) error {
+	for _, table := range userDataTables {
+		if _, err := qb.tx.Exec("DELETE FROM "+table+" WHERE user_id = ?", id); err != nil {
+			return err
+		}
+	}
+
+	return qb.destroyExisting([]int{id})
+}
+
+func (qb *userQueryBuilder) TransferUserData(fromUserID int, toUserID int) error {
+	for _, table := range userDataTables {
+		if _, err := qb.tx.Exec("DELETE FROM "+table+" WHERE user_id = ?", toUserID); err != nil {
+			return err
+		}
+
+		if _, err := qb.tx.Exec("UPDATE "+table+" SET user_id = ? WHERE user_id = ?", toUserID, fromUserID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *userQueryBuilder) Find(id int) (*models.User, error) {
+	var ret models.User
+	if err := qb.get(id, &ret); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *userQueryBuilder) queryUser(query string, args []interface{}) (*models.User, error) {
+	var ret models.Users
+	if err := qb.query(query, args, &ret); err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *userQueryBuilder) FindByUsername(username string) (*models.User, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE username = ? LIMIT 1", userTable)
+	return qb.queryUser(query, []interface{}{username})
+}
+
+func (qb *userQueryBuilder) FindByAPIKey(apiKey string) (*models.User, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE api_key = ? LIMIT 1", userTable)
+	return qb.queryUser(query, []interface{}{apiKey})
+}
+
+func (qb *userQueryBuilder) All() ([]*models.User, error) {
+	var ret models.Users
+	if err := qb.query(selectAll(userTable)+qb.getUserSort(), nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.User(ret), nil
+}
+
+func (qb *userQueryBuilder) Count() (int, error) {
+	return qb.runCountQuery(qb.buildCountQuery("SELECT users.id FROM users"), nil)
+}
+
+func (qb *userQueryBuilder) CountByRole(role models.UserRole) (int, error) {
+	return qb.runCountQuery(qb.buildCountQuery("SELECT users.id FROM users WHERE role = ?"), []interface{}{role.String()})
+}
+
+func (qb *userQueryBuilder) getUserSort() string {
+	return getSort("username", "ASC", userTable)
+}
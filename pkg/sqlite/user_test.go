@@ -0,0 +1,188 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func createUser(qb models.UserReaderWriter, username string, role models.UserRole, apiKey string) (*models.User, error) {
+	currentTime := time.Now()
+	return qb.Create(models.User{
+		Username:  username,
+		Password:  "hash",
+		Role:      role,
+		APIKey:    sql.NullString{String: apiKey, Valid: apiKey != ""},
+		CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+	})
+}
+
+func TestUserFind(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.User()
+
+		created, err := createUser(qb, "admin", models.UserRoleAdmin, "key")
+		if err != nil {
+			t.Errorf("Error creating user: %s", err.Error())
+			return nil
+		}
+
+		found, err := qb.Find(created.ID)
+		if err != nil {
+			t.Errorf("Error finding user: %s", err.Error())
+			return nil
+		}
+
+		if assert.NotNil(t, found) {
+			assert.Equal(t, "admin", found.Username)
+			assert.Equal(t, models.UserRoleAdmin, found.Role)
+		}
+
+		found, err = qb.FindByUsername("admin")
+		if err != nil {
+			t.Errorf("Error finding user by username: %s", err.Error())
+			return nil
+		}
+
+		if assert.NotNil(t, found) {
+			assert.Equal(t, created.ID, found.ID)
+		}
+
+		found, err = qb.FindByAPIKey("key")
+		if err != nil {
+			t.Errorf("Error finding user by api key: %s", err.Error())
+			return nil
+		}
+
+		if assert.NotNil(t, found) {
+			assert.Equal(t, created.ID, found.ID)
+		}
+
+		found, err = qb.FindByAPIKey("invalid")
+		if err != nil {
+			t.Errorf("Error finding user by api key: %s", err.Error())
+			return nil
+		}
+
+		assert.Nil(t, found)
+
+		return nil
+	})
+}
+
+func TestUserUniqueUsername(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.User()
+
+		if _, err := createUser(qb, "viewer", models.UserRoleViewer, ""); err != nil {
+			t.Errorf("Error creating user: %s", err.Error())
+			return nil
+		}
+
+		_, err := createUser(qb, "viewer", models.UserRoleEditor, "")
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
+func TestUserCountByRole(t *testing.T) {
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.User()
+
+		for _, u := range []struct {
+			username string
+			role     models.UserRole
+		}{
+			{"admin1", models.UserRoleAdmin},
+			{"admin2", models.UserRoleAdmin},
+			{"editor", models.UserRoleEditor},
+		} {
+			if _, err := createUser(qb, u.username, u.role, ""); err != nil {
+				t.Errorf("Error creating user: %s", err.Error())
+				return nil
+			}
+		}
+
+		count, err := qb.CountByRole(models.UserRoleAdmin)
+		if err != nil {
+			t.Errorf("Error counting users: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, 2, count)
+
+		count, err = qb.Count()
+		if err != nil {
+			t.Errorf("Error counting users: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, 3, count)
+
+		all, err := qb.All()
+		if err != nil {
+			t.Errorf("Error getting users: %s", err.Error())
+			return nil
+		}
+
+		if assert.Len(t, all, 3) {
+			assert.Equal(t, "admin1", all[0].Username)
+		}
+
+		return nil
+	})
+}
+
+func TestUserTransferUserData(t *testing.T) {
+	const sceneIdx = 1
+
+	withRollbackTxn(func(r models.Repository) error {
+		qb := r.User()
+		sqb := r.Scene()
+		sceneID := sceneIDs[sceneIdx]
+
+		created, err := createUser(qb, "admin", models.UserRoleAdmin, "")
+		if err != nil {
+			t.Errorf("Error creating user: %s", err.Error())
+			return nil
+		}
+
+		if err := qb.TransferUserData(0, created.ID); err != nil {
+			t.Errorf("Error transferring user data: %s", err.Error())
+			return nil
+		}
+
+		userData, err := sqb.GetUserData(sceneID, created.ID)
+		if err != nil {
+			t.Errorf("Error getting scene user data: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, getRating(sceneIdx), userData.Rating)
+		assert.Equal(t, getOCounter(sceneIdx), userData.OCounter)
+		assert.False(t, getSceneUserData(t, sqb, sceneID).Rating.Valid)
+
+		if err := qb.Destroy(created.ID); err != nil {
+			t.Errorf("Error destroying user: %s", err.Error())
+			return nil
+		}
+
+		all, err := sqb.GetAllUserData(sceneID)
+		if err != nil {
+			t.Errorf("Error getting scene user data: %s", err.Error())
+			return nil
+		}
+
+		assert.Len(t, all, 0)
+
+		return nil
+	})
+}
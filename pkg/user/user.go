@@ -0,0 +1,64 @@
+// Package user provides the password and role handling for user accounts.
+package user
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrEmptyUsername = errors.New("username must not be empty")
+	ErrEmptyPassword = errors.New("password must not be empty")
+)
+
+// roleLevels orders the roles so that each role includes the permissions of
+// the roles below it.
+var roleLevels = map[models.UserRole]int{
+	models.UserRoleViewer: 1,
+	models.UserRoleEditor: 2,
+	models.UserRoleAdmin:  3,
+}
+
+// HasRole returns true if the user has the provided role, or a role that
+// includes it. Returns false if u is nil.
+func HasRole(u *models.User, role models.UserRole) bool {
+	if u == nil {
+		return false
+	}
+
+	return roleLevels[u.Role] >= roleLevels[role]
+}
+
+// HashPassword returns the bcrypt hash of the password.
+func HashPassword(password string) (string, error) {
+	if password == "" {
+		return "", ErrEmptyPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// CheckPassword returns true if password matches the password hash of the
+// user.
+func CheckPassword(u *models.User, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)) == nil
+}
+
+// ValidateUsername returns the trimmed username, or an error if the username
+// is empty.
+func ValidateUsername(username string) (string, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return "", ErrEmptyUsername
+	}
+
+	return username, nil
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// GetUsernames returns the usernames of all users, keyed by user id.
+func GetUsernames(reader models.UserReader) (map[int]string, error) {
+	users, err := reader.All()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[int]string)
+	for _, u := range users {
+		ret[u.ID] = u.Username
+	}
+
+	return ret, nil
+}
+
+// ToUserDataJSON converts the values of an object into their JSON
+// equivalent, using usernames to refer to the users. Values that are not set
+// are omitted.
+func ToUserDataJSON(data []*models.UserData, usernames map[int]string) []jsonschema.UserData {
+	var ret []jsonschema.UserData
+	for _, d := range data {
+		if !d.Rating.Valid && d.OCounter == 0 && !d.Watched {
+			continue
+		}
+
+		ret = append(ret, jsonschema.UserData{
+			User:     usernames[d.UserID],
+			Rating:   int(d.Rating.Int64),
+			OCounter: d.OCounter,
+			Watched:  d.Watched,
+		})
+	}
+
+	return ret
+}
+
+// UserDataFromJSON converts the JSON values of an object into user data,
+// resolving the usernames to user ids. Values without a username are
+// assigned to the first admin, or to no user if there are no users. Values
+// of users that do not exist cause an error if missingRefBehaviour is
+// ImportMissingRefEnumFail, and are skipped otherwise.
+func UserDataFromJSON(reader models.UserReader, input []jsonschema.UserData, missingRefBehaviour models.ImportMissingRefEnum) ([]*models.UserData, error) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+
+	users, err := reader.All()
+	if err != nil {
+		return nil, fmt.Errorf("error finding users: %s", err.Error())
+	}
+
+	var ret []*models.UserData
+	found := make(map[int]bool)
+	for _, d := range input {
+		userID, ok := findUserDataID(users, d.User)
+		if !ok {
+			if missingRefBehaviour == models.ImportMissingRefEnumFail {
+				return nil, fmt.Errorf("user %s not found", d.User)
+			}
+			continue
+		}
+
+		// only use the first values of each user
+		if found[userID] {
+			continue
+		}
+		found[userID] = true
+
+		ret = append(ret, &models.UserData{
+			UserID:   userID,
+			Rating:   sql.NullInt64{Int64: int64(d.Rating), Valid: d.Rating != 0},
+			OCounter: d.OCounter,
+			Watched:  d.Watched,
+		})
+	}
+
+	return ret, nil
+}
+
+func findUserDataID(users []*models.User, username string) (int, bool) {
+	if username == "" {
+		ret := 0
+		for _, u := range users {
+			if u.Role == models.UserRoleAdmin && (ret == 0 || u.ID < ret) {
+				ret = u.ID
+			}
+		}
+
+		return ret, true
+	}
+
+	for _, u := range users {
+		if u.Username == username {
+			return u.ID, true
+		}
+	}
+
+	return 0, false
+}
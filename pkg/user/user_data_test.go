@@ -0,0 +1,120 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	adminID       = 1
+	adminUsername = "admin"
+	viewerID      = 2
+	viewerName    = "viewer"
+)
+
+var testUsers = []*models.User{
+	{
+		ID:       viewerID,
+		Username: viewerName,
+		Role:     models.UserRoleViewer,
+	},
+	{
+		ID:       adminID,
+		Username: adminUsername,
+		Role:     models.UserRoleAdmin,
+	},
+}
+
+func TestToUserDataJSON(t *testing.T) {
+	usernames := map[int]string{
+		adminID:  adminUsername,
+		viewerID: viewerName,
+	}
+
+	data := []*models.UserData{
+		{
+			UserID:   0,
+			Rating:   models.NullInt64(3),
+			OCounter: 1,
+		},
+		{
+			UserID:  adminID,
+			Watched: true,
+		},
+		{
+			UserID: viewerID,
+		},
+	}
+
+	assert.Equal(t, []jsonschema.UserData{
+		{
+			Rating:   3,
+			OCounter: 1,
+		},
+		{
+			User:    adminUsername,
+			Watched: true,
+		},
+	}, ToUserDataJSON(data, usernames))
+}
+
+func TestUserDataFromJSON(t *testing.T) {
+	reader := &mocks.UserReaderWriter{}
+	reader.On("All").Return(testUsers, nil)
+
+	input := []jsonschema.UserData{
+		{
+			Rating: 3,
+		},
+		{
+			User:     viewerName,
+			OCounter: 2,
+			Watched:  true,
+		},
+		{
+			User:   adminUsername,
+			Rating: 5,
+		},
+		{
+			User: "missing",
+		},
+	}
+
+	ret, err := UserDataFromJSON(reader, input, models.ImportMissingRefEnumIgnore)
+	assert.Nil(t, err)
+
+	// values without a user belong to the admin, and the later values of
+	// the admin are ignored
+	assert.Equal(t, []*models.UserData{
+		{
+			UserID: adminID,
+			Rating: models.NullInt64(3),
+		},
+		{
+			UserID:   viewerID,
+			OCounter: 2,
+			Watched:  true,
+		},
+	}, ret)
+
+	_, err = UserDataFromJSON(reader, input, models.ImportMissingRefEnumFail)
+	assert.NotNil(t, err)
+}
+
+func TestUserDataFromJSONNoUsers(t *testing.T) {
+	reader := &mocks.UserReaderWriter{}
+	reader.On("All").Return(nil, nil)
+
+	ret, err := UserDataFromJSON(reader, []jsonschema.UserData{{Rating: 3}}, models.ImportMissingRefEnumFail)
+	assert.Nil(t, err)
+	assert.Equal(t, []*models.UserData{
+		{
+			UserID: 0,
+			Rating: models.NullInt64(3),
+		},
+	}, ret)
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasRole(t *testing.T) {
+	tests := []struct {
+		role     models.UserRole
+		required models.UserRole
+		want     bool
+	}{
+		{models.UserRoleAdmin, models.UserRoleAdmin, true},
+		{models.UserRoleAdmin, models.UserRoleEditor, true},
+		{models.UserRoleAdmin, models.UserRoleViewer, true},
+		{models.UserRoleEditor, models.UserRoleAdmin, false},
+		{models.UserRoleEditor, models.UserRoleEditor, true},
+		{models.UserRoleEditor, models.UserRoleViewer, true},
+		{models.UserRoleViewer, models.UserRoleAdmin, false},
+		{models.UserRoleViewer, models.UserRoleEditor, false},
+		{models.UserRoleViewer, models.UserRoleViewer, true},
+		{"", models.UserRoleViewer, false},
+	}
+
+	for _, tt := range tests {
+		u := &models.User{Role: tt.role}
+		assert.Equal(t, tt.want, HasRole(u, tt.required), "%s has role %s", tt.role, tt.required)
+	}
+
+	assert.False(t, HasRole(nil, models.UserRoleViewer))
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u := &models.User{Password: hash}
+	assert.True(t, CheckPassword(u, "secret"))
+	assert.False(t, CheckPassword(u, "wrong"))
+
+	_, err = HashPassword("")
+	assert.Equal(t, ErrEmptyPassword, err)
+}
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const vttHeader = "WEBVTT\n\n"
+
+// normaliseCaption removes the byte order mark and Windows line endings of
+// a caption file.
+func normaliseCaption(s string) string {
+	s = strings.TrimPrefix(s, "\ufeff")
+	return strings.ReplaceAll(s, "\r\n", "\n")
+}
+
+// ConvertVTT returns the WebVTT caption with the header added if it is
+// missing.
+func ConvertVTT(vtt string) string {
+	vtt = normaliseCaption(vtt)
+	if !strings.HasPrefix(vtt, "WEBVTT") {
+		vtt = vttHeader + vtt
+	}
+
+	return vtt
+}
+
+// ConvertSRTToVTT converts a SubRip caption to WebVTT. SubRip cues are valid
+// WebVTT cues, except for the decimal separator of the cue timings.
+func ConvertSRTToVTT(srt string) string {
+	lines := strings.Split(normaliseCaption(srt), "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "-->") {
+			lines[i] = strings.ReplaceAll(line, ",", ".")
+		}
+	}
+
+	return vttHeader + strings.Join(lines, "\n")
+}
+
+var (
+	assOverrideRE = regexp.MustCompile(`\{[^}]*\}`)
+	assTimeRE     = regexp.MustCompile(`^(\d+):(\d{2}):(\d{2})\.(\d{2})$`)
+)
+
+type vttCue struct {
+	start int
+	end   int
+	text  string
+}
+
+// parseASSTime returns the milliseconds of an ASS timestamp (h:mm:ss.cc).
+func parseASSTime(s string) (int, bool) {
+	match := assTimeRE.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return 0, false
+	}
+
+	var parts [4]int
+	for i := range parts {
+		parts[i], _ = strconv.Atoi(match[i+1])
+	}
+
+	return ((parts[0]*60+parts[1])*60+parts[2])*1000 + parts[3]*10, true
+}
+
+// formatVTTTime returns the WebVTT timestamp (hh:mm:ss.mmm) of milliseconds.
+func formatVTTTime(ms int) string {
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// convertASSText removes the override tags of ASS dialogue text and escapes
+// it for WebVTT.
+func convertASSText(text string) string {
+	text = assOverrideRE.ReplaceAllString(text, "")
+	text = strings.NewReplacer(
+		`\N`, "\n",
+		`\n`, "\n",
+		`\h`, " ",
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	).Replace(text)
+
+	return strings.TrimSpace(text)
+}
+
+// ConvertASSToVTT converts the dialogue of an ASS or SSA caption to WebVTT.
+// Styles and positioning are not converted.
+func ConvertASSToVTT(ass string) string {
+	var cues []vttCue
+	var format []string
+	inEvents := false
+
+	for _, line := range strings.Split(normaliseCaption(ass), "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "[") {
+			inEvents = strings.EqualFold(line, "[Events]")
+			continue
+		}
+
+		if !inEvents {
+			continue
+		}
+
+		key, value := line, ""
+		if i := strings.Index(line, ":"); i != -1 {
+			key, value = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		switch key {
+		case "Format":
+			format = strings.Split(value, ",")
+			for i := range format {
+				format[i] = strings.TrimSpace(format[i])
+			}
+		case "Dialogue":
+			// the text is the last field, and may contain commas
+			fields := strings.SplitN(value, ",", len(format))
+			if len(fields) != len(format) {
+				continue
+			}
+
+			var cue vttCue
+			valid := 0
+			for i, name := range format {
+				var ok bool
+				switch name {
+				case "Start":
+					cue.start, ok = parseASSTime(fields[i])
+				case "End":
+					cue.end, ok = parseASSTime(fields[i])
+				case "Text":
+					cue.text = convertASSText(fields[i])
+					ok = cue.text != ""
+				}
+
+				if ok {
+					valid++
+				}
+			}
+
+			if valid == 3 {
+				cues = append(cues, cue)
+			}
+		}
+	}
+
+	// WebVTT cues are ordered by their start time
+	sort.SliceStable(cues, func(i, j int) bool {
+		return cues[i].start < cues[j].start
+	})
+
+	var b strings.Builder
+	b.WriteString(vttHeader)
+	for _, cue := range cues {
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", formatVTTTime(cue.start), formatVTTTime(cue.end), cue.text)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertSRTToVTT(t *testing.T) {
+	srt := "\ufeff1\r\n00:00:01,500 --> 00:00:03,000\r\n<i>Hello, world</i>\r\n\r\n2\r\n00:01:00,000 --> 00:01:02,250\r\nSecond line\r\n"
+	want := "WEBVTT\n\n1\n00:00:01.500 --> 00:00:03.000\n<i>Hello, world</i>\n\n2\n00:01:00.000 --> 00:01:02.250\nSecond line\n"
+
+	assert.Equal(t, want, ConvertSRTToVTT(srt))
+}
+
+func TestConvertVTT(t *testing.T) {
+	vtt := "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n"
+	assert.Equal(t, vtt, ConvertVTT(vtt))
+	assert.Equal(t, vtt, ConvertVTT("00:00:01.000 --> 00:00:02.000\nHello\n"))
+}
+
+func TestConvertASSToVTT(t *testing.T) {
+	ass := `[Script Info]
+Title: Test
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize
+Style: Default,Arial,20
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:05.00,0:00:07.50,Default,,0,0,0,,Second, with a comma
+Dialogue: 0,0:00:01.29,0:00:03.00,Default,,0,0,0,,{\i1}First{\i0}\Nline & <more>
+Comment: 0,0:00:02.00,0:00:03.00,Default,,0,0,0,,Not shown
+Dialogue: 0,0:00:08.00,0:00:09.00,Default,,0,0,0,,{\p1}
+Dialogue: 0,invalid,0:00:09.00,Default,,0,0,0,,Invalid
+`
+
+	want := `WEBVTT
+
+00:00:01.290 --> 00:00:03.000
+First
+line &amp; &lt;more&gt;
+
+00:00:05.000 --> 00:00:07.500
+Second, with a comma
+
+`
+
+	assert.Equal(t, want, ConvertASSToVTT(ass))
+}
@@ -175,7 +175,6 @@ func SafeMove(src, dst string) error {
 func IsZipFileUncompressed(path string) (bool, error) {
 	r, err := zip.OpenReader(path)
 	if err != nil {
-		fmt.Printf("Error reading zip file %s: %s\n", path, err)
 		return false, err
 	} else {
 		defer r.Close()
@@ -0,0 +1,27 @@
+package watcher
+
+import (
+	"syscall"
+)
+
+// filesystem magic numbers of network filesystems, which do not deliver
+// inotify events for changes made by other hosts
+var networkFilesystems = map[uint32]bool{
+	0x6969:     true, // NFS
+	0x517b:     true, // SMB
+	0xff534d42: true, // CIFS
+	0xfe534d42: true, // SMB2
+	0x01021997: true, // 9P
+	0x5346414f: true, // AFS
+	0x00c36400: true, // Ceph
+}
+
+// isNetworkPath returns true if path is on a network filesystem.
+func isNetworkPath(path string) bool {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return false
+	}
+
+	return networkFilesystems[uint32(stat.Type)]
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package watcher
+
+// isNetworkPath returns false, since network filesystems cannot be detected
+// on this platform.
+func isNetworkPath(path string) bool {
+	return false
+}
@@ -0,0 +1,11 @@
+package watcher
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// isNetworkPath returns true if path is a UNC path to a network share.
+func isNetworkPath(path string) bool {
+	return strings.HasPrefix(filepath.Clean(path), `\\`)
+}
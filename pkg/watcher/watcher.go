@@ -0,0 +1,358 @@
+// Package watcher watches directory trees for new and modified files.
+//
+// Directories are watched using filesystem notifications where they are
+// supported. Directories on network filesystems, which do not reliably
+// deliver notifications, are polled instead. Changed files are reported once
+// their size and modification time have stopped changing.
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const (
+	defaultStableDelay  = 10 * time.Second
+	defaultPollInterval = 5 * time.Minute
+)
+
+// Options configures the behaviour of a Watcher.
+type Options struct {
+	// StableDelay is the duration for which the size and modification time of
+	// a changed file must remain unchanged before the file is reported.
+	StableDelay time.Duration
+
+	// PollInterval is the interval between walks of polled directories.
+	PollInterval time.Duration
+
+	// ForcePoll polls all directories instead of using filesystem
+	// notifications.
+	ForcePoll bool
+
+	// Filter returns false if the path should be ignored. Directories that
+	// are ignored are not watched. If nil, all paths are included.
+	Filter func(path string, isDir bool) bool
+}
+
+// Handler is called with the paths of changed files once they are stable.
+type Handler func(paths []string)
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+func newFileState(info os.FileInfo) fileState {
+	return fileState{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+	}
+}
+
+type pendingFile struct {
+	state   fileState
+	changed time.Time
+}
+
+type polledRoot struct {
+	path     string
+	snapshot map[string]fileState
+}
+
+// Watcher watches directory trees and reports new and modified files.
+type Watcher struct {
+	roots   []string
+	options Options
+	handler Handler
+
+	fsWatcher *fsnotify.Watcher
+	polled    []*polledRoot
+
+	pending map[string]*pendingFile
+	mutex   sync.Mutex
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New returns a Watcher for the provided root directories. The handler is
+// called from a background goroutine. The Watcher does not watch until Start
+// is called.
+func New(roots []string, options Options, handler Handler) *Watcher {
+	if options.StableDelay <= 0 {
+		options.StableDelay = defaultStableDelay
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultPollInterval
+	}
+
+	return &Watcher{
+		roots:   roots,
+		options: options,
+		handler: handler,
+		pending: make(map[string]*pendingFile),
+		stop:    make(chan struct{}),
+	}
+}
+
+// Start starts watching the root directories. Roots that cannot be watched
+// using filesystem notifications are polled.
+func (w *Watcher) Start() error {
+	if !w.options.ForcePoll {
+		fsWatcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			logger.Warnf("Filesystem notifications not available, polling instead: %s", err.Error())
+		} else {
+			w.fsWatcher = fsWatcher
+		}
+	}
+
+	for _, root := range w.roots {
+		if w.fsWatcher == nil || isNetworkPath(root) {
+			w.addPolledRoot(root)
+			continue
+		}
+
+		if err := w.watchDir(root); err != nil {
+			logger.Warnf("Could not watch %s, polling instead: %s", root, err.Error())
+			w.addPolledRoot(root)
+		}
+	}
+
+	if w.fsWatcher != nil {
+		w.wg.Add(1)
+		go w.eventLoop()
+	}
+
+	if len(w.polled) > 0 {
+		w.wg.Add(1)
+		go w.pollLoop()
+	}
+
+	w.wg.Add(1)
+	go w.stableLoop()
+
+	return nil
+}
+
+// Stop stops watching and waits for the background goroutines to exit.
+// Pending files that have not yet been reported are discarded.
+func (w *Watcher) Stop() {
+	close(w.stop)
+
+	if w.fsWatcher != nil {
+		w.fsWatcher.Close()
+	}
+
+	w.wg.Wait()
+}
+
+func (w *Watcher) include(path string, isDir bool) bool {
+	return w.options.Filter == nil || w.options.Filter(path, isDir)
+}
+
+// walk calls fn for each included file in the directory tree, skipping
+// excluded directories. dirFn is called for each included directory.
+func (w *Watcher) walk(root string, dirFn func(path string) error, fileFn func(path string, info os.FileInfo)) error {
+	return utils.SymWalk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			logger.Debugf("error walking %s: %s", path, err.Error())
+			return nil
+		}
+
+		if info.IsDir() {
+			if path != root && !w.include(path, true) {
+				return filepath.SkipDir
+			}
+
+			if dirFn != nil {
+				return dirFn(path)
+			}
+			return nil
+		}
+
+		if fileFn != nil && w.include(path, false) {
+			fileFn(path, info)
+		}
+
+		return nil
+	})
+}
+
+func (w *Watcher) watchDir(root string) error {
+	return w.walk(root, w.fsWatcher.Add, nil)
+}
+
+func (w *Watcher) addPolledRoot(root string) {
+	r := &polledRoot{
+		path:     root,
+		snapshot: w.snapshot(root),
+	}
+	w.polled = append(w.polled, r)
+}
+
+func (w *Watcher) snapshot(root string) map[string]fileState {
+	ret := make(map[string]fileState)
+	if err := w.walk(root, nil, func(path string, info os.FileInfo) {
+		ret[path] = newFileState(info)
+	}); err != nil {
+		logger.Warnf("error walking %s: %s", root, err.Error())
+	}
+	return ret
+}
+
+func (w *Watcher) eventLoop() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event)
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Warnf("Filesystem watcher error: %s", err.Error())
+		}
+	}
+}
+
+func (w *Watcher) handleEvent(event fsnotify.Event) {
+	if event.Op&(fsnotify.Create|fsnotify.Write) == 0 {
+		return
+	}
+
+	info, err := os.Stat(event.Name)
+	if err != nil {
+		// removed before we could process it
+		return
+	}
+
+	if !info.IsDir() {
+		if w.include(event.Name, false) {
+			w.touch(event.Name, info)
+		}
+		return
+	}
+
+	if event.Op&fsnotify.Create == 0 || !w.include(event.Name, true) {
+		return
+	}
+
+	// watch the new directory and add any files created before the watch
+	// was added
+	if err := w.walk(event.Name, w.fsWatcher.Add, w.touch); err != nil {
+		logger.Warnf("Could not watch %s: %s", event.Name, err.Error())
+	}
+}
+
+func (w *Watcher) pollLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			for _, r := range w.polled {
+				w.poll(r)
+			}
+		}
+	}
+}
+
+func (w *Watcher) poll(r *polledRoot) {
+	current := w.snapshot(r.path)
+	for path, state := range current {
+		if old, found := r.snapshot[path]; !found || old != state {
+			w.mutex.Lock()
+			w.setPending(path, state)
+			w.mutex.Unlock()
+		}
+	}
+
+	r.snapshot = current
+}
+
+// touch marks the file as changed.
+func (w *Watcher) touch(path string, info os.FileInfo) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.setPending(path, newFileState(info))
+}
+
+func (w *Watcher) setPending(path string, state fileState) {
+	w.pending[path] = &pendingFile{
+		state:   state,
+		changed: time.Now(),
+	}
+}
+
+func (w *Watcher) stableLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.options.StableDelay / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if stable := w.popStable(); len(stable) > 0 {
+				w.handler(stable)
+			}
+		}
+	}
+}
+
+// popStable removes and returns the pending files that have not changed for
+// at least the stable delay.
+func (w *Watcher) popStable() []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var ret []string
+	now := time.Now()
+	for path, p := range w.pending {
+		info, err := os.Stat(path)
+		if err != nil {
+			// file was removed
+			delete(w.pending, path)
+			continue
+		}
+
+		state := newFileState(info)
+		if state != p.state {
+			// still being written
+			p.state = state
+			p.changed = now
+			continue
+		}
+
+		if now.Sub(p.changed) >= w.options.StableDelay {
+			ret = append(ret, path)
+			delete(w.pending, path)
+		}
+	}
+
+	sort.Strings(ret)
+	return ret
+}
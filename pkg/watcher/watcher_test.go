@@ -0,0 +1,206 @@
+package watcher
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testStableDelay  = 200 * time.Millisecond
+	testPollInterval = 50 * time.Millisecond
+	testTimeout      = 5 * time.Second
+)
+
+type recordingHandler struct {
+	mutex sync.Mutex
+	calls [][]string
+	ch    chan struct{}
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{
+		ch: make(chan struct{}, 100),
+	}
+}
+
+func (h *recordingHandler) handle(paths []string) {
+	h.mutex.Lock()
+	h.calls = append(h.calls, paths)
+	h.mutex.Unlock()
+
+	h.ch <- struct{}{}
+}
+
+func (h *recordingHandler) paths() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var ret []string
+	for _, c := range h.calls {
+		ret = append(ret, c...)
+	}
+	return ret
+}
+
+// waitFor waits until the handler has reported n paths.
+func (h *recordingHandler) waitFor(t *testing.T, n int) {
+	t.Helper()
+
+	timeout := time.After(testTimeout)
+	for len(h.paths()) < n {
+		select {
+		case <-h.ch:
+		case <-timeout:
+			t.Fatalf("timed out waiting for %d paths, got %v", n, h.paths())
+		}
+	}
+}
+
+func writeFile(t *testing.T, path string, data string) {
+	t.Helper()
+
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("error writing %s: %s", path, err.Error())
+	}
+}
+
+func startWatcher(t *testing.T, root string, options Options, h *recordingHandler) *Watcher {
+	t.Helper()
+
+	options.StableDelay = testStableDelay
+	options.PollInterval = testPollInterval
+
+	w := New([]string{root}, options, h.handle)
+	if err := w.Start(); err != nil {
+		t.Fatalf("error starting watcher: %s", err.Error())
+	}
+
+	return w
+}
+
+func testNewFiles(t *testing.T, forcePoll bool) {
+	root := t.TempDir()
+
+	existing := filepath.Join(root, "existing.mp4")
+	writeFile(t, existing, "existing")
+
+	h := newRecordingHandler()
+	w := startWatcher(t, root, Options{ForcePoll: forcePoll}, h)
+	defer w.Stop()
+
+	newFile := filepath.Join(root, "new.mp4")
+	writeFile(t, newFile, "new")
+
+	subDir := filepath.Join(root, "sub")
+	if err := os.Mkdir(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	subFile := filepath.Join(subDir, "sub.mp4")
+	writeFile(t, subFile, "sub")
+
+	h.waitFor(t, 2)
+
+	// wait to ensure nothing else is reported
+	time.Sleep(testStableDelay * 2)
+
+	assert.ElementsMatch(t, []string{newFile, subFile}, h.paths())
+}
+
+func TestWatcherNotify(t *testing.T) {
+	testNewFiles(t, false)
+}
+
+func TestWatcherPoll(t *testing.T) {
+	testNewFiles(t, true)
+}
+
+func TestWatcherModifiedFile(t *testing.T) {
+	root := t.TempDir()
+
+	existing := filepath.Join(root, "existing.mp4")
+	writeFile(t, existing, "existing")
+
+	h := newRecordingHandler()
+	w := startWatcher(t, root, Options{ForcePoll: true}, h)
+	defer w.Stop()
+
+	writeFile(t, existing, "modified content")
+
+	h.waitFor(t, 1)
+	assert.Equal(t, []string{existing}, h.paths())
+}
+
+func TestWatcherDebounce(t *testing.T) {
+	root := t.TempDir()
+
+	h := newRecordingHandler()
+	w := startWatcher(t, root, Options{}, h)
+	defer w.Stop()
+
+	path := filepath.Join(root, "growing.mp4")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// keep writing for longer than the stable delay
+	start := time.Now()
+	for time.Since(start) < testStableDelay*3 {
+		if _, err := f.WriteString("data"); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(testStableDelay / 4)
+	}
+
+	assert.Empty(t, h.paths(), "file reported while being written")
+
+	f.Close()
+
+	h.waitFor(t, 1)
+	assert.Equal(t, []string{path}, h.paths())
+}
+
+func TestWatcherFilter(t *testing.T) {
+	root := t.TempDir()
+
+	excludedDir := filepath.Join(root, "excluded")
+	if err := os.Mkdir(excludedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := func(path string, isDir bool) bool {
+		if isDir {
+			return filepath.Base(path) != "excluded"
+		}
+
+		return strings.HasSuffix(path, ".mp4")
+	}
+
+	for _, forcePoll := range []bool{false, true} {
+		h := newRecordingHandler()
+		w := startWatcher(t, root, Options{
+			ForcePoll: forcePoll,
+			Filter:    filter,
+		}, h)
+
+		included := filepath.Join(root, "included.mp4")
+		writeFile(t, filepath.Join(root, "ignored.txt"), "ignored")
+		writeFile(t, filepath.Join(excludedDir, "excluded.mp4"), "excluded")
+		writeFile(t, included, "included")
+
+		h.waitFor(t, 1)
+		time.Sleep(testStableDelay * 2)
+		w.Stop()
+
+		assert.Equal(t, []string{included}, h.paths())
+
+		os.Remove(included)
+	}
+}
@@ -48,7 +48,9 @@ func main() {
 
 	initNaming(*c)
 
-	database.Initialize(c.Database)
+	if err := database.Initialize(c.Database); err != nil {
+		panic(err)
+	}
 	populateDB()
 }
 
@@ -19,6 +19,7 @@ import { GalleryImagesPanel } from "./GalleryImagesPanel";
 import { GalleryAddPanel } from "./GalleryAddPanel";
 import { GalleryFileInfoPanel } from "./GalleryFileInfoPanel";
 import { GalleryScenesPanel } from "./GalleryScenesPanel";
+import { GalleryChaptersPanel } from "./GalleryChaptersPanel";
 
 interface IGalleryParams {
   id?: string;
@@ -166,6 +167,11 @@ export const Gallery: React.FC = () => {
                 </Nav.Link>
               </Nav.Item>
             )}
+            <Nav.Item>
+              <Nav.Link eventKey="gallery-chapters-panel">
+                <FormattedMessage id="chapters" />
+              </Nav.Link>
+            </Nav.Item>
             {gallery.path ? (
               <Nav.Item>
                 <Nav.Link eventKey="gallery-file-info-panel">
@@ -193,6 +199,9 @@ export const Gallery: React.FC = () => {
           <Tab.Pane eventKey="gallery-details-panel">
             <GalleryDetailPanel gallery={gallery} />
           </Tab.Pane>
+          <Tab.Pane eventKey="gallery-chapters-panel">
+            <GalleryChaptersPanel gallery={gallery} />
+          </Tab.Pane>
           <Tab.Pane
             className="file-info-panel"
             eventKey="gallery-file-info-panel"
@@ -0,0 +1,62 @@
+import React, { useState } from "react";
+import { Button } from "react-bootstrap";
+import { FormattedMessage } from "react-intl";
+import * as GQL from "src/core/generated-graphql";
+import { GalleryChapterForm } from "./GalleryChapterForm";
+
+interface IGalleryChaptersPanelProps {
+  gallery: GQL.GalleryDataFragment;
+}
+
+export const GalleryChaptersPanel: React.FC<IGalleryChaptersPanelProps> = ({
+  gallery,
+}) => {
+  const [isEditorOpen, setIsEditorOpen] = useState<boolean>(false);
+  const [
+    editingChapter,
+    setEditingChapter,
+  ] = useState<GQL.GalleryChapterDataFragment>();
+
+  function onOpenEditor(chapter?: GQL.GalleryChapterDataFragment) {
+    setIsEditorOpen(true);
+    setEditingChapter(chapter ?? undefined);
+  }
+
+  const closeEditor = () => {
+    setEditingChapter(undefined);
+    setIsEditorOpen(false);
+  };
+
+  if (isEditorOpen)
+    return (
+      <GalleryChapterForm
+        galleryID={gallery.id}
+        editingChapter={editingChapter}
+        onClose={closeEditor}
+      />
+    );
+
+  return (
+    <div className="gallery-chapters-panel">
+      <Button onClick={() => onOpenEditor()}>
+        <FormattedMessage id="actions.create_chapter" />
+      </Button>
+      <div className="mt-3">
+        {gallery.chapters.map((chapter) => (
+          <div key={chapter.id} className="d-flex align-items-center mb-2">
+            <Button
+              variant="link"
+              className="p-0 mr-2"
+              onClick={() => onOpenEditor(chapter)}
+            >
+              <FormattedMessage id="actions.edit" />
+            </Button>
+            <span>
+              {chapter.title} - #{chapter.image_index}
+            </span>
+          </div>
+        ))}
+      </div>
+    </div>
+  );
+};
@@ -3,7 +3,12 @@ import * as GQL from "src/core/generated-graphql";
 import { GalleriesCriterion } from "src/models/list-filter/criteria/galleries";
 import { ListFilterModel } from "src/models/list-filter/filter";
 import { ImageList } from "src/components/Images/ImageList";
-import { mutateRemoveGalleryImages } from "src/core/StashService";
+import {
+  mutateInsertGalleryImages,
+  mutateRemoveGalleryImages,
+  mutateReorderGalleryImages,
+  mutateSetGalleryCover,
+} from "src/core/StashService";
 import { showWhenSelected, PersistanceLevel } from "src/hooks/ListHook";
 import { useToast } from "src/hooks";
 import { TextUtils } from "src/utils";
@@ -76,7 +81,80 @@ export const GalleryImagesPanel: React.FC<IGalleryDetailsProps> = ({
     }
   }
 
+  async function moveToStart(
+    result: GQL.FindImagesQueryResult,
+    filter: ListFilterModel,
+    selectedIds: Set<string>
+  ) {
+    try {
+      await mutateReorderGalleryImages({
+        gallery_id: gallery.id!,
+        image_ids: Array.from(selectedIds.values()),
+      });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  async function moveToEnd(
+    result: GQL.FindImagesQueryResult,
+    filter: ListFilterModel,
+    selectedIds: Set<string>
+  ) {
+    try {
+      // inserting without a position moves the images to the end
+      await mutateInsertGalleryImages({
+        gallery_id: gallery.id!,
+        image_ids: Array.from(selectedIds.values()),
+      });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  async function setCover(
+    result: GQL.FindImagesQueryResult,
+    filter: ListFilterModel,
+    selectedIds: Set<string>
+  ) {
+    try {
+      await mutateSetGalleryCover({
+        gallery_id: gallery.id!,
+        cover_image_id: Array.from(selectedIds.values())[0],
+      });
+      Toast.success({
+        content: intl.formatMessage(
+          { id: "toast.updated_entity" },
+          { entity: intl.formatMessage({ id: "gallery" }) }
+        ),
+      });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
   const otherOperations = [
+    {
+      text: intl.formatMessage({ id: "actions.set_as_cover" }),
+      onClick: setCover,
+      isDisplayed: (
+        result: GQL.FindImagesQueryResult,
+        filter: ListFilterModel,
+        selectedIds: Set<string>
+      ) => selectedIds.size === 1,
+    },
+    {
+      text: intl.formatMessage({ id: "actions.move_to_start" }),
+      onClick: moveToStart,
+      isDisplayed: showWhenSelected,
+      postRefetch: true,
+    },
+    {
+      text: intl.formatMessage({ id: "actions.move_to_end" }),
+      onClick: moveToEnd,
+      isDisplayed: showWhenSelected,
+      postRefetch: true,
+    },
     {
       text: "Remove from Gallery",
       onClick: removeImages,
@@ -93,6 +171,7 @@ export const GalleryImagesPanel: React.FC<IGalleryDetailsProps> = ({
       extraOperations={otherOperations}
       persistState={PersistanceLevel.VIEW}
       persistanceKey="galleryimages"
+      defaultSort="gallery_image_position"
     />
   );
 };
@@ -3,7 +3,7 @@ import { Button, ButtonGroup } from "react-bootstrap";
 import cx from "classnames";
 import * as GQL from "src/core/generated-graphql";
 import { Icon, TagLink, HoverPopover, SweatDrops } from "src/components/Shared";
-import { TextUtils } from "src/utils";
+import { ImageUtils, TextUtils } from "src/utils";
 import { PerformerPopoverButton } from "../Shared/PerformerPopoverButton";
 import { GridCard } from "../Shared/GridCard";
 import { RatingBanner } from "../Shared/RatingBanner";
@@ -113,7 +113,10 @@ export const ImageCard: React.FC<IImageCardProps> = (
             <img
               className="image-card-preview-image"
               alt={props.image.title ?? ""}
-              src={props.image.paths.thumbnail ?? ""}
+              src={ImageUtils.zoomThumbnailURL(
+                props.image.paths.thumbnail,
+                props.zoomIndex
+              )}
             />
           </div>
           <RatingBanner rating={props.image.rating} />
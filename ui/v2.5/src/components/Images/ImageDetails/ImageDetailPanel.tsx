@@ -1,5 +1,6 @@
 import React from "react";
 import { Link } from "react-router-dom";
+import { FormattedDate } from "react-intl";
 import * as GQL from "src/core/generated-graphql";
 import { TextUtils } from "src/utils";
 import { TagLink, TruncatedText } from "src/components/Shared";
@@ -72,6 +73,15 @@ export const ImageDetailPanel: React.FC<IImageDetailProps> = (props) => {
               />
             </h3>
           </div>
+          {props.image.date ? (
+            <h5>
+              <FormattedDate
+                value={props.image.date}
+                format="long"
+                timeZone="utc"
+              />
+            </h5>
+          ) : undefined}
           {props.image.rating ? (
             <h6>
               Rating: <RatingStars value={props.image.rating} />
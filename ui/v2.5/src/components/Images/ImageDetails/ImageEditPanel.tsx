@@ -1,14 +1,27 @@
 import React, { useEffect, useState } from "react";
-import { Button, Form, Col, Row } from "react-bootstrap";
+import {
+  Button,
+  Dropdown,
+  DropdownButton,
+  Form,
+  Col,
+  Row,
+} from "react-bootstrap";
 import { FormattedMessage, useIntl } from "react-intl";
 import Mousetrap from "mousetrap";
 import * as GQL from "src/core/generated-graphql";
 import * as yup from "yup";
-import { useImageUpdate } from "src/core/StashService";
+import {
+  queryScrapeImage,
+  useImageUpdate,
+  useListImageScrapers,
+  mutateReloadScrapers,
+} from "src/core/StashService";
 import {
   PerformerSelect,
   TagSelect,
   StudioSelect,
+  Icon,
   LoadingIndicator,
 } from "src/components/Shared";
 import { useToast } from "src/hooks";
@@ -16,6 +29,7 @@ import { FormUtils } from "src/utils";
 import { useFormik } from "formik";
 import { Prompt } from "react-router";
 import { RatingStars } from "src/components/Scenes/SceneDetails/RatingStars";
+import { ImageScrapeDialog } from "./ImageScrapeDialog";
 
 interface IProps {
   image: GQL.ImageDataFragment;
@@ -31,6 +45,11 @@ export const ImageEditPanel: React.FC<IProps> = ({
   const intl = useIntl();
   const Toast = useToast();
 
+  const Scrapers = useListImageScrapers();
+  const [queryableScrapers, setQueryableScrapers] = useState<GQL.Scraper[]>([]);
+
+  const [scrapedImage, setScrapedImage] = useState<GQL.ScrapedImage | null>();
+
   // Network state
   const [isLoading, setIsLoading] = useState(false);
 
@@ -38,6 +57,7 @@ export const ImageEditPanel: React.FC<IProps> = ({
 
   const schema = yup.object({
     title: yup.string().optional().nullable(),
+    date: yup.string().optional().nullable(),
     rating: yup.number().optional().nullable(),
     studio_id: yup.string().optional().nullable(),
     performer_ids: yup.array(yup.string().required()).optional().nullable(),
@@ -46,6 +66,7 @@ export const ImageEditPanel: React.FC<IProps> = ({
 
   const initialValues = {
     title: image.title ?? "",
+    date: image.date ?? "",
     rating: image.rating ?? null,
     studio_id: image.studio?.id,
     performer_ids: (image.performers ?? []).map((p) => p.id),
@@ -106,6 +127,16 @@ export const ImageEditPanel: React.FC<IProps> = ({
     }
   });
 
+  useEffect(() => {
+    const newQueryableScrapers = (
+      Scrapers?.data?.listImageScrapers ?? []
+    ).filter((s) =>
+      s.image?.supported_scrapes.includes(GQL.ScrapeType.Fragment)
+    );
+
+    setQueryableScrapers(newQueryableScrapers);
+  }, [Scrapers]);
+
   function getImageInput(input: InputValues): GQL.ImageUpdateInput {
     return {
       id: image.id,
@@ -136,6 +167,125 @@ export const ImageEditPanel: React.FC<IProps> = ({
     setIsLoading(false);
   }
 
+  async function onScrapeClicked(scraper: GQL.Scraper) {
+    setIsLoading(true);
+    try {
+      const result = await queryScrapeImage(scraper.id, image.id);
+      if (!result.data || !result.data.scrapeSingleImage?.length) {
+        Toast.success({
+          content: "No images found",
+        });
+        return;
+      }
+      setScrapedImage(result.data.scrapeSingleImage[0]);
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setIsLoading(false);
+    }
+  }
+
+  async function onReloadScrapers() {
+    setIsLoading(true);
+    try {
+      await mutateReloadScrapers();
+
+      // reload the image scrapers
+      await Scrapers.refetch();
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setIsLoading(false);
+    }
+  }
+
+  function onScrapeDialogClosed(data?: GQL.ScrapedImageDataFragment) {
+    if (data) {
+      updateImageFromScrapedImage(data);
+    }
+    setScrapedImage(undefined);
+  }
+
+  function maybeRenderScrapeDialog() {
+    if (!scrapedImage) {
+      return;
+    }
+
+    const currentImage = getImageInput(formik.values);
+
+    return (
+      <ImageScrapeDialog
+        image={currentImage}
+        scraped={scrapedImage}
+        onClose={(data) => {
+          onScrapeDialogClosed(data);
+        }}
+      />
+    );
+  }
+
+  function renderScraperMenu() {
+    return (
+      <DropdownButton
+        className="d-inline-block"
+        id="image-scrape"
+        title={intl.formatMessage({ id: "actions.scrape_with" })}
+      >
+        {queryableScrapers.map((s) => (
+          <Dropdown.Item key={s.name} onClick={() => onScrapeClicked(s)}>
+            {s.name}
+          </Dropdown.Item>
+        ))}
+        <Dropdown.Item onClick={() => onReloadScrapers()}>
+          <span className="fa-icon">
+            <Icon icon="sync-alt" />
+          </span>
+          <span>
+            <FormattedMessage id="actions.reload_scrapers" />
+          </span>
+        </Dropdown.Item>
+      </DropdownButton>
+    );
+  }
+
+  function updateImageFromScrapedImage(
+    imageData: GQL.ScrapedImageDataFragment
+  ) {
+    if (imageData.title) {
+      formik.setFieldValue("title", imageData.title);
+    }
+
+    if (imageData.date) {
+      formik.setFieldValue("date", imageData.date);
+    }
+
+    if (imageData.studio?.stored_id) {
+      formik.setFieldValue("studio_id", imageData.studio.stored_id);
+    }
+
+    if (imageData.performers?.length) {
+      const idPerfs = imageData.performers.filter((p) => {
+        return p.stored_id !== undefined && p.stored_id !== null;
+      });
+
+      if (idPerfs.length > 0) {
+        const newIds = idPerfs.map((p) => p.stored_id);
+        formik.setFieldValue("performer_ids", newIds as string[]);
+      }
+    }
+
+    if (imageData?.tags?.length) {
+      const idTags = imageData.tags.filter((t) => {
+        return t.stored_id !== undefined && t.stored_id !== null;
+      });
+
+      if (idTags.length > 0) {
+        const newIds = idTags.map((t) => t.stored_id);
+        formik.setFieldValue("tag_ids", newIds as string[]);
+      }
+    }
+  }
+
   function renderTextField(field: string, title: string, placeholder?: string) {
     return (
       <Form.Group controlId={title} as={Row}>
@@ -166,6 +316,7 @@ export const ImageEditPanel: React.FC<IProps> = ({
         message={intl.formatMessage({ id: "dialogs.unsaved_changes" })}
       />
 
+      {maybeRenderScrapeDialog()}
       <Form noValidate onSubmit={formik.handleSubmit}>
         <div className="form-container row px-3 pt-3">
           <div className="col edit-buttons mb-3 pl-0">
@@ -185,10 +336,18 @@ export const ImageEditPanel: React.FC<IProps> = ({
               <FormattedMessage id="actions.delete" />
             </Button>
           </div>
+          <Col xs={6} className="text-right">
+            {renderScraperMenu()}
+          </Col>
         </div>
         <div className="form-container row px-3">
           <div className="col-12 col-lg-6 col-xl-12">
             {renderTextField("title", intl.formatMessage({ id: "title" }))}
+            {renderTextField(
+              "date",
+              intl.formatMessage({ id: "date" }),
+              "YYYY-MM-DD"
+            )}
             <Form.Group controlId="rating" as={Row}>
               {FormUtils.renderLabel({
                 title: intl.formatMessage({ id: "rating" }),
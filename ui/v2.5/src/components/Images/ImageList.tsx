@@ -110,6 +110,7 @@ interface IImageList {
   filterHook?: (filter: ListFilterModel) => ListFilterModel;
   persistState?: PersistanceLevel;
   persistanceKey?: string;
+  defaultSort?: string;
   extraOperations?: IListHookOperation<FindImagesQueryResult>[];
 }
 
@@ -117,6 +118,7 @@ export const ImageList: React.FC<IImageList> = ({
   filterHook,
   persistState,
   persistanceKey,
+  defaultSort,
   extraOperations,
 }) => {
   const intl = useIntl();
@@ -164,6 +166,7 @@ export const ImageList: React.FC<IImageList> = ({
     addKeybinds,
     persistState,
     persistanceKey,
+    defaultSort,
   });
 
   async function viewRandom(
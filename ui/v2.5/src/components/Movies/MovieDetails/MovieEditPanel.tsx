@@ -4,8 +4,10 @@ import * as GQL from "src/core/generated-graphql";
 import * as yup from "yup";
 import Mousetrap from "mousetrap";
 import {
+  queryScrapeMovie,
   queryScrapeMovieURL,
   useListMovieScrapers,
+  mutateReloadScrapers,
 } from "src/core/StashService";
 import {
   LoadingIndicator,
@@ -17,6 +19,7 @@ import {
 import { useToast } from "src/hooks";
 import {
   Modal as BSModal,
+  Dropdown,
   Form,
   Button,
   Col,
@@ -28,6 +31,7 @@ import { RatingStars } from "src/components/Scenes/SceneDetails/RatingStars";
 import { useFormik } from "formik";
 import { Prompt } from "react-router-dom";
 import { MovieScrapeDialog } from "./MovieScrapeDialog";
+import MovieScrapeModal from "./MovieScrapeModal";
 
 interface IMovieEditPanel {
   movie?: Partial<GQL.MovieDataFragment>;
@@ -63,6 +67,8 @@ export const MovieEditPanel: React.FC<IMovieEditPanel> = ({
   );
 
   const Scrapers = useListMovieScrapers();
+  const [queryableScrapers, setQueryableScrapers] = useState<GQL.Scraper[]>([]);
+  const [scraper, setScraper] = useState<GQL.Scraper | undefined>();
   const [scrapedMovie, setScrapedMovie] = useState<
     GQL.ScrapedMovie | undefined
   >();
@@ -122,6 +128,14 @@ export const MovieEditPanel: React.FC<IMovieEditPanel> = ({
     encodingImage,
   ]);
 
+  useEffect(() => {
+    const newQueryableScrapers = (
+      Scrapers?.data?.listMovieScrapers ?? []
+    ).filter((s) => s.movie?.supported_scrapes.includes(GQL.ScrapeType.Name));
+
+    setQueryableScrapers(newQueryableScrapers);
+  }, [Scrapers]);
+
   function setRating(v: number) {
     formik.setFieldValue("rating", v);
   }
@@ -248,6 +262,102 @@ export const MovieEditPanel: React.FC<IMovieEditPanel> = ({
     }
   }
 
+  async function onReloadScrapers() {
+    setIsLoading(true);
+    try {
+      await mutateReloadScrapers();
+
+      // reload the movie scrapers
+      await Scrapers.refetch();
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setIsLoading(false);
+    }
+  }
+
+  async function onScrapeMovie(
+    selectedMovie: GQL.ScrapedMovieDataFragment,
+    selectedScraper: GQL.Scraper
+  ) {
+    setScraper(undefined);
+    setIsLoading(true);
+
+    try {
+      const {
+        __typename,
+        studio: _studio,
+        front_image: _frontImage,
+        back_image: _backImage,
+        ...ret
+      } = selectedMovie;
+
+      const result = await queryScrapeMovie(selectedScraper.id, ret);
+      if (!result?.data?.scrapeSingleMovie?.length) return;
+
+      // assume one result
+      // if this is a new movie, just dump the data
+      if (isNew) {
+        updateMovieEditStateFromScraper(result.data.scrapeSingleMovie[0]);
+      } else {
+        setScrapedMovie(result.data.scrapeSingleMovie[0]);
+      }
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setIsLoading(false);
+    }
+  }
+
+  function renderScraperMenu() {
+    return (
+      <Dropdown drop="up" className="d-inline-block">
+        <Dropdown.Toggle variant="secondary" className="mr-2">
+          <FormattedMessage id="actions.scrape_with" />
+        </Dropdown.Toggle>
+        <Dropdown.Menu id="movie-scraper-popover">
+          {queryableScrapers.map((s) => (
+            <Dropdown.Item
+              as={Button}
+              key={s.name}
+              className="minimal"
+              onClick={() => setScraper(s)}
+            >
+              {s.name}
+            </Dropdown.Item>
+          ))}
+          <Dropdown.Item
+            as={Button}
+            className="minimal"
+            onClick={() => onReloadScrapers()}
+          >
+            <span className="fa-icon">
+              <Icon icon="sync-alt" />
+            </span>
+            <span>
+              <FormattedMessage id="actions.reload_scrapers" />
+            </span>
+          </Dropdown.Item>
+        </Dropdown.Menu>
+      </Dropdown>
+    );
+  }
+
+  function maybeRenderScrapeModal() {
+    if (!scraper) {
+      return;
+    }
+
+    return (
+      <MovieScrapeModal
+        scraper={scraper}
+        onHide={() => setScraper(undefined)}
+        onSelectMovie={onScrapeMovie}
+        name={formik.values.name || ""}
+      />
+    );
+  }
+
   function urlScrapable(scrapedUrl: string) {
     return (
       !!scrapedUrl &&
@@ -507,8 +617,10 @@ export const MovieEditPanel: React.FC<IMovieEditPanel> = ({
           formik.setFieldValue("back_image", null);
         }}
         onDelete={onDelete}
+        customButtons={renderScraperMenu()}
       />
 
+      {maybeRenderScrapeModal()}
       {maybeRenderScrapeDialog()}
       {renderImageAlert()}
     </div>
@@ -0,0 +1,83 @@
+import React, { useEffect, useRef, useState } from "react";
+import { debounce } from "lodash";
+import { Button, Form } from "react-bootstrap";
+import { useIntl } from "react-intl";
+
+import * as GQL from "src/core/generated-graphql";
+import { Modal, LoadingIndicator } from "src/components/Shared";
+import { useScrapeMovieList } from "src/core/StashService";
+
+const CLASSNAME = "MovieScrapeModal";
+const CLASSNAME_LIST = `${CLASSNAME}-list`;
+
+interface IProps {
+  scraper: GQL.Scraper;
+  onHide: () => void;
+  onSelectMovie: (
+    movie: GQL.ScrapedMovieDataFragment,
+    scraper: GQL.Scraper
+  ) => void;
+  name?: string;
+}
+const MovieScrapeModal: React.FC<IProps> = ({
+  scraper,
+  name,
+  onHide,
+  onSelectMovie,
+}) => {
+  const intl = useIntl();
+  const inputRef = useRef<HTMLInputElement>(null);
+  const [query, setQuery] = useState<string>(name ?? "");
+  const { data, loading } = useScrapeMovieList(scraper.id, query);
+
+  const movies = data?.scrapeSingleMovie ?? [];
+
+  const onInputChange = debounce((input: string) => {
+    setQuery(input);
+  }, 500);
+
+  useEffect(() => inputRef.current?.focus(), []);
+
+  return (
+    <Modal
+      show
+      onHide={onHide}
+      header={`Scrape movie from ${scraper.name}`}
+      accept={{
+        text: intl.formatMessage({ id: "actions.cancel" }),
+        onClick: onHide,
+        variant: "secondary",
+      }}
+    >
+      <div className={CLASSNAME}>
+        <Form.Control
+          onChange={(e) => onInputChange(e.currentTarget.value)}
+          defaultValue={name ?? ""}
+          placeholder="Movie name..."
+          className="text-input mb-4"
+          ref={inputRef}
+        />
+        {loading ? (
+          <div className="m-4 text-center">
+            <LoadingIndicator inline />
+          </div>
+        ) : (
+          <ul className={CLASSNAME_LIST}>
+            {movies.map((m) => (
+              <li key={m.url ?? m.name}>
+                <Button
+                  variant="link"
+                  onClick={() => onSelectMovie(m, scraper)}
+                >
+                  {m.name}
+                </Button>
+              </li>
+            ))}
+          </ul>
+        )}
+      </div>
+    </Modal>
+  );
+};
+
+export default MovieScrapeModal;
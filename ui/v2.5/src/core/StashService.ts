@@ -273,7 +273,21 @@ export const useListSceneScrapers = () => GQL.useListSceneScrapersQuery();
 
 export const useListGalleryScrapers = () => GQL.useListGalleryScrapersQuery();
 
+export const useListImageScrapers = () => GQL.useListImageScrapersQuery();
+
 export const useListMovieScrapers = () => GQL.useListMovieScrapersQuery();
+export const useScrapeMovieList = (scraperId: string, q: string) =>
+  GQL.useScrapeSingleMovieQuery({
+    variables: {
+      source: {
+        scraper_id: scraperId,
+      },
+      input: {
+        query: q,
+      },
+    },
+    skip: q === "",
+  });
 
 export const useScrapeFreeonesPerformers = (q: string) =>
   GQL.useScrapeFreeonesPerformersQuery({ variables: { q } });
@@ -577,6 +591,27 @@ export const useGalleryDestroy = (input: GQL.GalleryDestroyInput) =>
     update: deleteCache(galleryMutationImpactedQueries),
   });
 
+const galleryChapterMutationImpactedQueries = [
+  GQL.FindGalleryDocument,
+  GQL.FindGalleriesDocument,
+];
+
+export const useGalleryChapterCreate = () =>
+  GQL.useGalleryChapterCreateMutation({
+    refetchQueries: getQueryNames([GQL.FindGalleryDocument]),
+    update: deleteCache(galleryChapterMutationImpactedQueries),
+  });
+export const useGalleryChapterUpdate = () =>
+  GQL.useGalleryChapterUpdateMutation({
+    refetchQueries: getQueryNames([GQL.FindGalleryDocument]),
+    update: deleteCache(galleryChapterMutationImpactedQueries),
+  });
+export const useGalleryChapterDestroy = () =>
+  GQL.useGalleryChapterDestroyMutation({
+    refetchQueries: getQueryNames([GQL.FindGalleryDocument]),
+    update: deleteCache(galleryChapterMutationImpactedQueries),
+  });
+
 export const mutateAddGalleryImages = (input: GQL.GalleryAddInput) =>
   client.mutate<GQL.AddGalleryImagesMutation>({
     mutation: GQL.AddGalleryImagesDocument,
@@ -591,6 +626,27 @@ export const mutateRemoveGalleryImages = (input: GQL.GalleryRemoveInput) =>
     update: deleteCache(galleryMutationImpactedQueries),
   });
 
+export const mutateInsertGalleryImages = (input: GQL.GalleryInsertInput) =>
+  client.mutate<GQL.InsertGalleryImagesMutation>({
+    mutation: GQL.InsertGalleryImagesDocument,
+    variables: input,
+    update: deleteCache(galleryMutationImpactedQueries),
+  });
+
+export const mutateReorderGalleryImages = (input: GQL.GalleryReorderInput) =>
+  client.mutate<GQL.ReorderGalleryImagesMutation>({
+    mutation: GQL.ReorderGalleryImagesDocument,
+    variables: input,
+    update: deleteCache(galleryMutationImpactedQueries),
+  });
+
+export const mutateSetGalleryCover = (input: GQL.GallerySetCoverInput) =>
+  client.mutate<GQL.SetGalleryCoverMutation>({
+    mutation: GQL.SetGalleryCoverDocument,
+    variables: input,
+    update: deleteCache(galleryMutationImpactedQueries),
+  });
+
 export const studioMutationImpactedQueries = [
   GQL.FindStudiosDocument,
   GQL.FindSceneDocument,
@@ -869,6 +925,32 @@ export const queryScrapeGalleryURL = (url: string) =>
     fetchPolicy: "network-only",
   });
 
+export const queryScrapeImageURL = (url: string) =>
+  client.query<GQL.ScrapeImageUrlQuery>({
+    query: GQL.ScrapeImageUrlDocument,
+    variables: {
+      url,
+    },
+    fetchPolicy: "network-only",
+  });
+
+export const queryScrapeMovie = (
+  scraperId: string,
+  scrapedMovie: GQL.ScrapedMovieInput
+) =>
+  client.query<GQL.ScrapeSingleMovieQuery>({
+    query: GQL.ScrapeSingleMovieDocument,
+    variables: {
+      source: {
+        scraper_id: scraperId,
+      },
+      input: {
+        movie_input: scrapedMovie,
+      },
+    },
+    fetchPolicy: "network-only",
+  });
+
 export const queryScrapeMovieURL = (url: string) =>
   client.query<GQL.ScrapeMovieUrlQuery>({
     query: GQL.ScrapeMovieUrlDocument,
@@ -936,6 +1018,20 @@ export const queryScrapeGallery = (scraperId: string, galleryId: string) =>
     fetchPolicy: "network-only",
   });
 
+export const queryScrapeImage = (scraperId: string, imageId: string) =>
+  client.query<GQL.ScrapeSingleImageQuery>({
+    query: GQL.ScrapeSingleImageDocument,
+    variables: {
+      source: {
+        scraper_id: scraperId,
+      },
+      input: {
+        image_id: imageId,
+      },
+    },
+    fetchPolicy: "network-only",
+  });
+
 export const mutateReloadScrapers = () =>
   client.mutate<GQL.ReloadScrapersMutation>({
     mutation: GQL.ReloadScrapersDocument,
@@ -18,9 +18,12 @@ import { DisplayMode } from "./types";
 
 const defaultSortBy = "path";
 
-const sortByOptions = ["o_counter", "filesize", ...MediaSortByOptions].map(
-  ListFilterOptions.createSortBy
-);
+const sortByOptions = [
+  "o_counter",
+  "filesize",
+  "gallery_image_position",
+  ...MediaSortByOptions,
+].map(ListFilterOptions.createSortBy);
 
 const displayModeOptions = [DisplayMode.Grid, DisplayMode.Wall];
 const criterionOptions = [
@@ -62,8 +62,33 @@ const usePasteImage = (
   return isEncoding;
 };
 
+// the widths of the image cards at each zoom level
+const zoomWidths = [240, 320, 480, 640];
+
+// Returns the thumbnail URL for an image displayed at the width. The size is
+// scaled for high density displays, and rounded up to a thumbnail size by
+// the server.
+const thumbnailURL = (url: string | null | undefined, width: number) => {
+  if (!url) return "";
+
+  const size = Math.ceil(width * (window.devicePixelRatio || 1));
+  const separator = url.includes("?") ? "&" : "?";
+  return `${url}${separator}size=${size}`;
+};
+
+const zoomThumbnailURL = (
+  url: string | null | undefined,
+  zoomIndex: number
+) =>
+  thumbnailURL(
+    url,
+    zoomWidths[zoomIndex] ?? zoomWidths[zoomWidths.length - 1]
+  );
+
 const Image = {
   onImageChange,
   usePasteImage,
+  thumbnailURL,
+  zoomThumbnailURL,
 };
 export default Image;